@@ -12,16 +12,24 @@ import (
 	"github.com/pivotal/kpack/pkg/logs"
 	"github.com/spf13/cobra"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	buildcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/build"
 	buildercmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/builder"
 	clusterbuildercmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/clusterbuilder"
 	clusterstackcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/clusterstack"
 	clusterstorecmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/clusterstore"
+	configcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/config"
+	eventscmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/events"
+	exportcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/export"
 	imgcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/image"
 	importcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/import"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands/lifecycle"
 	secretcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/secret"
+	statuscmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/status"
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+	"github.com/vmware-tanzu/kpack-cli/pkg/git"
+	"github.com/vmware-tanzu/kpack-cli/pkg/image"
 	importpkg "github.com/vmware-tanzu/kpack-cli/pkg/import"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
@@ -38,6 +46,8 @@ func main() {
 
 	var clientSetProvider k8s.DefaultClientSetProvider
 
+	configPath, _ := config.DefaultCLIConfigPath()
+
 	rootCmd := &cobra.Command{
 		Use: "kp",
 		Long: `kp controls the kpack installation on Kubernetes.
@@ -45,6 +55,13 @@ func main() {
 kpack extends Kubernetes and utilizes unprivileged kubernetes primitives to provide 
 builds of OCI images as a platform implementation of Cloud Native Buildpacks (CNB).
 Learn more about kpack @ https://github.com/pivotal/kpack`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadCLIConfig(configPath)
+			if err != nil {
+				return err
+			}
+			return commands.ApplyCLIConfigDefaults(cmd, cfg)
+		},
 	}
 	rootCmd.AddCommand(
 		getVersionCommand(),
@@ -57,11 +74,17 @@ Learn more about kpack @ https://github.com/pivotal/kpack`,
 		getStoreCommand(clientSetProvider),
 		getLifecycleCommand(clientSetProvider),
 		getImportCommand(clientSetProvider),
+		exportcmds.NewExportCommand(clientSetProvider),
+		eventscmds.NewEventsCommand(clientSetProvider),
+		statuscmds.NewStatusCommand(clientSetProvider),
+		getConfigCommand(configPath, clientSetProvider),
 		getCompletionCommand(),
 	)
 
 	err := rootCmd.Execute()
-	if err != nil {
+	if commands.IsTimeoutError(err) {
+		os.Exit(commands.TimeoutExitCode)
+	} else if err != nil {
 		os.Exit(1)
 	}
 
@@ -84,24 +107,41 @@ func getVersionCommand() *cobra.Command {
 	return versionCmd
 }
 
+type buildLogsTailer struct {
+	*logs.BuildLogsClient
+	*build.LogsClient
+}
+
+func newBuildLogsTailer(clientSet k8s.ClientSet) commands.BuildLogsTailer {
+	return buildLogsTailer{
+		BuildLogsClient: logs.NewBuildLogsClient(clientSet.K8sClient),
+		LogsClient:      build.NewLogsClient(clientSet.K8sClient),
+	}
+}
+
 func getImageCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	newImageWaiter := func(clientSet k8s.ClientSet) imgcmds.ImageWaiter {
 		return logs.NewImageWaiter(clientSet.KpackClient, logs.NewBuildLogsClient(clientSet.K8sClient))
 	}
 
+	newRevisionResolver := func(clientSet k8s.ClientSet) image.RevisionResolver {
+		return git.NewRevisionResolver(clientSet.K8sClient)
+	}
+
 	imageRootCmd := &cobra.Command{
 		Use:     "image",
 		Short:   "Image commands",
 		Aliases: []string{"images", "imgs", "img"},
 	}
 	imageRootCmd.AddCommand(
-		imgcmds.NewCreateCommand(clientSetProvider, registry.DefaultUtilProvider{}, newImageWaiter),
-		imgcmds.NewPatchCommand(clientSetProvider, registry.DefaultUtilProvider{}, newImageWaiter),
-		imgcmds.NewSaveCommand(clientSetProvider, registry.DefaultUtilProvider{}, newImageWaiter),
+		imgcmds.NewCreateCommand(clientSetProvider, registry.DefaultUtilProvider{}, newImageWaiter, newRevisionResolver),
+		imgcmds.NewPatchCommand(clientSetProvider, registry.DefaultUtilProvider{}, newImageWaiter, newRevisionResolver),
+		imgcmds.NewSaveCommand(clientSetProvider, registry.DefaultUtilProvider{}, newImageWaiter, newRevisionResolver),
 		imgcmds.NewListCommand(clientSetProvider),
-		imgcmds.NewDeleteCommand(clientSetProvider),
-		imgcmds.NewTriggerCommand(clientSetProvider),
+		imgcmds.NewDeleteCommand(clientSetProvider, commands.NewConfirmationProvider()),
+		imgcmds.NewTriggerCommand(clientSetProvider, newBuildLogsTailer),
 		imgcmds.NewStatusCommand(clientSetProvider),
+		imgcmds.NewDescribeCommand(clientSetProvider),
 	)
 	return imageRootCmd
 }
@@ -115,7 +155,8 @@ func getBuildCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	buildRootCmd.AddCommand(
 		buildcmds.NewListCommand(clientSetProvider),
 		buildcmds.NewStatusCommand(clientSetProvider, registry.DefaultUtilProvider{}),
-		buildcmds.NewLogsCommand(clientSetProvider),
+		buildcmds.NewLogsCommand(clientSetProvider, newBuildLogsTailer),
+		buildcmds.NewCancelCommand(clientSetProvider),
 	)
 	return buildRootCmd
 }
@@ -150,8 +191,10 @@ func getClusterBuilderCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Co
 		clusterbuildercmds.NewPatchCommand(clientSetProvider, commands.NewResourceWaiter),
 		clusterbuildercmds.NewSaveCommand(clientSetProvider, commands.NewResourceWaiter),
 		clusterbuildercmds.NewListCommand(clientSetProvider),
-		clusterbuildercmds.NewStatusCommand(clientSetProvider),
+		clusterbuildercmds.NewStatusCommand(clientSetProvider, registry.DefaultUtilProvider{}),
+		clusterbuildercmds.NewDescribeCommand(clientSetProvider),
 		clusterbuildercmds.NewDeleteCommand(clientSetProvider),
+		clusterbuildercmds.NewWaitCommand(clientSetProvider),
 	)
 	return clusterBuilderRootCmd
 }
@@ -182,6 +225,7 @@ func getStackCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	stackRootCmd.AddCommand(
 		clusterstackcmds.NewCreateCommand(clientSetProvider, registry.DefaultUtilProvider{}, commands.NewResourceWaiter),
 		clusterstackcmds.NewUpdateCommand(clientSetProvider, registry.DefaultUtilProvider{}, commands.NewResourceWaiter),
+		clusterstackcmds.NewPatchCommand(clientSetProvider, registry.DefaultUtilProvider{}, commands.NewResourceWaiter),
 		clusterstackcmds.NewSaveCommand(clientSetProvider, registry.DefaultUtilProvider{}, commands.NewResourceWaiter),
 		clusterstackcmds.NewListCommand(clientSetProvider),
 		clusterstackcmds.NewStatusCommand(clientSetProvider),
@@ -231,6 +275,21 @@ func getImportCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	)
 }
 
+func getConfigCommand(configPath string, clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	configRootCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Config Commands",
+	}
+	configRootCmd.AddCommand(
+		configcmds.NewSetCommand(configPath),
+		configcmds.NewGetCommand(configPath),
+		configcmds.NewUnsetCommand(configPath),
+		configcmds.NewViewCommand(configPath),
+		configcmds.NewDefaultClusterBuilderCommand(clientSetProvider),
+	)
+	return configRootCmd
+}
+
 func getCompletionCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "completion [bash|zsh|fish|powershell]",
@@ -265,6 +324,13 @@ $ kp completion fish | source
 
 # To load completions for each session, execute once:
 $ kp completion fish > ~/.config/fish/completions/kp.fish
+
+PowerShell:
+
+PS> kp completion powershell | Out-String | Invoke-Expression
+
+# To load completions for each session, add the output to your profile:
+PS> kp completion powershell >> $PROFILE
 `,
 		DisableFlagsInUseLine: true,
 		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
@@ -272,13 +338,13 @@ $ kp completion fish > ~/.config/fish/completions/kp.fish
 		Run: func(cmd *cobra.Command, args []string) {
 			switch args[0] {
 			case "bash":
-				cmd.Root().GenBashCompletion(os.Stdout)
+				cmd.Root().GenBashCompletion(cmd.OutOrStdout())
 			case "zsh":
-				cmd.Root().GenZshCompletion(os.Stdout)
+				cmd.Root().GenZshCompletion(cmd.OutOrStdout())
 			case "fish":
-				cmd.Root().GenFishCompletion(os.Stdout, true)
+				cmd.Root().GenFishCompletion(cmd.OutOrStdout(), true)
 			case "powershell":
-				cmd.Root().GenPowerShellCompletion(os.Stdout)
+				cmd.Root().GenPowerShellCompletion(cmd.OutOrStdout())
 			}
 		},
 	}
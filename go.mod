@@ -3,6 +3,7 @@ module github.com/vmware-tanzu/kpack-cli
 go 1.14
 
 require (
+	github.com/BurntSushi/toml v0.3.1
 	github.com/aryann/difflib v0.0.0-20170710044230-e206f873d14a
 	github.com/evanphx/json-patch v4.9.0+incompatible
 	github.com/ghodss/yaml v1.0.0
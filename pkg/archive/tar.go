@@ -17,23 +17,33 @@ var (
 	normalizedTime = time.Date(1980, time.January, 1, 0, 0, 1, 0, time.UTC)
 )
 
-func CreateTar(path string) (string, error) {
+// FileFilter reports whether a file should be written to the tar. The path is slash separated and relative
+// to the root of the tar. Returning false for a directory skips the directory and its contents.
+type FileFilter func(path string, fi os.FileInfo) bool
+
+func CreateTar(path string, filter FileFilter) (string, error) {
 	fh, err := ioutil.TempFile("", "")
 	if err != nil {
 		return "", fmt.Errorf("create file for tar: %s", err)
 	}
 	defer fh.Close()
 
-	tw := tar.NewWriter(fh)
-	defer tw.Close()
-
-	if err := writeDirToTar(tw, path, "/", 0, 0, -1); err != nil {
+	if err := WriteTar(fh, path, filter); err != nil {
 		return "", err
 	}
 
 	return fh.Name(), nil
 }
 
+// WriteTar writes the contents of the directory at path as a tar to the writer
+func WriteTar(w io.Writer, path string, filter FileFilter) error {
+	tw := tar.NewWriter(w)
+	if err := writeDirToTar(tw, path, "/", 0, 0, -1, filter); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
 func ReadTar(reader io.Reader, dir string) error {
 	tarReader := tar.NewReader(reader)
 	for {
@@ -65,7 +75,7 @@ func ReadTar(reader io.Reader, dir string) error {
 	return nil
 }
 
-func writeDirToTar(tw *tar.Writer, srcDir, basePath string, uid, gid int, mode int64) error {
+func writeDirToTar(tw *tar.Writer, srcDir, basePath string, uid, gid int, mode int64, filter FileFilter) error {
 	return filepath.Walk(srcDir, func(file string, fi os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -75,6 +85,20 @@ func writeDirToTar(tw *tar.Writer, srcDir, basePath string, uid, gid int, mode i
 			return nil
 		}
 
+		relPath, err := filepath.Rel(srcDir, file)
+		if err != nil {
+			return err
+		} else if relPath == "." {
+			return nil
+		}
+
+		if filter != nil && !filter(filepath.ToSlash(relPath), fi) {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		var header *tar.Header
 		if fi.Mode()&os.ModeSymlink != 0 {
 			target, err := os.Readlink(file)
@@ -93,13 +117,6 @@ func writeDirToTar(tw *tar.Writer, srcDir, basePath string, uid, gid int, mode i
 			}
 		}
 
-		relPath, err := filepath.Rel(srcDir, file)
-		if err != nil {
-			return err
-		} else if relPath == "." {
-			return nil
-		}
-
 		header.Name = filepath.ToSlash(filepath.Join(basePath, relPath))
 		finalizeHeader(header, uid, gid, mode)
 
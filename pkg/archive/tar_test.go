@@ -0,0 +1,96 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package archive_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/archive"
+)
+
+func TestTar(t *testing.T) {
+	spec.Run(t, "Test Tar operations", testTar)
+}
+
+func testTar(t *testing.T, when spec.G, it spec.S) {
+	when("#CreateTar", func() {
+		var srcDir string
+
+		it.Before(func() {
+			var err error
+			srcDir, err = ioutil.TempDir("", "src")
+			require.NoError(t, err)
+
+			require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "app", "vendor"), 0755))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(srcDir, "app", "main.go"), []byte("main"), 0644))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(srcDir, "app", "vendor", "lib.go"), []byte("lib"), 0644))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(srcDir, "README.md"), []byte("readme"), 0644))
+		})
+
+		it.After(func() {
+			require.NoError(t, os.RemoveAll(srcDir))
+		})
+
+		it("writes all files when no filter is provided", func() {
+			tarPath, err := archive.CreateTar(srcDir, nil)
+			require.NoError(t, err)
+			defer os.Remove(tarPath)
+
+			require.ElementsMatch(t, []string{"/README.md", "/app", "/app/main.go", "/app/vendor", "/app/vendor/lib.go"}, tarEntries(t, tarPath))
+		})
+
+		it("skips files and directories rejected by the filter", func() {
+			var filtered []string
+			tarPath, err := archive.CreateTar(srcDir, func(path string, fi os.FileInfo) bool {
+				filtered = append(filtered, path)
+				return path != "app/vendor" && path != "README.md"
+			})
+			require.NoError(t, err)
+			defer os.Remove(tarPath)
+
+			require.ElementsMatch(t, []string{"/app", "/app/main.go"}, tarEntries(t, tarPath))
+			require.ElementsMatch(t, []string{"README.md", "app", "app/main.go", "app/vendor"}, filtered)
+		})
+
+		it("writes the same tar to a writer", func() {
+			tarPath, err := archive.CreateTar(srcDir, nil)
+			require.NoError(t, err)
+			defer os.Remove(tarPath)
+
+			expected, err := ioutil.ReadFile(tarPath)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, archive.WriteTar(&buf, srcDir, nil))
+			require.Equal(t, expected, buf.Bytes())
+		})
+	})
+}
+
+func tarEntries(t *testing.T, tarPath string) []string {
+	f, err := os.Open(tarPath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var names []string
+	tr := tar.NewReader(f)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, header.Name)
+	}
+	return names
+}
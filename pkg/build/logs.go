@@ -0,0 +1,128 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"context"
+	"io"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// LogOptions limit the log lines read from each container of a build pod
+type LogOptions struct {
+	SinceSeconds *int64
+	SinceTime    *metav1.Time
+	TailLines    *int64
+}
+
+type LogsClient struct {
+	k8sClient kubernetes.Interface
+}
+
+func NewLogsClient(k8sClient kubernetes.Interface) *LogsClient {
+	return &LogsClient{k8sClient: k8sClient}
+}
+
+// TailWithOptions streams the logs of each build step in order, waiting for a step to start before reading it
+func (c *LogsClient) TailWithOptions(ctx context.Context, writer io.Writer, image, buildNumber, namespace string, opts LogOptions) error {
+	selector := v1alpha1.ImageLabel + "=" + image + "," + v1alpha1.BuildNumberLabel + "=" + buildNumber
+
+	pod, err := c.waitForPod(ctx, namespace, selector, func(*corev1.Pod) bool { return true })
+	if err != nil {
+		return err
+	}
+
+	containers := append(pod.Spec.InitContainers, pod.Spec.Containers...)
+	for _, container := range containers {
+		name := container.Name
+		pod, err = c.waitForPod(ctx, namespace, selector, func(p *corev1.Pod) bool {
+			return containerStarted(p, name) || podFinished(p)
+		})
+		if err != nil {
+			return err
+		}
+
+		// the remaining steps will not run when the pod finished before this one started
+		if !containerStarted(pod, name) {
+			return nil
+		}
+
+		if err := c.streamContainer(ctx, writer, pod, name, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *LogsClient) waitForPod(ctx context.Context, namespace, selector string, ready func(*corev1.Pod) bool) (*corev1.Pod, error) {
+	pods, err := c.k8sClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pods.Items) > 0 && ready(&pods.Items[0]) {
+		return &pods.Items[0], nil
+	}
+
+	watcher, err := c.k8sClient.CoreV1().Pods(namespace).Watch(ctx, metav1.ListOptions{
+		LabelSelector:   selector,
+		ResourceVersion: pods.ResourceVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case e, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil, errors.New("stopped watching the build pod before it was ready")
+			}
+
+			if pod, ok := e.Object.(*corev1.Pod); ok && ready(pod) {
+				return pod, nil
+			}
+		}
+	}
+}
+
+func (c *LogsClient) streamContainer(ctx context.Context, writer io.Writer, pod *corev1.Pod, container string, opts LogOptions) error {
+	stream, err := c.k8sClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container:    container,
+		Follow:       true,
+		SinceSeconds: opts.SinceSeconds,
+		SinceTime:    opts.SinceTime,
+		TailLines:    opts.TailLines,
+	}).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	_, err = io.Copy(writer, stream)
+	return err
+}
+
+func containerStarted(pod *corev1.Pod, name string) bool {
+	statuses := append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.Name == name {
+			return status.State.Running != nil || status.State.Terminated != nil
+		}
+	}
+	return false
+}
+
+func podFinished(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
@@ -4,6 +4,7 @@
 package builder
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -12,16 +13,20 @@ import (
 
 	"github.com/ghodss/yaml"
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pkg/errors"
 )
 
-func ReadOrder(path string) ([]v1alpha1.OrderEntry, error) {
+func ReadOrder(path string, stdin io.Reader) ([]v1alpha1.OrderEntry, error) {
 	var (
 		file io.ReadCloser
 		err  error
 	)
 
 	if path == "-" {
-		file = os.Stdin
+		if isTerminal(stdin) {
+			return nil, errors.New("order yaml must be piped to stdin when using --order -")
+		}
+		file = ioutil.NopCloser(stdin)
 	} else {
 		file, err = os.Open(path)
 		if err != nil {
@@ -35,10 +40,31 @@ func ReadOrder(path string) ([]v1alpha1.OrderEntry, error) {
 		return nil, err
 	}
 
+	if path == "-" && len(bytes.TrimSpace(buf)) == 0 {
+		return nil, errors.New("no order yaml provided on stdin")
+	}
+
 	var order []v1alpha1.OrderEntry
 	return order, yaml.Unmarshal(buf, &order)
 }
 
+func isTerminal(r io.Reader) bool {
+	if s, ok := r.(interface{ Source() io.Reader }); ok {
+		r = s.Source()
+	}
+
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
 func CreateOrder(buildpacks []string) []v1alpha1.OrderEntry {
 	group := make([]v1alpha1.BuildpackRef, 0)
 
@@ -12,6 +12,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
 	"github.com/vmware-tanzu/kpack-cli/pkg/stackimage"
 )
@@ -31,6 +32,17 @@ type Printer interface {
 type Factory struct {
 	Uploader Uploader
 	Printer  Printer
+	Metadata k8s.Metadata
+
+	// uploaded is reused when a stack is updated again after a conflict
+	uploaded *uploadedImages
+}
+
+type uploadedImages struct {
+	buildTag, runTag string
+	stackID          string
+	buildImageRef    string
+	runImageRef      string
 }
 
 func NewFactory(printer Printer, relocator registry.Relocator, fetcher registry.Fetcher) *Factory {
@@ -44,6 +56,10 @@ func NewFactory(printer Printer, relocator registry.Relocator, fetcher registry.
 }
 
 func (f *Factory) MakeStack(keychain authn.Keychain, name, buildImageTag, runImageTag string, kpConfig config.KpConfig) (*v1alpha1.ClusterStack, error) {
+	if err := f.Metadata.Validate(); err != nil {
+		return nil, err
+	}
+
 	stackID, err := f.validate(keychain, buildImageTag, runImageTag)
 	if err != nil {
 		return nil, err
@@ -58,7 +74,7 @@ func (f *Factory) MakeStack(keychain authn.Keychain, name, buildImageTag, runIma
 		return nil, err
 	}
 
-	return &v1alpha1.ClusterStack{
+	stack := &v1alpha1.ClusterStack{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       v1alpha1.ClusterStackKind,
 			APIVersion: "kpack.io/v1alpha1",
@@ -76,30 +92,71 @@ func (f *Factory) MakeStack(keychain authn.Keychain, name, buildImageTag, runIma
 				Image: relocatedRunImageRef,
 			},
 		},
-	}, nil
+	}
+	return stack, f.Metadata.Apply(stack)
 }
 
 func (f *Factory) UpdateStack(keychain authn.Keychain, stack *v1alpha1.ClusterStack, buildImageTag, runImageTag string, kpConfig config.KpConfig) (bool, error) {
-	stackID, err := f.validate(keychain, buildImageTag, runImageTag)
-	if err != nil {
+	if err := f.Metadata.Validate(); err != nil {
 		return false, err
 	}
 
-	if err := f.Printer.PrintStatus("Uploading to '%s'...", kpConfig.CanonicalRepository); err != nil {
+	stackID, relocatedBuildImageRef, relocatedRunImageRef, err := f.upload(keychain, buildImageTag, runImageTag, kpConfig)
+	if err != nil {
 		return false, err
 	}
 
-	relocatedBuildImageRef, relocatedRunImageRef, err := f.Uploader.UploadStackImages(keychain, buildImageTag, runImageTag, kpConfig.CanonicalRepository)
+	imagesUpdated, err := wasUpdated(stack, relocatedBuildImageRef, relocatedRunImageRef, stackID)
 	if err != nil {
 		return false, err
 	}
 
-	if wasUpdated, err := wasUpdated(stack, relocatedBuildImageRef, relocatedRunImageRef, stackID); err != nil {
+	metadataUpdated, err := f.applyMetadata(stack)
+	if err != nil {
 		return false, err
-	} else if !wasUpdated {
-		return false, f.Printer.Printlnf("Build and Run images already exist in stack")
 	}
-	return true, nil
+
+	if !imagesUpdated {
+		if err := f.Printer.Printlnf("Build and Run images already exist in stack"); err != nil {
+			return false, err
+		}
+	}
+	return imagesUpdated || metadataUpdated, nil
+}
+
+// PatchStack returns a copy of the stack with the provided images uploaded and set. An image
+// that is not provided keeps its current reference and is only used to validate the stack id.
+func (f *Factory) PatchStack(keychain authn.Keychain, stack *v1alpha1.ClusterStack, buildImageTag, runImageTag string, kpConfig config.KpConfig) (*v1alpha1.ClusterStack, error) {
+	if err := f.Metadata.Validate(); err != nil {
+		return nil, err
+	}
+
+	patchedStack := stack.DeepCopy()
+	if buildImageTag == "" && runImageTag == "" {
+		return patchedStack, f.Metadata.Apply(patchedStack)
+	}
+
+	buildTag, runTag := buildImageTag, runImageTag
+	if buildTag == "" {
+		buildTag = stack.Spec.BuildImage.Image
+	}
+	if runTag == "" {
+		runTag = stack.Spec.RunImage.Image
+	}
+
+	stackID, relocatedBuildImageRef, relocatedRunImageRef, err := f.upload(keychain, buildTag, runTag, kpConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	patchedStack.Spec.Id = stackID
+	if buildImageTag != "" {
+		patchedStack.Spec.BuildImage.Image = relocatedBuildImageRef
+	}
+	if runImageTag != "" {
+		patchedStack.Spec.RunImage.Image = relocatedRunImageRef
+	}
+	return patchedStack, f.Metadata.Apply(patchedStack)
 }
 
 func (f *Factory) RelocatedBuildImage(keychain authn.Keychain, kpConfig config.KpConfig, tag string) (string, error) {
@@ -110,6 +167,48 @@ func (f *Factory) RelocatedRunImage(keychain authn.Keychain, kpConfig config.KpC
 	return f.Uploader.UploadedRunImageRef(keychain, tag, kpConfig.CanonicalRepository)
 }
 
+// applyMetadata returns whether the labels or annotations of the stack were changed
+func (f *Factory) applyMetadata(stack *v1alpha1.ClusterStack) (bool, error) {
+	original := stack.DeepCopy()
+	if err := f.Metadata.Apply(stack); err != nil {
+		return false, err
+	}
+
+	patch, err := k8s.CreatePatch(original, stack)
+	return len(patch) > 0, err
+}
+
+// upload validates and uploads the stack images once, a stack that is updated again after a
+// conflict with the same images uses the references uploaded by the first attempt
+func (f *Factory) upload(keychain authn.Keychain, buildTag, runTag string, kpConfig config.KpConfig) (string, string, string, error) {
+	if u := f.uploaded; u != nil && u.buildTag == buildTag && u.runTag == runTag {
+		return u.stackID, u.buildImageRef, u.runImageRef, nil
+	}
+
+	stackID, err := f.validate(keychain, buildTag, runTag)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if err := f.Printer.PrintStatus("Uploading to '%s'...", kpConfig.CanonicalRepository); err != nil {
+		return "", "", "", err
+	}
+
+	buildImageRef, runImageRef, err := f.Uploader.UploadStackImages(keychain, buildTag, runTag, kpConfig.CanonicalRepository)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	f.uploaded = &uploadedImages{
+		buildTag:      buildTag,
+		runTag:        runTag,
+		stackID:       stackID,
+		buildImageRef: buildImageRef,
+		runImageRef:   runImageRef,
+	}
+	return stackID, buildImageRef, runImageRef, nil
+}
+
 func (f *Factory) validate(keychain authn.Keychain, buildTag, runTag string) (string, error) {
 	return f.Uploader.ValidateStackIDs(keychain, buildTag, runTag)
 }
@@ -29,6 +29,7 @@ type Printer interface {
 type Factory struct {
 	Uploader BuildpackageUploader
 	Printer  Printer
+	Metadata k8s.Metadata
 }
 
 func NewFactory(printer Printer, relocator registry.Relocator, fetcher registry.Fetcher) *Factory {
@@ -46,6 +47,10 @@ func (f *Factory) MakeStore(keychain authn.Keychain, name string, kpConfig confi
 		return nil, err
 	}
 
+	if err := f.Metadata.Validate(); err != nil {
+		return nil, err
+	}
+
 	newStore := &v1alpha1.ClusterStore{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       v1alpha1.ClusterStoreKind,
@@ -69,10 +74,20 @@ func (f *Factory) MakeStore(keychain authn.Keychain, name string, kpConfig confi
 		})
 	}
 
+	if err := f.Metadata.Apply(newStore); err != nil {
+		return nil, err
+	}
+
 	return newStore, k8s.SetLastAppliedCfg(newStore)
 }
 
+// AddToStore returns a copy of the store with the buildpackages that are not already present
 func (f *Factory) AddToStore(keychain authn.Keychain, store *v1alpha1.ClusterStore, kpConfig config.KpConfig, buildpackages ...string) (*v1alpha1.ClusterStore, bool, error) {
+	if err := f.Metadata.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	store = store.DeepCopy()
 	storeUpdated := false
 	for _, buildpackage := range buildpackages {
 		uploadedBp, err := f.Uploader.UploadBuildpackage(keychain, buildpackage, kpConfig.CanonicalRepository)
@@ -81,7 +96,7 @@ func (f *Factory) AddToStore(keychain authn.Keychain, store *v1alpha1.ClusterSto
 		}
 
 		if storeContains(store, uploadedBp) {
-			if err = f.Printer.Printlnf("\tBuildpackage already exists in the store"); err != nil {
+			if err = f.Printer.Printlnf("\tBuildpackage already present, skipping"); err != nil {
 				return store, false, err
 			}
 			continue
@@ -98,7 +113,19 @@ func (f *Factory) AddToStore(keychain authn.Keychain, store *v1alpha1.ClusterSto
 		storeUpdated = true
 	}
 
-	return store, storeUpdated, nil
+	return store, storeUpdated, f.Metadata.Apply(store)
+}
+
+// AddSourcesToStore returns a copy of the store with the sources it does not contain yet, it adds
+// buildpackages that were uploaded by AddToStore without uploading them again
+func (f *Factory) AddSourcesToStore(store *v1alpha1.ClusterStore, sources []v1alpha1.StoreImage) (*v1alpha1.ClusterStore, error) {
+	store = store.DeepCopy()
+	for _, source := range sources {
+		if !storeContains(store, source.Image) {
+			store.Spec.Sources = append(store.Spec.Sources, source)
+		}
+	}
+	return store, f.Metadata.Apply(store)
 }
 
 func (f *Factory) RelocatedBuildpackage(keychain authn.Keychain, kpConfig config.KpConfig, buildPackage string) (string, error) {
@@ -0,0 +1,178 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+const (
+	kpackGroup       = "kpack.io"
+	cancelAnnotation = "kpack.io/cancel"
+)
+
+// cancelAnnotationVersions are the kpack api versions known to cancel a build with the cancel annotation,
+// the build pod is deleted on clusters that do not serve any of them
+var cancelAnnotationVersions = []string{"v1alpha2"}
+
+func NewCancelCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	var (
+		namespace   string
+		buildNumber string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cancel <image-name>",
+		Short: "Cancel an image build",
+		Long: `Cancels a running build of an image in the provided namespace.
+
+The build defaults to the latest build number.
+The namespace defaults to the kubernetes current-context namespace.
+
+Clusters serving the v1alpha2 kpack API cancel the build with the "kpack.io/cancel" annotation.
+Otherwise the build pod is deleted which causes the build to fail.
+
+Use the --wait flag to wait until the build has stopped, the command exits with code 124 when the build
+is still running after the --wait-timeout.`,
+		Example:           "kp build cancel my-image\nkp build cancel my-image -b 2 -n my-namespace\nkp build cancel my-image --wait --wait-timeout 5m\nkp build cancel my-image --dry-run",
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ImageNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cs, err := clientSetProvider.GetClientSet(namespace)
+			if err != nil {
+				return err
+			}
+
+			ch, err := commands.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+
+			buildList, err := cs.KpackClient.KpackV1alpha1().Builds(cs.Namespace).List(ctx, metav1.ListOptions{
+				LabelSelector: v1alpha1.ImageLabel + "=" + args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(buildList.Items) == 0 {
+				return errors.New("no builds found")
+			}
+
+			sort.Slice(buildList.Items, build.Sort(buildList.Items))
+			bld, err := findBuild(buildList, buildNumber)
+			if err != nil {
+				return err
+			}
+
+			number := bld.Labels[v1alpha1.BuildNumberLabel]
+			if !bld.IsRunning() {
+				return errors.Errorf("build %s is already complete", number)
+			}
+
+			if err := cancelBuild(ctx, cs, ch, bld); err != nil {
+				return err
+			}
+
+			if ch.ShouldWait() {
+				if err := waitForCancel(ctx, cs, ch, bld.Name, number); err != nil {
+					return err
+				}
+			}
+
+			return ch.PrintResult("Canceled build %s for Image %q", number, args[0])
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	cmd.Flags().StringVarP(&buildNumber, "build", "b", "", "build number")
+	cmd.Flags().BoolP(commands.WaitFlag, "w", false, "wait for the build to stop before returning")
+	cmd.Flags().Bool(commands.DryRunFlag, false, "print the build that would be canceled without canceling it")
+	commands.SetServerDryRunFlag(cmd)
+	commands.SetWaitTimeoutFlag(cmd)
+
+	return cmd
+}
+
+// cancelBuild only checks that the build can be canceled for client side dry runs
+func cancelBuild(ctx context.Context, cs k8s.ClientSet, ch *commands.CommandHelper, bld v1alpha1.Build) error {
+	annotationSupported, err := supportsCancelAnnotation(cs)
+	if err != nil {
+		return err
+	}
+
+	if annotationSupported {
+		patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:"true"}}}`, cancelAnnotation)
+		if !ch.ShouldSubmit() {
+			return nil
+		}
+
+		_, err = cs.KpackClient.KpackV1alpha1().Builds(cs.Namespace).Patch(ctx, bld.Name, types.MergePatchType, []byte(patch), ch.PatchOptions())
+		return err
+	}
+
+	if bld.Status.PodName == "" {
+		return errors.Errorf("build %s does not have a pod to delete", bld.Labels[v1alpha1.BuildNumberLabel])
+	}
+
+	if !ch.ShouldSubmit() {
+		return nil
+	}
+
+	err = cs.K8sClient.CoreV1().Pods(cs.Namespace).Delete(ctx, bld.Status.PodName, ch.DeleteOptions())
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func waitForCancel(ctx context.Context, cs k8s.ClientSet, ch *commands.CommandHelper, name, number string) error {
+	ctx, cancel := context.WithTimeout(ctx, ch.WaitTimeout())
+	defer cancel()
+
+	_, err := waitForBuildCompletion(ctx, cs, name)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return commands.NewTimeoutError("timed out after %v waiting for build %s to stop", ch.WaitTimeout(), number)
+	}
+	return err
+}
+
+// the v1alpha1 api has no cancel support so the annotation is only used when a version known to support it is served
+func supportsCancelAnnotation(cs k8s.ClientSet) (bool, error) {
+	groups, err := cs.K8sClient.Discovery().ServerGroups()
+	if err != nil {
+		return false, err
+	}
+
+	for _, group := range groups.Groups {
+		if group.Name != kpackGroup {
+			continue
+		}
+
+		for _, version := range group.Versions {
+			for _, supported := range cancelAnnotationVersions {
+				if version.Version == supported {
+					return true, nil
+				}
+			}
+		}
+	}
+
+	return false, nil
+}
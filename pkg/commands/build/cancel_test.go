@@ -0,0 +1,237 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build_test
+
+import (
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8sfakes "k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestBuildCancelCommand(t *testing.T) {
+	spec.Run(t, "TestBuildCancelCommand", testBuildCancelCommand)
+}
+
+func testBuildCancelCommand(t *testing.T, when spec.G, it spec.S) {
+	const (
+		image     = "test-image"
+		namespace = "some-namespace"
+	)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-three",
+			Namespace: namespace,
+		},
+	}
+
+	var (
+		servedVersion string
+		watchedBuild  *v1alpha1.Build
+		k8sClientSet  *k8sfakes.Clientset
+	)
+
+	cmdFunc := func(k8sClient *k8sfakes.Clientset, kpackClient *kpackfakes.Clientset) *cobra.Command {
+		k8sClientSet = k8sClient
+		k8sClient.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+			{GroupVersion: "kpack.io/" + servedVersion},
+		}
+
+		if watchedBuild != nil {
+			kpackClient.PrependWatchReactor("builds", func(action clientgotesting.Action) (bool, watch.Interface, error) {
+				w := watch.NewRaceFreeFake()
+				w.Modify(watchedBuild)
+				return true, w, nil
+			})
+		}
+
+		return build.NewCancelCommand(testhelpers.GetFakeClusterProvider(k8sClient, kpackClient))
+	}
+
+	it.Before(func() {
+		servedVersion = "v1alpha1"
+		watchedBuild = nil
+	})
+
+	when("the cluster serves the v1alpha1 api", func() {
+		it("deletes the pod of the latest build", func() {
+			testhelpers.CommandTest{
+				Objects: append(testhelpers.MakeTestBuilds(image, namespace), pod),
+				Args:    []string{image, "-n", namespace},
+				ExpectDeletes: []clientgotesting.DeleteActionImpl{
+					{
+						ActionImpl: clientgotesting.ActionImpl{
+							Namespace: namespace,
+						},
+						Name: "pod-three",
+					},
+				},
+				ExpectedOutput: "Canceled build 3 for Image \"test-image\"\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("waits for the build to stop when the wait flag is used", func() {
+			for _, obj := range testhelpers.MakeTestBuilds(image, namespace) {
+				if bld := obj.(*v1alpha1.Build); bld.Name == "build-three" {
+					watchedBuild = bld.DeepCopy()
+				}
+			}
+			watchedBuild.Status.Conditions = corev1alpha1.Conditions{
+				{Type: corev1alpha1.ConditionSucceeded, Status: corev1.ConditionFalse},
+			}
+
+			testhelpers.CommandTest{
+				Objects: append(testhelpers.MakeTestBuilds(image, namespace), pod),
+				Args:    []string{image, "-n", namespace, "--wait"},
+				ExpectDeletes: []clientgotesting.DeleteActionImpl{
+					{
+						ActionImpl: clientgotesting.ActionImpl{
+							Namespace: namespace,
+						},
+						Name: "pod-three",
+					},
+				},
+				ExpectedOutput: "Canceled build 3 for Image \"test-image\"\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+	})
+
+	when("the wait flag is used and the build does not stop before the timeout", func() {
+		it("returns a timeout error", func() {
+			for _, obj := range testhelpers.MakeTestBuilds(image, namespace) {
+				if bld := obj.(*v1alpha1.Build); bld.Name == "build-three" {
+					watchedBuild = bld.DeepCopy()
+				}
+			}
+
+			testhelpers.CommandTest{
+				Objects: append(testhelpers.MakeTestBuilds(image, namespace), pod),
+				Args:    []string{image, "-n", namespace, "--wait", "--wait-timeout", "10ms"},
+				ExpectDeletes: []clientgotesting.DeleteActionImpl{
+					{
+						ActionImpl: clientgotesting.ActionImpl{
+							Namespace: namespace,
+						},
+						Name: "pod-three",
+					},
+				},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: timed out after 10ms waiting for build 3 to stop\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+	})
+
+	when("the dry-run flag is used", func() {
+		it("does not delete the pod", func() {
+			testhelpers.CommandTest{
+				Objects:        append(testhelpers.MakeTestBuilds(image, namespace), pod),
+				Args:           []string{image, "-n", namespace, "--dry-run"},
+				ExpectedOutput: "Canceled build 3 for Image \"test-image\" (dry run)\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+	})
+
+	when("the server-side-dry-run flag is used", func() {
+		it("deletes the pod with the dry run option", func() {
+			testhelpers.CommandTest{
+				Objects: append(testhelpers.MakeTestBuilds(image, namespace), pod),
+				Args:    []string{image, "-n", namespace, "--server-side-dry-run"},
+				ExpectDeletes: []clientgotesting.DeleteActionImpl{
+					{
+						ActionImpl: clientgotesting.ActionImpl{
+							Namespace: namespace,
+						},
+						Name: "pod-three",
+					},
+				},
+				ExpectedOutput: "Canceled build 3 for Image \"test-image\" (server dry run)\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+
+			var deleteOptions []metav1.DeleteOptions
+			for _, action := range k8sClientSet.Actions() {
+				if deleteAction, ok := action.(clientgotesting.DeleteActionImpl); ok {
+					deleteOptions = append(deleteOptions, deleteAction.GetDeleteOptions())
+				}
+			}
+			require.Equal(t, []metav1.DeleteOptions{{DryRun: []string{metav1.DryRunAll}}}, deleteOptions)
+		})
+	})
+
+	when("the cluster serves a kpack api that is not known to support the cancel annotation", func() {
+		it("deletes the pod of the latest build", func() {
+			servedVersion = "v1beta1"
+
+			testhelpers.CommandTest{
+				Objects: append(testhelpers.MakeTestBuilds(image, namespace), pod),
+				Args:    []string{image, "-n", namespace},
+				ExpectDeletes: []clientgotesting.DeleteActionImpl{
+					{
+						ActionImpl: clientgotesting.ActionImpl{
+							Namespace: namespace,
+						},
+						Name: "pod-three",
+					},
+				},
+				ExpectedOutput: "Canceled build 3 for Image \"test-image\"\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+	})
+
+	when("the cluster serves a kpack api that supports the cancel annotation", func() {
+		it("annotates the build with the cancel annotation", func() {
+			servedVersion = "v1alpha2"
+
+			testhelpers.CommandTest{
+				Objects: testhelpers.MakeTestBuilds(image, namespace),
+				Args:    []string{image, "-n", namespace},
+				ExpectPatches: []string{
+					`{"metadata":{"annotations":{"kpack.io/cancel":"true"}}}`,
+				},
+				ExpectedOutput: "Canceled build 3 for Image \"test-image\"\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+	})
+
+	it("errors when the build is already complete", func() {
+		testhelpers.CommandTest{
+			Objects:        testhelpers.MakeTestBuilds(image, namespace),
+			Args:           []string{image, "-n", namespace, "-b", "1"},
+			ExpectErr:      true,
+			ExpectedOutput: "Error: build 1 is already complete\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("errors when the build does not exist", func() {
+		testhelpers.CommandTest{
+			Objects:        testhelpers.MakeTestBuilds(image, namespace),
+			Args:           []string{image, "-n", namespace, "-b", "123"},
+			ExpectErr:      true,
+			ExpectedOutput: "Error: build \"123\" not found\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("errors when there are no builds", func() {
+		testhelpers.CommandTest{
+			Objects:        []runtime.Object{pod},
+			Args:           []string{image, "-n", namespace},
+			ExpectErr:      true,
+			ExpectedOutput: "Error: no builds found\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+}
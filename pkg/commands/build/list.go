@@ -4,12 +4,15 @@
 package build
 
 import (
+	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/build"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
@@ -18,7 +21,18 @@ import (
 
 func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	var (
-		namespace string
+		namespace     string
+		imageName     string
+		limit         int
+		allNamespaces bool
+		labelSelector string
+		fieldSelector string
+		status        string
+		failed        bool
+		succeeded     bool
+		running       bool
+		watch         bool
+		sortBy        string
 	)
 
 	cmd := &cobra.Command{
@@ -26,54 +40,269 @@ func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 		Short: "List builds",
 		Long: `Prints a table of the most important information about builds in the provided namespace.
 
-The namespace defaults to the kubernetes current-context namespace.`,
+The namespace defaults to the kubernetes current-context namespace.
+Use the --all-namespaces flag to list builds in all namespaces.
+Use the --watch flag to keep the table updated as builds change until interrupted.
+Use "--output wide" to add the started, finished and pod name columns to the table.
 
-		Example:      "kp build list\nkp build list my-image\nkp build list my-image -n my-namespace",
+Use the image-name argument or the --image flag to only list the builds of an image.
+Use the --limit flag to only list the most recently created builds.
+
+Use --field-selector to pass a field selector to the kubernetes API.
+Use the --status flag with one of success, failure, building or pending to only list builds with that status.
+The --failed, --succeeded and --running flags are shorthands for the --status flag.
+
+Use the --sort-by flag to sort the builds by image, by status or by the time they were started.`,
+
+		Example:      "kp build list\nkp build list my-image\nkp build list my-image -n my-namespace\nkp build list -A\nkp build list -l team=my-team\nkp build list --image my-image --limit 5\nkp build list my-image --status failure\nkp build list my-image --watch\nkp build list my-image -o wide\nkp build list -A --sort-by started",
 		Args:         commands.OptionalArgsWithUsage(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := commands.ValidateAllNamespacesFlag(cmd); err != nil {
+				return err
+			}
+
+			if err := commands.ValidateLabelSelector(labelSelector); err != nil {
+				return err
+			}
+
+			if err := commands.ValidateSortBy(sortBy, buildSortKeys...); err != nil {
+				return err
+			}
+
 			cs, err := clientSetProvider.GetClientSet(namespace)
 			if err != nil {
 				return err
 			}
 
-			opts := metav1.ListOptions{}
+			ch, err := commands.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
 
-			if len(args) > 0 {
-				opts.LabelSelector = v1alpha1.ImageLabel + "=" + args[0]
+			if watch && ch.IsOutput() {
+				return errors.New("--watch cannot be used with --output")
 			}
 
-			buildList, err := cs.KpackClient.KpackV1alpha1().Builds(cs.Namespace).List(cmd.Context(), opts)
+			statusFilter, err := getStatusFilter(status, failed, succeeded, running)
 			if err != nil {
 				return err
 			}
 
-			if len(buildList.Items) == 0 {
+			if len(args) > 0 {
+				if imageName != "" {
+					return errors.New("image name cannot be provided as an argument and with --image")
+				}
+				imageName = args[0]
+			}
+
+			if limit < 0 {
+				return errors.New("--limit must be a positive number")
+			}
+
+			var selectors []string
+			if imageName != "" {
+				selectors = append(selectors, v1alpha1.ImageLabel+"="+imageName)
+			}
+			if labelSelector != "" {
+				selectors = append(selectors, labelSelector)
+			}
+
+			opts := metav1.ListOptions{
+				LabelSelector: strings.Join(selectors, ","),
+				FieldSelector: fieldSelector,
+			}
+
+			buildsNamespace := cs.Namespace
+			if allNamespaces {
+				buildsNamespace = metav1.NamespaceAll
+			}
+
+			buildList, err := cs.KpackClient.KpackV1alpha1().Builds(buildsNamespace).List(cmd.Context(), opts)
+			if err != nil {
+				return commands.AllNamespacesListError(err, "builds", allNamespaces)
+			}
+
+			if watch {
+				opts.ResourceVersion = buildList.ResourceVersion
+				watcher, err := cs.KpackClient.KpackV1alpha1().Builds(buildsNamespace).Watch(cmd.Context(), opts)
+				if err != nil {
+					return commands.AllNamespacesListError(err, "builds", allNamespaces)
+				}
+				defer watcher.Stop()
+
+				var objs []runtime.Object
+				for i := range buildList.Items {
+					objs = append(objs, &buildList.Items[i])
+				}
+
+				return commands.WatchTable(cmd.Context(), cmd.OutOrStdout(), objs, watcher, func(objs []runtime.Object) error {
+					watchedList := &v1alpha1.BuildList{}
+					for _, obj := range objs {
+						if bld, ok := obj.(*v1alpha1.Build); ok {
+							watchedList.Items = append(watchedList.Items, *bld)
+						}
+					}
+
+					filterBuilds(watchedList, statusFilter)
+					limitBuilds(watchedList, limit)
+
+					sortBuilds(watchedList, sortBy)
+					return displayBuildsTable(cmd, watchedList, allNamespaces, ch.IsWide())
+				})
+			}
+
+			filterBuilds(buildList, statusFilter)
+			limitBuilds(buildList, limit)
+			sortBuilds(buildList, sortBy)
+
+			if ch.IsOutput() {
+				return ch.PrintObj(buildList)
+			}
+
+			if len(buildList.Items) == 0 && statusFilter != "" {
+				_, err := fmt.Fprintln(cmd.ErrOrStderr(), "no builds found")
+				return err
+			} else if len(buildList.Items) == 0 {
 				return errors.New("no builds found")
 			} else {
-				sort.Slice(buildList.Items, build.Sort(buildList.Items))
-				return displayBuildsTable(cmd, buildList)
+				return displayBuildsTable(cmd, buildList, allNamespaces, ch.IsWide())
 			}
 		},
 	}
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	commands.SetAllNamespacesFlag(cmd, &allNamespaces)
+	commands.SetLabelSelectorFlag(cmd, &labelSelector)
+	commands.SetSortByFlag(cmd, &sortBy, buildSortKeys...)
+	cmd.Flags().StringVar(&imageName, "image", "", "name of the image to list the builds of")
+	cmd.Flags().IntVar(&limit, "limit", 0, "maximum number of the most recently created builds to list")
+	cmd.Flags().StringVar(&fieldSelector, "field-selector", "", "field selector to filter on, passed to the kubernetes API as is")
+	cmd.Flags().StringVar(&status, "status", "", "only list builds with this status, one of success, failure, building or pending")
+	cmd.Flags().BoolVar(&failed, "failed", false, "only list failed builds")
+	cmd.Flags().BoolVar(&succeeded, "succeeded", false, "only list succeeded builds")
+	cmd.Flags().BoolVar(&running, "running", false, "only list running builds")
+	commands.SetWideListOutputFlag(cmd)
+	commands.SetWatchFlag(cmd, &watch)
+	commands.SetNoColorFlag(cmd)
 
 	return cmd
 }
 
-func displayBuildsTable(cmd *cobra.Command, buildList *v1alpha1.BuildList) error {
-	writer, err := commands.NewTableWriter(cmd.OutOrStdout(), "Build", "Status", "Image", "Reason")
+var buildStatuses = map[string]string{
+	"success":  "SUCCESS",
+	"failure":  "FAILURE",
+	"building": "BUILDING",
+	"pending":  "UNKNOWN",
+}
+
+// getStatusFilter returns the build status to keep, the status condition of a build
+// cannot be used in a field selector as the kubernetes API only supports metadata fields for custom resources
+func getStatusFilter(status string, failed, succeeded, running bool) (string, error) {
+	var statuses []string
+	if status != "" {
+		s, ok := buildStatuses[strings.ToLower(status)]
+		if !ok {
+			return "", errors.Errorf("invalid status '%s', must be one of success, failure, building or pending", status)
+		}
+		statuses = append(statuses, s)
+	}
+	if failed {
+		statuses = append(statuses, "FAILURE")
+	}
+	if succeeded {
+		statuses = append(statuses, "SUCCESS")
+	}
+	if running {
+		statuses = append(statuses, "BUILDING")
+	}
+
+	if len(statuses) > 1 {
+		return "", errors.New("only one of --status, --failed, --succeeded or --running can be used")
+	} else if len(statuses) == 0 {
+		return "", nil
+	}
+	return statuses[0], nil
+}
+
+func filterBuilds(buildList *v1alpha1.BuildList, status string) {
+	if status == "" {
+		return
+	}
+
+	var filtered []v1alpha1.Build
+	for _, bld := range buildList.Items {
+		if getStatus(bld) == status {
+			filtered = append(filtered, bld)
+		}
+	}
+	buildList.Items = filtered
+}
+
+// limitBuilds keeps the most recently created builds, the builds are sorted for display afterwards
+func limitBuilds(buildList *v1alpha1.BuildList, limit int) {
+	if limit <= 0 || len(buildList.Items) <= limit {
+		return
+	}
+
+	sort.SliceStable(buildList.Items, func(i, j int) bool {
+		return buildList.Items[j].CreationTimestamp.Before(&buildList.Items[i].CreationTimestamp)
+	})
+	buildList.Items = buildList.Items[:limit]
+}
+
+var buildSortKeys = []string{"image", "status", "started"}
+
+func sortBuilds(buildList *v1alpha1.BuildList, sortBy string) {
+	items := buildList.Items
+	sort.Slice(items, build.Sort(items))
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Namespace < items[j].Namespace
+	})
+
+	switch sortBy {
+	case "status":
+		sort.SliceStable(items, func(i, j int) bool {
+			return getStatus(items[i]) < getStatus(items[j])
+		})
+	case "started":
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].CreationTimestamp.Before(&items[j].CreationTimestamp)
+		})
+	}
+}
+
+func displayBuildsTable(cmd *cobra.Command, buildList *v1alpha1.BuildList, allNamespaces, wide bool) error {
+	headers := []string{"Build", "Status", "Image", "Reason"}
+	if allNamespaces {
+		headers = append([]string{"Namespace"}, headers...)
+	}
+
+	writer, err := commands.NewWideTableWriter(cmd.OutOrStdout(), wide, headers, "Started", "Finished", "Pod Name")
 	if err != nil {
 		return err
 	}
 
+	color, err := commands.ColorEnabled(cmd)
+	if err != nil {
+		return err
+	}
+
+	if color {
+		writer.ColorizeStatusColumn("Status")
+	}
+
 	for _, bld := range buildList.Items {
-		err := writer.AddRow(
+		row := []string{
 			bld.Labels[v1alpha1.BuildNumberLabel],
 			getStatus(bld),
 			bld.Status.LatestImage,
 			getTruncatedReason(bld),
-		)
+		}
+		if allNamespaces {
+			row = append([]string{bld.Namespace}, row...)
+		}
+
+		err := writer.AddWideRow(row, getStarted(bld), getFinished(bld), bld.Status.PodName)
 		if err != nil {
 			return err
 		}
@@ -4,12 +4,21 @@
 package build_test
 
 import (
+	"io"
+	"io/ioutil"
 	"testing"
 
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
 	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
 	"github.com/sclevine/spec"
 	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	clientgotesting "k8s.io/client-go/testing"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands/build"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
 )
@@ -31,6 +40,8 @@ func testBuildListCommand(t *testing.T, when spec.G, it spec.S) {
 `
 	)
 
+	isTerminal := commands.IsTerminal
+
 	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
 		clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
 		return build.NewListCommand(clientSetProvider)
@@ -46,6 +57,32 @@ func testBuildListCommand(t *testing.T, when spec.G, it spec.S) {
 						ExpectedOutput: expectedOutput,
 					}.TestKpack(t, cmdFunc)
 				})
+
+				it("colors the status when the output is a terminal", func() {
+					commands.IsTerminal = func(io.Writer) bool { return true }
+					defer func() { commands.IsTerminal = isTerminal }()
+
+					testhelpers.CommandTest{
+						Objects: testhelpers.MakeTestBuilds(image, defaultNamespace),
+						ExpectedOutput: "BUILD    \x1b[39mSTATUS\x1b[0m      IMAGE                         REASON\n" +
+							"1        \x1b[32mSUCCESS\x1b[0m     repo.com/image-1:tag          CONFIG\n" +
+							"2        \x1b[31mFAILURE\x1b[0m     repo.com/image-2:tag          COMMIT+\n" +
+							"3        \x1b[33mBUILDING\x1b[0m    repo.com/image-3:tag          TRIGGER\n" +
+							"1        \x1b[33mBUILDING\x1b[0m    repo.com/other-image-1:tag    UNKNOWN\n" +
+							"\n",
+					}.TestKpack(t, cmdFunc)
+				})
+
+				it("does not color the status with the no-color flag", func() {
+					commands.IsTerminal = func(io.Writer) bool { return true }
+					defer func() { commands.IsTerminal = isTerminal }()
+
+					testhelpers.CommandTest{
+						Objects:        testhelpers.MakeTestBuilds(image, defaultNamespace),
+						Args:           []string{"--no-color"},
+						ExpectedOutput: expectedOutput,
+					}.TestKpack(t, cmdFunc)
+				})
 			})
 
 			when("there are no builds", func() {
@@ -84,6 +121,203 @@ func testBuildListCommand(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("all namespaces is specified", func() {
+			const expectedOutput = `NAMESPACE                 BUILD    STATUS      IMAGE                         REASON
+some-default-namespace    1        SUCCESS     repo.com/image-1:tag          CONFIG
+some-default-namespace    2        FAILURE     repo.com/image-2:tag          COMMIT+
+some-default-namespace    3        BUILDING    repo.com/image-3:tag          TRIGGER
+some-default-namespace    1        BUILDING    repo.com/other-image-1:tag    UNKNOWN
+test-namespace            1        SUCCESS     repo.com/image-1:tag          CONFIG
+test-namespace            2        FAILURE     repo.com/image-2:tag          COMMIT+
+test-namespace            3        BUILDING    repo.com/image-3:tag          TRIGGER
+test-namespace            1        BUILDING    repo.com/other-image-1:tag    UNKNOWN
+
+`
+			it("lists the builds in all namespaces ordered by namespace", func() {
+				testhelpers.CommandTest{
+					Objects: append(
+						testhelpers.MakeTestBuilds(image, "test-namespace"),
+						testhelpers.MakeTestBuilds(image, defaultNamespace)...,
+					),
+					Args:           []string{"-A"},
+					ExpectedOutput: expectedOutput,
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("returns an error when a namespace is also provided", func() {
+				testhelpers.CommandTest{
+					Args:           []string{"-A", "-n", "test-namespace"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: --namespace and --all-namespaces are mutually exclusive\n",
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
+		when("a label selector is provided", func() {
+			it("rejects an invalid selector before listing the builds", func() {
+				client := fake.NewSimpleClientset()
+				cmd := cmdFunc(client)
+				cmd.SetOut(ioutil.Discard)
+				cmd.SetErr(ioutil.Discard)
+				cmd.SetArgs([]string{image, "-l", "=a"})
+
+				err := cmd.Execute()
+				require.Error(t, err)
+				require.Contains(t, err.Error(), `invalid label selector "=a"`)
+				require.Len(t, client.Actions(), 0)
+			})
+
+			it("combines the selector with the image selector", func() {
+				var client *fake.Clientset
+				testhelpers.CommandTest{
+					Args:           []string{image, "-l", "team=a"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: no builds found\n",
+				}.TestKpack(t, func(clientSet *fake.Clientset) *cobra.Command {
+					client = clientSet
+					return cmdFunc(clientSet)
+				})
+
+				require.Len(t, client.Actions(), 1)
+				listAction := client.Actions()[0].(clientgotesting.ListAction)
+				require.Equal(t, "image.kpack.io/image=test-image,team=a", listAction.GetListRestrictions().Labels.String())
+			})
+		})
+
+		when("the image flag is provided", func() {
+			it("filters the builds with the image label selector", func() {
+				var client *fake.Clientset
+				testhelpers.CommandTest{
+					Args:           []string{"--image", image, "-l", "team=a"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: no builds found\n",
+				}.TestKpack(t, func(clientSet *fake.Clientset) *cobra.Command {
+					client = clientSet
+					return cmdFunc(clientSet)
+				})
+
+				require.Len(t, client.Actions(), 1)
+				listAction := client.Actions()[0].(clientgotesting.ListAction)
+				require.Equal(t, "image.kpack.io/image=test-image,team=a", listAction.GetListRestrictions().Labels.String())
+			})
+
+			it("returns an error when the image is also provided as an argument", func() {
+				testhelpers.CommandTest{
+					Args:           []string{image, "--image", image},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: image name cannot be provided as an argument and with --image\n",
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
+		when("a limit is provided", func() {
+			it("lists the most recently created builds", func() {
+				testhelpers.CommandTest{
+					Objects: testhelpers.MakeTestBuilds(image, defaultNamespace),
+					Args:    []string{"--image", image, "--limit", "2"},
+					ExpectedOutput: `BUILD    STATUS      IMAGE                   REASON
+2        FAILURE     repo.com/image-2:tag    COMMIT+
+3        BUILDING    repo.com/image-3:tag    TRIGGER
+
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
+		when("a sort key is provided", func() {
+			it("sorts the builds by status", func() {
+				testhelpers.CommandTest{
+					Objects:        testhelpers.MakeTestBuilds(image, defaultNamespace),
+					Args:           []string{"--image", image, "--sort-by", "status", "-o", "jsonpath={.items[*].metadata.name}"},
+					ExpectedOutput: "build-three build-two build-one\n",
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("returns an error for an unsupported sort key", func() {
+				testhelpers.CommandTest{
+					Args:           []string{image, "--sort-by", "name"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: invalid --sort-by \"name\", must be one of image, status, started\n",
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
+		when("a field selector is provided", func() {
+			it("passes the selector to the list call", func() {
+				var client *fake.Clientset
+				testhelpers.CommandTest{
+					Args:           []string{image, "--field-selector", "status.conditions[0].status=False"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: no builds found\n",
+				}.TestKpack(t, func(clientSet *fake.Clientset) *cobra.Command {
+					client = clientSet
+					return cmdFunc(clientSet)
+				})
+
+				require.Len(t, client.Actions(), 1)
+				listAction := client.Actions()[0].(clientgotesting.ListAction)
+				require.Equal(t, "status.conditions[0].status=False", listAction.GetListRestrictions().Fields.String())
+			})
+		})
+
+		when("a status flag is provided", func() {
+			it("lists only the builds with that status", func() {
+				testhelpers.CommandTest{
+					Objects: testhelpers.MakeTestBuilds(image, defaultNamespace),
+					Args:    []string{image, "--failed"},
+					ExpectedOutput: `BUILD    STATUS     IMAGE                   REASON
+2        FAILURE    repo.com/image-2:tag    COMMIT+
+
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("returns an error when more than one status is provided", func() {
+				testhelpers.CommandTest{
+					Args:           []string{image, "--failed", "--running"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: only one of --status, --failed, --succeeded or --running can be used\n",
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
+		when("the status flag is provided", func() {
+			it("lists only the builds with that status", func() {
+				testhelpers.CommandTest{
+					Objects: testhelpers.MakeTestBuilds(image, defaultNamespace),
+					Args:    []string{"--image", image, "--status", "building"},
+					ExpectedOutput: `BUILD    STATUS      IMAGE                   REASON
+3        BUILDING    repo.com/image-3:tag    TRIGGER
+
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("prints a message to stderr and does not error when no builds match", func() {
+				testhelpers.CommandTest{
+					Objects:             testhelpers.MakeTestBuilds(image, defaultNamespace),
+					Args:                []string{image, "--status", "pending"},
+					ExpectedErrorOutput: "no builds found\n",
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("returns an error for an invalid status", func() {
+				testhelpers.CommandTest{
+					Args:           []string{image, "--status", "done"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: invalid status 'done', must be one of success, failure, building or pending\n",
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("returns an error when used with another status flag", func() {
+				testhelpers.CommandTest{
+					Args:           []string{image, "--status", "failure", "--running"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: only one of --status, --failed, --succeeded or --running can be used\n",
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
 		when("an image is specified", func() {
 			const expectedOutput = `BUILD    STATUS      IMAGE                   REASON
 1        SUCCESS     repo.com/image-1:tag    CONFIG
@@ -101,6 +335,21 @@ func testBuildListCommand(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("the output is wide", func() {
+				it("adds the started, finished and pod name columns", func() {
+					testhelpers.CommandTest{
+						Objects: testhelpers.MakeTestBuilds(image, defaultNamespace),
+						Args:    []string{image, "-o", "wide"},
+						ExpectedOutput: `BUILD    STATUS      IMAGE                   REASON     STARTED                FINISHED               POD NAME
+1        SUCCESS     repo.com/image-1:tag    CONFIG     0001-01-01 00:00:00    0001-01-01 00:00:00    pod-one
+2        FAILURE     repo.com/image-2:tag    COMMIT+    0001-01-01 01:00:00    0001-01-01 00:00:00    pod-two
+3        BUILDING    repo.com/image-3:tag    TRIGGER    0001-01-01 05:00:00                           pod-three
+
+`,
+					}.TestKpack(t, cmdFunc)
+				})
+			})
+
 			when("there are no builds", func() {
 				it("prints an appropriate message", func() {
 					testhelpers.CommandTest{
@@ -111,5 +360,44 @@ func testBuildListCommand(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 		})
+
+		when("the watch flag is used", func() {
+			it("re-renders the table for each event without duplicating rows", func() {
+				var completedBuild *v1alpha1.Build
+				for _, obj := range testhelpers.MakeTestBuilds(image, defaultNamespace) {
+					if bld := obj.(*v1alpha1.Build); bld.Name == "build-three" {
+						completedBuild = bld
+					}
+				}
+				completedBuild.Status.Conditions = corev1alpha1.Conditions{
+					{Type: corev1alpha1.ConditionSucceeded, Status: corev1.ConditionTrue},
+				}
+
+				watcher := watch.NewRaceFreeFake()
+				watcher.Modify(completedBuild)
+				watcher.Stop()
+
+				testhelpers.CommandTest{
+					Objects: testhelpers.MakeTestBuilds(image, defaultNamespace),
+					Args:    []string{image, "--watch"},
+					ExpectedOutput: "\033[H\033[2J" + `BUILD    STATUS      IMAGE                   REASON
+1        SUCCESS     repo.com/image-1:tag    CONFIG
+2        FAILURE     repo.com/image-2:tag    COMMIT+
+3        BUILDING    repo.com/image-3:tag    TRIGGER
+
+` + "\033[H\033[2J" + `BUILD    STATUS     IMAGE                   REASON
+1        SUCCESS    repo.com/image-1:tag    CONFIG
+2        FAILURE    repo.com/image-2:tag    COMMIT+
+3        SUCCESS    repo.com/image-3:tag    TRIGGER
+
+`,
+				}.TestKpack(t, func(clientSet *fake.Clientset) *cobra.Command {
+					clientSet.PrependWatchReactor("builds", func(action clientgotesting.Action) (bool, watch.Interface, error) {
+						return true, watcher, nil
+					})
+					return cmdFunc(clientSet)
+				})
+			})
+		})
 	})
 }
@@ -5,23 +5,32 @@ package build
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"math"
 	"sort"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
-	"github.com/pivotal/kpack/pkg/logs"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/build"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 )
 
-func NewLogsCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+func NewLogsCommand(clientSetProvider k8s.ClientSetProvider, newBuildLogsTailer func(k8s.ClientSet) commands.BuildLogsTailer) *cobra.Command {
 	var (
 		namespace   string
 		buildNumber string
+		follow      bool
+		since       time.Duration
+		sinceTime   string
+		tail        int64
 	)
 
 	cmd := &cobra.Command{
@@ -30,17 +39,30 @@ func NewLogsCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 		Long: `Tails logs from the containers of a specific build of an image in the provided namespace.
 
 The build defaults to the latest build number.
-The namespace defaults to the kubernetes current-context namespace.`,
-		Example:      "kp build logs my-image\nkp build logs my-image -b 2 -n my-namespace",
-		Args:         commands.ExactArgsWithUsage(1),
-		SilenceUsage: true,
+The namespace defaults to the kubernetes current-context namespace.
+
+Use the "--follow" flag to keep streaming until the build completes and print its final status.
+The command exits with a non-zero status if the build fails.
+
+Use one of the "--since", "--since-time", or "--tail" flags to only show recent logs from each build step.`,
+		Example:           "kp build logs my-image\nkp build logs my-image -b 2 -n my-namespace\nkp build logs my-image --follow\nkp build logs my-image --since 2m\nkp build logs my-image --tail 100",
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ImageNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			logOpts, err := parseLogOptions(cmd, since, sinceTime, tail)
+			if err != nil {
+				return err
+			}
+
 			cs, err := clientSetProvider.GetClientSet(namespace)
 			if err != nil {
 				return err
 			}
 
-			buildList, err := cs.KpackClient.KpackV1alpha1().Builds(cs.Namespace).List(cmd.Context(), metav1.ListOptions{
+			ctx := cmd.Context()
+
+			buildList, err := cs.KpackClient.KpackV1alpha1().Builds(cs.Namespace).List(ctx, metav1.ListOptions{
 				LabelSelector: v1alpha1.ImageLabel + "=" + args[0],
 			})
 			if err != nil {
@@ -49,18 +71,132 @@ The namespace defaults to the kubernetes current-context namespace.`,
 
 			if len(buildList.Items) == 0 {
 				return errors.New("no builds found")
+			}
+
+			sort.Slice(buildList.Items, build.Sort(buildList.Items))
+			bld, err := findBuild(buildList, buildNumber)
+			if err != nil {
+				return err
+			}
+
+			number := bld.Labels[v1alpha1.BuildNumberLabel]
+			tailer := newBuildLogsTailer(cs)
+			if logOpts != nil {
+				err = tailer.TailWithOptions(ctx, cmd.OutOrStdout(), args[0], number, cs.Namespace, *logOpts)
 			} else {
-				sort.Slice(buildList.Items, build.Sort(buildList.Items))
-				bld, err := findBuild(buildList, buildNumber)
-				if err != nil {
-					return err
-				}
-				return logs.NewBuildLogsClient(cs.K8sClient).Tail(context.Background(), cmd.OutOrStdout(), args[0], bld.Labels[v1alpha1.BuildNumberLabel], cs.Namespace)
+				err = tailer.Tail(ctx, cmd.OutOrStdout(), args[0], number, cs.Namespace)
+			}
+			if err != nil {
+				return err
+			}
+
+			if !follow {
+				return nil
 			}
+
+			completed, err := waitForBuildCompletion(ctx, cs, bld.Name)
+			if err != nil {
+				return err
+			}
+
+			return printBuildResult(cmd.OutOrStdout(), completed)
 		},
 	}
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
 	cmd.Flags().StringVarP(&buildNumber, "build", "b", "", "build number")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "stream logs until the build completes and exit non-zero if it fails")
+	cmd.Flags().DurationVar(&since, "since", 0, "only show logs newer than a relative duration like 2m or 1h")
+	cmd.Flags().StringVar(&sinceTime, "since-time", "", "only show logs after a RFC3339 timestamp like 2021-06-01T10:00:00Z")
+	cmd.Flags().Int64Var(&tail, "tail", 0, "number of recent log lines to show from each build step")
 
 	return cmd
 }
+
+// parseLogOptions returns nil when none of the log option flags are used
+func parseLogOptions(cmd *cobra.Command, since time.Duration, sinceTime string, tail int64) (*build.LogOptions, error) {
+	var used []string
+	for _, name := range []string{"since", "since-time", "tail"} {
+		if cmd.Flags().Changed(name) {
+			used = append(used, name)
+		}
+	}
+
+	switch {
+	case len(used) == 0:
+		return nil, nil
+	case len(used) > 1:
+		return nil, errors.New("--since, --since-time, and --tail are mutually exclusive")
+	}
+
+	opts := &build.LogOptions{}
+	switch used[0] {
+	case "since":
+		if since <= 0 {
+			return nil, errors.New("--since must be a positive duration")
+		}
+		seconds := int64(math.Ceil(since.Seconds()))
+		opts.SinceSeconds = &seconds
+	case "since-time":
+		t, err := time.Parse(time.RFC3339, sinceTime)
+		if err != nil {
+			return nil, errors.Errorf("invalid --since-time %q, expected RFC3339 format", sinceTime)
+		}
+		opts.SinceTime = &metav1.Time{Time: t}
+	case "tail":
+		if tail < 0 {
+			return nil, errors.New("--tail must not be negative")
+		}
+		opts.TailLines = &tail
+	}
+
+	return opts, nil
+}
+
+func waitForBuildCompletion(ctx context.Context, cs k8s.ClientSet, name string) (*v1alpha1.Build, error) {
+	bld, err := cs.KpackClient.KpackV1alpha1().Builds(cs.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if !bld.IsRunning() {
+		return bld, nil
+	}
+
+	watcher, err := cs.KpackClient.KpackV1alpha1().Builds(cs.Namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+		ResourceVersion: bld.ResourceVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case e, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil, errors.Errorf("stopped watching build %q before it completed", name)
+			}
+
+			if b, ok := e.Object.(*v1alpha1.Build); ok && !b.IsRunning() {
+				return b, nil
+			}
+		}
+	}
+}
+
+func printBuildResult(writer io.Writer, bld *v1alpha1.Build) error {
+	number := bld.Labels[v1alpha1.BuildNumberLabel]
+
+	if getStatus(*bld) != "SUCCESS" {
+		if message := bld.Status.GetCondition(corev1alpha1.ConditionSucceeded).Message; message != "" {
+			return errors.Errorf("build %s failed: %s", number, message)
+		}
+		return errors.Errorf("build %s failed", number)
+	}
+
+	_, err := fmt.Fprintf(writer, "Build %s succeeded\n", number)
+	return err
+}
@@ -5,12 +5,19 @@ package build_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
 	"github.com/sclevine/spec"
 	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	buildpkg "github.com/vmware-tanzu/kpack-cli/pkg/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands/build"
+	cmdFakes "github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
 )
 
@@ -24,12 +31,120 @@ func testBuildLogsCommand(t *testing.T, when spec.G, it spec.S) {
 		defaultNamespace = "some-default-namespace"
 	)
 
+	fakeBuildLogsTailer := &cmdFakes.FakeBuildLogsTailer{}
+
 	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
 		clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
-		return build.NewLogsCommand(clientSetProvider)
+		return build.NewLogsCommand(clientSetProvider, func(k8s.ClientSet) commands.BuildLogsTailer {
+			return fakeBuildLogsTailer
+		})
 	}
 
+	it.Before(func() {
+		fakeBuildLogsTailer.Calls = nil
+	})
+
 	when("getting build logs", func() {
+		it("tails the logs of the latest build", func() {
+			testhelpers.CommandTest{
+				Objects: testhelpers.MakeTestBuilds(image, defaultNamespace),
+				Args:    []string{image},
+			}.TestKpack(t, cmdFunc)
+			require.Equal(t, []cmdFakes.TailCall{{Image: image, Build: "3", Namespace: defaultNamespace}}, fakeBuildLogsTailer.Calls)
+		})
+
+		it("tails the logs of the provided build number", func() {
+			testhelpers.CommandTest{
+				Objects: testhelpers.MakeTestBuilds(image, defaultNamespace),
+				Args:    []string{image, "-b", "2"},
+			}.TestKpack(t, cmdFunc)
+			require.Equal(t, []cmdFakes.TailCall{{Image: image, Build: "2", Namespace: defaultNamespace}}, fakeBuildLogsTailer.Calls)
+		})
+
+		when("the follow flag is used", func() {
+			it("prints the result of a successful build", func() {
+				testhelpers.CommandTest{
+					Objects:        testhelpers.MakeTestBuilds(image, defaultNamespace),
+					Args:           []string{image, "-b", "1", "--follow"},
+					ExpectedOutput: "Build 1 succeeded\n",
+				}.TestKpack(t, cmdFunc)
+				require.Equal(t, []cmdFakes.TailCall{{Image: image, Build: "1", Namespace: defaultNamespace}}, fakeBuildLogsTailer.Calls)
+			})
+
+			it("errors when the build failed", func() {
+				testhelpers.CommandTest{
+					Objects:        testhelpers.MakeTestBuilds(image, defaultNamespace),
+					Args:           []string{image, "-b", "2", "-f"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: build 2 failed\n",
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
+		when("log option flags are used", func() {
+			it("forwards --since as whole seconds", func() {
+				testhelpers.CommandTest{
+					Objects: testhelpers.MakeTestBuilds(image, defaultNamespace),
+					Args:    []string{image, "--since", "90500ms"},
+				}.TestKpack(t, cmdFunc)
+
+				sinceSeconds := int64(91)
+				require.Equal(t, []cmdFakes.TailCall{{
+					Image:     image,
+					Build:     "3",
+					Namespace: defaultNamespace,
+					Options:   buildpkg.LogOptions{SinceSeconds: &sinceSeconds},
+				}}, fakeBuildLogsTailer.Calls)
+			})
+
+			it("forwards --since-time", func() {
+				testhelpers.CommandTest{
+					Objects: testhelpers.MakeTestBuilds(image, defaultNamespace),
+					Args:    []string{image, "--since-time", "2021-06-01T10:00:00Z"},
+				}.TestKpack(t, cmdFunc)
+
+				sinceTime := metav1.NewTime(time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC))
+				require.Len(t, fakeBuildLogsTailer.Calls, 1)
+				require.Nil(t, fakeBuildLogsTailer.Calls[0].Options.SinceSeconds)
+				require.Nil(t, fakeBuildLogsTailer.Calls[0].Options.TailLines)
+				require.True(t, sinceTime.Equal(fakeBuildLogsTailer.Calls[0].Options.SinceTime))
+			})
+
+			it("forwards --tail", func() {
+				testhelpers.CommandTest{
+					Objects: testhelpers.MakeTestBuilds(image, defaultNamespace),
+					Args:    []string{image, "-b", "2", "--tail", "50"},
+				}.TestKpack(t, cmdFunc)
+
+				tailLines := int64(50)
+				require.Equal(t, []cmdFakes.TailCall{{
+					Image:     image,
+					Build:     "2",
+					Namespace: defaultNamespace,
+					Options:   buildpkg.LogOptions{TailLines: &tailLines},
+				}}, fakeBuildLogsTailer.Calls)
+			})
+
+			it("errors when more than one log option flag is used", func() {
+				testhelpers.CommandTest{
+					Objects:        testhelpers.MakeTestBuilds(image, defaultNamespace),
+					Args:           []string{image, "--since", "2m", "--tail", "50"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: --since, --since-time, and --tail are mutually exclusive\n",
+				}.TestKpack(t, cmdFunc)
+				require.Empty(t, fakeBuildLogsTailer.Calls)
+			})
+
+			it("errors when --since-time is not RFC3339", func() {
+				testhelpers.CommandTest{
+					Objects:        testhelpers.MakeTestBuilds(image, defaultNamespace),
+					Args:           []string{image, "--since-time", "yesterday"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: invalid --since-time \"yesterday\", expected RFC3339 format\n",
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
 		when("in the default namespace", func() {
 			when("the build does not exist", func() {
 				when("the build flag is provided", func() {
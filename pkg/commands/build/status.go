@@ -50,9 +50,10 @@ When using the --bom flag, only the built image's bill of materials will be prin
 Using the --bom flag will read metadata from the build's built image in the registry
 Therefore, you must have credentials to access the registry on your machine when using the --bom flag.
 --registry-ca-cert-path and --registry-verify-certs are only used when using the --bom flag.`,
-		Example:      "kp build status my-image\nkp build status my-image -b 2 -n my-namespace",
-		Args:         commands.ExactArgsWithUsage(1),
-		SilenceUsage: true,
+		Example:           "kp build status my-image\nkp build status my-image -b 2 -n my-namespace",
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ImageNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet(namespace)
 			if err != nil {
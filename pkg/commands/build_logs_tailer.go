@@ -0,0 +1,16 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"context"
+	"io"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+)
+
+type BuildLogsTailer interface {
+	Tail(ctx context.Context, writer io.Writer, image, build, namespace string) error
+	TailWithOptions(ctx context.Context, writer io.Writer, image, buildNumber, namespace string, opts build.LogOptions) error
+}
@@ -6,6 +6,7 @@ package builder
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 	"github.com/spf13/cobra"
@@ -15,12 +16,14 @@ import (
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/builder"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 )
 
 const (
-	defaultStack = "default"
-	defaultStore = "default"
+	defaultStack          = "default"
+	defaultStore          = "default"
+	defaultServiceAccount = "default"
 )
 
 func NewCreateCommand(clientSetProvider k8s.ClientSetProvider, newWaiter func(dynamic.Interface) commands.ResourceWaiter) *cobra.Command {
@@ -54,11 +57,13 @@ kp builder create my-builder --tag my-registry.com/my-builder-tag --buildpack my
 				return err
 			}
 
+			flags.stdin = cmd.InOrStdin()
+
 			name := args[0]
 			flags.namespace = cs.Namespace
 
 			ctx := cmd.Context()
-			return create(ctx, name, flags, ch, cs, newWaiter(cs.DynamicClient))
+			return create(ctx, name, flags, ch, cs, commands.WithWaitTimeout(newWaiter(cs.DynamicClient), ch.WaitTimeout()))
 		},
 	}
 
@@ -66,23 +71,38 @@ kp builder create my-builder --tag my-registry.com/my-builder-tag --buildpack my
 	cmd.Flags().StringVarP(&flags.namespace, "namespace", "n", "", "kubernetes namespace")
 	cmd.Flags().StringVarP(&flags.stack, "stack", "s", defaultStack, "stack resource to use")
 	cmd.Flags().StringVar(&flags.store, "store", defaultStore, "buildpack store to use")
-	cmd.Flags().StringVarP(&flags.order, "order", "o", "", "path to buildpack order yaml")
+	cmd.Flags().StringVar(&flags.serviceAccount, "service-account", defaultServiceAccount, "service account used by the builder")
+	cmd.Flags().StringVarP(&flags.order, "order", "o", "", "path to buildpack order yaml, or \"-\" to read from stdin")
 	cmd.Flags().StringSliceVarP(&flags.buildpacks, "buildpack", "b", []string{}, "buildpack id and optional version in the form of either '<buildpack>@<version>' or '<buildpack>'\n  repeat for each buildpack in order, or supply once with comma-separated list")
+	commands.SetMetadataFlags(cmd, &flags.metadata)
 	commands.SetDryRunOutputFlags(cmd)
+	commands.SetReadyTimeoutFlag(cmd)
+	commands.SetServerDryRunFlag(cmd)
+	commands.SetConfigDefault(cmd, "stack", config.DefaultStackKey)
+	commands.SetConfigDefault(cmd, "store", config.DefaultStoreKey)
+	commands.SetConfigDefault(cmd, "service-account", config.DefaultServiceAccountKey)
 	_ = cmd.MarkFlagRequired("tag")
 	return cmd
 }
 
 type CommandFlags struct {
-	tag        string
-	namespace  string
-	stack      string
-	store      string
-	order      string
-	buildpacks []string
+	tag            string
+	namespace      string
+	stack          string
+	store          string
+	serviceAccount string
+	order          string
+	buildpacks     []string
+	clearOrder     bool
+	stdin          io.Reader
+	metadata       k8s.Metadata
 }
 
 func create(ctx context.Context, name string, flags CommandFlags, ch *commands.CommandHelper, cs k8s.ClientSet, w commands.ResourceWaiter) (err error) {
+	if flags.serviceAccount == "" {
+		flags.serviceAccount = defaultServiceAccount
+	}
+
 	bldr := &v1alpha1.Builder{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       v1alpha1.BuilderKind,
@@ -105,7 +125,7 @@ func create(ctx context.Context, name string, flags CommandFlags, ch *commands.C
 					Kind: v1alpha1.ClusterStoreKind,
 				},
 			},
-			ServiceAccount: "default",
+			ServiceAccount: flags.serviceAccount,
 		},
 	}
 
@@ -118,24 +138,30 @@ func create(ctx context.Context, name string, flags CommandFlags, ch *commands.C
 	}
 
 	if flags.order != "" {
-		bldr.Spec.Order, err = builder.ReadOrder(flags.order)
+		bldr.Spec.Order, err = builder.ReadOrder(flags.order, flags.stdin)
 		if err != nil {
 			return err
 		}
 	}
 
+	if err = flags.metadata.Apply(bldr); err != nil {
+		return err
+	}
+
 	err = k8s.SetLastAppliedCfg(bldr)
 	if err != nil {
 		return err
 	}
 
-	if !ch.IsDryRun() {
-		bldr, err = cs.KpackClient.KpackV1alpha1().Builders(cs.Namespace).Create(ctx, bldr, metav1.CreateOptions{})
+	if ch.ShouldSubmit() {
+		bldr, err = cs.KpackClient.KpackV1alpha1().Builders(cs.Namespace).Create(ctx, bldr, ch.CreateOptions())
 		if err != nil {
 			return err
 		}
-		if err := w.Wait(ctx, bldr); err != nil {
-			return err
+		if !ch.IsDryRun() {
+			if err := w.Wait(ctx, bldr); err != nil {
+				return err
+			}
 		}
 	}
 
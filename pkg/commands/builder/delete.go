@@ -24,8 +24,9 @@ func NewDeleteCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 		Long: `Delete a builder in the provided namespace.
 
 The namespace defaults to the kubernetes current-context namespace.`,
-		Example: "kp builder delete my-builder\nkp builder delete -n my-namespace other-builder",
-		Args:    commands.ExactArgsWithUsage(1),
+		Example:           "kp builder delete my-builder\nkp builder delete -n my-namespace other-builder",
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.BuilderNameCompletion(clientSetProvider),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet(namespace)
 			if err != nil {
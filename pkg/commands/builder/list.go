@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
@@ -18,7 +19,10 @@ import (
 
 func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	var (
-		namespace string
+		namespace     string
+		allNamespaces bool
+		labelSelector string
+		sortBy        string
 	)
 
 	cmd := &cobra.Command{
@@ -26,47 +30,114 @@ func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 		Short: "List available builders",
 		Long: `Prints a table of the most important information about the available builders in the provided namespace.
 
-The namespace defaults to the kubernetes current-context namespace.`,
-		Example:      "kp builder list\nkp builder list -n my-namespace",
+The namespace defaults to the kubernetes current-context namespace.
+Use the --all-namespaces flag to list builders in all namespaces.
+With --output, the builders of all namespaces are printed as a single "List" resource.
+Use the --sort-by flag to sort the builders by name or by ready status.`,
+		Example:      "kp builder list\nkp builder list -n my-namespace\nkp builder list -A\nkp builder list -l team=my-team\nkp builder list --sort-by ready\nkp builder list -o custom-columns=NAME:.metadata.name,IMAGE:.status.latestImage",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := commands.ValidateAllNamespacesFlag(cmd); err != nil {
+				return err
+			}
+
+			if err := commands.ValidateLabelSelector(labelSelector); err != nil {
+				return err
+			}
+
+			if err := commands.ValidateSortBy(sortBy, builderSortKeys...); err != nil {
+				return err
+			}
+
 			cs, err := clientSetProvider.GetClientSet(namespace)
 			if err != nil {
 				return err
 			}
 
-			builderList, err := cs.KpackClient.KpackV1alpha1().Builders(cs.Namespace).List(cmd.Context(), metav1.ListOptions{})
+			ch, err := commands.NewCommandHelper(cmd)
 			if err != nil {
 				return err
 			}
 
+			buildersNamespace := cs.Namespace
+			if allNamespaces {
+				buildersNamespace = metav1.NamespaceAll
+			}
+
+			builderList, err := cs.KpackClient.KpackV1alpha1().Builders(buildersNamespace).List(cmd.Context(), metav1.ListOptions{
+				LabelSelector: labelSelector,
+			})
+			if err != nil {
+				return commands.AllNamespacesListError(err, "builders", allNamespaces)
+			}
+
+			sort.Slice(builderList.Items, Sort(builderList.Items))
+			if sortBy == "ready" {
+				sort.SliceStable(builderList.Items, func(i, j int) bool {
+					return getStatus(builderList.Items[i]) < getStatus(builderList.Items[j])
+				})
+			}
+
+			if ch.IsOutput() && allNamespaces {
+				var objs []runtime.Object
+				for i := range builderList.Items {
+					objs = append(objs, &builderList.Items[i])
+				}
+				return ch.PrintList(objs)
+			} else if ch.IsOutput() {
+				return ch.PrintObj(builderList)
+			}
+
 			if len(builderList.Items) == 0 {
 				return errors.New("no builders found")
 			} else {
-				sort.Slice(builderList.Items, Sort(builderList.Items))
-				return displayClusterBuildersTable(cmd, builderList)
+				return displayBuildersTable(cmd, builderList, allNamespaces)
 			}
 		},
 	}
 
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	commands.SetAllNamespacesFlag(cmd, &allNamespaces)
+	commands.SetLabelSelectorFlag(cmd, &labelSelector)
+	commands.SetSortByFlag(cmd, &sortBy, builderSortKeys...)
+	commands.SetListOutputFlag(cmd)
+	commands.SetNoColorFlag(cmd)
 
 	return cmd
 }
 
-func displayClusterBuildersTable(cmd *cobra.Command, builderList *v1alpha1.BuilderList) error {
-	writer, err := commands.NewTableWriter(cmd.OutOrStdout(), "Name", "Ready", "Stack", "Image")
+func displayBuildersTable(cmd *cobra.Command, builderList *v1alpha1.BuilderList, allNamespaces bool) error {
+	headers := []string{"Name", "Ready", "Stack", "Image"}
+	if allNamespaces {
+		headers = append([]string{"Namespace"}, headers...)
+	}
+
+	writer, err := commands.NewTableWriter(cmd.OutOrStdout(), headers...)
+	if err != nil {
+		return err
+	}
+
+	color, err := commands.ColorEnabled(cmd)
 	if err != nil {
 		return err
 	}
 
+	if color {
+		writer.ColorizeStatusColumn("Ready")
+	}
+
 	for _, bldr := range builderList.Items {
-		err := writer.AddRow(
+		row := []string{
 			bldr.ObjectMeta.Name,
 			getStatus(bldr),
 			bldr.Status.Stack.ID,
 			bldr.Status.LatestImage,
-		)
+		}
+		if allNamespaces {
+			row = append([]string{bldr.Namespace}, row...)
+		}
+
+		err := writer.AddRow(row...)
 
 		if err != nil {
 			return err
@@ -76,8 +147,13 @@ func displayClusterBuildersTable(cmd *cobra.Command, builderList *v1alpha1.Build
 	return writer.Write()
 }
 
+var builderSortKeys = []string{"name", "ready"}
+
 func Sort(builds []v1alpha1.Builder) func(i int, j int) bool {
 	return func(i, j int) bool {
+		if builds[i].Namespace != builds[j].Namespace {
+			return builds[i].Namespace < builds[j].Namespace
+		}
 		return builds[j].ObjectMeta.Name > builds[i].ObjectMeta.Name
 	}
 }
@@ -4,6 +4,7 @@
 package builder_test
 
 import (
+	"io"
 	"testing"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
@@ -15,6 +16,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands/builder"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
 )
@@ -377,6 +379,8 @@ test-builder-3    true     io.buildpacks.stacks.bionic    some-registry.com/test
 		}
 	)
 
+	isTerminal := commands.IsTerminal
+
 	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
 		clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
 		return builder.NewListCommand(clientSetProvider)
@@ -396,6 +400,25 @@ test-builder-3    true     io.buildpacks.stacks.bionic    some-registry.com/test
 						ExpectedOutput: expectedOutput,
 					}.TestKpack(t, cmdFunc)
 				})
+
+				it("colors the ready status when the output is a terminal", func() {
+					commands.IsTerminal = func(io.Writer) bool { return true }
+					defer func() { commands.IsTerminal = isTerminal }()
+
+					testhelpers.CommandTest{
+						Objects: []runtime.Object{
+							otherNamespacedBuilder1,
+							otherNamespacedBuilder2,
+							otherNamespacedBuilder3,
+						},
+						Args: []string{"-n", "test-namespace"},
+						ExpectedOutput: "NAME              \x1b[39mREADY\x1b[0m    STACK                          IMAGE\n" +
+							"test-builder-1    \x1b[32mtrue\x1b[0m     io.buildpacks.stacks.centos    some-registry.com/test-builder-1:tag\n" +
+							"test-builder-2    \x1b[31mfalse\x1b[0m                                   \n" +
+							"test-builder-3    \x1b[32mtrue\x1b[0m     io.buildpacks.stacks.bionic    some-registry.com/test-builder-3:tag\n" +
+							"\n",
+					}.TestKpack(t, cmdFunc)
+				})
 			})
 
 			when("there are no builders in the namespace", func() {
@@ -408,6 +431,57 @@ test-builder-3    true     io.buildpacks.stacks.bionic    some-registry.com/test
 			})
 		})
 
+		when("all namespaces is specified", func() {
+			it("lists the builders in all namespaces ordered by namespace", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						otherNamespacedBuilder1,
+						defaultNamespacedBuilder2,
+						otherNamespacedBuilder3,
+						defaultNamespacedBuilder1,
+						defaultNamespacedBuilder3,
+						otherNamespacedBuilder2,
+					},
+					Args: []string{"-A"},
+					ExpectedOutput: `NAMESPACE                 NAME              READY    STACK                          IMAGE
+some-default-namespace    test-builder-1    true     io.buildpacks.stacks.centos    some-registry.com/test-builder-1:tag
+some-default-namespace    test-builder-2    false                                   
+some-default-namespace    test-builder-3    true     io.buildpacks.stacks.bionic    some-registry.com/test-builder-3:tag
+test-namespace            test-builder-1    true     io.buildpacks.stacks.centos    some-registry.com/test-builder-1:tag
+test-namespace            test-builder-2    false                                   
+test-namespace            test-builder-3    true     io.buildpacks.stacks.bionic    some-registry.com/test-builder-3:tag
+
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("outputs the builders of all namespaces as a single list", func() {
+				untypedBuilder := &v1alpha1.Builder{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-builder-1",
+						Namespace: "test-namespace",
+					},
+				}
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						untypedBuilder,
+						defaultNamespacedBuilder1,
+					},
+					Args:           []string{"-A", "-o", "jsonpath={.apiVersion}/{.kind}{range .items[*]} {.kind}:{.metadata.namespace}/{.metadata.name}{end}"},
+					ExpectedOutput: "v1/List Builder:some-default-namespace/test-builder-1 Builder:test-namespace/test-builder-1\n",
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("returns an error when a namespace is also provided", func() {
+				testhelpers.CommandTest{
+					Args:           []string{"-A", "-n", "test-namespace"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: --namespace and --all-namespaces are mutually exclusive\n",
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
 		when("namespace is not provided", func() {
 			when("there are builders in the default namespace", func() {
 				it("lists the builders", func() {
@@ -30,14 +30,18 @@ func NewPatchCommand(clientSetProvider k8s.ClientSetProvider, newWaiter func(dyn
 		Long: `Patch an existing builder configuration by providing command line arguments.
 
 A buildpack order must be provided with either the path to an order yaml or via the --buildpack flag.
-Multiple buildpacks provided via the --buildpack flag will be added to the same order group. 
+Multiple buildpacks provided via the --buildpack flag will be added to the same order group.
+Use the --clear-order flag to remove the existing buildpack order.
 
 The namespace defaults to the kubernetes current-context namespace.`,
 		Example: `kp builder patch my-builder --order /path/to/order.yaml --stack tiny --store my-store
 kp builder patch my-builder --order /path/to/order.yaml
-kp builder patch my-builder --buildpack my-buildpack-id --buildpack my-other-buildpack@1.0.1`,
-		Args:         commands.ExactArgsWithUsage(1),
-		SilenceUsage: true,
+cat /path/to/order.yaml | kp builder patch my-builder --order -
+kp builder patch my-builder --buildpack my-buildpack-id --buildpack my-other-buildpack@1.0.1
+kp builder patch my-builder --clear-order`,
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.BuilderNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet(flags.namespace)
 			if err != nil {
@@ -49,17 +53,21 @@ kp builder patch my-builder --buildpack my-buildpack-id --buildpack my-other-bui
 				return err
 			}
 
+			flags.stdin = commands.NewReplayReader(cmd.InOrStdin())
+
 			name := args[0]
 			flags.namespace = cs.Namespace
 
 			ctx := cmd.Context()
 
-			cb, err := cs.KpackClient.KpackV1alpha1().Builders(cs.Namespace).Get(ctx, name, metav1.GetOptions{})
-			if err != nil {
-				return err
-			}
+			return commands.RetryOnConflict(func() error {
+				cb, err := cs.KpackClient.KpackV1alpha1().Builders(cs.Namespace).Get(ctx, name, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
 
-			return patch(ctx, cb, flags, ch, cs, newWaiter(cs.DynamicClient))
+				return patch(ctx, cb, flags, ch, cs, commands.WithWaitTimeout(newWaiter(cs.DynamicClient), ch.WaitTimeout()))
+			})
 		},
 	}
 
@@ -67,9 +75,13 @@ kp builder patch my-builder --buildpack my-buildpack-id --buildpack my-other-bui
 	cmd.Flags().StringVarP(&flags.namespace, "namespace", "n", "", "kubernetes namespace")
 	cmd.Flags().StringVarP(&flags.stack, "stack", "s", "", "stack resource to use")
 	cmd.Flags().StringVar(&flags.store, "store", "", "buildpack store to use")
-	cmd.Flags().StringVarP(&flags.order, "order", "o", "", "path to buildpack order yaml")
+	cmd.Flags().StringVarP(&flags.order, "order", "o", "", "path to buildpack order yaml, or \"-\" to read from stdin")
 	cmd.Flags().StringSliceVarP(&flags.buildpacks, "buildpack", "b", []string{}, "buildpack id and optional version in the form of either '<buildpack>@<version>' or '<buildpack>'\n  repeat for each buildpack in order, or supply once with comma-separated list")
+	cmd.Flags().BoolVar(&flags.clearOrder, "clear-order", false, "remove the existing buildpack order")
+	commands.SetMetadataPatchFlags(cmd, &flags.metadata)
 	commands.SetDryRunOutputFlags(cmd)
+	commands.SetReadyTimeoutFlag(cmd)
+	commands.SetServerDryRunFlag(cmd)
 	return cmd
 }
 
@@ -92,8 +104,16 @@ func patch(ctx context.Context, bldr *v1alpha1.Builder, flags CommandFlags, ch *
 		return fmt.Errorf("cannot use --order and --buildpack together")
 	}
 
+	if flags.clearOrder && (len(flags.buildpacks) > 0 || flags.order != "") {
+		return fmt.Errorf("cannot use --clear-order with --order or --buildpack")
+	}
+
+	if flags.clearOrder {
+		patchedBldr.Spec.Order = nil
+	}
+
 	if flags.order != "" {
-		orderEntries, err := builder.ReadOrder(flags.order)
+		orderEntries, err := builder.ReadOrder(flags.order, flags.stdin)
 		if err != nil {
 			return err
 		}
@@ -105,19 +125,30 @@ func patch(ctx context.Context, bldr *v1alpha1.Builder, flags CommandFlags, ch *
 		patchedBldr.Spec.Order = builder.CreateOrder(flags.buildpacks)
 	}
 
+	if err := flags.metadata.Apply(patchedBldr); err != nil {
+		return err
+	}
+
 	patch, err := k8s.CreatePatch(bldr, patchedBldr)
 	if err != nil {
 		return err
 	}
 
+	patch, err = k8s.AddResourceVersion(patch, bldr)
+	if err != nil {
+		return err
+	}
+
 	hasPatch := len(patch) > 0
-	if hasPatch && !ch.IsDryRun() {
-		patchedBldr, err = cs.KpackClient.KpackV1alpha1().Builders(cs.Namespace).Patch(ctx, patchedBldr.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if hasPatch && ch.ShouldSubmit() {
+		patchedBldr, err = cs.KpackClient.KpackV1alpha1().Builders(cs.Namespace).Patch(ctx, patchedBldr.Name, types.MergePatchType, patch, ch.PatchOptions())
 		if err != nil {
 			return err
 		}
-		if err := w.Wait(ctx, patchedBldr); err != nil {
-			return err
+		if !ch.IsDryRun() {
+			if err := w.Wait(ctx, patchedBldr); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -143,6 +143,48 @@ func testBuilderPatchCommand(t *testing.T, when spec.G, it spec.S) {
 		}.TestKpack(t, cmdFunc)
 	})
 
+	it("patches the labels and annotations of a Builder", func() {
+		bldr.Labels = map[string]string{"cost-center": "123"}
+
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				bldr,
+			},
+			Args: []string{
+				bldr.Name,
+				"-n", bldr.Namespace,
+				"--label", "team=some-team",
+				"--annotation", "description=some description",
+				"--delete-label", "cost-center",
+			},
+			ExpectedOutput: `Builder "test-builder" patched
+`,
+			ExpectPatches: []string{
+				`{"metadata":{"annotations":{"description":"some description"},"labels":{"cost-center":null,"team":"some-team"}}}`,
+			},
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("removes a label provided in the form key-", func() {
+		bldr.Labels = map[string]string{"cost-center": "123", "team": "some-team"}
+
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				bldr,
+			},
+			Args: []string{
+				bldr.Name,
+				"-n", bldr.Namespace,
+				"--label", "cost-center-",
+			},
+			ExpectedOutput: `Builder "test-builder" patched
+`,
+			ExpectPatches: []string{
+				`{"metadata":{"labels":{"cost-center":null}}}`,
+			},
+		}.TestKpack(t, cmdFunc)
+	})
+
 	it("patches a Builder with buildpack flags", func() {
 		bldr.Namespace = defaultNamespace
 
@@ -166,6 +208,102 @@ func testBuilderPatchCommand(t *testing.T, when spec.G, it spec.S) {
 		}.TestKpack(t, cmdFunc)
 	})
 
+	when("only some flags are provided", func() {
+		for _, tc := range []struct {
+			name          string
+			args          []string
+			expectedPatch string
+		}{
+			{
+				name:          "patches only the stack",
+				args:          []string{"--stack", "some-other-stack"},
+				expectedPatch: `{"spec":{"stack":{"name":"some-other-stack"}}}`,
+			},
+			{
+				name:          "patches only the store",
+				args:          []string{"--store", "some-other-store"},
+				expectedPatch: `{"spec":{"store":{"name":"some-other-store"}}}`,
+			},
+			{
+				name:          "patches only the order",
+				args:          []string{"--order", "./testdata/patched-order.yaml"},
+				expectedPatch: `{"spec":{"order":[{"group":[{"id":"org.cloudfoundry.test-bp"}]},{"group":[{"id":"org.cloudfoundry.fake-bp"}]}]}}`,
+			},
+			{
+				name:          "clears the order",
+				args:          []string{"--clear-order"},
+				expectedPatch: `{"spec":{"order":null}}`,
+			},
+		} {
+			tc := tc
+			it(tc.name, func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						bldr,
+					},
+					Args: append([]string{bldr.Name, "-n", bldr.Namespace}, tc.args...),
+					ExpectedOutput: `Builder "test-builder" patched
+`,
+					ExpectPatches: []string{tc.expectedPatch},
+				}.TestKpack(t, cmdFunc)
+				require.Len(t, fakeWaiter.WaitCalls, 1)
+			})
+		}
+	})
+
+	when("the flags match the existing Builder", func() {
+		for _, tc := range []struct {
+			name string
+			args []string
+		}{
+			{
+				name: "does not patch the same stack",
+				args: []string{"--stack", "some-stack"},
+			},
+			{
+				name: "does not patch the same store",
+				args: []string{"--store", "some-store"},
+			},
+			{
+				name: "does not patch the same tag",
+				args: []string{"--tag", "some-registry.com/test-builder"},
+			},
+			{
+				name: "does not patch the same order",
+				args: []string{"--order", "./testdata/order.yaml"},
+			},
+		} {
+			tc := tc
+			it(tc.name, func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						bldr,
+					},
+					Args: append([]string{bldr.Name, "-n", bldr.Namespace}, tc.args...),
+					ExpectedOutput: `Builder "test-builder" patched (no change)
+`,
+				}.TestKpack(t, cmdFunc)
+				require.Len(t, fakeWaiter.WaitCalls, 0)
+			})
+		}
+	})
+
+	it("returns error when clear-order and order flags are used together", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				bldr,
+			},
+			Args: []string{
+				bldr.Name,
+				"-n", bldr.Namespace,
+				"--clear-order",
+				"--order", "./testdata/patched-order.yaml",
+			},
+			ExpectErr:      true,
+			ExpectedOutput: "Error: cannot use --clear-order with --order or --buildpack\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
 	it("returns error when buildpack and order flags are used together", func() {
 		bldr.Namespace = defaultNamespace
 
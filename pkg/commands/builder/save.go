@@ -11,6 +11,7 @@ import (
 	"k8s.io/client-go/dynamic"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 )
 
@@ -45,36 +46,44 @@ kp builder save my-builder --tag my-registry.com/my-builder-tag --buildpack my-b
 			}
 
 			ctx := cmd.Context()
-			w := newWaiter(cs.DynamicClient)
 
 			ch, err := commands.NewCommandHelper(cmd)
 			if err != nil {
 				return err
 			}
+			w := commands.WithWaitTimeout(newWaiter(cs.DynamicClient), ch.WaitTimeout())
+
+			flags.stdin = commands.NewReplayReader(cmd.InOrStdin())
 
 			name := args[0]
 			flags.namespace = cs.Namespace
 
-			bldr, err := cs.KpackClient.KpackV1alpha1().Builders(cs.Namespace).Get(ctx, name, metav1.GetOptions{})
-			if k8serrors.IsNotFound(err) {
-				if flags.tag == "" {
-					return errors.New("--tag is required to create the resource")
-				}
+			return commands.RetryOnConflict(func() error {
+				bldr, err := cs.KpackClient.KpackV1alpha1().Builders(cs.Namespace).Get(ctx, name, metav1.GetOptions{})
+				if k8serrors.IsNotFound(err) {
+					if flags.tag == "" {
+						return errors.New("--tag is required to create the resource")
+					}
 
-				if flags.stack == "" {
-					flags.stack = defaultStack
-				}
+					if flags.stack == "" {
+						flags.stack = commands.CreateConfigDefault(cmd, "stack", defaultStack)
+					}
 
-				if flags.store == "" {
-					flags.store = defaultStore
-				}
+					if flags.store == "" {
+						flags.store = commands.CreateConfigDefault(cmd, "store", defaultStore)
+					}
 
-				return create(ctx, name, flags, ch, cs, w)
-			} else if err != nil {
-				return err
-			}
+					if flags.serviceAccount == "" {
+						flags.serviceAccount = commands.CreateConfigDefault(cmd, "service-account", defaultServiceAccount)
+					}
+
+					return create(ctx, name, flags, ch, cs, w)
+				} else if err != nil {
+					return err
+				}
 
-			return patch(ctx, bldr, flags, ch, cs, w)
+				return patch(ctx, bldr, flags, ch, cs, w)
+			})
 		},
 	}
 
@@ -82,8 +91,14 @@ kp builder save my-builder --tag my-registry.com/my-builder-tag --buildpack my-b
 	cmd.Flags().StringVarP(&flags.namespace, "namespace", "n", "", "kubernetes namespace")
 	cmd.Flags().StringVarP(&flags.stack, "stack", "s", "", "stack resource to use (default \"default\" for a create)")
 	cmd.Flags().StringVar(&flags.store, "store", "", "buildpack store to use (default \"default\" for a create)")
-	cmd.Flags().StringVarP(&flags.order, "order", "o", "", "path to buildpack order yaml")
+	cmd.Flags().StringVarP(&flags.order, "order", "o", "", "path to buildpack order yaml, or \"-\" to read from stdin")
 	cmd.Flags().StringSliceVarP(&flags.buildpacks, "buildpack", "b", []string{}, "buildpack id and optional version in the form of either '<buildpack>@<version>' or '<buildpack>'\n  repeat for each buildpack in order, or supply once with comma-separated list")
+	commands.SetMetadataPatchFlags(cmd, &flags.metadata)
 	commands.SetDryRunOutputFlags(cmd)
+	commands.SetReadyTimeoutFlag(cmd)
+	commands.SetServerDryRunFlag(cmd)
+	commands.SetCreateConfigDefault(cmd, "stack", config.DefaultStackKey)
+	commands.SetCreateConfigDefault(cmd, "store", config.DefaultStoreKey)
+	commands.SetCreateConfigDefault(cmd, "service-account", config.DefaultServiceAccountKey)
 	return cmd
 }
@@ -29,9 +29,10 @@ func NewStatusCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 		Long: `Prints detailed information about the status of a specific builder in the provided namespace.
 
 The namespace defaults to the kubernetes current-context namespace.`,
-		Example:      "kp builder status my-builder\nkp builder status -n my-namespace other-builder",
-		Args:         commands.ExactArgsWithUsage(1),
-		SilenceUsage: true,
+		Example:           "kp builder status my-builder\nkp builder status -n my-namespace other-builder",
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.BuilderNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet(namespace)
 			if err != nil {
@@ -0,0 +1,141 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+)
+
+const (
+	configDefaultAnnotationPrefix       = "kp-config-default/"
+	createConfigDefaultAnnotationPrefix = "kp-config-create-default/"
+	createConfigValueAnnotationPrefix   = "kp-config-create-value/"
+
+	// configDefaultFlagAnnotation marks a flag that was set from the config, the flag is not marked as
+	// changed so that a command can still tell whether it was provided on the command line
+	configDefaultFlagAnnotation = "kp-config-default"
+)
+
+// globalConfigDefaults only holds the namespace, the output format is applied to the list and status
+// commands with SetConfigDefault so that it does not change the output of the commands that write resources
+var globalConfigDefaults = map[string]string{
+	"namespace": config.DefaultNamespaceKey,
+}
+
+func SetConfigDefault(cmd *cobra.Command, flagName, key string) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[configDefaultAnnotationPrefix+flagName] = key
+}
+
+// SetCreateConfigDefault registers a config default that save commands only use when they create
+// the resource, it is read with CreateConfigDefault and no default is assumed for a patch
+func SetCreateConfigDefault(cmd *cobra.Command, name, key string) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[createConfigDefaultAnnotationPrefix+name] = key
+}
+
+// CreateConfigDefault returns the config value registered with SetCreateConfigDefault or fallback
+// when the value is not configured
+func CreateConfigDefault(cmd *cobra.Command, name, fallback string) string {
+	if value := cmd.Annotations[createConfigValueAnnotationPrefix+name]; value != "" {
+		return value
+	}
+	return fallback
+}
+
+// flagIsSet returns whether the flag was provided on the command line or set from the config
+func flagIsSet(cmd *cobra.Command, name string) bool {
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil {
+		return false
+	}
+	_, fromConfig := flag.Annotations[configDefaultFlagAnnotation]
+	return flag.Changed || fromConfig
+}
+
+func ApplyCLIConfigDefaults(cmd *cobra.Command, cfg config.CLIConfig) error {
+	if err := applyCreateConfigDefaults(cmd, cfg); err != nil {
+		return err
+	}
+
+	defaults := map[string]string{}
+	for flagName, key := range globalConfigDefaults {
+		defaults[flagName] = key
+	}
+	for annotation, key := range cmd.Annotations {
+		if strings.HasPrefix(annotation, configDefaultAnnotationPrefix) {
+			defaults[strings.TrimPrefix(annotation, configDefaultAnnotationPrefix)] = key
+		}
+	}
+
+	allNamespaces, err := GetBoolFlag(AllNamespacesFlag, cmd)
+	if err != nil {
+		return err
+	}
+
+	var flagNames []string
+	for flagName := range defaults {
+		flagNames = append(flagNames, flagName)
+	}
+	sort.Strings(flagNames)
+
+	for _, flagName := range flagNames {
+		flag := cmd.Flags().Lookup(flagName)
+		if flag == nil || flag.Changed {
+			continue
+		}
+
+		if flagName == "namespace" && allNamespaces {
+			continue
+		}
+
+		value, err := cfg.Get(defaults[flagName])
+		if err != nil {
+			return err
+		}
+
+		if value == "" {
+			continue
+		}
+
+		if flagName == OutputFlag && value == OutputWide && !supportsWideOutput(cmd) {
+			continue
+		}
+
+		if err := flag.Value.Set(value); err != nil {
+			return err
+		}
+		if err := cmd.Flags().SetAnnotation(flagName, configDefaultFlagAnnotation, []string{defaults[flagName]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyCreateConfigDefaults(cmd *cobra.Command, cfg config.CLIConfig) error {
+	for annotation, key := range cmd.Annotations {
+		if !strings.HasPrefix(annotation, createConfigDefaultAnnotationPrefix) {
+			continue
+		}
+
+		value, err := cfg.Get(key)
+		if err != nil {
+			return err
+		}
+
+		if value != "" {
+			cmd.Annotations[createConfigValueAnnotationPrefix+strings.TrimPrefix(annotation, createConfigDefaultAnnotationPrefix)] = value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+)
+
+func TestApplyCLIConfigDefaults(t *testing.T) {
+	spec.Run(t, "TestApplyCLIConfigDefaults", testApplyCLIConfigDefaults)
+}
+
+func testApplyCLIConfigDefaults(t *testing.T, when spec.G, it spec.S) {
+	cfg := config.CLIConfig{
+		DefaultNamespace: "config-namespace",
+		DefaultStack:     "config-stack",
+		OutputFormat:     "yaml",
+	}
+
+	var (
+		namespace string
+		stack     string
+		cmd       *cobra.Command
+	)
+
+	it.Before(func() {
+		namespace, stack = "", ""
+		cmd = &cobra.Command{}
+		cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+		cmd.Flags().StringVar(&stack, "stack", "default", "stack resource to use")
+		commands.SetDryRunOutputFlags(cmd)
+		commands.SetAllNamespacesFlag(cmd, new(bool))
+	})
+
+	it("applies config values to flags that were not provided", func() {
+		require.NoError(t, commands.ApplyCLIConfigDefaults(cmd, cfg))
+
+		require.Equal(t, "config-namespace", namespace)
+	})
+
+	it("does not apply the output format to commands that write resources", func() {
+		require.NoError(t, commands.ApplyCLIConfigDefaults(cmd, cfg))
+
+		output, err := commands.GetStringFlag(commands.OutputFlag, cmd)
+		require.NoError(t, err)
+		require.Equal(t, "", output)
+	})
+
+	when("the command lists resources", func() {
+		var listCmd *cobra.Command
+
+		it.Before(func() {
+			listCmd = &cobra.Command{}
+			commands.SetListOutputFlag(listCmd)
+		})
+
+		it("applies the output format", func() {
+			require.NoError(t, commands.ApplyCLIConfigDefaults(listCmd, cfg))
+
+			output, err := commands.GetStringFlag(commands.OutputFlag, listCmd)
+			require.NoError(t, err)
+			require.Equal(t, "yaml", output)
+		})
+
+		it("only applies the wide output format to commands that support it", func() {
+			cfg.OutputFormat = commands.OutputWide
+
+			require.NoError(t, commands.ApplyCLIConfigDefaults(listCmd, cfg))
+			output, err := commands.GetStringFlag(commands.OutputFlag, listCmd)
+			require.NoError(t, err)
+			require.Equal(t, "", output)
+
+			wideListCmd := &cobra.Command{}
+			commands.SetWideListOutputFlag(wideListCmd)
+
+			require.NoError(t, commands.ApplyCLIConfigDefaults(wideListCmd, cfg))
+			output, err = commands.GetStringFlag(commands.OutputFlag, wideListCmd)
+			require.NoError(t, err)
+			require.Equal(t, commands.OutputWide, output)
+		})
+	})
+
+	it("prefers flags provided on the command line", func() {
+		require.NoError(t, cmd.ParseFlags([]string{"-n", "flag-namespace", "--output", "json"}))
+		require.NoError(t, commands.ApplyCLIConfigDefaults(cmd, cfg))
+
+		require.Equal(t, "flag-namespace", namespace)
+		output, err := commands.GetStringFlag(commands.OutputFlag, cmd)
+		require.NoError(t, err)
+		require.Equal(t, "json", output)
+	})
+
+	it("only applies command specific defaults to flags that opt in", func() {
+		require.NoError(t, commands.ApplyCLIConfigDefaults(cmd, cfg))
+		require.Equal(t, "default", stack)
+
+		commands.SetConfigDefault(cmd, "stack", config.DefaultStackKey)
+		require.NoError(t, commands.ApplyCLIConfigDefaults(cmd, cfg))
+		require.Equal(t, "config-stack", stack)
+	})
+
+	it("does not mark the flags set from the config as changed", func() {
+		commands.SetConfigDefault(cmd, "stack", config.DefaultStackKey)
+		require.NoError(t, commands.ApplyCLIConfigDefaults(cmd, cfg))
+
+		require.Equal(t, "config-stack", stack)
+		require.False(t, cmd.Flags().Changed("stack"))
+		require.False(t, cmd.Flags().Changed("namespace"))
+	})
+
+	when("a default is only used to create resources", func() {
+		it("returns the config value without setting the flag", func() {
+			commands.SetCreateConfigDefault(cmd, "stack", config.DefaultStackKey)
+			require.NoError(t, commands.ApplyCLIConfigDefaults(cmd, cfg))
+
+			require.Equal(t, "default", stack)
+			require.Equal(t, "config-stack", commands.CreateConfigDefault(cmd, "stack", "fallback-stack"))
+		})
+
+		it("returns the fallback when the value is not configured", func() {
+			commands.SetCreateConfigDefault(cmd, "store", config.DefaultStoreKey)
+			require.NoError(t, commands.ApplyCLIConfigDefaults(cmd, cfg))
+
+			require.Equal(t, "fallback-store", commands.CreateConfigDefault(cmd, "store", "fallback-store"))
+		})
+	})
+
+	it("does not apply the default namespace with --all-namespaces", func() {
+		require.NoError(t, cmd.ParseFlags([]string{"-A"}))
+		require.NoError(t, commands.ApplyCLIConfigDefaults(cmd, cfg))
+
+		require.Equal(t, "", namespace)
+		require.NoError(t, commands.ValidateAllNamespacesFlag(cmd))
+	})
+}
@@ -6,6 +6,7 @@ package clusterbuilder
 import (
 	"context"
 	"fmt"
+	"io"
 	"path"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/builder"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 )
 
@@ -60,19 +62,26 @@ kp cb create my-builder --tag my-registry.com/my-builder-tag --buildpack my-buil
 				return err
 			}
 
+			flags.stdin = cmd.InOrStdin()
+
 			name := args[0]
 			ctx := cmd.Context()
 
-			return create(ctx, name, flags, ch, cs, newWaiter(cs.DynamicClient))
+			return create(ctx, name, flags, ch, cs, commands.WithWaitTimeout(newWaiter(cs.DynamicClient), ch.WaitTimeout()))
 		},
 	}
 
 	cmd.Flags().StringVarP(&flags.tag, "tag", "t", "", "registry location where the builder will be created")
 	cmd.Flags().StringVarP(&flags.stack, "stack", "s", defaultStack, "stack resource to use")
 	cmd.Flags().StringVar(&flags.store, "store", defaultStore, "buildpack store to use")
-	cmd.Flags().StringVarP(&flags.order, "order", "o", "", "path to buildpack order yaml")
+	cmd.Flags().StringVarP(&flags.order, "order", "o", "", "path to buildpack order yaml, or \"-\" to read from stdin")
 	cmd.Flags().StringSliceVarP(&flags.buildpacks, "buildpack", "b", []string{}, "buildpack id and optional version in the form of either '<buildpack>@<version>' or '<buildpack>'\n  repeat for each buildpack in order, or supply once with comma-separated list")
+	commands.SetMetadataFlags(cmd, &flags.metadata)
 	commands.SetDryRunOutputFlags(cmd)
+	commands.SetReadyTimeoutFlag(cmd)
+	commands.SetServerDryRunFlag(cmd)
+	commands.SetConfigDefault(cmd, "stack", config.DefaultStackKey)
+	commands.SetConfigDefault(cmd, "store", config.DefaultStoreKey)
 	return cmd
 }
 
@@ -82,6 +91,9 @@ type CommandFlags struct {
 	store      string
 	order      string
 	buildpacks []string
+	clearOrder bool
+	stdin      io.Reader
+	metadata   k8s.Metadata
 }
 
 func create(ctx context.Context, name string, flags CommandFlags, ch *commands.CommandHelper, cs k8s.ClientSet, waiter commands.ResourceWaiter) error {
@@ -138,24 +150,30 @@ func create(ctx context.Context, name string, flags CommandFlags, ch *commands.C
 	}
 
 	if flags.order != "" {
-		cb.Spec.Order, err = builder.ReadOrder(flags.order)
+		cb.Spec.Order, err = builder.ReadOrder(flags.order, flags.stdin)
 		if err != nil {
 			return err
 		}
 	}
 
+	if err = flags.metadata.Apply(cb); err != nil {
+		return err
+	}
+
 	err = k8s.SetLastAppliedCfg(cb)
 	if err != nil {
 		return err
 	}
 
-	if !ch.IsDryRun() {
-		cb, err = cs.KpackClient.KpackV1alpha1().ClusterBuilders().Create(ctx, cb, metav1.CreateOptions{})
+	if ch.ShouldSubmit() {
+		cb, err = cs.KpackClient.KpackV1alpha1().ClusterBuilders().Create(ctx, cb, ch.CreateOptions())
 		if err != nil {
 			return err
 		}
-		if err := waiter.Wait(ctx, cb); err != nil {
-			return err
+		if !ch.IsDryRun() {
+			if err := waiter.Wait(ctx, cb); err != nil {
+				return err
+			}
 		}
 	}
 
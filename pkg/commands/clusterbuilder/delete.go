@@ -15,11 +15,12 @@ import (
 
 func NewDeleteCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "delete <name>",
-		Short:   "Delete a cluster builder",
-		Long:    "Delete a cluster builder from the cluster.",
-		Example: "kp cb delete my-builder",
-		Args:    commands.ExactArgsWithUsage(1),
+		Use:               "delete <name>",
+		Short:             "Delete a cluster builder",
+		Long:              "Delete a cluster builder from the cluster.",
+		Example:           "kp cb delete my-builder",
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ClusterBuilderNameCompletion(clientSetProvider),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet("")
 			if err != nil {
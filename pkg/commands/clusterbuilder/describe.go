@@ -0,0 +1,140 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterbuilder
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+func NewDescribeCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe <name>",
+		Short: "Describe a cluster builder",
+		Long: `Prints the configuration, buildpack order and status conditions of a specific cluster builder.
+
+Use "--output yaml" or "--output json" to print the cluster builder resource instead.`,
+		Example:           "kp cb describe my-builder\nkp cb describe my-builder -o yaml",
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ClusterBuilderNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cs, err := clientSetProvider.GetClientSet("")
+			if err != nil {
+				return err
+			}
+
+			ch, err := commands.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			bldr, err := cs.KpackClient.KpackV1alpha1().ClusterBuilders().Get(cmd.Context(), args[0], metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			if ch.IsOutput() {
+				return ch.PrintObj(bldr)
+			}
+
+			return describeBuilder(bldr, cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringP(commands.OutputFlag, "o", "", "print the cluster builder resource in the specified format instead of a description; supported formats are: yaml, json")
+
+	return cmd
+}
+
+func describeBuilder(bldr *v1alpha1.ClusterBuilder, writer io.Writer) error {
+	statusWriter := commands.NewStatusWriter(writer)
+
+	err := statusWriter.AddBlock(
+		"",
+		"Name", bldr.Name,
+		"Tag", bldr.Spec.Tag,
+		"Stack Ref", " ",
+		"  Name", bldr.Spec.Stack.Name,
+		"  Kind", bldr.Spec.Stack.Kind,
+		"Store Ref", " ",
+		"  Name", bldr.Spec.Store.Name,
+		"  Kind", bldr.Spec.Store.Kind,
+		"Service Account Ref", " ",
+		"  Name", bldr.Spec.ServiceAccountRef.Name,
+		"  Namespace", bldr.Spec.ServiceAccountRef.Namespace,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = statusWriter.AddBlock(
+		"Status",
+		"Ready", getReadyStatus(bldr),
+		"Latest Image", bldr.Status.LatestImage,
+		"Stack ID", bldr.Status.Stack.ID,
+		"Run Image", bldr.Status.Stack.RunImage,
+	)
+	if err != nil {
+		return err
+	}
+
+	orderTableWriter, err := newSectionTableWriter(writer, statusWriter, "Order", "Group", "Buildpack ID", "Version")
+	if err != nil {
+		return err
+	}
+
+	for i, entry := range bldr.Spec.Order {
+		for _, ref := range entry.Group {
+			if err := orderTableWriter.AddRow(fmt.Sprint(i+1), ref.Id, ref.Version); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := orderTableWriter.Write(); err != nil {
+		return err
+	}
+
+	condTableWriter, err := newSectionTableWriter(writer, statusWriter, "Conditions", "Type", "Status", "Reason", "Message")
+	if err != nil {
+		return err
+	}
+
+	for _, cond := range bldr.Status.Conditions {
+		if err := condTableWriter.AddRow(string(cond.Type), string(cond.Status), cond.Reason, cond.Message); err != nil {
+			return err
+		}
+	}
+
+	return condTableWriter.Write()
+}
+
+func getReadyStatus(bldr *v1alpha1.ClusterBuilder) string {
+	cond := bldr.Status.GetCondition(corev1alpha1.ConditionReady)
+	if cond == nil {
+		return string(corev1.ConditionUnknown)
+	}
+	return string(cond.Status)
+}
+
+func newSectionTableWriter(writer io.Writer, statusWriter *commands.StatusWriter, title string, headers ...string) (*commands.TableWriter, error) {
+	if err := statusWriter.Write(); err != nil {
+		return nil, err
+	}
+
+	if _, err := fmt.Fprintln(writer, title); err != nil {
+		return nil, err
+	}
+	return commands.NewTableWriter(writer, headers...)
+}
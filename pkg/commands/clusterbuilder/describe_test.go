@@ -0,0 +1,163 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterbuilder_test
+
+import (
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/clusterbuilder"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestClusterBuilderDescribeCommand(t *testing.T) {
+	spec.Run(t, "TestClusterBuilderDescribeCommand", testClusterBuilderDescribeCommand)
+}
+
+func testClusterBuilderDescribeCommand(t *testing.T, when spec.G, it spec.S) {
+	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
+		clientSetProvider := testhelpers.GetFakeKpackClusterProvider(clientSet)
+		return clusterbuilder.NewDescribeCommand(clientSetProvider)
+	}
+
+	bldr := &v1alpha1.ClusterBuilder{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-builder",
+		},
+		Spec: v1alpha1.ClusterBuilderSpec{
+			BuilderSpec: v1alpha1.BuilderSpec{
+				Tag: "some-registry.com/test-builder",
+				Stack: corev1.ObjectReference{
+					Name: "test-stack",
+					Kind: "ClusterStack",
+				},
+				Store: corev1.ObjectReference{
+					Name: "test-store",
+					Kind: "ClusterStore",
+				},
+				Order: []v1alpha1.OrderEntry{
+					{
+						Group: []v1alpha1.BuildpackRef{
+							{BuildpackInfo: v1alpha1.BuildpackInfo{Id: "org.cloudfoundry.nodejs", Version: "v0.2.1"}},
+							{BuildpackInfo: v1alpha1.BuildpackInfo{Id: "org.cloudfoundry.npm"}},
+						},
+					},
+					{
+						Group: []v1alpha1.BuildpackRef{
+							{BuildpackInfo: v1alpha1.BuildpackInfo{Id: "org.cloudfoundry.go", Version: "v0.0.3"}},
+						},
+					},
+				},
+			},
+			ServiceAccountRef: corev1.ObjectReference{
+				Name:      "some-service-account",
+				Namespace: "kpack",
+			},
+		},
+		Status: v1alpha1.BuilderStatus{
+			Status: corev1alpha1.Status{
+				Conditions: corev1alpha1.Conditions{
+					{
+						Type:    corev1alpha1.ConditionReady,
+						Status:  corev1.ConditionFalse,
+						Reason:  "ReconcileFailed",
+						Message: "stack not ready",
+					},
+				},
+			},
+			LatestImage: "some-registry.com/test-builder@sha256:abc123",
+			Stack: v1alpha1.BuildStack{
+				RunImage: "gcr.io/paketo-buildpacks/run:base",
+				ID:       "io.buildpacks.stacks.bionic",
+			},
+		},
+	}
+
+	it("prints the spec order, refs and status conditions", func() {
+		const expectedOutput = `Name:                   test-builder
+Tag:                    some-registry.com/test-builder
+Stack Ref:               
+  Name:                 test-stack
+  Kind:                 ClusterStack
+Store Ref:               
+  Name:                 test-store
+  Kind:                 ClusterStore
+Service Account Ref:     
+  Name:                 some-service-account
+  Namespace:            kpack
+
+Status
+Ready:           False
+Latest Image:    some-registry.com/test-builder@sha256:abc123
+Stack ID:        io.buildpacks.stacks.bionic
+Run Image:       gcr.io/paketo-buildpacks/run:base
+
+Order
+GROUP    BUILDPACK ID               VERSION
+1        org.cloudfoundry.nodejs    v0.2.1
+1        org.cloudfoundry.npm       
+2        org.cloudfoundry.go        v0.0.3
+
+Conditions
+TYPE     STATUS    REASON             MESSAGE
+Ready    False     ReconcileFailed    stack not ready
+
+`
+
+		testhelpers.CommandTest{
+			Objects:        []runtime.Object{bldr},
+			Args:           []string{"test-builder"},
+			ExpectedOutput: expectedOutput,
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("prints the cluster builder resource when the output flag is used", func() {
+		bldr.Spec.Order = bldr.Spec.Order[1:]
+		bldr.Status = v1alpha1.BuilderStatus{}
+
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{bldr},
+			Args:    []string{"test-builder", "-o", "yaml"},
+			ExpectedOutput: `apiVersion: kpack.io/v1alpha1
+kind: ClusterBuilder
+metadata:
+  creationTimestamp: null
+  name: test-builder
+spec:
+  order:
+  - group:
+    - id: org.cloudfoundry.go
+      version: v0.0.3
+  serviceAccountRef:
+    name: some-service-account
+    namespace: kpack
+  stack:
+    kind: ClusterStack
+    name: test-stack
+  store:
+    kind: ClusterStore
+    name: test-store
+  tag: some-registry.com/test-builder
+status:
+  stack: {}
+`,
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("returns an error when the cluster builder does not exist", func() {
+		testhelpers.CommandTest{
+			Args:           []string{"test-builder"},
+			ExpectErr:      true,
+			ExpectedOutput: "Error: clusterbuilders.kpack.io \"test-builder\" not found\n",
+		}.TestKpack(t, cmdFunc)
+	})
+}
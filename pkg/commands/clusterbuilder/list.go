@@ -43,6 +43,7 @@ func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 			}
 		},
 	}
+	commands.SetNoColorFlag(cmd)
 
 	return cmd
 }
@@ -53,6 +54,15 @@ func displayClusterBuildersTable(cmd *cobra.Command, builderList *v1alpha1.Clust
 		return err
 	}
 
+	color, err := commands.ColorEnabled(cmd)
+	if err != nil {
+		return err
+	}
+
+	if color {
+		writer.ColorizeStatusColumn("Ready")
+	}
+
 	for _, bldr := range builderList.Items {
 		err := writer.AddRow(
 			bldr.ObjectMeta.Name,
@@ -4,6 +4,7 @@
 package clusterbuilder_test
 
 import (
+	"io"
 	"testing"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
@@ -15,6 +16,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands/clusterbuilder"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
 )
@@ -214,6 +216,8 @@ test-builder-3    true     io.buildpacks.stacks.bionic    some-registry.com/test
 		}
 	)
 
+	isTerminal := commands.IsTerminal
+
 	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
 		clientSetProvider := testhelpers.GetFakeKpackClusterProvider(clientSet)
 		return clusterbuilder.NewListCommand(clientSetProvider)
@@ -231,6 +235,24 @@ test-builder-3    true     io.buildpacks.stacks.bionic    some-registry.com/test
 					ExpectedOutput: expectedOutput,
 				}.TestKpack(t, cmdFunc)
 			})
+
+			it("colors the ready status when the output is a terminal", func() {
+				commands.IsTerminal = func(io.Writer) bool { return true }
+				defer func() { commands.IsTerminal = isTerminal }()
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						clusterBuilder1,
+						clusterBuilder2,
+						clusterBuilder3,
+					},
+					ExpectedOutput: "NAME              \x1b[39mREADY\x1b[0m    STACK                          IMAGE\n" +
+						"test-builder-1    \x1b[32mtrue\x1b[0m     io.buildpacks.stacks.centos    some-registry.com/test-builder-1:tag\n" +
+						"test-builder-2    \x1b[31mfalse\x1b[0m                                   \n" +
+						"test-builder-3    \x1b[32mtrue\x1b[0m     io.buildpacks.stacks.bionic    some-registry.com/test-builder-3:tag\n" +
+						"\n",
+				}.TestKpack(t, cmdFunc)
+			})
 		})
 
 		when("there are no clusterbuilders", func() {
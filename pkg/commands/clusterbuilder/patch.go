@@ -30,12 +30,16 @@ func NewPatchCommand(clientSetProvider k8s.ClientSetProvider, newWaiter func(dyn
 		Long: `Patch an existing clusterbuilder configuration by providing command line arguments.
 
 A buildpack order must be provided with either the path to an order yaml or via the --buildpack flag.
-Multiple buildpacks provided via the --buildpack flag will be added to the same order group.`,
+Multiple buildpacks provided via the --buildpack flag will be added to the same order group.
+Use the --clear-order flag to remove the existing buildpack order.`,
 		Example: `kp cb patch my-builder --order /path/to/order.yaml --stack tiny --store my-store
 kp cb patch my-builder --order /path/to/order.yaml
-kp cb patch my-builder --buildpack my-buildpack-id --buildpack my-other-buildpack@1.0.1`,
-		Args:         commands.ExactArgsWithUsage(1),
-		SilenceUsage: true,
+cat /path/to/order.yaml | kp cb patch my-builder --order -
+kp cb patch my-builder --buildpack my-buildpack-id --buildpack my-other-buildpack@1.0.1
+kp cb patch my-builder --clear-order`,
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ClusterBuilderNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet("")
 			if err != nil {
@@ -47,24 +51,32 @@ kp cb patch my-builder --buildpack my-buildpack-id --buildpack my-other-buildpac
 				return err
 			}
 
+			flags.stdin = commands.NewReplayReader(cmd.InOrStdin())
+
 			name := args[0]
 
 			ctx := cmd.Context()
-			cb, err := cs.KpackClient.KpackV1alpha1().ClusterBuilders().Get(ctx, name, metav1.GetOptions{})
-			if err != nil {
-				return err
-			}
-
-			return patch(ctx, cb, flags, ch, cs, newWaiter(cs.DynamicClient))
+			return commands.RetryOnConflict(func() error {
+				cb, err := cs.KpackClient.KpackV1alpha1().ClusterBuilders().Get(ctx, name, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+
+				return patch(ctx, cb, flags, ch, cs, commands.WithWaitTimeout(newWaiter(cs.DynamicClient), ch.WaitTimeout()))
+			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&flags.tag, "tag", "t", "", "registry location where the builder will be created")
 	cmd.Flags().StringVarP(&flags.stack, "stack", "s", "", "stack resource to use")
 	cmd.Flags().StringVar(&flags.store, "store", "", "buildpack store to use")
-	cmd.Flags().StringVarP(&flags.order, "order", "o", "", "path to buildpack order yaml")
+	cmd.Flags().StringVarP(&flags.order, "order", "o", "", "path to buildpack order yaml, or \"-\" to read from stdin")
 	cmd.Flags().StringSliceVarP(&flags.buildpacks, "buildpack", "b", []string{}, "buildpack id and optional version in the form of either '<buildpack>@<version>' or '<buildpack>'\n  repeat for each buildpack in order, or supply once with comma-separated list")
+	cmd.Flags().BoolVar(&flags.clearOrder, "clear-order", false, "remove the existing buildpack order")
+	commands.SetMetadataPatchFlags(cmd, &flags.metadata)
 	commands.SetDryRunOutputFlags(cmd)
+	commands.SetReadyTimeoutFlag(cmd)
+	commands.SetServerDryRunFlag(cmd)
 	return cmd
 }
 
@@ -87,8 +99,16 @@ func patch(ctx context.Context, cb *v1alpha1.ClusterBuilder, flags CommandFlags,
 		return fmt.Errorf("cannot use --order and --buildpack together")
 	}
 
+	if flags.clearOrder && (len(flags.buildpacks) > 0 || flags.order != "") {
+		return fmt.Errorf("cannot use --clear-order with --order or --buildpack")
+	}
+
+	if flags.clearOrder {
+		patchedCb.Spec.Order = nil
+	}
+
 	if flags.order != "" {
-		orderEntries, err := builder.ReadOrder(flags.order)
+		orderEntries, err := builder.ReadOrder(flags.order, flags.stdin)
 		if err != nil {
 			return err
 		}
@@ -100,19 +120,30 @@ func patch(ctx context.Context, cb *v1alpha1.ClusterBuilder, flags CommandFlags,
 		patchedCb.Spec.Order = builder.CreateOrder(flags.buildpacks)
 	}
 
+	if err := flags.metadata.Apply(patchedCb); err != nil {
+		return err
+	}
+
 	patch, err := k8s.CreatePatch(cb, patchedCb)
 	if err != nil {
 		return err
 	}
 
+	patch, err = k8s.AddResourceVersion(patch, cb)
+	if err != nil {
+		return err
+	}
+
 	hasPatch := len(patch) > 0
-	if hasPatch && !ch.IsDryRun() {
-		patchedCb, err = cs.KpackClient.KpackV1alpha1().ClusterBuilders().Patch(ctx, patchedCb.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if hasPatch && ch.ShouldSubmit() {
+		patchedCb, err = cs.KpackClient.KpackV1alpha1().ClusterBuilders().Patch(ctx, patchedCb.Name, types.MergePatchType, patch, ch.PatchOptions())
 		if err != nil {
 			return err
 		}
-		if err := waiter.Wait(ctx, patchedCb); err != nil {
-			return err
+		if !ch.IsDryRun() {
+			if err := waiter.Wait(ctx, patchedCb); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -107,6 +107,74 @@ func testClusterBuilderPatchCommand(t *testing.T, when spec.G, it spec.S) {
 		require.Len(t, fakeWaiter.WaitCalls, 1)
 	})
 
+	it("patches a ClusterBuilder with an order read from stdin", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				builder,
+			},
+			Args: []string{
+				builder.Name,
+				"--order", "-",
+			},
+			StdIn: `- group:
+  - id: org.cloudfoundry.test-bp
+- group:
+  - id: org.cloudfoundry.fake-bp
+`,
+			ExpectedOutput: `ClusterBuilder "test-builder" patched
+`,
+			ExpectPatches: []string{
+				`{"spec":{"order":[{"group":[{"id":"org.cloudfoundry.test-bp"}]},{"group":[{"id":"org.cloudfoundry.fake-bp"}]}]}}`,
+			},
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("returns error when the order is read from an empty stdin", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				builder,
+			},
+			Args: []string{
+				builder.Name,
+				"--order", "-",
+			},
+			ExpectErr:      true,
+			ExpectedOutput: "Error: no order yaml provided on stdin\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("clears the order of a ClusterBuilder", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				builder,
+			},
+			Args: []string{
+				builder.Name,
+				"--clear-order",
+			},
+			ExpectedOutput: `ClusterBuilder "test-builder" patched
+`,
+			ExpectPatches: []string{
+				`{"spec":{"order":null}}`,
+			},
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("returns error when clear-order and order flags are used together", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				builder,
+			},
+			Args: []string{
+				builder.Name,
+				"--clear-order",
+				"--order", "./testdata/patched-order.yaml",
+			},
+			ExpectErr:      true,
+			ExpectedOutput: "Error: cannot use --clear-order with --order or --buildpack\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
 	it("does not patch if there are no changes", func() {
 		testhelpers.CommandTest{
 			Objects: []runtime.Object{
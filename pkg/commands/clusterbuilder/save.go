@@ -10,6 +10,7 @@ import (
 	"k8s.io/client-go/dynamic"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 )
 
@@ -45,37 +46,46 @@ kp cb save my-builder --tag my-registry.com/my-builder-tag --buildpack my-buildp
 			}
 
 			ctx := cmd.Context()
-			w := newWaiter(cs.DynamicClient)
 
 			ch, err := commands.NewCommandHelper(cmd)
 			if err != nil {
 				return err
 			}
+			w := commands.WithWaitTimeout(newWaiter(cs.DynamicClient), ch.WaitTimeout())
+
+			flags.stdin = commands.NewReplayReader(cmd.InOrStdin())
 
 			name := args[0]
-			cb, err := cs.KpackClient.KpackV1alpha1().ClusterBuilders().Get(ctx, name, metav1.GetOptions{})
-			if k8serrors.IsNotFound(err) {
-				if flags.stack == "" {
-					flags.stack = defaultStack
-				}
+			return commands.RetryOnConflict(func() error {
+				cb, err := cs.KpackClient.KpackV1alpha1().ClusterBuilders().Get(ctx, name, metav1.GetOptions{})
+				if k8serrors.IsNotFound(err) {
+					if flags.stack == "" {
+						flags.stack = commands.CreateConfigDefault(cmd, "stack", defaultStack)
+					}
 
-				if flags.store == "" {
-					flags.store = defaultStore
+					if flags.store == "" {
+						flags.store = commands.CreateConfigDefault(cmd, "store", defaultStore)
+					}
+					return create(ctx, name, flags, ch, cs, w)
+				} else if err != nil {
+					return err
 				}
-				return create(ctx, name, flags, ch, cs, w)
-			} else if err != nil {
-				return err
-			}
 
-			return patch(ctx, cb, flags, ch, cs, w)
+				return patch(ctx, cb, flags, ch, cs, w)
+			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&flags.tag, "tag", "t", "", "registry location where the builder will be created")
 	cmd.Flags().StringVarP(&flags.stack, "stack", "s", "", "stack resource to use (default \"default\" for a create)")
 	cmd.Flags().StringVar(&flags.store, "store", "", "buildpack store to use (default \"default\" for a create)")
-	cmd.Flags().StringVarP(&flags.order, "order", "o", "", "path to buildpack order yaml")
+	cmd.Flags().StringVarP(&flags.order, "order", "o", "", "path to buildpack order yaml, or \"-\" to read from stdin")
 	cmd.Flags().StringSliceVarP(&flags.buildpacks, "buildpack", "b", []string{}, "buildpack id and optional version in the form of either '<buildpack>@<version>' or '<buildpack>'\n  repeat for each buildpack in order, or supply once with comma-separated list")
+	commands.SetMetadataPatchFlags(cmd, &flags.metadata)
 	commands.SetDryRunOutputFlags(cmd)
+	commands.SetReadyTimeoutFlag(cmd)
+	commands.SetServerDryRunFlag(cmd)
+	commands.SetCreateConfigDefault(cmd, "stack", config.DefaultStackKey)
+	commands.SetCreateConfigDefault(cmd, "store", config.DefaultStoreKey)
 	return cmd
 }
@@ -6,7 +6,9 @@ package clusterbuilder
 import (
 	"fmt"
 	"io"
+	"strings"
 
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
 	"github.com/spf13/cobra"
@@ -16,17 +18,28 @@ import (
 	"github.com/vmware-tanzu/kpack-cli/pkg/builder"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
 )
 
-func NewStatusCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+func NewStatusCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider) *cobra.Command {
+	var (
+		verbose   bool
+		tlsConfig registry.TLSConfig
+	)
 
 	cmd := &cobra.Command{
-		Use:          "status <name>",
-		Short:        "Display cluster builder status",
-		Long:         `Prints detailed information about the status of a specific cluster builder.`,
-		Example:      "kp cb status my-builder",
-		Args:         commands.ExactArgsWithUsage(1),
-		SilenceUsage: true,
+		Use:   "status <name>",
+		Short: "Display cluster builder status",
+		Long: `Prints detailed information about the status of a specific cluster builder.
+
+Use the --verbose flag to look up the cosign signatures, attestations and SBOM of the latest builder image in its registry.
+The signatures are read from the "sha256-<digest>.sig" tag, the attestations from the "sha256-<digest>.att" tag and the
+SBOM from the "sha256-<digest>.sbom" tag or from an SPDX or CycloneDX attestation.
+The registry credentials are read from the local docker config.`,
+		Example:           "kp cb status my-builder\nkp cb status my-builder --verbose",
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ClusterBuilderNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet("")
 			if err != nil {
@@ -38,17 +51,28 @@ func NewStatusCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 				return err
 			}
 
-			return displayBuilderStatus(bldr, cmd.OutOrStdout())
+			var signatureStatus *registry.SignatureStatus
+			if verbose && bldr.Status.LatestImage != "" {
+				status, err := registry.FetchSignatureStatus(rup.Fetcher(tlsConfig), authn.DefaultKeychain, bldr.Status.LatestImage)
+				if err != nil {
+					return err
+				}
+				signatureStatus = &status
+			}
+
+			return displayBuilderStatus(bldr, signatureStatus, cmd.OutOrStdout())
 		},
 	}
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "look up the signatures, attestations and SBOM of the latest builder image")
+	commands.SetTLSFlags(cmd, &tlsConfig)
 
 	return cmd
 }
 
-func displayBuilderStatus(bldr *v1alpha1.ClusterBuilder, writer io.Writer) error {
+func displayBuilderStatus(bldr *v1alpha1.ClusterBuilder, signatureStatus *registry.SignatureStatus, writer io.Writer) error {
 	if cond := bldr.Status.GetCondition(corev1alpha1.ConditionReady); cond != nil {
 		if cond.Status == corev1.ConditionTrue {
-			return printBuilderReadyStatus(bldr, writer)
+			return printBuilderReadyStatus(bldr, signatureStatus, writer)
 		} else {
 			return printBuilderNotReadyStatus(bldr, writer)
 		}
@@ -78,7 +102,7 @@ func printBuilderNotReadyStatus(bldr *v1alpha1.ClusterBuilder, writer io.Writer)
 	)
 }
 
-func printBuilderReadyStatus(bldr *v1alpha1.ClusterBuilder, writer io.Writer) error {
+func printBuilderReadyStatus(bldr *v1alpha1.ClusterBuilder, signatureStatus *registry.SignatureStatus, writer io.Writer) error {
 	statusWriter := commands.NewStatusWriter(writer)
 
 	err := statusWriter.AddBlock(
@@ -92,6 +116,18 @@ func printBuilderReadyStatus(bldr *v1alpha1.ClusterBuilder, writer io.Writer) er
 		return err
 	}
 
+	if signatureStatus != nil {
+		err = statusWriter.AddBlock(
+			"",
+			"Signed", signedText(*signatureStatus),
+			"SBOM", yesNo(signatureStatus.SBOM),
+			"Attestations", attestationsText(*signatureStatus),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = statusWriter.AddBlock(
 		"",
 		"Stack Ref", " ",
@@ -151,3 +187,28 @@ func printBuilderReadyStatus(bldr *v1alpha1.ClusterBuilder, writer io.Writer) er
 	}
 	return orderTableWriter.Write()
 }
+
+func signedText(status registry.SignatureStatus) string {
+	switch status.Signatures {
+	case 0:
+		return "No"
+	case 1:
+		return "Yes (1 signature)"
+	default:
+		return fmt.Sprintf("Yes (%d signatures)", status.Signatures)
+	}
+}
+
+func attestationsText(status registry.SignatureStatus) string {
+	if len(status.PredicateTypes) == 0 {
+		return "None"
+	}
+	return strings.Join(status.PredicateTypes, ", ")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
@@ -11,11 +11,13 @@ import (
 	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
 	"github.com/sclevine/spec"
 	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands/clusterbuilder"
+	registryfakes "github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
 )
 
@@ -262,9 +264,14 @@ Reason:    this builder is not ready for the purpose of a test
 		}
 	)
 
+	const builderDigest = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	fakeFetcher := &registryfakes.Fetcher{}
+	fakeFetcher.AddImage("some-registry.com/test-builder-1:tag", registryfakes.NewFakeImage(builderDigest))
+
 	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
 		clientSetProvider := testhelpers.GetFakeKpackClusterProvider(clientSet)
-		return clusterbuilder.NewStatusCommand(clientSetProvider)
+		return clusterbuilder.NewStatusCommand(clientSetProvider, registryfakes.UtilProvider{FakeFetcher: fakeFetcher})
 	}
 
 	when("getting clusterbuilder status", func() {
@@ -277,6 +284,8 @@ Reason:    this builder is not ready for the purpose of a test
 							Args:           []string{"test-builder-1"},
 							ExpectedOutput: expectedReadyOutputUsingSpecOrder,
 						}.TestKpack(t, cmdFunc)
+
+						require.Equal(t, 0, fakeFetcher.CallCount())
 					})
 				})
 				when("the order is in the builder status", func() {
@@ -312,6 +321,88 @@ Reason:    this builder is not ready for the purpose of a test
 					})
 
 				})
+
+				when("the verbose flag is used", func() {
+					it("shows the signatures, attestations and sbom of the latest image", func() {
+						fakeFetcher.AddImage("some-registry.com/test-builder-1:sha256-"+builderDigest+".sig", registryfakes.NewFakeAnnotatedImage("some-sig-digest",
+							map[string]string{"dev.cosignproject.cosign/signature": "some-signature"},
+						))
+						fakeFetcher.AddImage("some-registry.com/test-builder-1:sha256-"+builderDigest+".att", registryfakes.NewFakeAnnotatedImage("some-att-digest",
+							map[string]string{"predicateType": "https://slsa.dev/provenance/v0.2"},
+							map[string]string{"predicateType": "https://spdx.dev/Document"},
+						))
+
+						testhelpers.CommandTest{
+							Objects: []runtime.Object{readyClusterBuilder},
+							Args:    []string{"test-builder-1", "--verbose"},
+							ExpectedOutput: `Status:       Ready
+Image:        some-registry.com/test-builder-1:tag
+Stack ID:     io.buildpacks.stacks.centos
+Run Image:    gcr.io/paketo-buildpacks/run@sha256:iweuryaksdjhf9203847098234
+
+Signed:          Yes (1 signature)
+SBOM:            Yes
+Attestations:    https://slsa.dev/provenance/v0.2, https://spdx.dev/Document
+
+Stack Ref:     
+  Name:       test-stack
+  Kind:       ClusterStack
+Store Ref:     
+  Name:       test-store
+  Kind:       ClusterStore
+
+BUILDPACK ID               VERSION    HOMEPAGE
+org.cloudfoundry.nodejs    v0.2.1     https://github.com/paketo-buildpacks/nodejs
+org.cloudfoundry.go        v0.0.3     https://github.com/paketo-buildpacks/go
+
+
+DETECTION ORDER              
+Group #1                     
+  org.cloudfoundry.nodejs    
+Group #2                     
+  org.cloudfoundry.go        
+
+`,
+						}.TestKpack(t, cmdFunc)
+					})
+
+					it("shows that the latest image is not signed", func() {
+						testhelpers.CommandTest{
+							Objects: []runtime.Object{readyClusterBuilder},
+							Args:    []string{"test-builder-1", "-v"},
+							ExpectedOutput: `Status:       Ready
+Image:        some-registry.com/test-builder-1:tag
+Stack ID:     io.buildpacks.stacks.centos
+Run Image:    gcr.io/paketo-buildpacks/run@sha256:iweuryaksdjhf9203847098234
+
+Signed:          No
+SBOM:            No
+Attestations:    None
+
+Stack Ref:     
+  Name:       test-stack
+  Kind:       ClusterStack
+Store Ref:     
+  Name:       test-store
+  Kind:       ClusterStore
+
+BUILDPACK ID               VERSION    HOMEPAGE
+org.cloudfoundry.nodejs    v0.2.1     https://github.com/paketo-buildpacks/nodejs
+org.cloudfoundry.go        v0.0.3     https://github.com/paketo-buildpacks/go
+
+
+DETECTION ORDER              
+Group #1                     
+  org.cloudfoundry.nodejs    
+Group #2                     
+  org.cloudfoundry.go        
+
+`,
+						}.TestKpack(t, cmdFunc)
+
+						require.Equal(t, 4, fakeFetcher.CallCount())
+					})
+				})
 			})
 
 			when("the builder is not ready", func() {
@@ -0,0 +1,181 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterbuilder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+const defaultWaitTimeout = 10 * time.Minute
+
+type waitCondition struct {
+	conditionType corev1alpha1.ConditionType
+	status        corev1.ConditionStatus
+}
+
+func NewWaitCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	var (
+		forCondition string
+		timeout      time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "wait <name>",
+		Short: "Wait for a cluster builder condition",
+		Long: `Waits until a specific cluster builder has the condition provided with the "--for" flag.
+
+The condition has the form "condition=<type>" or "condition=<type>=<status>" and the status defaults to "True".
+A condition is only checked once the latest generation of the cluster builder has been reconciled.
+The command exits with a non-zero status if the timeout is reached before the condition is met.`,
+		Example:           "kp clusterbuilder wait my-builder\nkp cb wait my-builder --for=condition=Ready --timeout=5m",
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ClusterBuilderNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			condition, err := parseWaitCondition(forCondition)
+			if err != nil {
+				return err
+			}
+
+			if timeout <= 0 {
+				return errors.New("--timeout must be greater than 0")
+			}
+
+			cs, err := clientSetProvider.GetClientSet("")
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			cb, err := waitForClusterBuilder(ctx, cs, args[0], condition)
+			if err == context.DeadlineExceeded {
+				return timeoutError(args[0], cb, condition, timeout)
+			} else if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "ClusterBuilder %q condition met\n", args[0])
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&forCondition, "for", "condition=Ready", "condition to wait for in the form condition=<type>[=<status>]")
+	cmd.Flags().DurationVar(&timeout, "timeout", defaultWaitTimeout, "maximum time to wait for the condition")
+
+	return cmd
+}
+
+func parseWaitCondition(value string) (waitCondition, error) {
+	invalid := errors.Errorf("invalid --for %q, expected condition=<type>[=<status>]", value)
+
+	parts := strings.Split(value, "=")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] != "condition" || parts[1] == "" {
+		return waitCondition{}, invalid
+	}
+
+	condition := waitCondition{
+		conditionType: corev1alpha1.ConditionType(parts[1]),
+		status:        corev1.ConditionTrue,
+	}
+
+	if len(parts) == 3 {
+		switch {
+		case strings.EqualFold(parts[2], string(corev1.ConditionTrue)):
+			condition.status = corev1.ConditionTrue
+		case strings.EqualFold(parts[2], string(corev1.ConditionFalse)):
+			condition.status = corev1.ConditionFalse
+		case strings.EqualFold(parts[2], string(corev1.ConditionUnknown)):
+			condition.status = corev1.ConditionUnknown
+		default:
+			return waitCondition{}, invalid
+		}
+	}
+
+	return condition, nil
+}
+
+// waitForClusterBuilder returns the last seen cluster builder along with the
+// context error when the condition is not met in time
+func waitForClusterBuilder(ctx context.Context, cs k8s.ClientSet, name string, condition waitCondition) (*v1alpha1.ClusterBuilder, error) {
+	cb, err := cs.KpackClient.KpackV1alpha1().ClusterBuilders().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if condition.isMet(cb) {
+		return cb, nil
+	}
+
+	watcher, err := cs.KpackClient.KpackV1alpha1().ClusterBuilders().Watch(ctx, metav1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+		ResourceVersion: cb.ResourceVersion,
+	})
+	if err != nil {
+		return cb, err
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return cb, ctx.Err()
+		case e, ok := <-watcher.ResultChan():
+			if !ok {
+				return cb, errors.Errorf("stopped watching ClusterBuilder %q before the condition was met", name)
+			}
+
+			switch e.Type {
+			case watch.Error:
+				return cb, errors.Errorf("error on watch %+v", e.Object)
+			case watch.Deleted:
+				return cb, errors.Errorf("ClusterBuilder %q was deleted", name)
+			}
+
+			if updated, ok := e.Object.(*v1alpha1.ClusterBuilder); ok {
+				cb = updated
+				if condition.isMet(cb) {
+					return cb, nil
+				}
+			}
+		}
+	}
+}
+
+func (c waitCondition) isMet(cb *v1alpha1.ClusterBuilder) bool {
+	if cb.Status.ObservedGeneration < cb.Generation {
+		return false
+	}
+
+	cond := cb.Status.GetCondition(c.conditionType)
+	if cond == nil {
+		return c.status == corev1.ConditionUnknown
+	}
+	return cond.Status == c.status
+}
+
+func timeoutError(name string, cb *v1alpha1.ClusterBuilder, condition waitCondition, timeout time.Duration) error {
+	msg := fmt.Sprintf("timed out after %v waiting for ClusterBuilder %q condition %s=%s", timeout, name, condition.conditionType, condition.status)
+	if cb != nil {
+		if cond := cb.Status.GetCondition(condition.conditionType); cond != nil && cond.Message != "" {
+			return commands.NewTimeoutError("%s: %s", msg, cond.Message)
+		}
+	}
+	return commands.NewTimeoutError("%s", msg)
+}
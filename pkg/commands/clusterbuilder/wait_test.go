@@ -0,0 +1,121 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterbuilder_test
+
+import (
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/clusterbuilder"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestClusterBuilderWaitCommand(t *testing.T) {
+	spec.Run(t, "TestClusterBuilderWaitCommand", testClusterBuilderWaitCommand)
+}
+
+func testClusterBuilderWaitCommand(t *testing.T, when spec.G, it spec.S) {
+	makeClusterBuilder := func(generation int64, status corev1.ConditionStatus, message string) *v1alpha1.ClusterBuilder {
+		return &v1alpha1.ClusterBuilder{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "some-builder",
+				Generation: generation,
+			},
+			Status: v1alpha1.BuilderStatus{
+				Status: corev1alpha1.Status{
+					ObservedGeneration: 1,
+					Conditions: corev1alpha1.Conditions{
+						{
+							Type:    corev1alpha1.ConditionReady,
+							Status:  status,
+							Message: message,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	var watchedBuilder *v1alpha1.ClusterBuilder
+
+	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
+		if watchedBuilder != nil {
+			clientSet.PrependWatchReactor("clusterbuilders", func(action clientgotesting.Action) (bool, watch.Interface, error) {
+				w := watch.NewRaceFreeFake()
+				w.Modify(watchedBuilder)
+				return true, w, nil
+			})
+		}
+
+		clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, "")
+		return clusterbuilder.NewWaitCommand(clientSetProvider)
+	}
+
+	it.Before(func() {
+		watchedBuilder = nil
+	})
+
+	it("returns when the cluster builder is already ready", func() {
+		testhelpers.CommandTest{
+			Objects:        []runtime.Object{makeClusterBuilder(1, corev1.ConditionTrue, "")},
+			Args:           []string{"some-builder"},
+			ExpectedOutput: "ClusterBuilder \"some-builder\" condition met\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("watches the cluster builder until the condition is met", func() {
+		watchedBuilder = makeClusterBuilder(2, corev1.ConditionTrue, "")
+		watchedBuilder.Status.ObservedGeneration = 2
+
+		testhelpers.CommandTest{
+			Objects:        []runtime.Object{makeClusterBuilder(2, corev1.ConditionTrue, "")},
+			Args:           []string{"some-builder", "--for=condition=Ready", "--timeout=5m"},
+			ExpectedOutput: "ClusterBuilder \"some-builder\" condition met\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("can wait for a condition status other than true", func() {
+		testhelpers.CommandTest{
+			Objects:        []runtime.Object{makeClusterBuilder(1, corev1.ConditionFalse, "some-message")},
+			Args:           []string{"some-builder", "--for", "condition=Ready=false"},
+			ExpectedOutput: "ClusterBuilder \"some-builder\" condition met\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("errors when the timeout is reached before the condition is met", func() {
+		testhelpers.CommandTest{
+			Objects:        []runtime.Object{makeClusterBuilder(1, corev1.ConditionFalse, "some-message")},
+			Args:           []string{"some-builder", "--timeout", "10ms"},
+			ExpectErr:      true,
+			ExpectedOutput: "Error: timed out after 10ms waiting for ClusterBuilder \"some-builder\" condition Ready=True: some-message\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("errors when the condition is invalid", func() {
+		testhelpers.CommandTest{
+			Objects:        []runtime.Object{makeClusterBuilder(1, corev1.ConditionTrue, "")},
+			Args:           []string{"some-builder", "--for", "delete"},
+			ExpectErr:      true,
+			ExpectedOutput: "Error: invalid --for \"delete\", expected condition=<type>[=<status>]\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("errors when the cluster builder does not exist", func() {
+		testhelpers.CommandTest{
+			Args:           []string{"some-builder"},
+			ExpectErr:      true,
+			ExpectedOutput: "Error: clusterbuilders.kpack.io \"some-builder\" not found\n",
+		}.TestKpack(t, cmdFunc)
+	})
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/spf13/cobra"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/dynamic"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/clusterstack"
@@ -22,6 +21,7 @@ func NewCreateCommand(clientSetProvider k8s.ClientSetProvider, rup registry.Util
 		buildImageRef string
 		runImageRef   string
 		tlsCfg        registry.TLSConfig
+		metadata      k8s.Metadata
 	)
 
 	cmd := &cobra.Command{
@@ -34,6 +34,8 @@ Therefore, you must have credentials to access the registry on your machine.
 Additionally, your cluster must have read access to the registry.
 
 The canonical repository is read from the "canonical.repository" key in the "kp-config" ConfigMap within "kpack" namespace.
+
+The build and run images must have the same stack id, operating system and architecture.
 `,
 		Example: `kp clusterstack create my-stack --build-image my-registry.com/build --run-image my-registry.com/run
 kp clusterstack create my-stack --build-image ../path/to/build.tar --run-image ../path/to/run.tar`,
@@ -53,14 +55,18 @@ kp clusterstack create my-stack --build-image ../path/to/build.tar --run-image .
 			ctx := cmd.Context()
 
 			factory := clusterstack.NewFactory(ch, rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading()), rup.Fetcher(tlsCfg))
+			factory.Metadata = metadata
 
 			name := args[0]
-			return create(ctx, name, buildImageRef, runImageRef, factory, ch, cs, newWaiter(cs.DynamicClient))
+			return create(ctx, name, buildImageRef, runImageRef, factory, ch, cs, commands.WithWaitTimeout(newWaiter(cs.DynamicClient), ch.WaitTimeout()))
 		},
 	}
 	cmd.Flags().StringVarP(&buildImageRef, "build-image", "b", "", "build image tag or local tar file path")
 	cmd.Flags().StringVarP(&runImageRef, "run-image", "r", "", "run image tag or local tar file path")
+	commands.SetMetadataFlags(cmd, &metadata)
 	commands.SetImgUploadDryRunOutputFlags(cmd)
+	commands.SetReadyTimeoutFlag(cmd)
+	commands.SetServerDryRunFlag(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
 	_ = cmd.MarkFlagRequired("build-image")
 	_ = cmd.MarkFlagRequired("run-image")
@@ -83,13 +89,15 @@ func create(ctx context.Context, name, buildImageRef, runImageRef string, factor
 		return err
 	}
 
-	if !ch.IsDryRun() {
-		stack, err = cs.KpackClient.KpackV1alpha1().ClusterStacks().Create(ctx, stack, metav1.CreateOptions{})
+	if ch.ShouldSubmit() {
+		stack, err = cs.KpackClient.KpackV1alpha1().ClusterStacks().Create(ctx, stack, ch.CreateOptions())
 		if err != nil {
 			return err
 		}
-		if err := w.Wait(ctx, stack); err != nil {
-			return err
+		if !ch.IsDryRun() {
+			if err := w.Wait(ctx, stack); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -15,11 +15,12 @@ import (
 
 func NewDeleteCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "delete <name>",
-		Short:   "Delete a cluster stack",
-		Long:    "Delete a specific cluster-scoped stack from the cluster.",
-		Example: "kp clusterstack delete my-stack",
-		Args:    commands.ExactArgsWithUsage(1),
+		Use:               "delete <name>",
+		Short:             "Delete a cluster stack",
+		Long:              "Delete a specific cluster-scoped stack from the cluster.",
+		Example:           "kp clusterstack delete my-stack",
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ClusterStackNameCompletion(clientSetProvider),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet("")
 			if err != nil {
@@ -40,6 +40,7 @@ func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 
 		},
 	}
+	commands.SetNoColorFlag(cmd)
 
 	return cmd
 }
@@ -50,6 +51,15 @@ func displayStacksTable(cmd *cobra.Command, stackList *v1alpha1.ClusterStackList
 		return err
 	}
 
+	color, err := commands.ColorEnabled(cmd)
+	if err != nil {
+		return err
+	}
+
+	if color {
+		writer.ColorizeStatusColumn("Ready")
+	}
+
 	for _, s := range stackList.Items {
 		err := writer.AddRow(s.Name, getReadyText(s), s.Status.Id)
 		if err != nil {
@@ -4,6 +4,7 @@
 package clusterstack_test
 
 import (
+	"io"
 	"testing"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
@@ -15,6 +16,7 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands/clusterstack"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
 )
@@ -24,68 +26,70 @@ func TestClusterStackListCommand(t *testing.T) {
 }
 
 func testClusterStackListCommand(t *testing.T, when spec.G, it spec.S) {
+	isTerminal := commands.IsTerminal
+
 	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
 		clientSetProvider := testhelpers.GetFakeKpackClusterProvider(clientSet)
 		return clusterstack.NewListCommand(clientSetProvider)
 	}
 
 	when("the namespaces has images", func() {
-		it("returns a table of image details", func() {
-			stack1 := &v1alpha1.ClusterStack{
-				ObjectMeta: v1.ObjectMeta{
-					Name: "test-stack-1",
-				},
-				Status: v1alpha1.ClusterStackStatus{
-					Status: corev1alpha1.Status{
-						Conditions: []corev1alpha1.Condition{
-							{
-								Type:   corev1alpha1.ConditionReady,
-								Status: corev1.ConditionFalse,
-							},
+		stack1 := &v1alpha1.ClusterStack{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "test-stack-1",
+			},
+			Status: v1alpha1.ClusterStackStatus{
+				Status: corev1alpha1.Status{
+					Conditions: []corev1alpha1.Condition{
+						{
+							Type:   corev1alpha1.ConditionReady,
+							Status: corev1.ConditionFalse,
 						},
 					},
-					ResolvedClusterStack: v1alpha1.ResolvedClusterStack{
-						Id: "stack-id-1",
-					},
 				},
-			}
-			stack2 := &v1alpha1.ClusterStack{
-				ObjectMeta: v1.ObjectMeta{
-					Name: "test-stack-2",
+				ResolvedClusterStack: v1alpha1.ResolvedClusterStack{
+					Id: "stack-id-1",
 				},
-				Status: v1alpha1.ClusterStackStatus{
-					Status: corev1alpha1.Status{
-						Conditions: []corev1alpha1.Condition{
-							{
-								Type:   corev1alpha1.ConditionReady,
-								Status: corev1.ConditionTrue,
-							},
+			},
+		}
+		stack2 := &v1alpha1.ClusterStack{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "test-stack-2",
+			},
+			Status: v1alpha1.ClusterStackStatus{
+				Status: corev1alpha1.Status{
+					Conditions: []corev1alpha1.Condition{
+						{
+							Type:   corev1alpha1.ConditionReady,
+							Status: corev1.ConditionTrue,
 						},
 					},
-					ResolvedClusterStack: v1alpha1.ResolvedClusterStack{
-						Id: "stack-id-2",
-					},
 				},
-			}
-			stack3 := &v1alpha1.ClusterStack{
-				ObjectMeta: v1.ObjectMeta{
-					Name: "test-stack-3",
+				ResolvedClusterStack: v1alpha1.ResolvedClusterStack{
+					Id: "stack-id-2",
 				},
-				Status: v1alpha1.ClusterStackStatus{
-					Status: corev1alpha1.Status{
-						Conditions: []corev1alpha1.Condition{
-							{
-								Type:   corev1alpha1.ConditionReady,
-								Status: corev1.ConditionUnknown,
-							},
+			},
+		}
+		stack3 := &v1alpha1.ClusterStack{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "test-stack-3",
+			},
+			Status: v1alpha1.ClusterStackStatus{
+				Status: corev1alpha1.Status{
+					Conditions: []corev1alpha1.Condition{
+						{
+							Type:   corev1alpha1.ConditionReady,
+							Status: corev1.ConditionUnknown,
 						},
 					},
-					ResolvedClusterStack: v1alpha1.ResolvedClusterStack{
-						Id: "stack-id-3",
-					},
 				},
-			}
+				ResolvedClusterStack: v1alpha1.ResolvedClusterStack{
+					Id: "stack-id-3",
+				},
+			},
+		}
 
+		it("returns a table of image details", func() {
 			testhelpers.CommandTest{
 				Objects: []runtime.Object{
 					stack1,
@@ -101,6 +105,24 @@ test-stack-3    Unknown    stack-id-3
 			}.TestKpack(t, cmdFunc)
 		})
 
+		it("colors the ready status when the output is a terminal", func() {
+			commands.IsTerminal = func(io.Writer) bool { return true }
+			defer func() { commands.IsTerminal = isTerminal }()
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					stack1,
+					stack2,
+					stack3,
+				},
+				ExpectedOutput: "NAME            \x1b[39mREADY\x1b[0m      ID\n" +
+					"test-stack-1    \x1b[31mFalse\x1b[0m      stack-id-1\n" +
+					"test-stack-2    \x1b[32mTrue\x1b[0m       stack-id-2\n" +
+					"test-stack-3    \x1b[33mUnknown\x1b[0m    stack-id-3\n" +
+					"\n",
+			}.TestKpack(t, cmdFunc)
+		})
+
 		when("there are no stacks", func() {
 			it("returns a message that no stacks were found", func() {
 				testhelpers.CommandTest{
@@ -0,0 +1,156 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterstack
+
+import (
+	"context"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/clusterstack"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
+)
+
+func NewPatchCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newWaiter func(dynamic.Interface) commands.ResourceWaiter) *cobra.Command {
+	var (
+		buildImageRef string
+		runImageRef   string
+		tlsCfg        registry.TLSConfig
+		metadata      k8s.Metadata
+	)
+
+	cmd := &cobra.Command{
+		Use:   "patch <name>",
+		Short: "Patch an existing cluster stack",
+		Long: `Patches the build image, the run image or both images of a specific cluster-scoped stack.
+
+The provided images will be uploaded to the the registry configured on your stack.
+Therefore, you must have credentials to access the registry on your machine.
+An image that is not provided is left unchanged and must have the same stack id as the provided image.`,
+		Example: `kp clusterstack patch my-stack --build-image my-registry.com/build
+kp clusterstack patch my-stack --run-image ../path/to/run.tar
+kp clusterstack patch my-stack --build-image my-registry.com/build --run-image my-registry.com/run`,
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ClusterStackNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if buildImageRef == "" && runImageRef == "" && metadata.IsEmpty() {
+				return errors.New("nothing to patch, provide --build-image or --run-image")
+			}
+
+			cs, err := clientSetProvider.GetClientSet("")
+			if err != nil {
+				return err
+			}
+
+			ch, err := commands.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+
+			stack, err := cs.KpackClient.KpackV1alpha1().ClusterStacks().Get(ctx, args[0], metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			factory := clusterstack.NewFactory(ch, rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading()), rup.Fetcher(tlsCfg))
+			factory.Metadata = metadata
+
+			return patch(ctx, authn.DefaultKeychain, stack, buildImageRef, runImageRef, factory, ch, cs, commands.WithWaitTimeout(newWaiter(cs.DynamicClient), ch.WaitTimeout()))
+		},
+	}
+
+	cmd.Flags().StringVarP(&buildImageRef, "build-image", "b", "", "build image tag or local tar file path")
+	cmd.Flags().StringVarP(&runImageRef, "run-image", "r", "", "run image tag or local tar file path")
+	commands.SetMetadataPatchFlags(cmd, &metadata)
+	commands.SetImgUploadDryRunOutputFlags(cmd)
+	commands.SetReadyTimeoutFlag(cmd)
+	commands.SetServerDryRunFlag(cmd)
+	commands.SetTLSFlags(cmd, &tlsCfg)
+	return cmd
+}
+
+func patch(ctx context.Context, keychain authn.Keychain, stack *v1alpha1.ClusterStack, buildImageRef, runImageRef string, factory *clusterstack.Factory, ch *commands.CommandHelper, cs k8s.ClientSet, w commands.ResourceWaiter) error {
+	if err := ch.PrintStatus("Patching ClusterStack..."); err != nil {
+		return err
+	}
+
+	helper := k8s.DefaultConfigHelper(cs)
+	kpConfig, err := helper.GetKpConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	patchedStack, patch, err := makePatch(keychain, stack, buildImageRef, runImageRef, factory, kpConfig)
+	if err != nil {
+		return err
+	}
+
+	if len(patch) == 0 {
+		return errors.Errorf("nothing to patch, ClusterStack %q already uses the provided images", stack.Name)
+	}
+
+	if ch.ShouldSubmit() {
+		retry := false
+		err = commands.RetryOnConflict(func() error {
+			current := stack
+			if retry {
+				current, err = cs.KpackClient.KpackV1alpha1().ClusterStacks().Get(ctx, stack.Name, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+
+				patchedStack, patch, err = makePatch(keychain, current, buildImageRef, runImageRef, factory, kpConfig)
+				if err != nil || len(patch) == 0 {
+					return err
+				}
+			}
+			retry = true
+
+			conditionalPatch, err := k8s.AddResourceVersion(patch, current)
+			if err != nil {
+				return err
+			}
+
+			patchedStack, err = cs.KpackClient.KpackV1alpha1().ClusterStacks().Patch(ctx, patchedStack.Name, types.MergePatchType, conditionalPatch, ch.PatchOptions())
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if !ch.IsDryRun() {
+			if err := w.Wait(ctx, patchedStack); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = ch.PrintObj(patchedStack); err != nil {
+		return err
+	}
+
+	return ch.PrintChangeResult(true, "ClusterStack %q patched", patchedStack.Name)
+}
+
+// makePatch returns the patched stack and the patch, the images are only uploaded by the first call
+func makePatch(keychain authn.Keychain, stack *v1alpha1.ClusterStack, buildImageRef, runImageRef string, factory *clusterstack.Factory, kpConfig config.KpConfig) (*v1alpha1.ClusterStack, []byte, error) {
+	patchedStack, err := factory.PatchStack(keychain, stack, buildImageRef, runImageRef, kpConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	patch, err := k8s.CreatePatch(stack, patchedStack)
+	return patchedStack, patch, err
+}
@@ -0,0 +1,348 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterstack_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	k8sfakes "k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	clusterstackcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/clusterstack"
+	commandsfakes "github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
+	registryfakes "github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestPatchCommand(t *testing.T) {
+	spec.Run(t, "TestPatchCommand", testPatchCommand)
+}
+
+func testPatchCommand(t *testing.T, when spec.G, it spec.S) {
+	fakeFetcher := registryfakes.NewStackImagesFetcher(
+		registryfakes.StackInfo{
+			StackID: "stack-id",
+			BuildImg: registryfakes.ImageInfo{
+				Ref:    "canonical-registry.io/canonical-repo/build@sha256:build-image-digest",
+				Digest: "build-image-digest",
+			},
+			RunImg: registryfakes.ImageInfo{
+				Ref:    "canonical-registry.io/canonical-repo/run@sha256:run-image-digest",
+				Digest: "run-image-digest",
+			},
+		},
+		registryfakes.StackInfo{
+			StackID: "stack-id",
+			BuildImg: registryfakes.ImageInfo{
+				Ref:    "some-registry.io/repo/new-build",
+				Digest: "new-build-image-digest",
+			},
+			RunImg: registryfakes.ImageInfo{
+				Ref:    "some-registry.io/repo/new-run",
+				Digest: "new-run-image-digest",
+			},
+		},
+		registryfakes.StackInfo{
+			StackID: "other-stack-id",
+			BuildImg: registryfakes.ImageInfo{
+				Ref:    "some-registry.io/repo/other-build",
+				Digest: "other-build-image-digest",
+			},
+			RunImg: registryfakes.ImageInfo{
+				Ref:    "some-registry.io/repo/other-run",
+				Digest: "other-run-image-digest",
+			},
+		},
+	)
+	fakeRegistryUtilProvider := &registryfakes.UtilProvider{
+		FakeFetcher: fakeFetcher,
+	}
+
+	stack := &v1alpha1.ClusterStack{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "stack-name",
+		},
+		Spec: v1alpha1.ClusterStackSpec{
+			Id: "stack-id",
+			BuildImage: v1alpha1.ClusterStackSpecImage{
+				Image: "canonical-registry.io/canonical-repo/build@sha256:build-image-digest",
+			},
+			RunImage: v1alpha1.ClusterStackSpecImage{
+				Image: "canonical-registry.io/canonical-repo/run@sha256:run-image-digest",
+			},
+		},
+	}
+
+	config := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kp-config",
+			Namespace: "kpack",
+		},
+		Data: map[string]string{
+			"canonical.repository":                "canonical-registry.io/canonical-repo",
+			"canonical.repository.serviceaccount": "some-serviceaccount",
+		},
+	}
+
+	fakeWaiter := &commandsfakes.FakeWaiter{}
+
+	cmdFunc := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *kpackfakes.Clientset) *cobra.Command {
+		clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClientSet, kpackClientSet)
+		return clusterstackcmds.NewPatchCommand(clientSetProvider, fakeRegistryUtilProvider, func(dynamic.Interface) commands.ResourceWaiter {
+			return fakeWaiter
+		})
+	}
+
+	it("patches the build and run images", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				config,
+				stack,
+			},
+			Args: []string{
+				"stack-name",
+				"--build-image", "some-registry.io/repo/new-build",
+				"--run-image", "some-registry.io/repo/new-run",
+			},
+			ExpectPatches: []string{
+				`{"spec":{"buildImage":{"image":"canonical-registry.io/canonical-repo/build@sha256:new-build-image-digest"},"runImage":{"image":"canonical-registry.io/canonical-repo/run@sha256:new-run-image-digest"}}}`,
+			},
+			ExpectedOutput: `Patching ClusterStack...
+Uploading to 'canonical-registry.io/canonical-repo'...
+	Uploading 'canonical-registry.io/canonical-repo/build@sha256:new-build-image-digest'
+	Uploading 'canonical-registry.io/canonical-repo/run@sha256:new-run-image-digest'
+ClusterStack "stack-name" patched
+`,
+		}.TestK8sAndKpack(t, cmdFunc)
+		require.Len(t, fakeWaiter.WaitCalls, 1)
+	})
+
+	it("patches only the build image", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				config,
+				stack,
+			},
+			Args: []string{
+				"stack-name",
+				"--build-image", "some-registry.io/repo/new-build",
+			},
+			ExpectPatches: []string{
+				`{"spec":{"buildImage":{"image":"canonical-registry.io/canonical-repo/build@sha256:new-build-image-digest"}}}`,
+			},
+			ExpectedOutput: `Patching ClusterStack...
+Uploading to 'canonical-registry.io/canonical-repo'...
+	Uploading 'canonical-registry.io/canonical-repo/build@sha256:new-build-image-digest'
+	Uploading 'canonical-registry.io/canonical-repo/run@sha256:run-image-digest'
+ClusterStack "stack-name" patched
+`,
+		}.TestK8sAndKpack(t, cmdFunc)
+		require.Len(t, fakeWaiter.WaitCalls, 1)
+	})
+
+	it("patches only the run image", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				config,
+				stack,
+			},
+			Args: []string{
+				"stack-name",
+				"--run-image", "some-registry.io/repo/new-run",
+			},
+			ExpectPatches: []string{
+				`{"spec":{"runImage":{"image":"canonical-registry.io/canonical-repo/run@sha256:new-run-image-digest"}}}`,
+			},
+			ExpectedOutput: `Patching ClusterStack...
+Uploading to 'canonical-registry.io/canonical-repo'...
+	Uploading 'canonical-registry.io/canonical-repo/build@sha256:build-image-digest'
+	Uploading 'canonical-registry.io/canonical-repo/run@sha256:new-run-image-digest'
+ClusterStack "stack-name" patched
+`,
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("patches only the labels and annotations without uploading images", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				config,
+				stack,
+			},
+			Args: []string{
+				"stack-name",
+				"--label", "team=some-team",
+				"--annotation", "description=some description",
+			},
+			ExpectPatches: []string{
+				`{"metadata":{"annotations":{"description":"some description"},"labels":{"team":"some-team"}}}`,
+			},
+			ExpectedOutput: `Patching ClusterStack...
+ClusterStack "stack-name" patched
+`,
+		}.TestK8sAndKpack(t, cmdFunc)
+		require.Len(t, fakeWaiter.WaitCalls, 1)
+	})
+
+	it("errors when the provided image does not match the stack id of the current image", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				config,
+				stack,
+			},
+			Args: []string{
+				"stack-name",
+				"--run-image", "some-registry.io/repo/other-run",
+			},
+			ExpectErr: true,
+			ExpectedOutput: `Patching ClusterStack...
+Error: build stack 'stack-id' does not match run stack 'other-stack-id'
+`,
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("errors when no image is provided", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				config,
+				stack,
+			},
+			Args:           []string{"stack-name"},
+			ExpectErr:      true,
+			ExpectedOutput: "Error: nothing to patch, provide --build-image or --run-image\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("errors when the provided images are already used by the stack", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				config,
+				stack,
+			},
+			Args: []string{
+				"stack-name",
+				"--build-image", "canonical-registry.io/canonical-repo/build@sha256:build-image-digest",
+			},
+			ExpectErr: true,
+			ExpectedOutput: `Patching ClusterStack...
+Uploading to 'canonical-registry.io/canonical-repo'...
+	Uploading 'canonical-registry.io/canonical-repo/build@sha256:build-image-digest'
+	Uploading 'canonical-registry.io/canonical-repo/run@sha256:run-image-digest'
+Error: nothing to patch, ClusterStack "stack-name" already uses the provided images
+`,
+		}.TestK8sAndKpack(t, cmdFunc)
+		require.Len(t, fakeWaiter.WaitCalls, 0)
+	})
+
+	when("the clusterstack is modified after it is read", func() {
+		it("gets the clusterstack again and patches it without uploading the images again", func() {
+			stale := stack.DeepCopy()
+			stale.ResourceVersion = "1"
+
+			modified := stack.DeepCopy()
+			modified.ResourceVersion = "2"
+			modified.Labels = map[string]string{"some-label": "some-value"}
+
+			modifiedAfterGet := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *kpackfakes.Clientset) *cobra.Command {
+				kpackClientSet.PrependReactor("get", "clusterstacks", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+					if stale == nil {
+						return false, nil, nil
+					}
+					s := stale
+					stale = nil
+					return true, s, nil
+				})
+				kpackClientSet.PrependReactor("patch", "clusterstacks", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+					var patch struct {
+						Metadata metav1.ObjectMeta `json:"metadata"`
+					}
+					if err := json.Unmarshal(action.(clientgotesting.PatchAction).GetPatch(), &patch); err != nil {
+						return true, nil, err
+					}
+
+					if patch.Metadata.ResourceVersion != modified.ResourceVersion {
+						return true, nil, k8serrors.NewConflict(schema.GroupResource{Group: "kpack.io", Resource: "clusterstacks"}, "stack-name", errors.New("the object has been modified"))
+					}
+					return false, nil, nil
+				})
+				return cmdFunc(k8sClientSet, kpackClientSet)
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					config,
+					modified,
+				},
+				Args: []string{
+					"stack-name",
+					"--build-image", "some-registry.io/repo/new-build",
+				},
+				ExpectPatches: []string{
+					`{"metadata":{"resourceVersion":"1"},"spec":{"buildImage":{"image":"canonical-registry.io/canonical-repo/build@sha256:new-build-image-digest"}}}`,
+					`{"metadata":{"resourceVersion":"2"},"spec":{"buildImage":{"image":"canonical-registry.io/canonical-repo/build@sha256:new-build-image-digest"}}}`,
+				},
+				ExpectedOutput: `Patching ClusterStack...
+Uploading to 'canonical-registry.io/canonical-repo'...
+	Uploading 'canonical-registry.io/canonical-repo/build@sha256:new-build-image-digest'
+	Uploading 'canonical-registry.io/canonical-repo/run@sha256:run-image-digest'
+ClusterStack "stack-name" patched
+`,
+			}.TestK8sAndKpack(t, modifiedAfterGet)
+			require.Len(t, fakeWaiter.WaitCalls, 1)
+		})
+	})
+
+	when("dry-run flag is used", func() {
+		it("does not patch the clusterstack and prints the resource output", func() {
+			const resourceYAML = `apiVersion: kpack.io/v1alpha1
+kind: ClusterStack
+metadata:
+  creationTimestamp: null
+  name: stack-name
+spec:
+  buildImage:
+    image: canonical-registry.io/canonical-repo/build@sha256:new-build-image-digest
+  id: stack-id
+  runImage:
+    image: canonical-registry.io/canonical-repo/run@sha256:run-image-digest
+status:
+  buildImage: {}
+  runImage: {}
+`
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					config,
+					stack,
+				},
+				Args: []string{
+					"stack-name",
+					"--build-image", "some-registry.io/repo/new-build",
+					"--dry-run",
+					"--output", "yaml",
+				},
+				ExpectedOutput: resourceYAML,
+				ExpectedErrorOutput: `Patching ClusterStack... (dry run)
+Uploading to 'canonical-registry.io/canonical-repo'... (dry run)
+	Skipping 'canonical-registry.io/canonical-repo/build@sha256:new-build-image-digest'
+	Skipping 'canonical-registry.io/canonical-repo/run@sha256:run-image-digest'
+`,
+			}.TestK8sAndKpack(t, cmdFunc)
+			require.Len(t, fakeWaiter.WaitCalls, 0)
+		})
+	})
+}
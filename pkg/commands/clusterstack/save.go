@@ -21,6 +21,7 @@ func NewSaveCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilPr
 		buildImageRef string
 		runImageRef   string
 		tlsCfg        registry.TLSConfig
+		metadata      k8s.Metadata
 	)
 
 	cmd := &cobra.Command{
@@ -33,6 +34,8 @@ Therefore, you must have credentials to access the registry on your machine.
 Additionally, your cluster must have read access to the registry.
 
 The canonical repository is read from the "canonical.repository" key in the "kp-config" ConfigMap within "kpack" namespace.
+
+The build and run images must have the same stack id, operating system and architecture.
 `,
 		Example: `kp clusterstack save my-stack --build-image my-registry.com/build --run-image my-registry.com/run
 kp clusterstack save my-stack --build-image ../path/to/build.tar --run-image ../path/to/run.tar`,
@@ -45,14 +48,15 @@ kp clusterstack save my-stack --build-image ../path/to/build.tar --run-image ../
 			}
 
 			ctx := cmd.Context()
-			w := newWaiter(cs.DynamicClient)
 
 			ch, err := commands.NewCommandHelper(cmd)
 			if err != nil {
 				return err
 			}
+			w := commands.WithWaitTimeout(newWaiter(cs.DynamicClient), ch.WaitTimeout())
 
 			factory := clusterstack.NewFactory(ch, rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading()), rup.Fetcher(tlsCfg))
+			factory.Metadata = metadata
 
 			name := args[0]
 			cStack, err := cs.KpackClient.KpackV1alpha1().ClusterStacks().Get(ctx, name, metav1.GetOptions{})
@@ -67,7 +71,10 @@ kp clusterstack save my-stack --build-image ../path/to/build.tar --run-image ../
 	}
 	cmd.Flags().StringVarP(&buildImageRef, "build-image", "b", "", "build image tag or local tar file path")
 	cmd.Flags().StringVarP(&runImageRef, "run-image", "r", "", "run image tag or local tar file path")
+	commands.SetMetadataPatchFlags(cmd, &metadata)
 	commands.SetImgUploadDryRunOutputFlags(cmd)
+	commands.SetReadyTimeoutFlag(cmd)
+	commands.SetServerDryRunFlag(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
 	_ = cmd.MarkFlagRequired("build-image")
 	_ = cmd.MarkFlagRequired("run-image")
@@ -23,12 +23,13 @@ func NewStatusCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	)
 
 	cmd := &cobra.Command{
-		Use:          "status <name>",
-		Short:        "Display cluster stack status",
-		Long:         `Prints detailed information about the status of a specific cluster-scoped stack.`,
-		Example:      "kp clusterstack status my-stack",
-		Args:         commands.ExactArgsWithUsage(1),
-		SilenceUsage: true,
+		Use:               "status <name>",
+		Short:             "Display cluster stack status",
+		Long:              `Prints detailed information about the status of a specific cluster-scoped stack.`,
+		Example:           "kp clusterstack status my-stack",
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ClusterStackNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet("")
 			if err != nil {
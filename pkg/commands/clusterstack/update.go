@@ -33,6 +33,7 @@ func NewUpdateCommand(clientSetProvider k8s.ClientSetProvider, rup registry.Util
 		buildImageRef string
 		runImageRef   string
 		tlsCfg        registry.TLSConfig
+		metadata      k8s.Metadata
 	)
 
 	cmd := &cobra.Command{
@@ -44,8 +45,9 @@ The run and build images will be uploaded to the the registry configured on your
 Therefore, you must have credentials to access the registry on your machine.`,
 		Example: `kp clusterstack update my-stack --build-image my-registry.com/build --run-image my-registry.com/run
 kp clusterstack update my-stack --build-image ../path/to/build.tar --run-image ../path/to/run.tar`,
-		Args:         commands.ExactArgsWithUsage(1),
-		SilenceUsage: true,
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ClusterStackNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet("")
 			if err != nil {
@@ -65,14 +67,18 @@ kp clusterstack update my-stack --build-image ../path/to/build.tar --run-image .
 			}
 
 			factory := clusterstack.NewFactory(ch, rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading()), rup.Fetcher(tlsCfg))
+			factory.Metadata = metadata
 
-			return update(ctx, authn.DefaultKeychain, stack, buildImageRef, runImageRef, factory, ch, cs, newWaiter(cs.DynamicClient))
+			return update(ctx, authn.DefaultKeychain, stack, buildImageRef, runImageRef, factory, ch, cs, commands.WithWaitTimeout(newWaiter(cs.DynamicClient), ch.WaitTimeout()))
 		},
 	}
 
 	cmd.Flags().StringVarP(&buildImageRef, "build-image", "b", "", "build image tag or local tar file path")
 	cmd.Flags().StringVarP(&runImageRef, "run-image", "r", "", "run image tag or local tar file path")
+	commands.SetMetadataPatchFlags(cmd, &metadata)
 	commands.SetImgUploadDryRunOutputFlags(cmd)
+	commands.SetReadyTimeoutFlag(cmd)
+	commands.SetServerDryRunFlag(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
 	_ = cmd.MarkFlagRequired("build-image")
 	_ = cmd.MarkFlagRequired("run-image")
@@ -90,18 +96,42 @@ func update(ctx context.Context, keychain authn.Keychain, stack *v1alpha1.Cluste
 		return err
 	}
 
+	name := stack.Name
 	hasUpdates, err := factory.UpdateStack(keychain, stack, buildImageRef, runImageRef, kpConfig)
 	if err != nil {
 		return err
 	}
 
-	if hasUpdates && !ch.IsDryRun() {
-		stack, err = cs.KpackClient.KpackV1alpha1().ClusterStacks().Update(ctx, stack, metav1.UpdateOptions{})
+	if hasUpdates && ch.ShouldSubmit() {
+		retry := false
+		err = commands.RetryOnConflict(func() error {
+			if retry {
+				stack, err = cs.KpackClient.KpackV1alpha1().ClusterStacks().Get(ctx, name, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+
+				hasUpdates, err = factory.UpdateStack(keychain, stack, buildImageRef, runImageRef, kpConfig)
+				if err != nil || !hasUpdates {
+					return err
+				}
+			}
+			retry = true
+
+			updatedStack, err := cs.KpackClient.KpackV1alpha1().ClusterStacks().Update(ctx, stack, ch.UpdateOptions())
+			if err != nil {
+				return err
+			}
+			stack = updatedStack
+			return nil
+		})
 		if err != nil {
 			return err
 		}
-		if err := w.Wait(ctx, stack); err != nil {
-			return err
+		if !ch.IsDryRun() {
+			if err := w.Wait(ctx, stack); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/clusterstore"
@@ -33,14 +34,16 @@ func NewAddCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilPro
 
 Buildpackages will be uploaded to the canonical repository.
 Therefore, you must have credentials to access the registry on your machine.
+Buildpackages with the same digest as an existing store source are skipped.
 
 The canonical repository is read from the "canonical.repository" key in the "kp-config" ConfigMap within "kpack" namespace.
 `,
 		Example: `kp clusterstore add my-store -b my-registry.com/my-buildpackage
 kp clusterstore add my-store -b my-registry.com/my-buildpackage -b my-registry.com/my-other-buildpackage -b my-registry.com/my-third-buildpackage
 kp clusterstore add my-store -b ../path/to/my-local-buildpackage.cnb`,
-		Args:         commands.ExactArgsWithUsage(1),
-		SilenceUsage: true,
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ClusterStoreNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet("")
 			if err != nil {
@@ -66,12 +69,14 @@ kp clusterstore add my-store -b ../path/to/my-local-buildpackage.cnb`,
 			fetcher := rup.Fetcher(tlsCfg)
 			factory := clusterstore.NewFactory(ch, relocator, fetcher)
 
-			return update(ctx, store, buildpackages, factory, ch, cs, newWaiter(cs.DynamicClient))
+			return update(ctx, store, buildpackages, factory, ch, cs, commands.WithWaitTimeout(newWaiter(cs.DynamicClient), ch.WaitTimeout()))
 		},
 	}
 
 	cmd.Flags().StringArrayVarP(&buildpackages, "buildpackage", "b", []string{}, "location of the buildpackage")
 	commands.SetImgUploadDryRunOutputFlags(cmd)
+	commands.SetReadyTimeoutFlag(cmd)
+	commands.SetServerDryRunFlag(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
 	return cmd
 }
@@ -87,18 +92,56 @@ func update(ctx context.Context, store *v1alpha1.ClusterStore, buildpackages []s
 		return err
 	}
 
-	updatedStore, storeUpdated, err := factory.AddToStore(authn.DefaultKeychain, store, kpConfig, buildpackages...)
+	updatedStore, _, err := factory.AddToStore(authn.DefaultKeychain, store, kpConfig, buildpackages...)
 	if err != nil {
 		return err
 	}
 
-	if storeUpdated && !ch.IsDryRun() {
-		updatedStore, err = cs.KpackClient.KpackV1alpha1().ClusterStores().Update(ctx, updatedStore, metav1.UpdateOptions{})
+	// buildpackages already present are skipped so the patch is empty when nothing was added
+	patch, err := k8s.CreatePatch(store, updatedStore)
+	if err != nil {
+		return err
+	}
+
+	storeUpdated := len(patch) > 0
+	if storeUpdated && ch.ShouldSubmit() {
+		addedSources := updatedStore.Spec.Sources[len(store.Spec.Sources):]
+		retry := false
+		err = commands.RetryOnConflict(func() error {
+			current := store
+			if retry {
+				current, err = cs.KpackClient.KpackV1alpha1().ClusterStores().Get(ctx, store.Name, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+
+				updatedStore, err = factory.AddSourcesToStore(current, addedSources)
+				if err != nil {
+					return err
+				}
+
+				patch, err = k8s.CreatePatch(current, updatedStore)
+				if err != nil || len(patch) == 0 {
+					return err
+				}
+			}
+			retry = true
+
+			conditionalPatch, err := k8s.AddResourceVersion(patch, current)
+			if err != nil {
+				return err
+			}
+
+			updatedStore, err = cs.KpackClient.KpackV1alpha1().ClusterStores().Patch(ctx, store.Name, types.MergePatchType, conditionalPatch, ch.PatchOptions())
+			return err
+		})
 		if err != nil {
 			return err
 		}
-		if err := w.Wait(ctx, updatedStore); err != nil {
-			return err
+		if !ch.IsDryRun() {
+			if err := w.Wait(ctx, updatedStore); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -4,6 +4,8 @@
 package clusterstore_test
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
@@ -12,8 +14,10 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	k8sfakes "k8s.io/client-go/kubernetes/fake"
 	clientgotesting "k8s.io/client-go/testing"
@@ -94,19 +98,8 @@ func testClusterStoreAddCommand(t *testing.T, when spec.G, it spec.S) {
 				"--registry-verify-certs",
 			},
 			ExpectErr: false,
-			ExpectUpdates: []clientgotesting.UpdateActionImpl{
-				{
-					Object: &v1alpha1.ClusterStore{
-						ObjectMeta: existingStore.ObjectMeta,
-						Spec: v1alpha1.ClusterStoreSpec{
-							Sources: []v1alpha1.StoreImage{
-								{Image: "canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest"},
-								{Image: "canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest"},
-								{Image: "canonical-registry.io/canonical-repo/sample_buildpackage@sha256:37d646bec2453ab05fe57288ede904dfd12f988dbc964e3e764c41c1bd3b58bf"},
-							},
-						},
-					},
-				},
+			ExpectPatches: []string{
+				`{"spec":{"sources":[{"image":"canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest"},{"image":"canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest"},{"image":"canonical-registry.io/canonical-repo/sample_buildpackage@sha256:37d646bec2453ab05fe57288ede904dfd12f988dbc964e3e764c41c1bd3b58bf"}]}}`,
 			},
 			ExpectedOutput: `Adding to ClusterStore...
 	Uploading 'canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest'
@@ -132,7 +125,7 @@ ClusterStore "store-name" updated
 			ExpectErr: false,
 			ExpectedOutput: `Adding to ClusterStore...
 	Uploading 'canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest'
-	Buildpackage already exists in the store
+	Buildpackage already present, skipping
 ClusterStore "store-name" updated (no change)
 `,
 		}.TestK8sAndKpack(t, cmdFunc)
@@ -190,6 +183,63 @@ ClusterStore "store-name" updated (no change)
 		}.TestK8sAndKpack(t, cmdFunc)
 	})
 
+	when("the clusterstore is modified after it is read", func() {
+		it("gets the clusterstore again and adds the uploaded buildpackage without uploading it again", func() {
+			stale := existingStore.DeepCopy()
+			stale.ResourceVersion = "1"
+
+			modified := existingStore.DeepCopy()
+			modified.ResourceVersion = "2"
+			modified.Spec.Sources = append(modified.Spec.Sources, v1alpha1.StoreImage{Image: "canonical-registry.io/canonical-repo/other-buildpack-id@sha256:other-buildpack-digest"})
+
+			modifiedAfterGet := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *kpackfakes.Clientset) *cobra.Command {
+				kpackClientSet.PrependReactor("get", "clusterstores", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+					if stale == nil {
+						return false, nil, nil
+					}
+					s := stale
+					stale = nil
+					return true, s, nil
+				})
+				kpackClientSet.PrependReactor("patch", "clusterstores", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+					var patch struct {
+						Metadata v1.ObjectMeta `json:"metadata"`
+					}
+					if err := json.Unmarshal(action.(clientgotesting.PatchAction).GetPatch(), &patch); err != nil {
+						return true, nil, err
+					}
+
+					if patch.Metadata.ResourceVersion != modified.ResourceVersion {
+						return true, nil, k8serrors.NewConflict(schema.GroupResource{Group: "kpack.io", Resource: "clusterstores"}, "store-name", errors.New("the object has been modified"))
+					}
+					return false, nil, nil
+				})
+				return cmdFunc(k8sClientSet, kpackClientSet)
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					config,
+					modified,
+				},
+				Args: []string{
+					"store-name",
+					"--buildpackage", "some-registry.io/repo/new-buildpack",
+				},
+				ExpectPatches: []string{
+					`{"metadata":{"resourceVersion":"1"},"spec":{"sources":[{"image":"canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest"},{"image":"canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest"}]}}`,
+					`{"metadata":{"resourceVersion":"2"},"spec":{"sources":[{"image":"canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest"},{"image":"canonical-registry.io/canonical-repo/other-buildpack-id@sha256:other-buildpack-digest"},{"image":"canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest"}]}}`,
+				},
+				ExpectedOutput: `Adding to ClusterStore...
+	Uploading 'canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest'
+	Added Buildpackage
+ClusterStore "store-name" updated
+`,
+			}.TestK8sAndKpack(t, modifiedAfterGet)
+			require.Len(t, fakeWaiter.WaitCalls, 1)
+		})
+	})
+
 	when("output flag is used", func() {
 		it("can output in yaml format", func() {
 			const resourceYAML = `apiVersion: kpack.io/v1alpha1
@@ -217,19 +267,8 @@ status: {}
 					"--output", "yaml",
 				},
 				ExpectErr: false,
-				ExpectUpdates: []clientgotesting.UpdateActionImpl{
-					{
-						Object: &v1alpha1.ClusterStore{
-							ObjectMeta: existingStore.ObjectMeta,
-							Spec: v1alpha1.ClusterStoreSpec{
-								Sources: []v1alpha1.StoreImage{
-									{Image: "canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest"},
-									{Image: "canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest"},
-									{Image: "canonical-registry.io/canonical-repo/sample_buildpackage@sha256:37d646bec2453ab05fe57288ede904dfd12f988dbc964e3e764c41c1bd3b58bf"},
-								},
-							},
-						},
-					},
+				ExpectPatches: []string{
+					`{"spec":{"sources":[{"image":"canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest"},{"image":"canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest"},{"image":"canonical-registry.io/canonical-repo/sample_buildpackage@sha256:37d646bec2453ab05fe57288ede904dfd12f988dbc964e3e764c41c1bd3b58bf"}]}}`,
 				},
 				ExpectedOutput: resourceYAML,
 				ExpectedErrorOutput: `Adding to ClusterStore...
@@ -277,19 +316,8 @@ status: {}
 					"-b", localCNBPath,
 					"--output", "json",
 				},
-				ExpectUpdates: []clientgotesting.UpdateActionImpl{
-					{
-						Object: &v1alpha1.ClusterStore{
-							ObjectMeta: existingStore.ObjectMeta,
-							Spec: v1alpha1.ClusterStoreSpec{
-								Sources: []v1alpha1.StoreImage{
-									{Image: "canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest"},
-									{Image: "canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest"},
-									{Image: "canonical-registry.io/canonical-repo/sample_buildpackage@sha256:37d646bec2453ab05fe57288ede904dfd12f988dbc964e3e764c41c1bd3b58bf"},
-								},
-							},
-						},
-					},
+				ExpectPatches: []string{
+					`{"spec":{"sources":[{"image":"canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest"},{"image":"canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest"},{"image":"canonical-registry.io/canonical-repo/sample_buildpackage@sha256:37d646bec2453ab05fe57288ede904dfd12f988dbc964e3e764c41c1bd3b58bf"}]}}`,
 				},
 				ExpectedOutput: resourceJSON,
 				ExpectedErrorOutput: `Adding to ClusterStore...
@@ -327,7 +355,7 @@ status: {}
 					ExpectErr: false,
 					ExpectedErrorOutput: `Adding to ClusterStore...
 	Uploading 'canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest'
-	Buildpackage already exists in the store
+	Buildpackage already present, skipping
 `,
 					ExpectedOutput: resourceYAML,
 				}.TestK8sAndKpack(t, cmdFunc)
@@ -374,7 +402,7 @@ ClusterStore "store-name" updated (dry run)
 					ExpectErr: false,
 					ExpectedOutput: `Adding to ClusterStore... (dry run)
 	Skipping 'canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest'
-	Buildpackage already exists in the store
+	Buildpackage already present, skipping
 ClusterStore "store-name" updated (dry run)
 `,
 				}.TestK8sAndKpack(t, cmdFunc)
@@ -458,7 +486,7 @@ ClusterStore "store-name" updated (dry run with image upload)
 					ExpectErr: false,
 					ExpectedOutput: `Adding to ClusterStore... (dry run with image upload)
 	Uploading 'canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest'
-	Buildpackage already exists in the store
+	Buildpackage already present, skipping
 ClusterStore "store-name" updated (dry run with image upload)
 `,
 				}.TestK8sAndKpack(t, cmdFunc)
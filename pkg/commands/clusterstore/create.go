@@ -8,7 +8,6 @@ import (
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/spf13/cobra"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/dynamic"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/clusterstore"
@@ -21,6 +20,7 @@ func NewCreateCommand(clientSetProvider k8s.ClientSetProvider, rup registry.Util
 	var (
 		buildpackages []string
 		tlsCfg        registry.TLSConfig
+		metadata      k8s.Metadata
 	)
 
 	cmd := &cobra.Command{
@@ -53,14 +53,18 @@ kp clusterstore create my-store -b ../path/to/my-local-buildpackage.cnb`,
 			ctx := cmd.Context()
 
 			factory := clusterstore.NewFactory(ch, rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading()), rup.Fetcher(tlsCfg))
+			factory.Metadata = metadata
 
 			name := args[0]
-			return create(ctx, name, buildpackages, factory, ch, cs, newWaiter(cs.DynamicClient))
+			return create(ctx, name, buildpackages, factory, ch, cs, commands.WithWaitTimeout(newWaiter(cs.DynamicClient), ch.WaitTimeout()))
 		},
 	}
 
 	cmd.Flags().StringArrayVarP(&buildpackages, "buildpackage", "b", []string{}, "location of the buildpackage")
+	commands.SetMetadataFlags(cmd, &metadata)
 	commands.SetImgUploadDryRunOutputFlags(cmd)
+	commands.SetReadyTimeoutFlag(cmd)
+	commands.SetServerDryRunFlag(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
 	return cmd
 }
@@ -81,13 +85,15 @@ func create(ctx context.Context, name string, buildpackages []string, factory *c
 		return err
 	}
 
-	if !ch.IsDryRun() {
-		newStore, err = cs.KpackClient.KpackV1alpha1().ClusterStores().Create(ctx, newStore, metav1.CreateOptions{})
+	if ch.ShouldSubmit() {
+		newStore, err = cs.KpackClient.KpackV1alpha1().ClusterStores().Create(ctx, newStore, ch.CreateOptions())
 		if err != nil {
 			return err
 		}
-		if err := w.Wait(ctx, newStore); err != nil {
-			return err
+		if !ch.IsDryRun() {
+			if err := w.Wait(ctx, newStore); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -12,6 +12,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 )
 
@@ -29,12 +30,13 @@ func NewDeleteCommand(clientSetProvider k8s.ClientSetProvider, confirmationProvi
 	)
 
 	cmd := &cobra.Command{
-		Use:          "delete <store>",
-		Short:        "Delete a cluster store",
-		Long:         fmt.Sprintf("Delete a specific cluster-scoped buildpack store.\n\n%s", warningMessage),
-		Example:      `kp clusterstore delete my-store`,
-		Args:         cobra.MinimumNArgs(1),
-		SilenceUsage: true,
+		Use:               "delete <store>",
+		Short:             "Delete a cluster store",
+		Long:              fmt.Sprintf("Delete a specific cluster-scoped buildpack store.\n\n%s", warningMessage),
+		Example:           `kp clusterstore delete my-store`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: commands.ClusterStoreNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet("")
 			if err != nil {
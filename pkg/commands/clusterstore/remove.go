@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
@@ -21,15 +22,21 @@ func NewRemoveCommand(clientSetProvider k8s.ClientSetProvider, newWaiter func(dy
 	var buildpackages []string
 
 	cmd := &cobra.Command{
-		Use:   "remove <store> -b <buildpackage> [-b <buildpackage>...]",
-		Short: "Remove buildpackage(s) from cluster store",
+		Use:     "remove <store> -b <buildpackage> [-b <buildpackage>...]",
+		Aliases: []string{"remove-buildpack"},
+		Short:   "Remove buildpackage(s) from cluster store",
 		Long: `Removes existing buildpackage(s) from a specific cluster-scoped buildpack store.
+
+A buildpackage is either the id and version of a buildpackage in the store, or a source image of the store.
+A warning is printed for buildpackages that are not in the store.
 `,
 		Example: `kp clusterstore remove my-store -b buildpackage@1.0.0
 kp clusterstore remove my-store -b buildpackage@1.0.0 -b other-buildpackage@2.0.0
+kp clusterstore remove my-store -b my-registry.com/my-buildpackage@sha256:1a2b3c
 `,
-		Args:         commands.ExactArgsWithUsage(1),
-		SilenceUsage: true,
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ClusterStoreNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet("")
 			if err != nil {
@@ -37,12 +44,12 @@ kp clusterstore remove my-store -b buildpackage@1.0.0 -b other-buildpackage@2.0.
 			}
 
 			ctx := cmd.Context()
-			w := newWaiter(cs.DynamicClient)
 
 			ch, err := commands.NewCommandHelper(cmd)
 			if err != nil {
 				return err
 			}
+			w := commands.WithWaitTimeout(newWaiter(cs.DynamicClient), ch.WaitTimeout())
 
 			storeName := args[0]
 
@@ -53,44 +60,71 @@ kp clusterstore remove my-store -b buildpackage@1.0.0 -b other-buildpackage@2.0.
 				return err
 			}
 
-			bpToStoreImage := map[string]v1alpha1.StoreImage{}
-			for _, bp := range buildpackages {
-				if storeImage, ok := getStoreImage(store, bp); !ok {
-					return errors.Errorf("Buildpackage '%s' does not exist in the ClusterStore", bp)
-				} else {
-					bpToStoreImage[bp] = storeImage
-				}
-			}
-
 			if err = ch.PrintStatus("Removing Buildpackages..."); err != nil {
 				return err
 			}
 
-			removeBuildpackages(ch, store, buildpackages, bpToStoreImage)
+			retry := false
+			return commands.RetryOnConflict(func() error {
+				if retry {
+					store, err = cs.KpackClient.KpackV1alpha1().ClusterStores().Get(ctx, storeName, metav1.GetOptions{})
+					if err != nil {
+						return err
+					}
+				}
+				retry = true
 
-			if !ch.IsDryRun() {
-				store, err = cs.KpackClient.KpackV1alpha1().ClusterStores().Update(ctx, store, metav1.UpdateOptions{})
+				patchedStore := store.DeepCopy()
+				if err := removeBuildpackages(ch, patchedStore, buildpackages); err != nil {
+					return err
+				}
+
+				patch, err := k8s.CreatePatch(store, patchedStore)
 				if err != nil {
 					return err
 				}
-				if err := w.Wait(ctx, store); err != nil {
+
+				patch, err = k8s.AddResourceVersion(patch, store)
+				if err != nil {
 					return err
 				}
-			}
 
-			if err = ch.PrintObj(store); err != nil {
-				return err
-			}
+				hasPatch := len(patch) > 0
+				if hasPatch && ch.ShouldSubmit() {
+					patchedStore, err = cs.KpackClient.KpackV1alpha1().ClusterStores().Patch(ctx, storeName, types.MergePatchType, patch, ch.PatchOptions())
+					if err != nil {
+						return err
+					}
+					if !ch.IsDryRun() {
+						if err := w.Wait(ctx, patchedStore); err != nil {
+							return err
+						}
+					}
+				}
+
+				if err = ch.PrintObj(patchedStore); err != nil {
+					return err
+				}
 
-			return ch.PrintResult("ClusterStore %q updated", store.Name)
+				return ch.PrintChangeResult(hasPatch, "ClusterStore %q updated", patchedStore.Name)
+			})
 		},
 	}
-	cmd.Flags().StringArrayVarP(&buildpackages, "buildpackage", "b", []string{}, "buildpackage to remove")
+	cmd.Flags().StringArrayVarP(&buildpackages, "buildpackage", "b", []string{}, "buildpackage id@version or source image to remove")
 	commands.SetDryRunOutputFlags(cmd)
+	commands.SetReadyTimeoutFlag(cmd)
 	return cmd
 }
 
+// getStoreImage finds the source of the store that matches a source image
+// or the id@version of a buildpackage in the store status
 func getStoreImage(store *v1alpha1.ClusterStore, buildpackage string) (v1alpha1.StoreImage, bool) {
+	for _, source := range store.Spec.Sources {
+		if source.Image == buildpackage {
+			return source, true
+		}
+	}
+
 	for _, bp := range store.Status.Buildpacks {
 		if fmt.Sprintf("%s@%s", bp.Id, bp.Version) == buildpackage {
 			return bp.StoreImage, true
@@ -99,15 +133,26 @@ func getStoreImage(store *v1alpha1.ClusterStore, buildpackage string) (v1alpha1.
 	return v1alpha1.StoreImage{}, false
 }
 
-func removeBuildpackages(ch *commands.CommandHelper, store *v1alpha1.ClusterStore, buildpackages []string, bpToStoreImage map[string]v1alpha1.StoreImage) {
+func removeBuildpackages(ch *commands.CommandHelper, store *v1alpha1.ClusterStore, buildpackages []string) error {
 	for _, bp := range buildpackages {
-		ch.Printlnf("Removing buildpackage %s", bp)
+		storeImage, ok := getStoreImage(store, bp)
+		if !ok {
+			if err := ch.Printlnf("Warning: buildpackage '%s' does not exist in the ClusterStore", bp); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := ch.Printlnf("Removing buildpackage %s", bp); err != nil {
+			return err
+		}
 
 		for i, img := range store.Spec.Sources {
-			if img.Image == bpToStoreImage[bp].Image {
+			if img.Image == storeImage.Image {
 				store.Spec.Sources = append(store.Spec.Sources[:i], store.Spec.Sources[i+1:]...)
 				break
 			}
 		}
 	}
+	return nil
 }
@@ -14,7 +14,6 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
-	clientgotesting "k8s.io/client-go/testing"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands/clusterstore"
@@ -90,20 +89,8 @@ func testClusterStoreRemoveCommand(t *testing.T, when spec.G, it spec.S) {
 				"--buildpackage", "some-buildpackage@1.2.3",
 			},
 			ExpectErr: false,
-			ExpectUpdates: []clientgotesting.UpdateActionImpl{
-				{
-					Object: &v1alpha1.ClusterStore{
-						ObjectMeta: store.ObjectMeta,
-						Spec: v1alpha1.ClusterStoreSpec{
-							Sources: []v1alpha1.StoreImage{
-								{
-									Image: image2InStore,
-								},
-							},
-						},
-						Status: store.Status,
-					},
-				},
+			ExpectPatches: []string{
+				`{"spec":{"sources":[{"image":"some/imageinStore2@sha256:1232alreadyInStore"}]}}`,
 			},
 			ExpectedOutput: `Removing Buildpackages...
 Removing buildpackage some-buildpackage@1.2.3
@@ -124,16 +111,8 @@ ClusterStore "some-store" updated
 				"-b", "another-buildpackage@4.5.6",
 			},
 			ExpectErr: false,
-			ExpectUpdates: []clientgotesting.UpdateActionImpl{
-				{
-					Object: &v1alpha1.ClusterStore{
-						ObjectMeta: store.ObjectMeta,
-						Spec: v1alpha1.ClusterStoreSpec{
-							Sources: []v1alpha1.StoreImage{},
-						},
-						Status: store.Status,
-					},
-				},
+			ExpectPatches: []string{
+				`{"spec":{"sources":null}}`,
 			},
 			ExpectedOutput: `Removing Buildpackages...
 Removing buildpackage some-buildpackage@1.2.3
@@ -158,7 +137,48 @@ ClusterStore "some-store" updated
 		}.TestKpack(t, cmdFunc)
 	})
 
-	it("fails if even one buildpackage is not in the store but the rest are", func() {
+	it("removes a buildpackage by its source image", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				store,
+			},
+			Args: []string{
+				storeName,
+				"-b", image1InStore,
+			},
+			ExpectPatches: []string{
+				`{"spec":{"sources":[{"image":"some/imageinStore2@sha256:1232alreadyInStore"}]}}`,
+			},
+			ExpectedOutput: `Removing Buildpackages...
+Removing buildpackage some/imageinStore1@sha256:1231alreadyInStore
+ClusterStore "some-store" updated
+`,
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("removes the last buildpackage of the store", func() {
+		singleSourceStore := store.DeepCopy()
+		singleSourceStore.Spec.Sources = singleSourceStore.Spec.Sources[:1]
+
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				singleSourceStore,
+			},
+			Args: []string{
+				storeName,
+				"-b", "some-buildpackage@1.2.3",
+			},
+			ExpectPatches: []string{
+				`{"spec":{"sources":null}}`,
+			},
+			ExpectedOutput: `Removing Buildpackages...
+Removing buildpackage some-buildpackage@1.2.3
+ClusterStore "some-store" updated
+`,
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("warns about buildpackages that are not in the store and removes the rest", func() {
 		testhelpers.CommandTest{
 			Objects: []runtime.Object{
 				store,
@@ -168,12 +188,18 @@ ClusterStore "some-store" updated
 				"-b", "some-buildpackage@1.2.3",
 				"-b", "does-not-exist-buildpackage@7.8.9",
 			},
-			ExpectErr:      true,
-			ExpectedOutput: "Error: Buildpackage 'does-not-exist-buildpackage@7.8.9' does not exist in the ClusterStore\n",
+			ExpectPatches: []string{
+				`{"spec":{"sources":[{"image":"some/imageinStore2@sha256:1232alreadyInStore"}]}}`,
+			},
+			ExpectedOutput: `Removing Buildpackages...
+Removing buildpackage some-buildpackage@1.2.3
+Warning: buildpackage 'does-not-exist-buildpackage@7.8.9' does not exist in the ClusterStore
+ClusterStore "some-store" updated
+`,
 		}.TestKpack(t, cmdFunc)
 	})
 
-	it("returns error if buildpackage does not exist in store", func() {
+	it("warns and does not patch the store when the buildpackage does not exist in store", func() {
 		testhelpers.CommandTest{
 			Objects: []runtime.Object{
 				store,
@@ -182,9 +208,12 @@ ClusterStore "some-store" updated
 				storeName,
 				"-b", "does-not-exist-buildpackage@7.8.9",
 			},
-			ExpectErr:      true,
-			ExpectedOutput: "Error: Buildpackage 'does-not-exist-buildpackage@7.8.9' does not exist in the ClusterStore\n",
+			ExpectedOutput: `Removing Buildpackages...
+Warning: buildpackage 'does-not-exist-buildpackage@7.8.9' does not exist in the ClusterStore
+ClusterStore "some-store" updated (no change)
+`,
 		}.TestKpack(t, cmdFunc)
+		require.Len(t, fakeWaiter.WaitCalls, 0)
 	})
 
 	when("output flag is used", func() {
@@ -220,20 +249,8 @@ status:
 					"--buildpackage", "some-buildpackage@1.2.3",
 					"--output", "yaml",
 				},
-				ExpectUpdates: []clientgotesting.UpdateActionImpl{
-					{
-						Object: &v1alpha1.ClusterStore{
-							ObjectMeta: store.ObjectMeta,
-							Spec: v1alpha1.ClusterStoreSpec{
-								Sources: []v1alpha1.StoreImage{
-									{
-										Image: image2InStore,
-									},
-								},
-							},
-							Status: store.Status,
-						},
-					},
+				ExpectPatches: []string{
+					`{"spec":{"sources":[{"image":"some/imageinStore2@sha256:1232alreadyInStore"}]}}`,
 				},
 				ExpectedOutput: resourceYAML,
 				ExpectedErrorOutput: `Removing Buildpackages...
@@ -254,20 +271,8 @@ Removing buildpackage some-buildpackage@1.2.3
 					"--buildpackage", "some-buildpackage@1.2.3",
 					"--output", "json",
 				},
-				ExpectUpdates: []clientgotesting.UpdateActionImpl{
-					{
-						Object: &v1alpha1.ClusterStore{
-							ObjectMeta: store.ObjectMeta,
-							Spec: v1alpha1.ClusterStoreSpec{
-								Sources: []v1alpha1.StoreImage{
-									{
-										Image: image2InStore,
-									},
-								},
-							},
-							Status: store.Status,
-						},
-					},
+				ExpectPatches: []string{
+					`{"spec":{"sources":[{"image":"some/imageinStore2@sha256:1232alreadyInStore"}]}}`,
 				},
 				ExpectedOutput: resourceJSON,
 				ExpectedErrorOutput: `Removing Buildpackages...
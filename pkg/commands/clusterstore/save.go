@@ -19,6 +19,7 @@ func NewSaveCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilPr
 	var (
 		buildpackages []string
 		tlsCfg        registry.TLSConfig
+		metadata      k8s.Metadata
 	)
 
 	cmd := &cobra.Command{
@@ -44,15 +45,16 @@ kp clusterstore save my-store -b ../path/to/my-local-buildpackage.cnb`,
 			}
 
 			ctx := cmd.Context()
-			w := newWaiter(cs.DynamicClient)
 
 			ch, err := commands.NewCommandHelper(cmd)
 			if err != nil {
 				return err
 			}
+			w := commands.WithWaitTimeout(newWaiter(cs.DynamicClient), ch.WaitTimeout())
 
 			name := args[0]
 			factory := clusterstore.NewFactory(ch, rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading()), rup.Fetcher(tlsCfg))
+			factory.Metadata = metadata
 
 			clusterStore, err := cs.KpackClient.KpackV1alpha1().ClusterStores().Get(ctx, name, metav1.GetOptions{})
 			if k8serrors.IsNotFound(err) {
@@ -66,7 +68,10 @@ kp clusterstore save my-store -b ../path/to/my-local-buildpackage.cnb`,
 	}
 
 	cmd.Flags().StringArrayVarP(&buildpackages, "buildpackage", "b", []string{}, "location of the buildpackage")
+	commands.SetMetadataPatchFlags(cmd, &metadata)
 	commands.SetImgUploadDryRunOutputFlags(cmd)
+	commands.SetReadyTimeoutFlag(cmd)
+	commands.SetServerDryRunFlag(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
 	return cmd
 }
@@ -16,7 +16,6 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
 	k8sfakes "k8s.io/client-go/kubernetes/fake"
-	clientgotesting "k8s.io/client-go/testing"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	storecmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/clusterstore"
@@ -402,19 +401,8 @@ status: {}
 					"--registry-verify-certs",
 				},
 				ExpectErr: false,
-				ExpectUpdates: []clientgotesting.UpdateActionImpl{
-					{
-						Object: &v1alpha1.ClusterStore{
-							ObjectMeta: existingStore.ObjectMeta,
-							Spec: v1alpha1.ClusterStoreSpec{
-								Sources: []v1alpha1.StoreImage{
-									{Image: "canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest"},
-									{Image: "canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest"},
-									{Image: "canonical-registry.io/canonical-repo/sample_buildpackage@sha256:37d646bec2453ab05fe57288ede904dfd12f988dbc964e3e764c41c1bd3b58bf"},
-								},
-							},
-						},
-					},
+				ExpectPatches: []string{
+					`{"spec":{"sources":[{"image":"canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest"},{"image":"canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest"},{"image":"canonical-registry.io/canonical-repo/sample_buildpackage@sha256:37d646bec2453ab05fe57288ede904dfd12f988dbc964e3e764c41c1bd3b58bf"}]}}`,
 				},
 				ExpectedOutput: `Adding to ClusterStore...
 	Uploading 'canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest'
@@ -454,19 +442,8 @@ status: {}
 						"--output", "yaml",
 					},
 					ExpectErr: false,
-					ExpectUpdates: []clientgotesting.UpdateActionImpl{
-						{
-							Object: &v1alpha1.ClusterStore{
-								ObjectMeta: existingStore.ObjectMeta,
-								Spec: v1alpha1.ClusterStoreSpec{
-									Sources: []v1alpha1.StoreImage{
-										{Image: "canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest"},
-										{Image: "canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest"},
-										{Image: "canonical-registry.io/canonical-repo/sample_buildpackage@sha256:37d646bec2453ab05fe57288ede904dfd12f988dbc964e3e764c41c1bd3b58bf"},
-									},
-								},
-							},
-						},
+					ExpectPatches: []string{
+						`{"spec":{"sources":[{"image":"canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest"},{"image":"canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest"},{"image":"canonical-registry.io/canonical-repo/sample_buildpackage@sha256:37d646bec2453ab05fe57288ede904dfd12f988dbc964e3e764c41c1bd3b58bf"}]}}`,
 					},
 					ExpectedOutput: resourceYAML,
 					ExpectedErrorOutput: `Adding to ClusterStore...
@@ -514,19 +491,8 @@ status: {}
 						"-b", localCNBPath,
 						"--output", "json",
 					},
-					ExpectUpdates: []clientgotesting.UpdateActionImpl{
-						{
-							Object: &v1alpha1.ClusterStore{
-								ObjectMeta: existingStore.ObjectMeta,
-								Spec: v1alpha1.ClusterStoreSpec{
-									Sources: []v1alpha1.StoreImage{
-										{Image: "canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest"},
-										{Image: "canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest"},
-										{Image: "canonical-registry.io/canonical-repo/sample_buildpackage@sha256:37d646bec2453ab05fe57288ede904dfd12f988dbc964e3e764c41c1bd3b58bf"},
-									},
-								},
-							},
-						},
+					ExpectPatches: []string{
+						`{"spec":{"sources":[{"image":"canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest"},{"image":"canonical-registry.io/canonical-repo/new-buildpack-id@sha256:new-buildpack-digest"},{"image":"canonical-registry.io/canonical-repo/sample_buildpackage@sha256:37d646bec2453ab05fe57288ede904dfd12f988dbc964e3e764c41c1bd3b58bf"}]}}`,
 					},
 					ExpectedOutput: resourceJSON,
 					ExpectedErrorOutput: `Adding to ClusterStore...
@@ -564,7 +530,7 @@ status: {}
 						ExpectErr: false,
 						ExpectedErrorOutput: `Adding to ClusterStore...
 	Uploading 'canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest'
-	Buildpackage already exists in the store
+	Buildpackage already present, skipping
 `,
 						ExpectedOutput: resourceYAML,
 					}.TestK8sAndKpack(t, cmdFunc)
@@ -611,7 +577,7 @@ ClusterStore "store-name" updated (dry run)
 						ExpectErr: false,
 						ExpectedOutput: `Adding to ClusterStore... (dry run)
 	Skipping 'canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest'
-	Buildpackage already exists in the store
+	Buildpackage already present, skipping
 ClusterStore "store-name" updated (dry run)
 `,
 					}.TestK8sAndKpack(t, cmdFunc)
@@ -695,7 +661,7 @@ ClusterStore "store-name" updated (dry run with image upload)
 						ExpectErr: false,
 						ExpectedOutput: `Adding to ClusterStore... (dry run with image upload)
 	Uploading 'canonical-registry.io/canonical-repo/old-buildpack-id@sha256:old-buildpack-digest'
-	Buildpackage already exists in the store
+	Buildpackage already present, skipping
 ClusterStore "store-name" updated (dry run with image upload)
 `,
 					}.TestK8sAndKpack(t, cmdFunc)
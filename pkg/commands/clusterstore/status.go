@@ -24,12 +24,13 @@ func NewStatusCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	)
 
 	cmd := &cobra.Command{
-		Use:          "status <store-name>",
-		Short:        "Display cluster store status",
-		Long:         `Prints information about the status of a specific cluster-scoped store.`,
-		Example:      "kp clusterstore status my-store",
-		Args:         commands.ExactArgsWithUsage(1),
-		SilenceUsage: true,
+		Use:               "status <store-name>",
+		Short:             "Display cluster store status",
+		Long:              `Prints information about the status of a specific cluster-scoped store.`,
+		Example:           "kp clusterstore status my-store",
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ClusterStoreNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet("")
 			if err != nil {
@@ -41,15 +42,21 @@ func NewStatusCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 				return err
 			}
 
+			color, err := commands.ColorEnabled(cmd)
+			if err != nil {
+				return err
+			}
+
 			if verbose {
-				return displayBuildpackagesDetailed(cmd.OutOrStdout(), store)
+				return displayBuildpackagesDetailed(cmd.OutOrStdout(), store, color)
 			} else {
-				return displayBuildpackages(cmd.OutOrStdout(), store)
+				return displayBuildpackages(cmd.OutOrStdout(), store, color)
 			}
 		},
 	}
 
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "includes buildpacks and detection order")
+	commands.SetNoColorFlag(cmd)
 	return cmd
 }
 
@@ -59,8 +66,12 @@ type buildpackageInfo struct {
 	homepage string
 }
 
-func displayStatus(out io.Writer, s *v1alpha1.ClusterStore) error {
+func displayStatus(out io.Writer, s *v1alpha1.ClusterStore, color bool) error {
 	statusWriter := commands.NewStatusWriter(out)
+	if color {
+		statusWriter.ColorizeStatus("Status")
+	}
+
 	status := getStatusText(s)
 	if err := statusWriter.AddBlock("", "Status", status); err != nil {
 		return err
@@ -79,8 +90,8 @@ func getStatusText(s *v1alpha1.ClusterStore) string {
 	return "Unknown"
 }
 
-func displayBuildpackages(out io.Writer, s *v1alpha1.ClusterStore) error {
-	if err := displayStatus(out, s); err != nil {
+func displayBuildpackages(out io.Writer, s *v1alpha1.ClusterStore, color bool) error {
+	if err := displayStatus(out, s, color); err != nil {
 		return err
 	}
 
@@ -104,8 +115,8 @@ func displayBuildpackages(out io.Writer, s *v1alpha1.ClusterStore) error {
 	return writer.Write()
 }
 
-func displayBuildpackagesDetailed(out io.Writer, s *v1alpha1.ClusterStore) error {
-	if err := displayStatus(out, s); err != nil {
+func displayBuildpackagesDetailed(out io.Writer, s *v1alpha1.ClusterStore, color bool) error {
+	if err := displayStatus(out, s, color); err != nil {
 		return err
 	}
 
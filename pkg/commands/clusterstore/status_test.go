@@ -5,6 +5,7 @@ package clusterstore_test
 
 import (
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
@@ -16,6 +17,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands/clusterstore"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
 )
@@ -25,6 +27,8 @@ func TestStatusCommand(t *testing.T) {
 }
 
 func testStatusCommand(t *testing.T, when spec.G, it spec.S) {
+	isTerminal := commands.IsTerminal
+
 	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
 		clientSetProvider := testhelpers.GetFakeKpackClusterProvider(clientSet)
 		return clusterstore.NewStatusCommand(clientSetProvider)
@@ -168,6 +172,30 @@ simple-buildpack    3          simple-3-buildpackage-homepage
 					ExpectedOutput: expectedOutput,
 				}.TestKpack(t, cmdFunc)
 			})
+
+			it("colors the status when the output is a terminal", func() {
+				commands.IsTerminal = func(io.Writer) bool { return true }
+				defer func() { commands.IsTerminal = isTerminal }()
+
+				store.Status.Conditions = append(store.Status.Conditions, corev1alpha1.Condition{
+					Type:    corev1alpha1.ConditionReady,
+					Status:  corev1.ConditionFalse,
+					Message: "some sample message",
+				})
+
+				const expectedOutput = "Status:    \x1b[31mNot Ready - some sample message\x1b[0m\n" +
+					"\n" +
+					"BUILDPACKAGE ID     VERSION    HOMEPAGE\n" +
+					"meta                1          meta-1-buildpackage-homepage\n" +
+					"simple-buildpack    3          simple-3-buildpackage-homepage\n" +
+					"\n"
+
+				testhelpers.CommandTest{
+					Objects:        append([]runtime.Object{store}),
+					Args:           []string{storeName},
+					ExpectedOutput: expectedOutput,
+				}.TestKpack(t, cmdFunc)
+			})
 		})
 	})
 
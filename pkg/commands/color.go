@@ -0,0 +1,84 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	NoColorFlag = "no-color"
+	noColorEnv  = "NO_COLOR"
+
+	colorReset   = "\x1b[0m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorDefault = "\x1b[39m"
+)
+
+// statusColors holds the lower case ready and build status values printed by the tables and status blocks
+var statusColors = map[string]string{
+	"true":      colorGreen,
+	"ready":     colorGreen,
+	"success":   colorGreen,
+	"false":     colorRed,
+	"not ready": colorRed,
+	"failure":   colorRed,
+	"unknown":   colorYellow,
+	"building":  colorYellow,
+}
+
+// IsTerminal reports whether out is a terminal, tests replace it to mock the check
+var IsTerminal = func(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// ColorEnabled reports whether the output of the command is colored, colors are turned off
+// with the --no-color flag or the NO_COLOR environment variable and when the output is not a terminal
+func ColorEnabled(cmd *cobra.Command) (bool, error) {
+	noColor, err := GetBoolFlag(NoColorFlag, cmd)
+	if err != nil {
+		return false, err
+	}
+
+	if noColor || os.Getenv(noColorEnv) != "" {
+		return false, nil
+	}
+	return IsTerminal(cmd.OutOrStdout()), nil
+}
+
+// colorize colors the ready and succeeded status values green, the not ready and failed values red
+// and the unknown and building values yellow, ignoring the case and a " - <message>" suffix. Other values
+// are wrapped in the default color so that every value of a column has escape sequences of
+// the same length, tabwriter counts them as characters and would otherwise misalign the column.
+func colorize(value string) string {
+	status := strings.ToLower(value)
+	if i := strings.Index(status, " - "); i >= 0 {
+		status = status[:i]
+	}
+
+	color, ok := statusColors[status]
+	if !ok {
+		return uncolored(value)
+	}
+	return color + value + colorReset
+}
+
+func uncolored(value string) string {
+	return colorDefault + value + colorReset
+}
@@ -4,25 +4,61 @@
 package commands
 
 import (
+	"strings"
+
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
 )
 
 func SetTLSFlags(cmd *cobra.Command, cfg *registry.TLSConfig) {
 	cmd.Flags().StringVar(&cfg.CaCertPath, "registry-ca-cert-path", "", "add CA certificate for registry API (format: /tmp/ca.crt)")
 	cmd.Flags().BoolVar(&cfg.VerifyCerts, "registry-verify-certs", true, "set whether to verify server's certificate chain and host name")
+	cmd.Flags().BoolVar(&cfg.Verbose, "registry-verbose", false, "log the registry requests and responses to stderr, including layer pushes, retries and auth challenges")
+}
+
+func SetMetadataFlags(cmd *cobra.Command, metadata *k8s.Metadata) {
+	cmd.Flags().StringArrayVar(&metadata.Labels, "label", []string{}, "label to set on the resource (format: KEY=VALUE)")
+	cmd.Flags().StringArrayVar(&metadata.Annotations, "annotation", []string{}, "annotation to set on the resource (format: KEY=VALUE)")
+}
+
+// SetMetadataPatchFlags also allows removing labels and annotations from existing resources
+func SetMetadataPatchFlags(cmd *cobra.Command, metadata *k8s.Metadata) {
+	metadata.AllowDelete = true
+	cmd.Flags().StringArrayVar(&metadata.Labels, "label", []string{}, "label to set on the resource (format: KEY=VALUE), or KEY- to remove it")
+	cmd.Flags().StringArrayVar(&metadata.Annotations, "annotation", []string{}, "annotation to set on the resource (format: KEY=VALUE), or KEY- to remove it")
+	cmd.Flags().StringArrayVar(&metadata.DeleteLabels, "delete-label", []string{}, "label key to remove from the resource")
+	cmd.Flags().StringArrayVar(&metadata.DeleteAnnotations, "delete-annotation", []string{}, "annotation key to remove from the resource")
 }
 
 func SetDryRunOutputFlags(cmd *cobra.Command) {
 	cmd.Flags().Bool(DryRunFlag, false, `perform validation with no side-effects; no objects are sent to the server.
   The --dry-run flag can be used in combination with the --output flag to
   view the Kubernetes resource(s) without sending anything to the server.`)
-	cmd.Flags().String(OutputFlag, "", `print Kubernetes resources in the specified format; supported formats are: yaml, json.
+	cmd.Flags().String(OutputFlag, "", `print Kubernetes resources in the specified format; supported formats are: yaml, json, name, jsonpath=<template>, jsonpath-file=<path>, go-template=<template>, go-template-file=<path>.
   The output can be used with the "kubectl apply -f" command. To allow this, the command 
   updates are redirected to stderr and only the Kubernetes resource(s) are written to stdout.`)
 }
 
+func SetListOutputFlag(cmd *cobra.Command) {
+	SetConfigDefault(cmd, OutputFlag, config.OutputFormatKey)
+	cmd.Flags().StringP(OutputFlag, "o", "", `print Kubernetes resources in the specified format instead of a table; supported formats are: yaml, json, name,
+  jsonpath=<template>, jsonpath-file=<path>, go-template=<template>, go-template-file=<path>, custom-columns=<spec>, custom-columns-file=<path>.`)
+}
+
+// SetWideListOutputFlag also allows "--output wide" to add columns to the table, see CommandHelper.IsWide
+func SetWideListOutputFlag(cmd *cobra.Command) {
+	SetConfigDefault(cmd, OutputFlag, config.OutputFormatKey)
+	cmd.Flags().StringP(OutputFlag, "o", "", `print Kubernetes resources in the specified format instead of a table, or "wide" to add columns to the table;
+  supported formats are: wide, yaml, json, name, jsonpath=<template>, jsonpath-file=<path>, go-template=<template>, go-template-file=<path>, custom-columns=<spec>, custom-columns-file=<path>.`)
+	_ = cmd.Flags().SetAnnotation(OutputFlag, wideOutputAnnotation, []string{"true"})
+}
+
 func SetImgUploadDryRunOutputFlags(cmd *cobra.Command) {
 	SetDryRunOutputFlags(cmd)
 	cmd.Flags().Bool(DryRunImgUploadFlag, false, `similar to --dry-run, but with container image uploads allowed.
@@ -30,3 +66,80 @@ func SetImgUploadDryRunOutputFlags(cmd *cobra.Command) {
   resource with generated container image references. A "kubectl apply -f" of the
   resource from --output without image uploads will result in a reconcile failure.`)
 }
+
+func SetServerDryRunFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(ServerDryRunFlag, false, `submit resources to the server for validation without persisting them.
+  Unlike --dry-run, admission webhooks and defaulting are applied. No container images are uploaded.`)
+}
+
+func SetWaitTimeoutFlag(cmd *cobra.Command) {
+	cmd.Flags().Duration(WaitTimeoutFlag, defaultWaitTimeout, "maximum time to wait for the resource to be reconciled when used with --wait")
+}
+
+// SetReadyTimeoutFlag is used by commands that always wait for the resource to be ready, see WithWaitTimeout
+func SetReadyTimeoutFlag(cmd *cobra.Command) {
+	cmd.Flags().Duration(WaitTimeoutFlag, defaultWaitTimeout, "maximum time to wait for the resource to be ready")
+}
+
+func SetAllNamespacesFlag(cmd *cobra.Command, allNamespaces *bool) {
+	cmd.Flags().BoolVarP(allNamespaces, AllNamespacesFlag, "A", false, "Return objects found in all namespaces")
+}
+
+func ValidateAllNamespacesFlag(cmd *cobra.Command) error {
+	allNamespaces, err := GetBoolFlag(AllNamespacesFlag, cmd)
+	if err != nil {
+		return err
+	}
+
+	if allNamespaces && cmd.Flags().Changed("namespace") {
+		return errors.Errorf("--namespace and --%s are mutually exclusive", AllNamespacesFlag)
+	}
+	return nil
+}
+
+// AllNamespacesListError adds a permission hint when listing a resource across all namespaces is forbidden,
+// the command does not fall back to the current namespace
+func AllNamespacesListError(err error, resource string, allNamespaces bool) error {
+	if !allNamespaces || !k8serrors.IsForbidden(err) {
+		return err
+	}
+	return errors.Errorf("%s\nlisting %s with --%s requires permission to list them cluster-wide, use --namespace to list a single namespace instead", err, resource, AllNamespacesFlag)
+}
+
+func SetWatchFlag(cmd *cobra.Command, watch *bool) {
+	cmd.Flags().BoolVarP(watch, "watch", "w", false, "watch for changes and re-render the table until interrupted")
+}
+
+func SetNoColorFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(NoColorFlag, false, "disable the colored output, it is also disabled when the output is not a terminal or NO_COLOR is set")
+}
+
+// SetLabelSelectorFlag keeps --label-selector as a deprecated alias of --selector
+func SetLabelSelectorFlag(cmd *cobra.Command, selector *string) {
+	cmd.Flags().StringVarP(selector, "selector", "l", "", "label selector to filter on, supports '=', '==', '!=', 'in', 'notin' and 'exists'")
+	cmd.Flags().StringVar(selector, "label-selector", "", "label selector to filter on")
+	_ = cmd.Flags().MarkDeprecated("label-selector", "use --selector instead")
+}
+
+// ValidateLabelSelector rejects an invalid selector before it is sent to the server
+func ValidateLabelSelector(selector string) error {
+	if _, err := labels.Parse(selector); err != nil {
+		return errors.Wrapf(err, "invalid label selector %q", selector)
+	}
+	return nil
+}
+
+// SetSortByFlag uses the first of the sort keys as the default, the rows are sorted with a stable
+// sort so that rows with the same key keep the default order of the list command
+func SetSortByFlag(cmd *cobra.Command, sortBy *string, keys ...string) {
+	cmd.Flags().StringVar(sortBy, "sort-by", keys[0], "sort the list by one of "+strings.Join(keys, ", "))
+}
+
+func ValidateSortBy(sortBy string, keys ...string) error {
+	for _, key := range keys {
+		if sortBy == key {
+			return nil
+		}
+	}
+	return errors.Errorf("invalid --sort-by %q, must be one of %s", sortBy, strings.Join(keys, ", "))
+}
@@ -4,27 +4,41 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"reflect"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/apis/build"
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 )
 
+type DryRunStrategy int
+
+const (
+	DryRunNone DryRunStrategy = iota
+	DryRunClient
+	DryRunServer
+)
+
 type CommandHelper struct {
 	dryRun          bool
 	dryRunImgUpload bool
+	serverDryRun    bool
 	output          bool
+	wide            bool
 	wait            bool
+	waitTimeout     time.Duration
 
 	outWriter  io.Writer
 	errWriter  io.Writer
@@ -36,8 +50,14 @@ type CommandHelper struct {
 const (
 	DryRunFlag          = "dry-run"
 	DryRunImgUploadFlag = "dry-run-with-image-upload"
+	ServerDryRunFlag    = "server-side-dry-run"
 	OutputFlag          = "output"
 	WaitFlag            = "wait"
+	WaitTimeoutFlag     = "wait-timeout"
+	AllNamespacesFlag   = "all-namespaces"
+
+	OutputWide           = "wide"
+	wideOutputAnnotation = "kp-wide-output"
 )
 
 func NewCommandHelper(cmd *cobra.Command) (*CommandHelper, error) {
@@ -51,6 +71,15 @@ func NewCommandHelper(cmd *cobra.Command) (*CommandHelper, error) {
 		return nil, err
 	}
 
+	serverDryRun, err := GetBoolFlag(ServerDryRunFlag, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if serverDryRun && (dryRun || dryRunImgUpload) {
+		return nil, errors.Errorf("--%s cannot be used with --%s or --%s", ServerDryRunFlag, DryRunFlag, DryRunImgUploadFlag)
+	}
+
 	output, err := GetStringFlag(OutputFlag, cmd)
 	if err != nil {
 		return nil, err
@@ -61,9 +90,19 @@ func NewCommandHelper(cmd *cobra.Command) (*CommandHelper, error) {
 		return nil, err
 	}
 
+	waitTimeout, err := GetDurationFlag(WaitTimeoutFlag, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if waitTimeout <= 0 {
+		waitTimeout = defaultWaitTimeout
+	}
+
 	var objPrinter k8s.ObjectPrinter
 
-	outputResource := len(output) > 0
+	wide := output == OutputWide && supportsWideOutput(cmd)
+	outputResource := len(output) > 0 && !wide
 	if outputResource {
 		objPrinter, err = k8s.NewObjectPrinter(output)
 		if err != nil {
@@ -74,8 +113,11 @@ func NewCommandHelper(cmd *cobra.Command) (*CommandHelper, error) {
 	return &CommandHelper{
 		dryRun:          dryRun,
 		dryRunImgUpload: dryRunImgUpload,
+		serverDryRun:    serverDryRun,
 		output:          outputResource,
+		wide:            wide,
 		wait:            wait,
+		waitTimeout:     waitTimeout,
 		outWriter:       cmd.OutOrStdout(),
 		errWriter:       cmd.ErrOrStderr(),
 		objPrinter:      objPrinter,
@@ -84,11 +126,49 @@ func NewCommandHelper(cmd *cobra.Command) (*CommandHelper, error) {
 }
 
 func (ch CommandHelper) IsDryRun() bool {
-	return ch.dryRun || ch.dryRunImgUpload
+	return ch.DryRunStrategy() != DryRunNone
+}
+
+func (ch CommandHelper) DryRunStrategy() DryRunStrategy {
+	switch {
+	case ch.dryRun || ch.dryRunImgUpload:
+		return DryRunClient
+	case ch.serverDryRun:
+		return DryRunServer
+	default:
+		return DryRunNone
+	}
+}
+
+func (ch CommandHelper) ShouldSubmit() bool {
+	return ch.DryRunStrategy() != DryRunClient
+}
+
+func (ch CommandHelper) CreateOptions() metav1.CreateOptions {
+	return metav1.CreateOptions{DryRun: ch.serverDryRunOpts()}
+}
+
+func (ch CommandHelper) UpdateOptions() metav1.UpdateOptions {
+	return metav1.UpdateOptions{DryRun: ch.serverDryRunOpts()}
+}
+
+func (ch CommandHelper) PatchOptions() metav1.PatchOptions {
+	return metav1.PatchOptions{DryRun: ch.serverDryRunOpts()}
+}
+
+func (ch CommandHelper) DeleteOptions() metav1.DeleteOptions {
+	return metav1.DeleteOptions{DryRun: ch.serverDryRunOpts()}
+}
+
+func (ch CommandHelper) serverDryRunOpts() []string {
+	if ch.DryRunStrategy() == DryRunServer {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
 }
 
 func (ch CommandHelper) IsUploading() bool {
-	return !ch.dryRun || ch.dryRunImgUpload
+	return !(ch.dryRun || ch.serverDryRun) || ch.dryRunImgUpload
 }
 
 func (ch CommandHelper) ValidateOnly() bool {
@@ -96,13 +176,25 @@ func (ch CommandHelper) ValidateOnly() bool {
 }
 
 func (ch CommandHelper) CanChangeState() bool {
-	return !ch.dryRun
+	return !ch.dryRun && !ch.serverDryRun
+}
+
+func (ch CommandHelper) IsOutput() bool {
+	return ch.output
+}
+
+func (ch CommandHelper) IsWide() bool {
+	return ch.wide
 }
 
 func (ch CommandHelper) ShouldWait() bool {
 	return ch.wait && !ch.IsDryRun() && !ch.output
 }
 
+func (ch CommandHelper) WaitTimeout() time.Duration {
+	return ch.waitTimeout
+}
+
 func (ch CommandHelper) PrintObjs(objs []runtime.Object) error {
 	for _, obj := range objs {
 		if err := ch.PrintObj(obj); err != nil {
@@ -112,6 +204,34 @@ func (ch CommandHelper) PrintObjs(objs []runtime.Object) error {
 	return nil
 }
 
+// PrintList prints the objects as a single v1 List so that objects of
+// several namespaces can be applied together
+func (ch CommandHelper) PrintList(objs []runtime.Object) error {
+	if !ch.output {
+		return nil
+	}
+
+	list := &metav1.List{}
+	for _, obj := range objs {
+		obj = obj.DeepCopyObject()
+		if gvk := obj.GetObjectKind().GroupVersionKind(); gvk.Version == "" || gvk.Kind == "" {
+			nGVK, ok := ch.typeToGVK[reflect.TypeOf(obj)]
+			if !ok {
+				return errors.Errorf("failed to output. unknown type %q", reflect.TypeOf(obj))
+			}
+			obj.GetObjectKind().SetGroupVersionKind(nGVK)
+		}
+
+		raw, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		list.Items = append(list.Items, runtime.RawExtension{Raw: raw, Object: obj})
+	}
+
+	return ch.PrintObj(list)
+}
+
 func (ch CommandHelper) PrintObj(obj runtime.Object) error {
 	if !ch.output {
 		return nil
@@ -135,6 +255,8 @@ func (ch CommandHelper) PrintChangeResult(change bool, format string, args ...in
 		format += " (dry run with image upload)"
 	} else if ch.dryRun {
 		format += " (dry run)"
+	} else if ch.serverDryRun {
+		format += " (server dry run)"
 	} else if !change {
 		format += " (no change)"
 	}
@@ -147,6 +269,8 @@ func (ch CommandHelper) PrintResult(format string, args ...interface{}) error {
 		format += " (dry run with image upload)"
 	} else if ch.dryRun {
 		format += " (dry run)"
+	} else if ch.serverDryRun {
+		format += " (server dry run)"
 	}
 	_, err := ch.OutOrDiscardWriter().Write([]byte(fmt.Sprintf(format+"\n", args...)))
 	return err
@@ -157,6 +281,8 @@ func (ch CommandHelper) PrintStatus(format string, args ...interface{}) error {
 		format += " (dry run with image upload)"
 	} else if ch.dryRun {
 		format += " (dry run)"
+	} else if ch.serverDryRun {
+		format += " (server dry run)"
 	}
 	_, err := ch.OutOrErrWriter().Write([]byte(fmt.Sprintf(format+"\n", args...)))
 	return err
@@ -187,13 +313,22 @@ func (ch CommandHelper) Writer() io.Writer {
 	return ch.OutOrErrWriter()
 }
 
+func supportsWideOutput(cmd *cobra.Command) bool {
+	flag := cmd.Flags().Lookup(OutputFlag)
+	if flag == nil {
+		return false
+	}
+	_, ok := flag.Annotations[wideOutputAnnotation]
+	return ok
+}
+
 func GetBoolFlag(name string, cmd *cobra.Command) (bool, error) {
 	flag := cmd.Flags().Lookup(name)
 	if flag == nil {
 		return false, nil
 	}
 
-	if !cmd.Flags().Changed(name) {
+	if !flagIsSet(cmd, name) {
 		return false, nil
 	}
 
@@ -210,7 +345,7 @@ func GetStringFlag(name string, cmd *cobra.Command) (string, error) {
 		return "", nil
 	}
 
-	if !cmd.Flags().Changed(name) {
+	if !flagIsSet(cmd, name) {
 		return "", nil
 	}
 
@@ -221,6 +356,15 @@ func GetStringFlag(name string, cmd *cobra.Command) (string, error) {
 	return value, nil
 }
 
+func GetDurationFlag(name string, cmd *cobra.Command) (time.Duration, error) {
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil {
+		return 0, nil
+	}
+
+	return cmd.Flags().GetDuration(name)
+}
+
 func getTypeToGVKLookup() map[reflect.Type]schema.GroupVersionKind {
 	v1GV := schema.GroupVersion{Group: v1.GroupName, Version: "v1"}
 	buildGV := schema.GroupVersion{Group: build.GroupName, Version: "v1alpha1"}
@@ -229,8 +373,13 @@ func getTypeToGVKLookup() map[reflect.Type]schema.GroupVersionKind {
 		reflect.TypeOf(&v1.Secret{}):               v1GV.WithKind("Secret"),
 		reflect.TypeOf(&v1.ServiceAccount{}):       v1GV.WithKind("ServiceAccount"),
 		reflect.TypeOf(&v1.ConfigMap{}):            v1GV.WithKind("ConfigMap"),
+		reflect.TypeOf(&metav1.List{}):             v1GV.WithKind("List"),
 		reflect.TypeOf(&v1alpha1.Image{}):          buildGV.WithKind("Image"),
+		reflect.TypeOf(&v1alpha1.ImageList{}):      buildGV.WithKind("ImageList"),
+		reflect.TypeOf(&v1alpha1.Build{}):          buildGV.WithKind("Build"),
+		reflect.TypeOf(&v1alpha1.BuildList{}):      buildGV.WithKind("BuildList"),
 		reflect.TypeOf(&v1alpha1.Builder{}):        buildGV.WithKind(v1alpha1.BuilderKind),
+		reflect.TypeOf(&v1alpha1.BuilderList{}):    buildGV.WithKind("BuilderList"),
 		reflect.TypeOf(&v1alpha1.ClusterStack{}):   buildGV.WithKind(v1alpha1.ClusterStackKind),
 		reflect.TypeOf(&v1alpha1.ClusterStore{}):   buildGV.WithKind(v1alpha1.ClusterStoreKind),
 		reflect.TypeOf(&v1alpha1.ClusterBuilder{}): buildGV.WithKind(v1alpha1.ClusterBuilderKind),
@@ -0,0 +1,127 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+// completionTimeout keeps the shell responsive when the cluster is unreachable
+const completionTimeout = 5 * time.Second
+
+type CompletionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+type objectLister func(ctx context.Context, cs k8s.ClientSet) (runtime.Object, error)
+
+func ImageNameCompletion(clientSetProvider k8s.ClientSetProvider) CompletionFunc {
+	return nameCompletion(clientSetProvider, listImages, false)
+}
+
+// ImageNamesCompletion completes every argument of commands that accept several images, the names
+// that were already provided are not completed again
+func ImageNamesCompletion(clientSetProvider k8s.ClientSetProvider) CompletionFunc {
+	return nameCompletion(clientSetProvider, listImages, true)
+}
+
+func BuilderNameCompletion(clientSetProvider k8s.ClientSetProvider) CompletionFunc {
+	return nameCompletion(clientSetProvider, listBuilders, false)
+}
+
+func ClusterBuilderNameCompletion(clientSetProvider k8s.ClientSetProvider) CompletionFunc {
+	return nameCompletion(clientSetProvider, listClusterBuilders, false)
+}
+
+func ClusterStackNameCompletion(clientSetProvider k8s.ClientSetProvider) CompletionFunc {
+	return nameCompletion(clientSetProvider, listClusterStacks, false)
+}
+
+func ClusterStoreNameCompletion(clientSetProvider k8s.ClientSetProvider) CompletionFunc {
+	return nameCompletion(clientSetProvider, listClusterStores, false)
+}
+
+func SecretNameCompletion(clientSetProvider k8s.ClientSetProvider) CompletionFunc {
+	return nameCompletion(clientSetProvider, listSecrets, false)
+}
+
+func listImages(ctx context.Context, cs k8s.ClientSet) (runtime.Object, error) {
+	return cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).List(ctx, metav1.ListOptions{})
+}
+
+func listBuilders(ctx context.Context, cs k8s.ClientSet) (runtime.Object, error) {
+	return cs.KpackClient.KpackV1alpha1().Builders(cs.Namespace).List(ctx, metav1.ListOptions{})
+}
+
+func listClusterBuilders(ctx context.Context, cs k8s.ClientSet) (runtime.Object, error) {
+	return cs.KpackClient.KpackV1alpha1().ClusterBuilders().List(ctx, metav1.ListOptions{})
+}
+
+func listClusterStacks(ctx context.Context, cs k8s.ClientSet) (runtime.Object, error) {
+	return cs.KpackClient.KpackV1alpha1().ClusterStacks().List(ctx, metav1.ListOptions{})
+}
+
+func listClusterStores(ctx context.Context, cs k8s.ClientSet) (runtime.Object, error) {
+	return cs.KpackClient.KpackV1alpha1().ClusterStores().List(ctx, metav1.ListOptions{})
+}
+
+func listSecrets(ctx context.Context, cs k8s.ClientSet) (runtime.Object, error) {
+	return cs.K8sClient.CoreV1().Secrets(cs.Namespace).List(ctx, metav1.ListOptions{})
+}
+
+// nameCompletion completes resource names from the namespace selected by --namespace, only the first
+// argument is completed unless multiple is set. Nothing is completed when the cluster cannot be reached.
+func nameCompletion(clientSetProvider k8s.ClientSetProvider, list objectLister, multiple bool) CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 && !multiple {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		namespace, _ := GetStringFlag("namespace", cmd)
+
+		cs, err := clientSetProvider.GetClientSet(namespace)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+		defer cancel()
+
+		obj, err := list(ctx, cs)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		items, err := meta.ExtractList(obj)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		provided := map[string]bool{}
+		for _, arg := range args {
+			provided[arg] = true
+		}
+
+		var completions []string
+		for _, item := range items {
+			accessor, err := meta.Accessor(item)
+			if err != nil {
+				continue
+			}
+
+			name := accessor.GetName()
+			if strings.HasPrefix(name, toComplete) && !provided[name] {
+				completions = append(completions, name)
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
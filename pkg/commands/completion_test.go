@@ -0,0 +1,96 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestNameCompletion(t *testing.T) {
+	spec.Run(t, "TestNameCompletion", testNameCompletion)
+}
+
+func testNameCompletion(t *testing.T, when spec.G, it spec.S) {
+	const defaultNamespace = "some-default-namespace"
+
+	makeImage := func(name, namespace string) *v1alpha1.Image {
+		return &v1alpha1.Image{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+		}
+	}
+
+	kpackClient := kpackfakes.NewSimpleClientset(
+		makeImage("app-one", defaultNamespace),
+		makeImage("app-two", defaultNamespace),
+		makeImage("other", defaultNamespace),
+		makeImage("app-three", "some-namespace"),
+	)
+	complete := commands.ImageNameCompletion(testhelpers.GetFakeKpackProvider(kpackClient, defaultNamespace))
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringP("namespace", "n", "", "kubernetes namespace")
+
+	it("completes names in the default namespace matching the prefix", func() {
+		names, directive := complete(cmd, nil, "app")
+		require.ElementsMatch(t, []string{"app-one", "app-two"}, names)
+		require.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+	})
+
+	it("completes names in the namespace provided with --namespace", func() {
+		require.NoError(t, cmd.Flags().Set("namespace", "some-namespace"))
+
+		names, directive := complete(cmd, nil, "")
+		require.Equal(t, []string{"app-three"}, names)
+		require.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+	})
+
+	it("does not complete once a name has been provided", func() {
+		names, directive := complete(cmd, []string{"app-one"}, "")
+		require.Empty(t, names)
+		require.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+	})
+
+	when("the command accepts several names", func() {
+		complete := commands.ImageNamesCompletion(testhelpers.GetFakeKpackProvider(kpackClient, defaultNamespace))
+
+		it("completes the names that were not provided yet", func() {
+			names, directive := complete(cmd, []string{"app-one"}, "")
+			require.ElementsMatch(t, []string{"app-two", "other"}, names)
+			require.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+		})
+
+		it("completes names matching the prefix", func() {
+			names, _ := complete(cmd, []string{"other"}, "app")
+			require.ElementsMatch(t, []string{"app-one", "app-two"}, names)
+		})
+	})
+
+	it("does not complete or fail when the resources cannot be listed", func() {
+		unreachableClient := kpackfakes.NewSimpleClientset()
+		unreachableClient.PrependReactor("list", "images", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("connection refused")
+		})
+		complete := commands.ImageNameCompletion(testhelpers.GetFakeKpackProvider(unreachableClient, defaultNamespace))
+
+		names, directive := complete(cmd, nil, "")
+		require.Empty(t, names)
+		require.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+	})
+}
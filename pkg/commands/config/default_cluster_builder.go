@@ -0,0 +1,70 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+func NewDefaultClusterBuilderCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "default-cluster-builder [name]",
+		Short: "Set or get the default cluster builder",
+		Long: `Set or get the cluster builder used by "kp image create" and "kp image save" when neither "--builder" nor "--cluster-builder" is provided.
+
+The default cluster builder is stored in the "default.clusterbuilder" key of the "kp-config" ConfigMap within "kpack" namespace.
+When no name is provided, the current default cluster builder is printed.`,
+		Example:           "kp config default-cluster-builder my-cluster-builder\nkp config default-cluster-builder",
+		Args:              commands.OptionalArgsWithUsage(1),
+		ValidArgsFunction: commands.ClusterBuilderNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cs, err := clientSetProvider.GetClientSet("")
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			helper := k8s.DefaultConfigHelper(cs)
+
+			if len(args) == 0 {
+				name, err := helper.GetDefaultClusterBuilder(ctx)
+				if err != nil {
+					return err
+				}
+
+				if name == "" {
+					return errors.New("default cluster builder is not set")
+				}
+
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), name)
+				return err
+			}
+
+			name := args[0]
+			_, err = cs.KpackClient.KpackV1alpha1().ClusterBuilders().Get(ctx, name, metav1.GetOptions{})
+			if k8serrors.IsNotFound(err) {
+				return errors.Errorf("clusterbuilder '%s' not found", name)
+			} else if err != nil {
+				return err
+			}
+
+			if err := helper.SetDefaultClusterBuilder(ctx, name); err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Default cluster builder set to %q\n", name)
+			return err
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,128 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfakes "k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+
+	configcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/config"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestDefaultClusterBuilderCommand(t *testing.T) {
+	spec.Run(t, "TestDefaultClusterBuilderCommand", testDefaultClusterBuilderCommand)
+}
+
+func testDefaultClusterBuilderCommand(t *testing.T, when spec.G, it spec.S) {
+	cmdFunc := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *kpackfakes.Clientset) *cobra.Command {
+		clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClientSet, kpackClientSet)
+		return configcmds.NewDefaultClusterBuilderCommand(clientSetProvider)
+	}
+
+	clusterBuilder := &v1alpha1.ClusterBuilder{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "some-cluster-builder",
+		},
+	}
+
+	kpConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kp-config",
+			Namespace: "kpack",
+		},
+		Data: map[string]string{
+			"canonical.repository": "some-registry/some-project",
+		},
+	}
+
+	when("a name is provided", func() {
+		it("adds the default cluster builder to the kp-config configmap", func() {
+			updatedConfig := kpConfig.DeepCopy()
+			updatedConfig.Data["default.clusterbuilder"] = "some-cluster-builder"
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					kpConfig,
+					clusterBuilder,
+				},
+				Args: []string{"some-cluster-builder"},
+				ExpectedOutput: `Default cluster builder set to "some-cluster-builder"
+`,
+				ExpectUpdates: []clientgotesting.UpdateActionImpl{
+					{
+						Object: updatedConfig,
+					},
+				},
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("creates the kp-config configmap when it does not exist", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					clusterBuilder,
+				},
+				Args: []string{"some-cluster-builder"},
+				ExpectedOutput: `Default cluster builder set to "some-cluster-builder"
+`,
+				ExpectCreates: []runtime.Object{
+					&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "kp-config",
+							Namespace: "kpack",
+						},
+						Data: map[string]string{
+							"default.clusterbuilder": "some-cluster-builder",
+						},
+					},
+				},
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("returns an error when the cluster builder does not exist", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					kpConfig,
+				},
+				Args:      []string{"some-cluster-builder"},
+				ExpectErr: true,
+				ExpectedOutput: `Error: clusterbuilder 'some-cluster-builder' not found
+`,
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+	})
+
+	when("no name is provided", func() {
+		it("prints the default cluster builder", func() {
+			configWithDefault := kpConfig.DeepCopy()
+			configWithDefault.Data["default.clusterbuilder"] = "some-cluster-builder"
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					configWithDefault,
+				},
+				ExpectedOutput: "some-cluster-builder\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("returns an error when the default is not set", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					kpConfig,
+				},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: default cluster builder is not set\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+	})
+}
@@ -0,0 +1,45 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+)
+
+func NewGetCommand(configPath string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "get <key>",
+		Short:        "Get a kp config value",
+		Long:         `Prints a value from the kp config file.`,
+		Example:      "kp config get default-namespace",
+		Args:         commands.ExactArgsWithUsage(1),
+		ValidArgs:    config.Keys(),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadCLIConfig(configPath)
+			if err != nil {
+				return err
+			}
+
+			value, err := cfg.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			if value == "" {
+				return errors.Errorf("config %q is not set", args[0])
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), value)
+			return err
+		},
+	}
+	return cmd
+}
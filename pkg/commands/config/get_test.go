@@ -0,0 +1,58 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+
+	configcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/config"
+)
+
+func TestConfigGetCommand(t *testing.T) {
+	spec.Run(t, "TestConfigGetCommand", testConfigGetCommand)
+}
+
+func testConfigGetCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tempDir    string
+		configPath string
+	)
+
+	it.Before(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "kp-config-get")
+		require.NoError(t, err)
+		configPath = filepath.Join(tempDir, "config.yaml")
+		require.NoError(t, ioutil.WriteFile(configPath, []byte("default-namespace: some-namespace\n"), 0600))
+	})
+
+	it.After(func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	})
+
+	it("prints the value", func() {
+		cmd := configcmds.NewGetCommand(configPath)
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{"default-namespace"})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, "some-namespace\n", out.String())
+	})
+
+	it("errors when the value is not set", func() {
+		cmd := configcmds.NewGetCommand(configPath)
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs([]string{"default-store"})
+
+		require.EqualError(t, cmd.Execute(), `config "default-store" is not set`)
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+func NewSetCommand(configPath string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a kp config value",
+		Long: `Set a value in the kp config file.
+
+Config values are used as defaults for the matching flags. Flags provided on the command line always take precedence.
+The config file is read from "~/.kp/config.yaml" unless the KP_CONFIG_PATH environment variable is set.
+The output-format value is only used by the list commands and kp image status, commands that create or patch resources ignore it.
+The save commands only use the default-service-account, default-stack and default-store values when they create a resource.
+
+Supported keys:
+  ` + strings.Join(config.Keys(), "\n  "),
+		Example:      "kp config set default-namespace my-namespace\nkp config set output-format yaml",
+		Args:         commands.ExactArgsWithUsage(2),
+		ValidArgs:    config.Keys(),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, value := args[0], args[1]
+
+			if key == config.OutputFormatKey {
+				if _, err := k8s.NewObjectPrinter(value); err != nil {
+					return err
+				}
+			}
+
+			cfg, err := config.LoadCLIConfig(configPath)
+			if err != nil {
+				return err
+			}
+
+			if err := cfg.Set(key, value); err != nil {
+				return err
+			}
+
+			if err := cfg.Save(configPath); err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Config %q set to %q\n", key, value)
+			return err
+		},
+	}
+	return cmd
+}
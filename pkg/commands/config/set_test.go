@@ -0,0 +1,73 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+
+	configcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/config"
+)
+
+func TestConfigSetCommand(t *testing.T) {
+	spec.Run(t, "TestConfigSetCommand", testConfigSetCommand)
+}
+
+func testConfigSetCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tempDir    string
+		configPath string
+	)
+
+	it.Before(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "kp-config-set")
+		require.NoError(t, err)
+		configPath = filepath.Join(tempDir, "config.yaml")
+	})
+
+	it.After(func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	})
+
+	it("writes the value to the config file", func() {
+		require.NoError(t, ioutil.WriteFile(configPath, []byte("default-stack: some-stack\n"), 0600))
+
+		cmd := configcmds.NewSetCommand(configPath)
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{"default-namespace", "some-namespace"})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, "Config \"default-namespace\" set to \"some-namespace\"\n", out.String())
+
+		buf, err := ioutil.ReadFile(configPath)
+		require.NoError(t, err)
+		require.Equal(t, "default-namespace: some-namespace\ndefault-stack: some-stack\n", string(buf))
+	})
+
+	it("errors on unknown keys", func() {
+		cmd := configcmds.NewSetCommand(configPath)
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs([]string{"unknown", "value"})
+
+		require.EqualError(t, cmd.Execute(), `unknown config key "unknown", supported keys are: default-namespace, default-service-account, default-stack, default-store, output-format`)
+		require.NoFileExists(t, configPath)
+	})
+
+	it("errors on unsupported output formats", func() {
+		cmd := configcmds.NewSetCommand(configPath)
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs([]string{"output-format", "table"})
+
+		require.Error(t, cmd.Execute())
+		require.NoFileExists(t, configPath)
+	})
+}
@@ -0,0 +1,43 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+)
+
+func NewUnsetCommand(configPath string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "unset <key>",
+		Short:        "Unset a kp config value",
+		Long:         `Removes a value from the kp config file.`,
+		Example:      "kp config unset default-namespace",
+		Args:         commands.ExactArgsWithUsage(1),
+		ValidArgs:    config.Keys(),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadCLIConfig(configPath)
+			if err != nil {
+				return err
+			}
+
+			if err := cfg.Set(args[0], ""); err != nil {
+				return err
+			}
+
+			if err := cfg.Save(configPath); err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Config %q unset\n", args[0])
+			return err
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,54 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+
+	configcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/config"
+)
+
+func TestConfigUnsetCommand(t *testing.T) {
+	spec.Run(t, "TestConfigUnsetCommand", testConfigUnsetCommand)
+}
+
+func testConfigUnsetCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tempDir    string
+		configPath string
+	)
+
+	it.Before(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "kp-config-unset")
+		require.NoError(t, err)
+		configPath = filepath.Join(tempDir, "config.yaml")
+		require.NoError(t, ioutil.WriteFile(configPath, []byte("default-namespace: some-namespace\noutput-format: yaml\n"), 0600))
+	})
+
+	it.After(func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	})
+
+	it("removes the value from the config file", func() {
+		cmd := configcmds.NewUnsetCommand(configPath)
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{"output-format"})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, "Config \"output-format\" unset\n", out.String())
+
+		buf, err := ioutil.ReadFile(configPath)
+		require.NoError(t, err)
+		require.Equal(t, "default-namespace: some-namespace\n", string(buf))
+	})
+}
@@ -0,0 +1,38 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+)
+
+func NewViewCommand(configPath string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "view",
+		Short:        "Display the kp config",
+		Long:         `Prints the contents of the kp config file.`,
+		Example:      "kp config view",
+		Args:         commands.ExactArgsWithUsage(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadCLIConfig(configPath)
+			if err != nil {
+				return err
+			}
+
+			buf, err := yaml.Marshal(cfg)
+			if err != nil {
+				return err
+			}
+
+			_, err = cmd.OutOrStdout().Write(buf)
+			return err
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,58 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+
+	configcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/config"
+)
+
+func TestConfigViewCommand(t *testing.T) {
+	spec.Run(t, "TestConfigViewCommand", testConfigViewCommand)
+}
+
+func testConfigViewCommand(t *testing.T, when spec.G, it spec.S) {
+	var tempDir string
+
+	it.Before(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "kp-config-view")
+		require.NoError(t, err)
+	})
+
+	it.After(func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	})
+
+	it("prints the config file", func() {
+		configPath := filepath.Join(tempDir, "config.yaml")
+		require.NoError(t, ioutil.WriteFile(configPath, []byte("output-format: json\ndefault-stack: some-stack\n"), 0600))
+
+		cmd := configcmds.NewViewCommand(configPath)
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, "default-stack: some-stack\noutput-format: json\n", out.String())
+	})
+
+	it("prints an empty config when the file does not exist", func() {
+		cmd := configcmds.NewViewCommand(filepath.Join(tempDir, "missing.yaml"))
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, "{}\n", out.String())
+	})
+}
@@ -0,0 +1,204 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+type resourceType struct {
+	kind       string
+	namespaced bool
+}
+
+var resourceTypes = map[string]resourceType{
+	"image":          {kind: "Image", namespaced: true},
+	"build":          {kind: "Build", namespaced: true},
+	"builder":        {kind: "Builder", namespaced: true},
+	"clusterbuilder": {kind: "ClusterBuilder"},
+	"clusterstack":   {kind: "ClusterStack"},
+	"clusterstore":   {kind: "ClusterStore"},
+}
+
+var resourceTypeNames = []string{"image", "build", "builder", "clusterbuilder", "clusterstack", "clusterstore"}
+
+func NewEventsCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	var (
+		namespace string
+		since     time.Duration
+		watch     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "events <resource-type> <name>",
+		Short: "List the events of a kpack resource",
+		Long: `Prints a table of the kubernetes events recorded for a kpack resource, sorted by the time they were last seen.
+
+The supported resource types are image, build, builder, clusterbuilder, clusterstack and clusterstore.
+The namespace defaults to the kubernetes current-context namespace, the events of the cluster scoped resources are listed in all namespaces.
+Use the --since flag to only list the events that were last seen within the provided duration.
+Use the --watch flag to keep the table updated as events are recorded until interrupted.`,
+		Example: `kp events image my-image
+kp events build my-image-build-1 -n my-namespace
+kp events clusterbuilder my-builder --since 1h
+kp events image my-image --watch`,
+		Args:         commands.ExactArgsWithUsage(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resource, ok := resourceTypes[args[0]]
+			if !ok {
+				return errors.Errorf("unsupported resource type %q, supported resource types are %s", args[0], strings.Join(resourceTypeNames, ", "))
+			}
+
+			if since < 0 {
+				return errors.New("--since must not be negative")
+			}
+
+			cs, err := clientSetProvider.GetClientSet(namespace)
+			if err != nil {
+				return err
+			}
+
+			eventsNamespace := metav1.NamespaceAll
+			if resource.namespaced {
+				eventsNamespace = cs.Namespace
+			}
+			fieldSelector := FieldSelector(resource.kind, args[1], eventsNamespace)
+
+			eventList, err := cs.K8sClient.CoreV1().Events(eventsNamespace).List(cmd.Context(), metav1.ListOptions{
+				FieldSelector: fieldSelector,
+			})
+			if err != nil {
+				return err
+			}
+
+			if watch {
+				watcher, err := cs.K8sClient.CoreV1().Events(eventsNamespace).Watch(cmd.Context(), metav1.ListOptions{
+					FieldSelector:   fieldSelector,
+					ResourceVersion: eventList.ResourceVersion,
+				})
+				if err != nil {
+					return err
+				}
+				defer watcher.Stop()
+
+				var objs []runtime.Object
+				for i := range eventList.Items {
+					objs = append(objs, &eventList.Items[i])
+				}
+
+				return commands.WatchTable(cmd.Context(), cmd.OutOrStdout(), objs, watcher, func(objs []runtime.Object) error {
+					var events []corev1.Event
+					for _, obj := range objs {
+						if event, ok := obj.(*corev1.Event); ok {
+							events = append(events, *event)
+						}
+					}
+					return displayEventsTable(cmd, sortEvents(filterEvents(events, since)))
+				})
+			}
+
+			events := sortEvents(filterEvents(eventList.Items, since))
+			if len(events) == 0 {
+				return errors.Errorf("no events found for %s %q", args[0], args[1])
+			}
+			return displayEventsTable(cmd, events)
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	cmd.Flags().DurationVar(&since, "since", 0, "only list the events last seen within the duration, for example 30m or 2h")
+	commands.SetWatchFlag(cmd, &watch)
+
+	return cmd
+}
+
+// FieldSelector selects the events of the involved object, the namespace is left out
+// of the selector for the cluster scoped resources
+func FieldSelector(kind, name, namespace string) string {
+	set := fields.Set{
+		"involvedObject.kind": kind,
+		"involvedObject.name": name,
+	}
+	if namespace != metav1.NamespaceAll {
+		set["involvedObject.namespace"] = namespace
+	}
+	return set.AsSelector().String()
+}
+
+// eventTime falls back to the event time and the creation timestamp for the
+// events that were recorded without a last timestamp
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+func filterEvents(events []corev1.Event, since time.Duration) []corev1.Event {
+	if since == 0 {
+		return events
+	}
+
+	cutoff := time.Now().Add(-since)
+	var filtered []corev1.Event
+	for _, event := range events {
+		if !eventTime(event).Before(cutoff) {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+func sortEvents(events []corev1.Event) []corev1.Event {
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+	return events
+}
+
+func displayEventsTable(cmd *cobra.Command, events []corev1.Event) error {
+	writer, err := commands.NewTableWriter(cmd.OutOrStdout(), "Type", "Reason", "Age", "From", "Message")
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		err := writer.AddRow(
+			event.Type,
+			event.Reason,
+			duration.HumanDuration(time.Since(eventTime(event))),
+			eventSource(event),
+			strings.TrimSpace(event.Message),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Write()
+}
+
+func eventSource(event corev1.Event) string {
+	if event.Source.Component != "" {
+		return event.Source.Component
+	}
+	return event.ReportingController
+}
@@ -0,0 +1,153 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package events_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/events"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestEventsCommand(t *testing.T) {
+	spec.Run(t, "TestEventsCommand", testEventsCommand)
+}
+
+func testEventsCommand(t *testing.T, when spec.G, it spec.S) {
+	const defaultNamespace = "some-default-namespace"
+
+	var client *fake.Clientset
+	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
+		client = clientSet
+		clientSetProvider := testhelpers.GetFakeK8sProvider(clientSet, defaultNamespace)
+		return events.NewEventsCommand(clientSetProvider)
+	}
+
+	event := func(name, namespace, eventType, reason, message string, lastSeen time.Duration) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Type:          eventType,
+			Reason:        reason,
+			Message:       message,
+			Source:        corev1.EventSource{Component: "kpack-controller"},
+			LastTimestamp: metav1.NewTime(time.Now().Add(-lastSeen)),
+		}
+	}
+
+	requireFieldSelector := func(t *testing.T, namespace, selector string) {
+		t.Helper()
+		require.Len(t, client.Actions(), 1)
+		listAction := client.Actions()[0].(clientgotesting.ListAction)
+		require.Equal(t, namespace, listAction.GetNamespace())
+		require.Equal(t, selector, listAction.GetListRestrictions().Fields.String())
+	}
+
+	when("the resource is namespaced", func() {
+		it("lists the events of the resource sorted by the time they were last seen", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					event("event-2", defaultNamespace, corev1.EventTypeWarning, "BuildFailed", "build some-image-build-1 failed", 20*time.Minute),
+					event("event-1", defaultNamespace, corev1.EventTypeNormal, "Created", "created build some-image-build-1", 30*time.Minute),
+				},
+				Args: []string{"image", "some-image"},
+				ExpectedOutput: `TYPE       REASON         AGE    FROM                MESSAGE
+Normal     Created        30m    kpack-controller    created build some-image-build-1
+Warning    BuildFailed    20m    kpack-controller    build some-image-build-1 failed
+
+`,
+			}.TestK8s(t, cmdFunc)
+
+			requireFieldSelector(t, defaultNamespace, "involvedObject.kind=Image,involvedObject.name=some-image,involvedObject.namespace=some-default-namespace")
+		})
+
+		it("selects the events in the provided namespace", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					event("event-1", "some-namespace", corev1.EventTypeNormal, "Created", "created build some-image-build-1", 30*time.Minute),
+				},
+				Args: []string{"build", "some-image-build-1", "-n", "some-namespace"},
+				ExpectedOutput: `TYPE      REASON     AGE    FROM                MESSAGE
+Normal    Created    30m    kpack-controller    created build some-image-build-1
+
+`,
+			}.TestK8s(t, cmdFunc)
+
+			requireFieldSelector(t, "some-namespace", "involvedObject.kind=Build,involvedObject.name=some-image-build-1,involvedObject.namespace=some-namespace")
+		})
+	})
+
+	when("the resource is cluster scoped", func() {
+		it("selects the events in all namespaces", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					event("event-1", "default", corev1.EventTypeNormal, "Ready", "builder is ready", 30*time.Minute),
+				},
+				Args: []string{"clusterbuilder", "some-builder"},
+				ExpectedOutput: `TYPE      REASON    AGE    FROM                MESSAGE
+Normal    Ready     30m    kpack-controller    builder is ready
+
+`,
+			}.TestK8s(t, cmdFunc)
+
+			requireFieldSelector(t, "", "involvedObject.kind=ClusterBuilder,involvedObject.name=some-builder")
+		})
+	})
+
+	when("the since flag is used", func() {
+		it("only lists the events last seen within the duration", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					event("event-1", defaultNamespace, corev1.EventTypeNormal, "Created", "created build some-image-build-1", 10*time.Hour),
+					event("event-2", defaultNamespace, corev1.EventTypeNormal, "Created", "created build some-image-build-2", 20*time.Minute),
+				},
+				Args: []string{"image", "some-image", "--since", "1h"},
+				ExpectedOutput: `TYPE      REASON     AGE    FROM                MESSAGE
+Normal    Created    20m    kpack-controller    created build some-image-build-2
+
+`,
+			}.TestK8s(t, cmdFunc)
+		})
+
+		it("errors when no events were last seen within the duration", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					event("event-1", defaultNamespace, corev1.EventTypeNormal, "Created", "created build some-image-build-1", 10*time.Hour),
+				},
+				Args:           []string{"image", "some-image", "--since", "1h"},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: no events found for image \"some-image\"\n",
+			}.TestK8s(t, cmdFunc)
+		})
+	})
+
+	it("errors for an unsupported resource type", func() {
+		testhelpers.CommandTest{
+			Args:           []string{"pod", "some-pod"},
+			ExpectErr:      true,
+			ExpectedOutput: "Error: unsupported resource type \"pod\", supported resource types are image, build, builder, clusterbuilder, clusterstack, clusterstore\n",
+		}.TestK8s(t, cmdFunc)
+
+		require.Len(t, client.Actions(), 0)
+	})
+
+	it("builds the field selector from the involved object", func() {
+		require.Equal(t, "involvedObject.kind=Image,involvedObject.name=some-image,involvedObject.namespace=some-namespace",
+			events.FieldSelector("Image", "some-image", "some-namespace"))
+		require.Equal(t, "involvedObject.kind=ClusterStack,involvedObject.name=some-stack",
+			events.FieldSelector("ClusterStack", "some-stack", ""))
+	})
+}
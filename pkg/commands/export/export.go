@@ -0,0 +1,286 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+const (
+	kpackAPIVersion = "kpack.io/v1alpha1"
+	imageKind       = "Image"
+)
+
+var serverManagedFields = []string{
+	"resourceVersion",
+	"uid",
+	"creationTimestamp",
+	"generation",
+	"selfLink",
+	"managedFields",
+}
+
+type resourceFlags struct {
+	all             bool
+	clusterStores   bool
+	clusterStacks   bool
+	clusterBuilders bool
+	builders        bool
+	images          bool
+	includeSecrets  bool
+}
+
+func (f resourceFlags) any() bool {
+	return f.all || f.clusterStores || f.clusterStacks || f.clusterBuilders || f.builders || f.images || f.includeSecrets
+}
+
+func NewExportCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	var (
+		namespace  string
+		outputFile string
+		flags      resourceFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export kpack resources as a multi-document yaml stream",
+		Long: `Export kpack resources as a multi-document yaml stream that can be re-imported with "kp import -f".
+
+Resources are selected with "--all" or with one or more resource type flags.
+Cluster-scoped resources are exported regardless of namespace. Builders and images are exported from the provided namespace.
+Server managed fields such as resourceVersion, uid, creationTimestamp, and status are removed.
+
+Secrets are excluded by default. Use "--include-secrets" to also export the secrets attached to the
+default service account of the namespace. Exported secrets contain credentials and must be stored securely.
+Secrets are not created by "kp import" and must be applied with "kubectl apply -f".
+
+The namespace defaults to the kubernetes current-context namespace.`,
+		Example: `kp export --all
+kp export --cluster-builders --builders -n my-namespace
+kp export --all --include-secrets --output-file backup.yaml`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !flags.any() {
+				return errors.New("must select resources to export with --all or a resource type flag")
+			}
+
+			cs, err := clientSetProvider.GetClientSet(namespace)
+			if err != nil {
+				return err
+			}
+
+			objs, err := fetchResources(cmd.Context(), cs, flags)
+			if err != nil {
+				return err
+			}
+
+			if outputFile == "" {
+				return writeResources(cmd.OutOrStdout(), objs)
+			}
+
+			file, err := os.Create(outputFile)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			if err := writeResources(file, objs); err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Exported %d resources to %q\n", len(objs), outputFile)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	cmd.Flags().StringVar(&outputFile, "output-file", "", "file to write the exported resources to (default stdout)")
+	cmd.Flags().BoolVar(&flags.all, "all", false, "export all cluster stores, cluster stacks, cluster builders, builders, and images")
+	cmd.Flags().BoolVar(&flags.clusterStores, "cluster-stores", false, "export cluster stores")
+	cmd.Flags().BoolVar(&flags.clusterStacks, "cluster-stacks", false, "export cluster stacks")
+	cmd.Flags().BoolVar(&flags.clusterBuilders, "cluster-builders", false, "export cluster builders")
+	cmd.Flags().BoolVar(&flags.builders, "builders", false, "export builders")
+	cmd.Flags().BoolVar(&flags.images, "images", false, "export images")
+	cmd.Flags().BoolVar(&flags.includeSecrets, "include-secrets", false, "export secrets attached to the default service account")
+
+	return cmd
+}
+
+type exportedResource struct {
+	kind       string
+	apiVersion string
+	obj        runtime.Object
+}
+
+// resources are returned in dependency order so that the stream can be imported as is
+func fetchResources(ctx context.Context, cs k8s.ClientSet, flags resourceFlags) ([]exportedResource, error) {
+	var resources []exportedResource
+
+	if flags.all || flags.clusterStores {
+		list, err := cs.KpackClient.KpackV1alpha1().ClusterStores().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Name < list.Items[j].Name })
+		for i := range list.Items {
+			resources = append(resources, exportedResource{v1alpha1.ClusterStoreKind, kpackAPIVersion, &list.Items[i]})
+		}
+	}
+
+	if flags.all || flags.clusterStacks {
+		list, err := cs.KpackClient.KpackV1alpha1().ClusterStacks().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Name < list.Items[j].Name })
+		for i := range list.Items {
+			resources = append(resources, exportedResource{v1alpha1.ClusterStackKind, kpackAPIVersion, &list.Items[i]})
+		}
+	}
+
+	if flags.all || flags.clusterBuilders {
+		list, err := cs.KpackClient.KpackV1alpha1().ClusterBuilders().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Name < list.Items[j].Name })
+		for i := range list.Items {
+			resources = append(resources, exportedResource{v1alpha1.ClusterBuilderKind, kpackAPIVersion, &list.Items[i]})
+		}
+	}
+
+	if flags.includeSecrets {
+		secrets, err := fetchSecrets(ctx, cs)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range secrets {
+			resources = append(resources, exportedResource{"Secret", "v1", s})
+		}
+	}
+
+	if flags.all || flags.builders {
+		list, err := cs.KpackClient.KpackV1alpha1().Builders(cs.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Name < list.Items[j].Name })
+		for i := range list.Items {
+			resources = append(resources, exportedResource{v1alpha1.BuilderKind, kpackAPIVersion, &list.Items[i]})
+		}
+	}
+
+	if flags.all || flags.images {
+		list, err := cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Name < list.Items[j].Name })
+		for i := range list.Items {
+			resources = append(resources, exportedResource{imageKind, kpackAPIVersion, &list.Items[i]})
+		}
+	}
+
+	return resources, nil
+}
+
+func fetchSecrets(ctx context.Context, cs k8s.ClientSet) ([]*corev1.Secret, error) {
+	serviceAccount, err := cs.K8sClient.CoreV1().ServiceAccounts(cs.Namespace).Get(ctx, "default", metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	names := map[string]struct{}{}
+	for _, s := range serviceAccount.Secrets {
+		names[s.Name] = struct{}{}
+	}
+	for _, s := range serviceAccount.ImagePullSecrets {
+		names[s.Name] = struct{}{}
+	}
+
+	var sortedNames []string
+	for name := range names {
+		sortedNames = append(sortedNames, name)
+	}
+	sort.Strings(sortedNames)
+
+	var secrets []*corev1.Secret
+	for _, name := range sortedNames {
+		s, err := cs.K8sClient.CoreV1().Secrets(cs.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		// service account token secrets are generated by the cluster and cannot be restored
+		if s.Type == corev1.SecretTypeServiceAccountToken {
+			continue
+		}
+
+		secrets = append(secrets, s)
+	}
+	return secrets, nil
+}
+
+func writeResources(writer io.Writer, resources []exportedResource) error {
+	for i, r := range resources {
+		u, err := stripServerFields(r)
+		if err != nil {
+			return err
+		}
+
+		buf, err := yaml.Marshal(u)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			if _, err := io.WriteString(writer, "---\n"); err != nil {
+				return err
+			}
+		}
+
+		if _, err := writer.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func stripServerFields(r exportedResource) (map[string]interface{}, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(r.obj)
+	if err != nil {
+		return nil, err
+	}
+
+	u["apiVersion"] = r.apiVersion
+	u["kind"] = r.kind
+	delete(u, "status")
+
+	for _, field := range serverManagedFields {
+		unstructured.RemoveNestedField(u, "metadata", field)
+	}
+
+	return u, nil
+}
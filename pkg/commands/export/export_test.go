@@ -0,0 +1,247 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package export_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfakes "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/export"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestExportCommand(t *testing.T) {
+	spec.Run(t, "TestExportCommand", testExportCommand)
+}
+
+func testExportCommand(t *testing.T, when spec.G, it spec.S) {
+	const namespace = "some-namespace"
+
+	serverMeta := func(name, ns string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         ns,
+			ResourceVersion:   "123",
+			UID:               types.UID("some-uid"),
+			Generation:        2,
+			CreationTimestamp: metav1.Now(),
+			Annotations:       map[string]string{"some-key": "some-value"},
+		}
+	}
+
+	readyStatus := corev1alpha1.Status{
+		ObservedGeneration: 2,
+		Conditions: corev1alpha1.Conditions{
+			{Type: corev1alpha1.ConditionReady, Status: corev1.ConditionTrue},
+		},
+	}
+
+	store := &v1alpha1.ClusterStore{
+		ObjectMeta: serverMeta("some-store", ""),
+		Spec: v1alpha1.ClusterStoreSpec{
+			Sources: []v1alpha1.StoreImage{{Image: "some-registry.io/some-buildpackage"}},
+		},
+		Status: v1alpha1.ClusterStoreStatus{Status: readyStatus},
+	}
+
+	stack := &v1alpha1.ClusterStack{
+		ObjectMeta: serverMeta("some-stack", ""),
+		Spec: v1alpha1.ClusterStackSpec{
+			Id:         "some-stack-id",
+			BuildImage: v1alpha1.ClusterStackSpecImage{Image: "some-registry.io/build"},
+			RunImage:   v1alpha1.ClusterStackSpecImage{Image: "some-registry.io/run"},
+		},
+		Status: v1alpha1.ClusterStackStatus{Status: readyStatus},
+	}
+
+	clusterBuilder := &v1alpha1.ClusterBuilder{
+		ObjectMeta: serverMeta("some-cluster-builder", ""),
+		Spec: v1alpha1.ClusterBuilderSpec{
+			BuilderSpec: v1alpha1.BuilderSpec{
+				Tag:   "some-registry.io/some-cluster-builder",
+				Stack: corev1.ObjectReference{Kind: v1alpha1.ClusterStackKind, Name: "some-stack"},
+				Store: corev1.ObjectReference{Kind: v1alpha1.ClusterStoreKind, Name: "some-store"},
+			},
+		},
+		Status: v1alpha1.BuilderStatus{Status: readyStatus, LatestImage: "some-registry.io/some-cluster-builder@sha256:123"},
+	}
+
+	builder := &v1alpha1.Builder{
+		ObjectMeta: serverMeta("some-builder", namespace),
+		Spec: v1alpha1.NamespacedBuilderSpec{
+			BuilderSpec: v1alpha1.BuilderSpec{
+				Tag:   "some-registry.io/some-builder",
+				Stack: corev1.ObjectReference{Kind: v1alpha1.ClusterStackKind, Name: "some-stack"},
+				Store: corev1.ObjectReference{Kind: v1alpha1.ClusterStoreKind, Name: "some-store"},
+			},
+			ServiceAccount: "default",
+		},
+		Status: v1alpha1.BuilderStatus{Status: readyStatus},
+	}
+
+	otherNamespaceBuilder := builder.DeepCopy()
+	otherNamespaceBuilder.Name = "other-builder"
+	otherNamespaceBuilder.Namespace = "other-namespace"
+
+	img := &v1alpha1.Image{
+		ObjectMeta: serverMeta("some-image", namespace),
+		Spec: v1alpha1.ImageSpec{
+			Tag:            "some-registry.io/some-image",
+			Builder:        corev1.ObjectReference{Kind: v1alpha1.ClusterBuilderKind, Name: "some-cluster-builder"},
+			ServiceAccount: "default",
+			Source: v1alpha1.SourceConfig{
+				Git: &v1alpha1.Git{URL: "some-git-url", Revision: "main"},
+			},
+		},
+		Status: v1alpha1.ImageStatus{Status: readyStatus, LatestImage: "some-registry.io/some-image@sha256:123"},
+	}
+
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: namespace},
+		Secrets: []corev1.ObjectReference{
+			{Name: "some-git-secret"},
+			{Name: "default-token-abcde"},
+		},
+		ImagePullSecrets: []corev1.LocalObjectReference{
+			{Name: "some-registry-secret"},
+		},
+	}
+
+	gitSecret := &corev1.Secret{
+		ObjectMeta: serverMeta("some-git-secret", namespace),
+		Type:       corev1.SecretTypeBasicAuth,
+		Data:       map[string][]byte{"username": []byte("some-user"), "password": []byte("some-password")},
+	}
+
+	registrySecret := &corev1.Secret{
+		ObjectMeta: serverMeta("some-registry-secret", namespace),
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte("{}")},
+	}
+
+	tokenSecret := &corev1.Secret{
+		ObjectMeta: serverMeta("default-token-abcde", namespace),
+		Type:       corev1.SecretTypeServiceAccountToken,
+	}
+
+	var out *bytes.Buffer
+
+	runExport := func(args ...string) error {
+		k8sClient := k8sfakes.NewSimpleClientset(serviceAccount, gitSecret, registrySecret, tokenSecret)
+		kpackClient := kpackfakes.NewSimpleClientset(store, stack, clusterBuilder, builder, otherNamespaceBuilder, img)
+
+		cmd := export.NewExportCommand(testhelpers.GetFakeClusterProvider(k8sClient, kpackClient))
+		out = &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(out)
+		cmd.SetArgs(append([]string{"-n", namespace}, args...))
+		return cmd.Execute()
+	}
+
+	readDocuments := func(stream string) []map[string]interface{} {
+		var docs []map[string]interface{}
+		for _, raw := range strings.Split(stream, "---\n") {
+			var doc map[string]interface{}
+			require.NoError(t, yaml.Unmarshal([]byte(raw), &doc))
+			docs = append(docs, doc)
+		}
+		return docs
+	}
+
+	kindsAndNames := func(docs []map[string]interface{}) []string {
+		var result []string
+		for _, doc := range docs {
+			metadata := doc["metadata"].(map[string]interface{})
+			result = append(result, doc["kind"].(string)+"/"+metadata["name"].(string))
+		}
+		return result
+	}
+
+	it("exports all kpack resources in dependency order without secrets", func() {
+		require.NoError(t, runExport("--all"))
+
+		docs := readDocuments(out.String())
+		require.Equal(t, []string{
+			"ClusterStore/some-store",
+			"ClusterStack/some-stack",
+			"ClusterBuilder/some-cluster-builder",
+			"Builder/some-builder",
+			"Image/some-image",
+		}, kindsAndNames(docs))
+	})
+
+	it("strips server managed fields and status", func() {
+		require.NoError(t, runExport("--all"))
+
+		for _, doc := range readDocuments(out.String()) {
+			require.Equal(t, "kpack.io/v1alpha1", doc["apiVersion"])
+			require.NotContains(t, doc, "status")
+			require.Contains(t, doc, "spec")
+
+			metadata := doc["metadata"].(map[string]interface{})
+			for _, field := range []string{"resourceVersion", "uid", "creationTimestamp", "generation", "selfLink", "managedFields"} {
+				require.NotContains(t, metadata, field)
+			}
+			require.Equal(t, map[string]interface{}{"some-key": "some-value"}, metadata["annotations"])
+		}
+	})
+
+	it("exports only the selected resource types", func() {
+		require.NoError(t, runExport("--cluster-stacks", "--images"))
+
+		require.Equal(t, []string{
+			"ClusterStack/some-stack",
+			"Image/some-image",
+		}, kindsAndNames(readDocuments(out.String())))
+	})
+
+	it("exports secrets attached to the default service account when include-secrets flag is used", func() {
+		require.NoError(t, runExport("--builders", "--include-secrets"))
+
+		docs := readDocuments(out.String())
+		require.Equal(t, []string{
+			"Secret/some-git-secret",
+			"Secret/some-registry-secret",
+			"Builder/some-builder",
+		}, kindsAndNames(docs))
+
+		require.Equal(t, "v1", docs[0]["apiVersion"])
+		require.NotContains(t, docs[0]["metadata"], "resourceVersion")
+		require.Equal(t, map[string]interface{}{"username": "c29tZS11c2Vy", "password": "c29tZS1wYXNzd29yZA=="}, docs[0]["data"])
+	})
+
+	it("writes the resources to a file when output-file flag is used", func() {
+		dir, err := ioutil.TempDir("", "kp-export")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		file := filepath.Join(dir, "export.yaml")
+		require.NoError(t, runExport("--cluster-stores", "--output-file", file))
+		require.Equal(t, "Exported 1 resources to \""+file+"\"\n", out.String())
+
+		buf, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		require.Equal(t, []string{"ClusterStore/some-store"}, kindsAndNames(readDocuments(string(buf))))
+	})
+
+	it("errors when no resources are selected", func() {
+		err := runExport()
+		require.EqualError(t, err, "must select resources to export with --all or a resource type flag")
+	})
+}
@@ -0,0 +1,32 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fakes
+
+import (
+	"context"
+	"io"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+)
+
+type TailCall struct {
+	Image     string
+	Build     string
+	Namespace string
+	Options   build.LogOptions
+}
+
+type FakeBuildLogsTailer struct {
+	Calls []TailCall
+}
+
+func (f *FakeBuildLogsTailer) Tail(ctx context.Context, writer io.Writer, image, build, namespace string) error {
+	f.Calls = append(f.Calls, TailCall{Image: image, Build: build, Namespace: namespace})
+	return nil
+}
+
+func (f *FakeBuildLogsTailer) TailWithOptions(ctx context.Context, writer io.Writer, image, buildNumber, namespace string, opts build.LogOptions) error {
+	f.Calls = append(f.Calls, TailCall{Image: image, Build: buildNumber, Namespace: namespace, Options: opts})
+	return nil
+}
@@ -11,10 +11,14 @@ import (
 )
 
 type FakeImageWaiter struct {
-	Calls []*v1alpha1.Image
+	Calls   []*v1alpha1.Image
+	Writers []io.Writer
+	Logs    string
 }
 
 func (f *FakeImageWaiter) Wait(ctx context.Context, writer io.Writer, image *v1alpha1.Image) (string, error) {
 	f.Calls = append(f.Calls, image)
-	return "", nil
+	f.Writers = append(f.Writers, writer)
+	_, err := io.WriteString(writer, f.Logs)
+	return "", err
 }
@@ -0,0 +1,30 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fakes
+
+import (
+	"github.com/pkg/errors"
+)
+
+type ResolveCall struct {
+	Namespace      string
+	ServiceAccount string
+	URL            string
+	Revision       string
+}
+
+type FakeRevisionResolver struct {
+	Commits map[string]string
+	Calls   []ResolveCall
+}
+
+func (f *FakeRevisionResolver) Resolve(namespace, serviceAccount, url, revision string) (string, error) {
+	f.Calls = append(f.Calls, ResolveCall{Namespace: namespace, ServiceAccount: serviceAccount, URL: url, Revision: revision})
+
+	commit, ok := f.Commits[url+"@"+revision]
+	if !ok {
+		return "", errors.Errorf("failed to list refs of %q", url)
+	}
+	return commit, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+var blobHttpClient = &http.Client{Timeout: 30 * time.Second}
+
+// setupBlobAuth checks that the blob source of the image can be read with the
+// credentials of the secret and attaches the secret to the image service account
+func setupBlobAuth(ctx context.Context, secretName string, img *v1alpha1.Image, ch *commands.CommandHelper, cs k8s.ClientSet) error {
+	if secretName == "" {
+		return nil
+	}
+
+	if img.Spec.Source.Blob == nil {
+		return errors.New("blob-auth-secret can only be used with a blob source")
+	}
+
+	if ch.IsDryRun() {
+		return nil
+	}
+
+	secret, err := cs.K8sClient.CoreV1().Secrets(cs.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return errors.Errorf("blob auth secret %q not found in namespace %q", secretName, cs.Namespace)
+	} else if err != nil {
+		return err
+	}
+
+	if secret.Type != corev1.SecretTypeBasicAuth {
+		return errors.Errorf("blob auth secret %q must be of type %q", secretName, corev1.SecretTypeBasicAuth)
+	}
+
+	if err := checkBlobAccess(ctx, img.Spec.Source.Blob.URL, secret); err != nil {
+		return err
+	}
+
+	return attachSecret(ctx, img.Spec.ServiceAccount, secretName, ch, cs)
+}
+
+func checkBlobAccess(ctx context.Context, url string, secret *corev1.Secret) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Wrapf(err, "invalid blob url %q", url)
+	}
+	req.SetBasicAuth(string(secret.Data[corev1.BasicAuthUsernameKey]), string(secret.Data[corev1.BasicAuthPasswordKey]))
+	req.Header.Set("Range", "bytes=0-0")
+
+	resp, err := blobHttpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "blob %q is not reachable", url)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return errors.Errorf("access to blob %q was denied with the credentials in secret %q (%d %s), check the secret username and password", url, secret.Name, resp.StatusCode, http.StatusText(resp.StatusCode))
+	case resp.StatusCode >= http.StatusBadRequest:
+		return errors.Errorf("blob %q is not reachable (%d %s)", url, resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	return nil
+}
+
+func attachSecret(ctx context.Context, saName, secretName string, ch *commands.CommandHelper, cs k8s.ClientSet) error {
+	if saName == "" {
+		saName = "default"
+	}
+
+	sa, err := cs.K8sClient.CoreV1().ServiceAccounts(cs.Namespace).Get(ctx, saName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return errors.Errorf("service account %q not found in namespace %q", saName, cs.Namespace)
+	} else if err != nil {
+		return err
+	}
+
+	for _, s := range sa.Secrets {
+		if s.Name == secretName {
+			return nil
+		}
+	}
+
+	sa.Secrets = append(sa.Secrets, corev1.ObjectReference{Name: secretName})
+	if _, err := cs.K8sClient.CoreV1().ServiceAccounts(cs.Namespace).Update(ctx, sa, ch.UpdateOptions()); err != nil {
+		return err
+	}
+
+	return ch.Printlnf("Secret %q added to service account %q", secretName, saName)
+}
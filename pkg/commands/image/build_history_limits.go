@@ -0,0 +1,30 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/image"
+)
+
+type buildHistoryLimitFlags struct {
+	success int64
+	failed  int64
+}
+
+func setBuildHistoryLimitFlags(cmd *cobra.Command, flags *buildHistoryLimitFlags) {
+	cmd.Flags().Int64Var(&flags.success, "success-build-history-limit", 0, "number of successful builds to keep")
+	cmd.Flags().Int64Var(&flags.failed, "failed-build-history-limit", 0, "number of failed builds to keep")
+}
+
+func (b *buildHistoryLimitFlags) apply(cmd *cobra.Command, factory *image.Factory) {
+	if cmd.Flags().Changed("success-build-history-limit") {
+		factory.SuccessBuildHistoryLimit = &b.success
+	}
+
+	if cmd.Flags().Changed("failed-build-history-limit") {
+		factory.FailedBuildHistoryLimit = &b.failed
+	}
+}
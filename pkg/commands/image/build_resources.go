@@ -0,0 +1,40 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/image"
+)
+
+type buildResourceFlags struct {
+	requestCPU    string
+	requestMemory string
+	limitCPU      string
+	limitMemory   string
+}
+
+func setBuildResourceFlags(cmd *cobra.Command, flags *buildResourceFlags) {
+	cmd.Flags().StringVar(&flags.requestCPU, "build-request-cpu", "", "cpu request for the build pod as a kubernetes quantity")
+	cmd.Flags().StringVar(&flags.requestMemory, "build-request-memory", "", "memory request for the build pod as a kubernetes quantity")
+	cmd.Flags().StringVar(&flags.limitCPU, "build-limit-cpu", "", "cpu limit for the build pod as a kubernetes quantity")
+	cmd.Flags().StringVar(&flags.limitMemory, "build-limit-memory", "", "memory limit for the build pod as a kubernetes quantity")
+}
+
+func (b *buildResourceFlags) buildResources(cmd *cobra.Command) image.BuildResources {
+	changed := func(name string, value *string) *string {
+		if cmd.Flags().Changed(name) {
+			return value
+		}
+		return nil
+	}
+
+	return image.BuildResources{
+		RequestCPU:    changed("build-request-cpu", &b.requestCPU),
+		RequestMemory: changed("build-request-memory", &b.requestMemory),
+		LimitCPU:      changed("build-limit-cpu", &b.limitCPU),
+		LimitMemory:   changed("build-limit-memory", &b.limitMemory),
+	}
+}
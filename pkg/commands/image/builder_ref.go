@@ -0,0 +1,94 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/image"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+const maxAvailableBuilders = 5
+
+// validateBuilderRef checks that the builder or cluster builder provided with flags exists so that
+// the image does not sit NotReady, it is skipped with --force and for dry runs
+func validateBuilderRef(ctx context.Context, cs k8s.ClientSet, ch *commands.CommandHelper, force bool, builder, clusterBuilder string) error {
+	if builder != "" && clusterBuilder != "" {
+		return errors.New("must provide one of builder or cluster-builder")
+	}
+
+	if force || ch.IsDryRun() {
+		return nil
+	}
+
+	if builder != "" {
+		_, err := cs.KpackClient.KpackV1alpha1().Builders(cs.Namespace).Get(ctx, builder, metav1.GetOptions{})
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+
+		list, err := cs.KpackClient.KpackV1alpha1().Builders(cs.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		var names []string
+		for _, b := range list.Items {
+			names = append(names, b.Name)
+		}
+		return errors.Errorf("builder '%s' not found in namespace '%s'; available: [%s]", builder, cs.Namespace, availableNames(names))
+	}
+
+	if clusterBuilder != "" {
+		_, err := cs.KpackClient.KpackV1alpha1().ClusterBuilders().Get(ctx, clusterBuilder, metav1.GetOptions{})
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+
+		list, err := cs.KpackClient.KpackV1alpha1().ClusterBuilders().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		var names []string
+		for _, b := range list.Items {
+			names = append(names, b.Name)
+		}
+		return errors.Errorf("clusterbuilder '%s' not found; available: [%s]", clusterBuilder, availableNames(names))
+	}
+
+	return nil
+}
+
+// useDefaultClusterBuilder sets the default cluster builder of the kp-config ConfigMap when no builder is provided,
+// the image uses the "default" cluster builder when it is not set
+func useDefaultClusterBuilder(ctx context.Context, cs k8s.ClientSet, ch *commands.CommandHelper, factory *image.Factory) error {
+	if factory.Builder != "" || factory.ClusterBuilder != "" {
+		return nil
+	}
+
+	name, err := k8s.DefaultConfigHelper(cs).GetDefaultClusterBuilder(ctx)
+	if err != nil || name == "" {
+		return err
+	}
+
+	factory.ClusterBuilder = name
+	return ch.Printlnf("Using default cluster builder %q", name)
+}
+
+func availableNames(names []string) string {
+	sort.Strings(names)
+	if len(names) > maxAvailableBuilders {
+		names = append(names[:maxAvailableBuilders], "...")
+	}
+	return strings.Join(names, ", ")
+}
@@ -5,24 +5,32 @@ package image
 
 import (
 	"context"
+	"net/url"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
 	"github.com/vmware-tanzu/kpack-cli/pkg/image"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
 )
 
-func NewCreateCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newImageWaiter func(k8s.ClientSet) ImageWaiter) *cobra.Command {
+func NewCreateCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newImageWaiter func(k8s.ClientSet) ImageWaiter, newRevisionResolver func(k8s.ClientSet) image.RevisionResolver) *cobra.Command {
 	var (
 		tag       string
+		file      string
 		namespace string
 		subPath   string
 		factory   image.Factory
 		tlsCfg    registry.TLSConfig
+		resources buildResourceFlags
+		limits    buildHistoryLimitFlags
+		quiet     bool
+		logs      bool
+		force     bool
 	)
 
 	cmd := &cobra.Command{
@@ -33,25 +41,65 @@ This image will be created only if it does not exist in the provided namespace.
 
 The namespace defaults to the kubernetes current-context namespace.
 
+Image resources may also be read from a YAML file by using the "--file" flag, or "--file -" to read from stdin.
+Each Image document in the file is created. The tag defaults to the canonical repository followed by
+the image name and the service account defaults to "default" when they are not set in the file.
+Flags provided with "--file" take precedence over values from the file.
+
 The flags for this command determine how the build will retrieve source code:
 
   "--git" and "--git-revision" to use Git based source
-  "--blob" to use source code hosted in a blob store
+  "--blob" to use source code hosted in a blob store, the url must use the http or https scheme
   "--local-path" to use source code from the local machine
 
+When neither "--builder" nor "--cluster-builder" is provided, the image uses the cluster builder set with
+"kp config default-cluster-builder", or the "default" cluster builder if it is not set.
+
+Use "--blob-auth-secret" with a blob source to download the blob with the credentials of a "kubernetes.io/basic-auth" secret.
+The blob is checked to be readable with the credentials and the secret is added to the image service account.
+
+Use "--pin-revision" with Git based source to replace a branch or tag with the commit it currently points to.
+The commit is resolved with git ls-remote using the git secrets of the image service account.
+
 Local source code will be pushed to the same registry provided for the image tag.
 Therefore, you must have credentials to access the registry on your machine.
+
+If a "project.toml" project descriptor exists at the root of the local path, or one is provided
+with the "--project-descriptor" flag, its "build.env" entries are added to the build environment
+and its "build.include" or "build.exclude" patterns are used to filter the uploaded source code.
+Values provided with the "--env" and "--env-file" flags take precedence over the descriptor.
+
+Files may be excluded from the uploaded local source code with ".gitignore" and ".kpignore" files at the root
+of the local path or with the "--exclude" or "--local-path-exclude" flags. All use gitignore syntax, including "**"
+and "!" negation, and ".kpignore" patterns take precedence over ".gitignore" patterns.
+Use "--verbose" to list the excluded files, for example with "--dry-run" to check the patterns.
 --registry-ca-cert-path and --registry-verify-certs are only used for local source type.
 
 Environment variables may be provided by using the "--env" flag.
 For each environment variable, supply the "--env" flag followed by the key value pair.
-For example, "--env key1=value1 --env key2=value2 ...".`,
+For example, "--env key1=value1 --env key2=value2 ...".
+
+Environment variables may also be loaded from a file by using the "--env-file" flag.
+The file must contain one "key=value" pair per line. Blank lines and lines starting with "#" are ignored.
+Use "--env-file -" to read the file from stdin.
+Values provided with the "--env" flag take precedence over values from the file.
+
+Service bindings may be provided by using the "--service-binding" flag.
+For each binding, supply the "--service-binding" flag followed by a name. The name must refer to
+a secret and a metadata config map of the same name in the image namespace.
+Use "--service-binding name=secret-name" to bind a secret with a different name than the binding.
+The binding secret is checked to exist before the image is submitted, with "--dry-run" a missing secret is a warning.
+
+Build pod resources may be set by using the "--build-request-cpu", "--build-request-memory",
+"--build-limit-cpu" and "--build-limit-memory" flags with kubernetes quantities.`,
 		Example: `kp image create my-image --tag my-registry.com/my-repo --git https://my-repo.com/my-app.git --git-revision my-branch
+kp image create my-image --tag my-registry.com/my-repo --git https://my-repo.com/my-monorepo.git --sub-path app/backend
 kp image create my-image --tag my-registry.com/my-repo --blob https://my-blob-host.com/my-blob
 kp image create my-image --tag my-registry.com/my-repo --local-path /path/to/local/source/code
 kp image create my-image --tag my-registry.com/my-repo --local-path /path/to/local/source/code --builder my-builder -n my-namespace
-kp image create my-image --tag my-registry.com/my-repo --blob https://my-blob-host.com/my-blob --env foo=bar --env color=red --env food=apple`,
-		Args:         commands.ExactArgsWithUsage(1),
+kp image create my-image --tag my-registry.com/my-repo --blob https://my-blob-host.com/my-blob --env foo=bar --env color=red --env food=apple
+kp image create -f image.yaml --env foo=bar`,
+		Args:         imageFileArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet(namespace)
@@ -64,68 +112,154 @@ kp image create my-image --tag my-registry.com/my-repo --blob https://my-blob-ho
 				return err
 			}
 
-			name := args[0]
-
-			factory.SubPath = &subPath
-			factory.SourceUploader = rup.SourceUploader(ch.Writer(), tlsCfg, ch.IsUploading())
+			factory.SourceUploader = rup.SourceUploader(uploadWriter(ch, quiet), tlsCfg, ch.IsUploading())
 			factory.Printer = ch
+			factory.RevisionResolver = newRevisionResolver(cs)
+			factory.Stdin = cmd.InOrStdin()
+			factory.BuildResources = resources.buildResources(cmd)
+			limits.apply(cmd, &factory)
+
+			if err := validateBlobURL(factory.Blob); err != nil {
+				return err
+			}
 
 			ctx := cmd.Context()
-			img, err := create(ctx, name, tag, &factory, ch, cs)
+
+			if file == "" {
+				if err := useDefaultClusterBuilder(ctx, cs, ch, &factory); err != nil {
+					return err
+				}
+			}
+
+			if err := validateBuilderRef(ctx, cs, ch, force, factory.Builder, factory.ClusterBuilder); err != nil {
+				return err
+			}
+
+			if file != "" {
+				if cmd.Flags().Changed("sub-path") {
+					factory.SubPath = &subPath
+				}
+
+				// the service account in the file is only overridden by an explicit flag
+				if !cmd.Flags().Changed("service-account") {
+					factory.ServiceAccount = ""
+				}
+
+				images, err := applyImageFile(ctx, cmd, file, tag, false, &factory, ch, cs)
+				if err != nil {
+					return err
+				}
+
+				if ch.ShouldWait() {
+					for _, img := range images {
+						if err := waitForImage(ctx, buildLogsWriter(cmd, logs), ch, cs, newImageWaiter(cs), img); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			}
+
+			if tag == "" {
+				return errors.New(`required flag(s) "tag" not set`)
+			}
+
+			factory.SubPath = &subPath
+			img, err := create(ctx, args[0], tag, &factory, ch, cs)
 			if err != nil {
 				return err
 			}
 
 			if ch.ShouldWait() {
-				_, err := newImageWaiter(cs).Wait(ctx, cmd.OutOrStdout(), img)
-				if err != nil {
+				if err := waitForImage(ctx, buildLogsWriter(cmd, logs), ch, cs, newImageWaiter(cs), img); err != nil {
 					return err
 				}
 			}
 			return nil
 		},
 	}
+	cmd.Flags().StringVarP(&file, "file", "f", "", "path to a file of image resources to create, or \"-\" to read from stdin")
 	cmd.Flags().StringVarP(&tag, "tag", "t", "", "registry location where the image will be created")
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
 	cmd.Flags().StringVar(&factory.GitRepo, "git", "", "git repository url")
 	cmd.Flags().StringVar(&factory.GitRevision, "git-revision", "", "git revision such as commit, tag, or branch (default \"main\")")
-	cmd.Flags().StringVar(&factory.Blob, "blob", "", "source code blob url")
+	cmd.Flags().BoolVar(&factory.PinRevision, "pin-revision", false, "resolve the git revision to the commit it currently points to")
+	cmd.Flags().StringVar(&factory.Blob, "blob", "", "source code blob url, must use the http or https scheme")
+	cmd.Flags().StringVar(&factory.BlobAuthSecret, "blob-auth-secret", "", "name of a basic-auth secret used to download the source code blob")
 	cmd.Flags().StringVar(&factory.LocalPath, "local-path", "", "path to local source code")
+	cmd.Flags().StringVar(&factory.ProjectDescriptor, "project-descriptor", "", "path to a project.toml descriptor for local source code (default \"<local-path>/project.toml\" if present)")
+	setExcludeFlags(cmd, &factory.Exclude)
+	cmd.Flags().BoolVarP(&factory.Verbose, "verbose", "v", false, "list the local source files excluded from the upload")
+	setQuietFlag(cmd, &quiet)
 	cmd.Flags().StringVar(&subPath, "sub-path", "", "build code at the sub path located within the source code directory")
 	cmd.Flags().StringVarP(&factory.Builder, "builder", "b", "", "builder name")
 	cmd.Flags().StringVarP(&factory.ClusterBuilder, "cluster-builder", "c", "", "cluster builder name")
+	cmd.Flags().BoolVar(&force, "force", false, "skip checking that the builder or cluster builder exists")
+	cmd.Flags().StringVar(&factory.ServiceAccount, "service-account", "default", "service account used for builds")
 	cmd.Flags().StringArrayVar(&factory.Env, "env", []string{}, "build time environment variables")
+	cmd.Flags().StringVar(&factory.EnvFile, "env-file", "", "path to a file of build time environment variables, or \"-\" to read from stdin")
+	cmd.Flags().StringArrayVar(&factory.Bindings, "service-binding", []string{}, "name of a service binding secret and metadata config map to bind to the build (format: name or name=secret-name)")
 	cmd.Flags().StringVar(&factory.CacheSize, "cache-size", "", "cache size as a kubernetes quantity (default \"2G\")")
+	setBuildResourceFlags(cmd, &resources)
+	setBuildHistoryLimitFlags(cmd, &limits)
+	commands.SetMetadataFlags(cmd, &factory.Metadata)
 	cmd.Flags().BoolP("wait", "w", false, "wait for image create to be reconciled and tail resulting build logs")
+	setLogsFlag(cmd, &logs)
+	commands.SetWaitTimeoutFlag(cmd)
 	commands.SetImgUploadDryRunOutputFlags(cmd)
+	commands.SetServerDryRunFlag(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
-	_ = cmd.MarkFlagRequired("tag")
+	commands.SetConfigDefault(cmd, "service-account", config.DefaultServiceAccountKey)
 	return cmd
 }
 
+func validateBlobURL(blob string) error {
+	if blob == "" {
+		return nil
+	}
+
+	u, err := url.Parse(blob)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.Errorf("blob url %q must use the http or https scheme", blob)
+	}
+	return nil
+}
+
 func create(ctx context.Context, name, tag string, factory *image.Factory, ch *commands.CommandHelper, cs k8s.ClientSet) (*v1alpha1.Image, error) {
 	if err := ch.PrintStatus("Creating Image..."); err != nil {
 		return nil, err
 	}
 
+	if err := validateServiceBindings(ctx, cs, ch, factory.Bindings); err != nil {
+		return nil, err
+	}
+
 	img, err := factory.MakeImage(name, cs.Namespace, tag)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := setupBlobAuth(ctx, factory.BlobAuthSecret, img, ch, cs); err != nil {
+		return nil, err
+	}
+
+	return submitCreate(ctx, img, ch, cs)
+}
+
+func submitCreate(ctx context.Context, img *v1alpha1.Image, ch *commands.CommandHelper, cs k8s.ClientSet) (*v1alpha1.Image, error) {
 	if err := k8s.SetLastAppliedCfg(img); err != nil {
 		return nil, err
 	}
 
-	if !ch.IsDryRun() {
-		img, err = cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).Create(ctx, img, metav1.CreateOptions{})
+	if ch.ShouldSubmit() {
+		var err error
+		img, err = cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).Create(ctx, img, ch.CreateOptions())
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	err = ch.PrintObj(img)
-	if err != nil {
+	if err := ch.PrintObj(img); err != nil {
 		return nil, err
 	}
 
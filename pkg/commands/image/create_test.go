@@ -4,6 +4,11 @@
 package image_test
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
@@ -15,9 +20,12 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	k8sfakes "k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
 
 	cmdFakes "github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
 	imgcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/image"
+	"github.com/vmware-tanzu/kpack-cli/pkg/image"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 	registryfakes "github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
@@ -30,14 +38,29 @@ func TestImageCreateCommand(t *testing.T) {
 func testImageCreateCommand(t *testing.T, when spec.G, it spec.S) {
 	const defaultNamespace = "some-default-namespace"
 
+	someBuilder := &v1alpha1.Builder{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "some-builder",
+			Namespace: defaultNamespace,
+		},
+	}
+	someClusterBuilder := &v1alpha1.ClusterBuilder{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "some-builder",
+		},
+	}
+
 	registryUtilProvider := registryfakes.UtilProvider{}
 
 	fakeImageWaiter := &cmdFakes.FakeImageWaiter{}
+	fakeRevisionResolver := &cmdFakes.FakeRevisionResolver{}
 
 	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
 		clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
 		return imgcmds.NewCreateCommand(clientSetProvider, registryUtilProvider, func(set k8s.ClientSet) imgcmds.ImageWaiter {
 			return fakeImageWaiter
+		}, func(set k8s.ClientSet) image.RevisionResolver {
+			return fakeRevisionResolver
 		})
 	}
 
@@ -111,6 +134,35 @@ Image "some-image" created
 				assert.Equal(t, fakeImageWaiter.Calls[0], expectedImage)
 			})
 
+			it("sets the provided labels and annotations", func() {
+				expectedImage.Labels = map[string]string{"team": "some-team"}
+				expectedImage.Annotations = map[string]string{
+					"description": "some description",
+					"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-namespace","creationTimestamp":null,"labels":{"team":"some-team"},"annotations":{"description":"some description"}},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"default"},"serviceAccount":"default","source":{"git":{"url":"some-git-url","revision":"some-git-rev"},"subPath":"some-sub-path"},"cacheSize":"2G","build":{"env":[{"name":"some-key","value":"some-val"}],"resources":{}}},"status":{}}`,
+				}
+
+				testhelpers.CommandTest{
+					Args: []string{
+						"some-image",
+						"--tag", "some-registry.io/some-repo",
+						"--git", "some-git-url",
+						"--git-revision", "some-git-rev",
+						"--sub-path", "some-sub-path",
+						"--env", "some-key=some-val",
+						"--cache-size", "2G",
+						"--label", "team=some-team",
+						"--annotation", "description=some description",
+						"-n", namespace,
+					},
+					ExpectedOutput: `Creating Image...
+Image "some-image" created
+`,
+					ExpectCreates: []runtime.Object{
+						expectedImage,
+					},
+				}.TestKpack(t, cmdFunc)
+			})
+
 			it("defaults the git revision to main", func() {
 				expectedImage.Spec.Source.Git.Revision = "main"
 				expectedImage.ObjectMeta.Annotations["kubectl.kubernetes.io/last-applied-configuration"] = `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"default"},"serviceAccount":"default","source":{"git":{"url":"some-git-url","revision":"main"},"subPath":"some-sub-path"},"cacheSize":"2G","build":{"env":[{"name":"some-key","value":"some-val"}],"resources":{}}},"status":{}}`
@@ -135,13 +187,72 @@ Image "some-image" created
 			})
 		})
 
+		when("the image is not ready before the wait timeout", func() {
+			it("returns a timeout error", func() {
+				cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
+					clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+					return imgcmds.NewCreateCommand(clientSetProvider, registryUtilProvider, func(set k8s.ClientSet) imgcmds.ImageWaiter {
+						return neverReadyImageWaiter{}
+					}, func(set k8s.ClientSet) image.RevisionResolver {
+						return fakeRevisionResolver
+					})
+				}
+
+				testhelpers.CommandTest{
+					Args: []string{
+						"some-image",
+						"--tag", "some-registry.io/some-repo",
+						"--git", "some-git-url",
+						"-n", namespace,
+						"--wait",
+						"--wait-timeout", "10ms",
+					},
+					ExpectErr: true,
+					ExpectedOutput: `Creating Image...
+Image "some-image" created
+Error: timed out after 10ms waiting for Image "some-image" to be ready
+`,
+					ExpectCreates: []runtime.Object{
+						&v1alpha1.Image{
+							TypeMeta: metav1.TypeMeta{
+								Kind:       "Image",
+								APIVersion: "kpack.io/v1alpha1",
+							},
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "some-image",
+								Namespace: namespace,
+								Annotations: map[string]string{
+									"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"default"},"serviceAccount":"default","source":{"git":{"url":"some-git-url","revision":"main"}},"build":{"resources":{}}},"status":{}}`,
+								},
+							},
+							Spec: v1alpha1.ImageSpec{
+								Tag: "some-registry.io/some-repo",
+								Builder: corev1.ObjectReference{
+									Kind: v1alpha1.ClusterBuilderKind,
+									Name: "default",
+								},
+								ServiceAccount: "default",
+								Source: v1alpha1.SourceConfig{
+									Git: &v1alpha1.Git{
+										URL:      "some-git-url",
+										Revision: "main",
+									},
+								},
+								Build: &v1alpha1.ImageBuild{},
+							},
+						},
+					},
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
 		when("the image config is invalid", func() {
 			it("returns an error", func() {
 				testhelpers.CommandTest{
 					Args: []string{
 						"some-image",
 						"--tag", "some-registry.io/some-repo",
-						"--blob", "some-blob",
+						"--blob", "https://some-blob-host.com/some-blob",
 						"--git", "some-git-url",
 						"-n", namespace,
 					},
@@ -223,7 +334,7 @@ Image "some-image" created
 					Args: []string{
 						"some-image",
 						"--tag", "some-registry.io/some-repo",
-						"--blob", "some-blob",
+						"--blob", "https://some-blob-host.com/some-blob",
 						"--git", "some-git-url",
 					},
 					ExpectErr: true,
@@ -308,7 +419,7 @@ Image "some-image" created
 					Name:      "some-image",
 					Namespace: defaultNamespace,
 					Annotations: map[string]string{
-						"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-default-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"Builder","namespace":"some-default-namespace","name":"some-builder"},"serviceAccount":"default","source":{"blob":{"url":"some-blob"}},"build":{"resources":{}}},"status":{}}`,
+						"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-default-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"Builder","namespace":"some-default-namespace","name":"some-builder"},"serviceAccount":"default","source":{"blob":{"url":"https://some-blob-host.com/some-blob"}},"build":{"resources":{}}},"status":{}}`,
 					},
 				},
 				Spec: v1alpha1.ImageSpec{
@@ -321,7 +432,7 @@ Image "some-image" created
 					ServiceAccount: "default",
 					Source: v1alpha1.SourceConfig{
 						Blob: &v1alpha1.Blob{
-							URL: "some-blob",
+							URL: "https://some-blob-host.com/some-blob",
 						},
 					},
 					Build: &v1alpha1.ImageBuild{},
@@ -329,10 +440,13 @@ Image "some-image" created
 			}
 
 			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					someBuilder,
+				},
 				Args: []string{
 					"some-image",
 					"--tag", "some-registry.io/some-repo",
-					"--blob", "some-blob",
+					"--blob", "https://some-blob-host.com/some-blob",
 					"--builder", "some-builder",
 				},
 				ExpectedOutput: `Creating Image...
@@ -358,7 +472,7 @@ Image "some-image" created
 					Name:      "some-image",
 					Namespace: defaultNamespace,
 					Annotations: map[string]string{
-						"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-default-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"some-builder"},"serviceAccount":"default","source":{"blob":{"url":"some-blob"}},"build":{"resources":{}}},"status":{}}`,
+						"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-default-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"some-builder"},"serviceAccount":"default","source":{"blob":{"url":"https://some-blob-host.com/some-blob"}},"build":{"resources":{}}},"status":{}}`,
 					},
 				},
 				Spec: v1alpha1.ImageSpec{
@@ -370,7 +484,7 @@ Image "some-image" created
 					ServiceAccount: "default",
 					Source: v1alpha1.SourceConfig{
 						Blob: &v1alpha1.Blob{
-							URL: "some-blob",
+							URL: "https://some-blob-host.com/some-blob",
 						},
 					},
 					Build: &v1alpha1.ImageBuild{},
@@ -378,10 +492,13 @@ Image "some-image" created
 			}
 
 			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					someClusterBuilder,
+				},
 				Args: []string{
 					"some-image",
 					"--tag", "some-registry.io/some-repo",
-					"--blob", "some-blob",
+					"--blob", "https://some-blob-host.com/some-blob",
 					"--cluster-builder", "some-builder",
 				},
 				ExpectedOutput: `Creating Image...
@@ -396,6 +513,142 @@ Image "some-image" created
 		})
 	})
 
+	when("the blob url does not use the http or https scheme", func() {
+		it("returns an error", func() {
+			for _, blob := range []string{"some-blob", "ftp://some-blob-host.com/some-blob", "s3://some-bucket/some-blob"} {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						someClusterBuilder,
+					},
+					Args: []string{
+						"some-image",
+						"--tag", "some-registry.io/some-repo",
+						"--blob", blob,
+						"--cluster-builder", "some-builder",
+					},
+					ExpectErr:      true,
+					ExpectedOutput: fmt.Sprintf("Error: blob url %q must use the http or https scheme\n", blob),
+				}.TestKpack(t, cmdFunc)
+			}
+		})
+	})
+
+	when("a blob and a local path are both provided", func() {
+		it("returns an error", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					someClusterBuilder,
+				},
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", "https://some-blob-host.com/some-blob",
+					"--local-path", "some-local-path",
+					"--cluster-builder", "some-builder",
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Creating Image...
+Error: image source must be one of git, blob, or local-path
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+	})
+
+	when("the builder does not exist", func() {
+		it("returns an error listing the available cluster builders", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					someClusterBuilder,
+				},
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", "https://some-blob-host.com/some-blob",
+					"--cluster-builder", "nonexistent",
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Error: clusterbuilder 'nonexistent' not found; available: [some-builder]
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("returns an error listing the available builders in the namespace", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					someBuilder,
+				},
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", "https://some-blob-host.com/some-blob",
+					"--builder", "nonexistent",
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Error: builder 'nonexistent' not found in namespace 'some-default-namespace'; available: [some-builder]
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("creates the image with the force flag", func() {
+			testhelpers.CommandTest{
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", "https://some-blob-host.com/some-blob",
+					"--cluster-builder", "nonexistent",
+					"--force",
+				},
+				ExpectedOutput: `Creating Image...
+Image "some-image" created
+`,
+				ExpectCreates: []runtime.Object{
+					&v1alpha1.Image{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       "Image",
+							APIVersion: "kpack.io/v1alpha1",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "some-image",
+							Namespace: defaultNamespace,
+							Annotations: map[string]string{
+								"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-default-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"nonexistent"},"serviceAccount":"default","source":{"blob":{"url":"https://some-blob-host.com/some-blob"}},"build":{"resources":{}}},"status":{}}`,
+							},
+						},
+						Spec: v1alpha1.ImageSpec{
+							Tag: "some-registry.io/some-repo",
+							Builder: corev1.ObjectReference{
+								Kind: v1alpha1.ClusterBuilderKind,
+								Name: "nonexistent",
+							},
+							ServiceAccount: "default",
+							Source: v1alpha1.SourceConfig{
+								Blob: &v1alpha1.Blob{
+									URL: "https://some-blob-host.com/some-blob",
+								},
+							},
+							Build: &v1alpha1.ImageBuild{},
+						},
+					},
+				},
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("does not check the builder with dry-run", func() {
+			testhelpers.CommandTest{
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", "https://some-blob-host.com/some-blob",
+					"--cluster-builder", "nonexistent",
+					"--dry-run",
+				},
+				ExpectedOutput: `Creating Image... (dry run)
+Image "some-image" created (dry run)
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+	})
+
 	when("output flag is used", func() {
 		when("the image config is invalid", func() {
 			it("returns an error", func() {
@@ -403,7 +656,7 @@ Image "some-image" created
 					Args: []string{
 						"some-image",
 						"--tag", "some-registry.io/some-repo",
-						"--blob", "some-blob",
+						"--blob", "https://some-blob-host.com/some-blob",
 						"--git", "some-git-url",
 					},
 					ExpectErr: true,
@@ -562,29 +815,8 @@ status: {}
 				}.TestKpack(t, cmdFunc)
 				assert.Len(t, fakeImageWaiter.Calls, 0)
 			})
-		})
-	})
-
-	when("dry-run flag is used", func() {
-		when("the image config is invalid", func() {
-			it("returns an error", func() {
-				testhelpers.CommandTest{
-					Args: []string{
-						"some-image",
-						"--tag", "some-registry.io/some-repo",
-						"--blob", "some-blob",
-						"--git", "some-git-url",
-					},
-					ExpectErr: true,
-					ExpectedOutput: `Creating Image...
-Error: image source must be one of git, blob, or local-path
-`,
-				}.TestKpack(t, cmdFunc)
-			})
-		})
 
-		when("the image config is valid", func() {
-			it("does not creates an image and prints result message with dry run indicated", func() {
+			it("can output a jsonpath expression and prints an empty string for missing fields", func() {
 				testhelpers.CommandTest{
 					Args: []string{
 						"some-image",
@@ -593,116 +825,906 @@ Error: image source must be one of git, blob, or local-path
 						"--git-revision", "some-git-rev",
 						"--sub-path", "some-sub-path",
 						"--env", "some-key=some-val",
-						"--dry-run",
-						"--wait",
+						"--output", "jsonpath={.spec.tag} {.status.latestImage}",
 					},
-					ExpectedOutput: `Creating Image... (dry run)
-Image "some-image" created (dry run)
+					ExpectedOutput: "some-registry.io/some-repo \n",
+					ExpectedErrorOutput: `Creating Image...
 `,
+					ExpectCreates: []runtime.Object{
+						expectedImage,
+					},
 				}.TestKpack(t, cmdFunc)
-				assert.Len(t, fakeImageWaiter.Calls, 0)
-			})
-
-			when("output flag is used", func() {
-				it("does not create an image and prints the resource output", func() {
-					const resourceYAML = `apiVersion: kpack.io/v1alpha1
-kind: Image
-metadata:
-  annotations:
-    kubectl.kubernetes.io/last-applied-configuration: '{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-default-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"default"},"serviceAccount":"default","source":{"git":{"url":"some-git-url","revision":"some-git-rev"},"subPath":"some-sub-path"},"build":{"env":[{"name":"some-key","value":"some-val"}],"resources":{}}},"status":{}}'
-  creationTimestamp: null
-  name: some-image
-  namespace: some-default-namespace
-spec:
-  build:
-    env:
-    - name: some-key
-      value: some-val
-    resources: {}
-  builder:
-    kind: ClusterBuilder
-    name: default
-  serviceAccount: default
-  source:
-    git:
-      revision: some-git-rev
-      url: some-git-url
-    subPath: some-sub-path
-  tag: some-registry.io/some-repo
-status: {}
-`
-
-					testhelpers.CommandTest{
-						Args: []string{
-							"some-image",
-							"--tag", "some-registry.io/some-repo",
-							"--git", "some-git-url",
-							"--git-revision", "some-git-rev",
-							"--sub-path", "some-sub-path",
-							"--env", "some-key=some-val",
-							"--output", "yaml",
-							"--dry-run",
-							"--wait",
-						},
-						ExpectedOutput: resourceYAML,
-						ExpectedErrorOutput: `Creating Image... (dry run)
-`,
-					}.TestKpack(t, cmdFunc)
-					assert.Len(t, fakeImageWaiter.Calls, 0)
-				})
 			})
 		})
 	})
 
-	when("dry-run-with-image-upload flag is used", func() {
-		when("the image config is invalid", func() {
-			it("returns an error", func() {
-				testhelpers.CommandTest{
-					Args: []string{
-						"some-image",
-						"--tag", "some-registry.io/some-repo",
-						"--blob", "some-blob",
-						"--git", "some-git-url",
-						"--dry-run-with-image-upload",
-					},
-					ExpectErr: true,
-					ExpectedOutput: `Creating Image... (dry run with image upload)
-Error: image source must be one of git, blob, or local-path
-`,
-				}.TestKpack(t, cmdFunc)
+	when("a default cluster builder is configured", func() {
+		k8sCmdFunc := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *fake.Clientset) *cobra.Command {
+			clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClientSet, kpackClientSet)
+			return imgcmds.NewCreateCommand(clientSetProvider, registryUtilProvider, func(set k8s.ClientSet) imgcmds.ImageWaiter {
+				return fakeImageWaiter
+			}, func(set k8s.ClientSet) image.RevisionResolver {
+				return fakeRevisionResolver
 			})
-		})
+		}
 
-		when("the image config is valid", func() {
-			it("does not creates an image and prints result message with dry run indicated", func() {
-				testhelpers.CommandTest{
-					Args: []string{
-						"some-image",
-						"--tag", "some-registry.io/some-repo",
-						"--local-path", "some-local-path",
-						"--sub-path", "some-sub-path",
-						"--env", "some-key=some-val",
-						"--dry-run-with-image-upload",
-						"--wait",
-					},
-					ExpectedOutput: `Creating Image... (dry run with image upload)
-Uploading to 'some-registry.io/some-repo-source'... (dry run with image upload)
-	Uploading 'some-registry.io/some-repo-source:source-id'
-Image "some-image" created (dry run with image upload)
-`,
-				}.TestKpack(t, cmdFunc)
-				assert.Len(t, fakeImageWaiter.Calls, 0)
-			})
+		kpConfig := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kp-config",
+				Namespace: "kpack",
+			},
+			Data: map[string]string{
+				"default.clusterbuilder": "some-builder",
+			},
+		}
 
-			when("output flag is used", func() {
-				it("does not create an image and prints the resource output", func() {
-					const resourceYAML = `apiVersion: kpack.io/v1alpha1
-kind: Image
-metadata:
-  annotations:
-    kubectl.kubernetes.io/last-applied-configuration: '{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-default-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"default"},"serviceAccount":"default","source":{"git":{"url":"some-git-url","revision":"some-git-rev"},"subPath":"some-sub-path"},"build":{"env":[{"name":"some-key","value":"some-val"}],"resources":{}}},"status":{}}'
-  creationTimestamp: null
-  name: some-image
+		it("uses the default cluster builder when no builder is provided", func() {
+			expectedImage := &v1alpha1.Image{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Image",
+					APIVersion: "kpack.io/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "some-image",
+					Namespace: defaultNamespace,
+					Annotations: map[string]string{
+						"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-default-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"some-builder"},"serviceAccount":"default","source":{"blob":{"url":"https://some-blob-host.com/some-blob"}},"build":{"resources":{}}},"status":{}}`,
+					},
+				},
+				Spec: v1alpha1.ImageSpec{
+					Tag: "some-registry.io/some-repo",
+					Builder: corev1.ObjectReference{
+						Kind: v1alpha1.ClusterBuilderKind,
+						Name: "some-builder",
+					},
+					ServiceAccount: "default",
+					Source: v1alpha1.SourceConfig{
+						Blob: &v1alpha1.Blob{
+							URL: "https://some-blob-host.com/some-blob",
+						},
+					},
+					Build: &v1alpha1.ImageBuild{},
+				},
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					kpConfig,
+					someClusterBuilder,
+				},
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", "https://some-blob-host.com/some-blob",
+					"-n", defaultNamespace,
+				},
+				ExpectedOutput: `Using default cluster builder "some-builder"
+Creating Image...
+Image "some-image" created
+`,
+				ExpectCreates: []runtime.Object{
+					expectedImage,
+				},
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("does not use the default when a builder is provided", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					kpConfig,
+					someBuilder,
+				},
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", "https://some-blob-host.com/some-blob",
+					"--builder", "some-builder",
+					"-n", defaultNamespace,
+					"--dry-run",
+				},
+				ExpectedOutput: `Creating Image... (dry run)
+Image "some-image" created (dry run)
+`,
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+	})
+
+	when("service bindings are provided", func() {
+		const namespace = "some-namespace"
+
+		k8sCmdFunc := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *fake.Clientset) *cobra.Command {
+			clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClientSet, kpackClientSet)
+			return imgcmds.NewCreateCommand(clientSetProvider, registryUtilProvider, func(set k8s.ClientSet) imgcmds.ImageWaiter {
+				return fakeImageWaiter
+			}, func(set k8s.ClientSet) image.RevisionResolver {
+				return fakeRevisionResolver
+			})
+		}
+
+		it("creates the image with the bindings", func() {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "some-binding",
+					Namespace: namespace,
+				},
+			}
+
+			expectedImage := &v1alpha1.Image{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Image",
+					APIVersion: "kpack.io/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "some-image",
+					Namespace: namespace,
+					Annotations: map[string]string{
+						"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"default"},"serviceAccount":"default","source":{"blob":{"url":"https://some-blob-host.com/some-blob"}},"build":{"bindings":[{"name":"some-binding","metadataRef":{"name":"some-binding"},"secretRef":{"name":"some-binding"}}],"resources":{}}},"status":{}}`,
+					},
+				},
+				Spec: v1alpha1.ImageSpec{
+					Tag: "some-registry.io/some-repo",
+					Builder: corev1.ObjectReference{
+						Kind: v1alpha1.ClusterBuilderKind,
+						Name: "default",
+					},
+					ServiceAccount: "default",
+					Source: v1alpha1.SourceConfig{
+						Blob: &v1alpha1.Blob{
+							URL: "https://some-blob-host.com/some-blob",
+						},
+					},
+					Build: &v1alpha1.ImageBuild{
+						Bindings: v1alpha1.Bindings{
+							{
+								Name:        "some-binding",
+								MetadataRef: &corev1.LocalObjectReference{Name: "some-binding"},
+								SecretRef:   &corev1.LocalObjectReference{Name: "some-binding"},
+							},
+						},
+					},
+				},
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					secret,
+				},
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", "https://some-blob-host.com/some-blob",
+					"--service-binding", "some-binding",
+					"-n", namespace,
+				},
+				ExpectedOutput: `Creating Image...
+Image "some-image" created
+`,
+				ExpectCreates: []runtime.Object{
+					expectedImage,
+				},
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("returns an error when the binding secret does not exist", func() {
+			testhelpers.CommandTest{
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", "https://some-blob-host.com/some-blob",
+					"--service-binding", "some-binding",
+					"-n", namespace,
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Creating Image...
+Error: service binding secret "some-binding" not found in namespace "some-namespace"
+`,
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("warns when the binding secret does not exist with dry-run", func() {
+			testhelpers.CommandTest{
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", "https://some-blob-host.com/some-blob",
+					"--service-binding", "some-binding",
+					"-n", namespace,
+					"--dry-run",
+				},
+				ExpectedOutput: `Creating Image... (dry run)
+Warning: service binding secret "some-binding" not found in namespace "some-namespace"
+Image "some-image" created (dry run)
+`,
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("binds a secret with a different name than the binding", func() {
+			otherSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "some-secret",
+					Namespace: namespace,
+				},
+			}
+
+			expectedImage := &v1alpha1.Image{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Image",
+					APIVersion: "kpack.io/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "some-image",
+					Namespace: namespace,
+					Annotations: map[string]string{
+						"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"default"},"serviceAccount":"default","source":{"blob":{"url":"https://some-blob-host.com/some-blob"}},"build":{"bindings":[{"name":"some-binding","metadataRef":{"name":"some-binding"},"secretRef":{"name":"some-secret"}}],"resources":{}}},"status":{}}`,
+					},
+				},
+				Spec: v1alpha1.ImageSpec{
+					Tag: "some-registry.io/some-repo",
+					Builder: corev1.ObjectReference{
+						Kind: v1alpha1.ClusterBuilderKind,
+						Name: "default",
+					},
+					ServiceAccount: "default",
+					Source: v1alpha1.SourceConfig{
+						Blob: &v1alpha1.Blob{
+							URL: "https://some-blob-host.com/some-blob",
+						},
+					},
+					Build: &v1alpha1.ImageBuild{
+						Bindings: v1alpha1.Bindings{
+							{
+								Name:        "some-binding",
+								MetadataRef: &corev1.LocalObjectReference{Name: "some-binding"},
+								SecretRef:   &corev1.LocalObjectReference{Name: "some-secret"},
+							},
+						},
+					},
+				},
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					otherSecret,
+				},
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", "https://some-blob-host.com/some-blob",
+					"--service-binding", "some-binding=some-secret",
+					"-n", namespace,
+				},
+				ExpectedOutput: `Creating Image...
+Image "some-image" created
+`,
+				ExpectCreates: []runtime.Object{
+					expectedImage,
+				},
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("returns an error when the binding is improperly formatted", func() {
+			testhelpers.CommandTest{
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", "https://some-blob-host.com/some-blob",
+					"--service-binding", "some-binding=",
+					"-n", namespace,
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Creating Image...
+Error: service binding "some-binding=" is improperly formatted, must be NAME or NAME=SECRET
+`,
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+	})
+
+	when("the pin-revision flag is used", func() {
+		const (
+			namespace = "some-namespace"
+			commit    = "0123456789abcdef0123456789abcdef01234567"
+		)
+
+		it("creates the image with the commit of the git revision", func() {
+			fakeRevisionResolver.Commits = map[string]string{"some-git-url@some-branch": commit}
+
+			expectedImage := &v1alpha1.Image{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Image",
+					APIVersion: "kpack.io/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "some-image",
+					Namespace: namespace,
+					Annotations: map[string]string{
+						"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"default"},"serviceAccount":"default","source":{"git":{"url":"some-git-url","revision":"0123456789abcdef0123456789abcdef01234567"}},"build":{"resources":{}}},"status":{}}`,
+					},
+				},
+				Spec: v1alpha1.ImageSpec{
+					Tag: "some-registry.io/some-repo",
+					Builder: corev1.ObjectReference{
+						Kind: v1alpha1.ClusterBuilderKind,
+						Name: "default",
+					},
+					ServiceAccount: "default",
+					Source: v1alpha1.SourceConfig{
+						Git: &v1alpha1.Git{
+							URL:      "some-git-url",
+							Revision: commit,
+						},
+					},
+					Build: &v1alpha1.ImageBuild{},
+				},
+			}
+
+			testhelpers.CommandTest{
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--git", "some-git-url",
+					"--git-revision", "some-branch",
+					"--pin-revision",
+					"-n", namespace,
+				},
+				ExpectedOutput: `Creating Image...
+Image "some-image" created
+`,
+				ExpectCreates: []runtime.Object{
+					expectedImage,
+				},
+			}.TestKpack(t, cmdFunc)
+
+			assert.Equal(t, []cmdFakes.ResolveCall{{
+				Namespace:      namespace,
+				ServiceAccount: "default",
+				URL:            "some-git-url",
+				Revision:       "some-branch",
+			}}, fakeRevisionResolver.Calls)
+		})
+
+		it("errors when the revision cannot be resolved", func() {
+			testhelpers.CommandTest{
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--git", "some-git-url",
+					"--pin-revision",
+					"-n", namespace,
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Creating Image...
+Error: failed to pin git revision "main": failed to list refs of "some-git-url"
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("errors when the source is not git", func() {
+			testhelpers.CommandTest{
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", "https://some-blob-host.com/some-blob",
+					"--pin-revision",
+					"-n", namespace,
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Creating Image...
+Error: pin-revision can only be used with a git source
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+	})
+
+	when("a blob auth secret is provided", func() {
+		const namespace = "some-namespace"
+
+		var server *httptest.Server
+
+		it.Before(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if user, pass, ok := r.BasicAuth(); !ok || user != "some-user" || pass != "some-password" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.WriteHeader(http.StatusPartialContent)
+			}))
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		k8sCmdFunc := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *fake.Clientset) *cobra.Command {
+			clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClientSet, kpackClientSet)
+			return imgcmds.NewCreateCommand(clientSetProvider, registryUtilProvider, func(set k8s.ClientSet) imgcmds.ImageWaiter {
+				return fakeImageWaiter
+			}, func(set k8s.ClientSet) image.RevisionResolver {
+				return fakeRevisionResolver
+			})
+		}
+
+		blobSecret := func(password string) *corev1.Secret {
+			return &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "some-blob-secret",
+					Namespace: namespace,
+				},
+				Type: corev1.SecretTypeBasicAuth,
+				Data: map[string][]byte{
+					corev1.BasicAuthUsernameKey: []byte("some-user"),
+					corev1.BasicAuthPasswordKey: []byte(password),
+				},
+			}
+		}
+
+		serviceAccount := &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "default",
+				Namespace: namespace,
+			},
+		}
+
+		it("checks the blob is readable and adds the secret to the service account", func() {
+			expectedImage := &v1alpha1.Image{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Image",
+					APIVersion: "kpack.io/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "some-image",
+					Namespace: namespace,
+					Annotations: map[string]string{
+						"kubectl.kubernetes.io/last-applied-configuration": fmt.Sprintf(`{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"default"},"serviceAccount":"default","source":{"blob":{"url":"%s"}},"build":{"resources":{}}},"status":{}}`, server.URL),
+					},
+				},
+				Spec: v1alpha1.ImageSpec{
+					Tag: "some-registry.io/some-repo",
+					Builder: corev1.ObjectReference{
+						Kind: v1alpha1.ClusterBuilderKind,
+						Name: "default",
+					},
+					ServiceAccount: "default",
+					Source: v1alpha1.SourceConfig{
+						Blob: &v1alpha1.Blob{
+							URL: server.URL,
+						},
+					},
+					Build: &v1alpha1.ImageBuild{},
+				},
+			}
+
+			expectedServiceAccount := serviceAccount.DeepCopy()
+			expectedServiceAccount.Secrets = []corev1.ObjectReference{{Name: "some-blob-secret"}}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					blobSecret("some-password"),
+					serviceAccount,
+				},
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", server.URL,
+					"--blob-auth-secret", "some-blob-secret",
+					"-n", namespace,
+				},
+				ExpectedOutput: `Creating Image...
+Secret "some-blob-secret" added to service account "default"
+Image "some-image" created
+`,
+				ExpectUpdates: []clientgotesting.UpdateActionImpl{
+					{
+						Object: expectedServiceAccount,
+					},
+				},
+				ExpectCreates: []runtime.Object{
+					expectedImage,
+				},
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("returns an actionable error when the credentials are denied", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					blobSecret("wrong-password"),
+					serviceAccount,
+				},
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", server.URL,
+					"--blob-auth-secret", "some-blob-secret",
+					"-n", namespace,
+				},
+				ExpectErr: true,
+				ExpectedOutput: fmt.Sprintf(`Creating Image...
+Error: access to blob %q was denied with the credentials in secret "some-blob-secret" (401 Unauthorized), check the secret username and password
+`, server.URL),
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("returns an error when the secret does not exist", func() {
+			testhelpers.CommandTest{
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", server.URL,
+					"--blob-auth-secret", "some-blob-secret",
+					"-n", namespace,
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Creating Image...
+Error: blob auth secret "some-blob-secret" not found in namespace "some-namespace"
+`,
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("errors when the source is not a blob", func() {
+			testhelpers.CommandTest{
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--git", "some-git-url",
+					"--blob-auth-secret", "some-blob-secret",
+					"-n", namespace,
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Creating Image...
+Error: blob-auth-secret can only be used with a blob source
+`,
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+	})
+
+	when("an image file is provided", func() {
+		const namespace = "some-namespace"
+
+		it("creates each image in the file with flags taking precedence", func() {
+			expectedImage := &v1alpha1.Image{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Image",
+					APIVersion: "kpack.io/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "some-image",
+					Namespace: namespace,
+					Annotations: map[string]string{
+						"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"default"},"serviceAccount":"default","source":{"git":{"url":"some-git-url","revision":"some-git-rev"}},"build":{"env":[{"name":"some-key","value":"some-val"}],"resources":{}}},"status":{}}`,
+					},
+				},
+				Spec: v1alpha1.ImageSpec{
+					Tag: "some-registry.io/some-repo",
+					Builder: corev1.ObjectReference{
+						Kind: v1alpha1.ClusterBuilderKind,
+						Name: "default",
+					},
+					ServiceAccount: "default",
+					Source: v1alpha1.SourceConfig{
+						Git: &v1alpha1.Git{
+							URL:      "some-git-url",
+							Revision: "some-git-rev",
+						},
+					},
+					Build: &v1alpha1.ImageBuild{
+						Env: []corev1.EnvVar{{Name: "some-key", Value: "some-val"}},
+					},
+				},
+			}
+
+			otherExpectedImage := &v1alpha1.Image{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Image",
+					APIVersion: "kpack.io/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "other-image",
+					Namespace: namespace,
+					Labels:    map[string]string{"team": "some-team"},
+					Annotations: map[string]string{
+						"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"other-image","namespace":"some-namespace","creationTimestamp":null,"labels":{"team":"some-team"}},"spec":{"tag":"some-registry.io/other-repo","builder":{"kind":"ClusterBuilder","name":"some-builder"},"serviceAccount":"some-sa","source":{"blob":{"url":"https://some-blob-host.com/some-blob"}},"build":{"env":[{"name":"some-key","value":"some-val"}],"resources":{}}},"status":{}}`,
+					},
+				},
+				Spec: v1alpha1.ImageSpec{
+					Tag: "some-registry.io/other-repo",
+					Builder: corev1.ObjectReference{
+						Kind: v1alpha1.ClusterBuilderKind,
+						Name: "some-builder",
+					},
+					ServiceAccount: "some-sa",
+					Source: v1alpha1.SourceConfig{
+						Blob: &v1alpha1.Blob{URL: "https://some-blob-host.com/some-blob"},
+					},
+					Build: &v1alpha1.ImageBuild{
+						Env: []corev1.EnvVar{{Name: "some-key", Value: "some-val"}},
+					},
+				},
+			}
+
+			testhelpers.CommandTest{
+				StdIn: `apiVersion: kpack.io/v1alpha1
+kind: Image
+metadata:
+  name: some-image
+spec:
+  tag: some-registry.io/some-repo
+  source:
+    git:
+      url: some-git-url
+      revision: some-git-rev
+  build:
+    env:
+    - name: some-key
+      value: some-file-val
+---
+apiVersion: kpack.io/v1alpha1
+kind: Image
+metadata:
+  name: other-image
+  labels:
+    team: some-team
+spec:
+  tag: some-registry.io/other-repo
+  serviceAccount: some-sa
+  builder:
+    kind: ClusterBuilder
+    name: some-builder
+  source:
+    blob:
+      url: https://some-blob-host.com/some-blob
+`,
+				Args: []string{
+					"-f", "-",
+					"--env", "some-key=some-val",
+					"-n", namespace,
+					"--wait",
+				},
+				ExpectedOutput: `Creating Image...
+Image "some-image" created
+Creating Image...
+Image "other-image" created
+`,
+				ExpectCreates: []runtime.Object{
+					expectedImage,
+					otherExpectedImage,
+				},
+			}.TestKpack(t, cmdFunc)
+
+			assert.Len(t, fakeImageWaiter.Calls, 2)
+		})
+
+		it("defaults the tag to the canonical repository", func() {
+			kpConfig := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kp-config",
+					Namespace: "kpack",
+				},
+				Data: map[string]string{
+					"canonical.repository": "canonical-registry.io/canonical-repo",
+				},
+			}
+
+			expectedImage := &v1alpha1.Image{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Image",
+					APIVersion: "kpack.io/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "some-image",
+					Namespace: namespace,
+					Annotations: map[string]string{
+						"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-namespace","creationTimestamp":null},"spec":{"tag":"canonical-registry.io/canonical-repo/some-image","builder":{"kind":"ClusterBuilder","name":"default"},"serviceAccount":"default","source":{"blob":{"url":"https://some-blob-host.com/some-blob"}},"build":{"resources":{}}},"status":{}}`,
+					},
+				},
+				Spec: v1alpha1.ImageSpec{
+					Tag: "canonical-registry.io/canonical-repo/some-image",
+					Builder: corev1.ObjectReference{
+						Kind: v1alpha1.ClusterBuilderKind,
+						Name: "default",
+					},
+					ServiceAccount: "default",
+					Source: v1alpha1.SourceConfig{
+						Blob: &v1alpha1.Blob{URL: "https://some-blob-host.com/some-blob"},
+					},
+					Build: &v1alpha1.ImageBuild{},
+				},
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{kpConfig},
+				StdIn: `apiVersion: kpack.io/v1alpha1
+kind: Image
+metadata:
+  name: some-image
+spec:
+  source:
+    blob:
+      url: https://some-blob-host.com/some-blob
+`,
+				Args: []string{"-f", "-", "-n", namespace},
+				ExpectedOutput: `Creating Image...
+Image "some-image" created
+`,
+				ExpectCreates: []runtime.Object{
+					expectedImage,
+				},
+			}.TestK8sAndKpack(t, func(k8sClient *k8sfakes.Clientset, kpackClient *fake.Clientset) *cobra.Command {
+				clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClient, kpackClient)
+				return imgcmds.NewCreateCommand(clientSetProvider, registryUtilProvider, func(set k8s.ClientSet) imgcmds.ImageWaiter {
+					return fakeImageWaiter
+				}, func(set k8s.ClientSet) image.RevisionResolver {
+					return fakeRevisionResolver
+				})
+			})
+		})
+
+		it("errors when an image name is also provided", func() {
+			testhelpers.CommandTest{
+				Args:           []string{"some-image", "-f", "-"},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: image name cannot be provided with --file\n",
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("errors when the file contains other resources", func() {
+			testhelpers.CommandTest{
+				StdIn: `apiVersion: kpack.io/v1alpha1
+kind: Builder
+metadata:
+  name: some-builder
+`,
+				Args:           []string{"-f", "-"},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: unsupported kind \"Builder\" in \"-\", only Image resources are supported\n",
+			}.TestKpack(t, cmdFunc)
+		})
+	})
+
+	it("errors when the tag is not provided", func() {
+		testhelpers.CommandTest{
+			Args:           []string{"some-image", "--blob", "https://some-blob-host.com/some-blob"},
+			ExpectErr:      true,
+			ExpectedOutput: "Error: required flag(s) \"tag\" not set\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	when("dry-run flag is used", func() {
+		when("the image config is invalid", func() {
+			it("returns an error", func() {
+				testhelpers.CommandTest{
+					Args: []string{
+						"some-image",
+						"--tag", "some-registry.io/some-repo",
+						"--blob", "https://some-blob-host.com/some-blob",
+						"--git", "some-git-url",
+					},
+					ExpectErr: true,
+					ExpectedOutput: `Creating Image...
+Error: image source must be one of git, blob, or local-path
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
+		when("the image config is valid", func() {
+			it("does not creates an image and prints result message with dry run indicated", func() {
+				testhelpers.CommandTest{
+					Args: []string{
+						"some-image",
+						"--tag", "some-registry.io/some-repo",
+						"--git", "some-git-url",
+						"--git-revision", "some-git-rev",
+						"--sub-path", "some-sub-path",
+						"--env", "some-key=some-val",
+						"--dry-run",
+						"--wait",
+					},
+					ExpectedOutput: `Creating Image... (dry run)
+Image "some-image" created (dry run)
+`,
+				}.TestKpack(t, cmdFunc)
+				assert.Len(t, fakeImageWaiter.Calls, 0)
+			})
+
+			when("output flag is used", func() {
+				it("does not create an image and prints the resource output", func() {
+					const resourceYAML = `apiVersion: kpack.io/v1alpha1
+kind: Image
+metadata:
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: '{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-default-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"default"},"serviceAccount":"default","source":{"git":{"url":"some-git-url","revision":"some-git-rev"},"subPath":"some-sub-path"},"build":{"env":[{"name":"some-key","value":"some-val"}],"resources":{}}},"status":{}}'
+  creationTimestamp: null
+  name: some-image
+  namespace: some-default-namespace
+spec:
+  build:
+    env:
+    - name: some-key
+      value: some-val
+    resources: {}
+  builder:
+    kind: ClusterBuilder
+    name: default
+  serviceAccount: default
+  source:
+    git:
+      revision: some-git-rev
+      url: some-git-url
+    subPath: some-sub-path
+  tag: some-registry.io/some-repo
+status: {}
+`
+
+					testhelpers.CommandTest{
+						Args: []string{
+							"some-image",
+							"--tag", "some-registry.io/some-repo",
+							"--git", "some-git-url",
+							"--git-revision", "some-git-rev",
+							"--sub-path", "some-sub-path",
+							"--env", "some-key=some-val",
+							"--output", "yaml",
+							"--dry-run",
+							"--wait",
+						},
+						ExpectedOutput: resourceYAML,
+						ExpectedErrorOutput: `Creating Image... (dry run)
+`,
+					}.TestKpack(t, cmdFunc)
+					assert.Len(t, fakeImageWaiter.Calls, 0)
+				})
+			})
+		})
+	})
+
+	when("dry-run-with-image-upload flag is used", func() {
+		when("the image config is invalid", func() {
+			it("returns an error", func() {
+				testhelpers.CommandTest{
+					Args: []string{
+						"some-image",
+						"--tag", "some-registry.io/some-repo",
+						"--blob", "https://some-blob-host.com/some-blob",
+						"--git", "some-git-url",
+						"--dry-run-with-image-upload",
+					},
+					ExpectErr: true,
+					ExpectedOutput: `Creating Image... (dry run with image upload)
+Error: image source must be one of git, blob, or local-path
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
+		when("the image config is valid", func() {
+			it("does not creates an image and prints result message with dry run indicated", func() {
+				testhelpers.CommandTest{
+					Args: []string{
+						"some-image",
+						"--tag", "some-registry.io/some-repo",
+						"--local-path", "some-local-path",
+						"--sub-path", "some-sub-path",
+						"--env", "some-key=some-val",
+						"--dry-run-with-image-upload",
+						"--wait",
+					},
+					ExpectedOutput: `Creating Image... (dry run with image upload)
+Uploading to 'some-registry.io/some-repo-source'... (dry run with image upload)
+	Uploading 'some-registry.io/some-repo-source:source-id'
+Image "some-image" created (dry run with image upload)
+`,
+				}.TestKpack(t, cmdFunc)
+				assert.Len(t, fakeImageWaiter.Calls, 0)
+			})
+
+			when("output flag is used", func() {
+				it("does not create an image and prints the resource output", func() {
+					const resourceYAML = `apiVersion: kpack.io/v1alpha1
+kind: Image
+metadata:
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: '{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-default-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"default"},"serviceAccount":"default","source":{"git":{"url":"some-git-url","revision":"some-git-rev"},"subPath":"some-sub-path"},"build":{"env":[{"name":"some-key","value":"some-val"}],"resources":{}}},"status":{}}'
+  creationTimestamp: null
+  name: some-image
   namespace: some-default-namespace
 spec:
   build:
@@ -744,4 +1766,72 @@ status: {}
 			})
 		})
 	})
+
+	when("server-side-dry-run flag is used", func() {
+		it("submits the image to the server and does not wait", func() {
+			testhelpers.CommandTest{
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", "https://some-blob-host.com/some-blob",
+					"--server-side-dry-run",
+					"--wait",
+				},
+				ExpectedOutput: `Creating Image... (server dry run)
+Image "some-image" created (server dry run)
+`,
+				ExpectCreates: []runtime.Object{
+					&v1alpha1.Image{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       "Image",
+							APIVersion: "kpack.io/v1alpha1",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "some-image",
+							Namespace: defaultNamespace,
+							Annotations: map[string]string{
+								"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-default-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"default"},"serviceAccount":"default","source":{"blob":{"url":"https://some-blob-host.com/some-blob"}},"build":{"resources":{}}},"status":{}}`,
+							},
+						},
+						Spec: v1alpha1.ImageSpec{
+							Tag: "some-registry.io/some-repo",
+							Builder: corev1.ObjectReference{
+								Kind: v1alpha1.ClusterBuilderKind,
+								Name: "default",
+							},
+							ServiceAccount: "default",
+							Source: v1alpha1.SourceConfig{
+								Blob: &v1alpha1.Blob{
+									URL: "https://some-blob-host.com/some-blob",
+								},
+							},
+							Build: &v1alpha1.ImageBuild{},
+						},
+					},
+				},
+			}.TestKpack(t, cmdFunc)
+			assert.Len(t, fakeImageWaiter.Calls, 0)
+		})
+
+		it("cannot be combined with dry-run", func() {
+			testhelpers.CommandTest{
+				Args: []string{
+					"some-image",
+					"--tag", "some-registry.io/some-repo",
+					"--blob", "https://some-blob-host.com/some-blob",
+					"--server-side-dry-run",
+					"--dry-run",
+				},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: --server-side-dry-run cannot be used with --dry-run or --dry-run-with-image-upload\n",
+			}.TestKpack(t, cmdFunc)
+		})
+	})
+}
+
+type neverReadyImageWaiter struct{}
+
+func (neverReadyImageWaiter) Wait(ctx context.Context, _ io.Writer, _ *v1alpha1.Image) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
 }
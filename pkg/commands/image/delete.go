@@ -4,18 +4,34 @@
 package image
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"time"
 
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 )
 
-func NewDeleteCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+const deletePollInterval = time.Second
+
+type ConfirmationProvider interface {
+	Confirm(message string, okayResponses ...string) (bool, error)
+}
+
+func NewDeleteCommand(clientSetProvider k8s.ClientSetProvider, confirmationProvider ConfirmationProvider) *cobra.Command {
 	var (
 		namespace string
+		all       bool
+		selector  string
+		yes       bool
 	)
 
 	cmd := &cobra.Command{
@@ -23,27 +39,145 @@ func NewDeleteCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 		Short: "Delete an image",
 		Long: `Delete an image and its associated image builds in the provided namespace.
 
-namespace defaults to the kubernetes current-context namespace.`,
-		Example: "kp image delete my-image",
-		Args:    commands.ExactArgsWithUsage(1),
+namespace defaults to the kubernetes current-context namespace.
+
+Use the "--all" flag to delete every image in the namespace, optionally scoped by labels with the "--selector" flag.
+The deletion of all images must be confirmed unless the "--yes" flag is provided.
+Use the "--dry-run" flag to print the images that would be deleted.
+
+The image builds are removed asynchronously by the kubernetes garbage collector.
+Use the "--wait" flag to return only once the image and its builds are gone.`,
+		Example: `kp image delete my-image
+kp image delete my-image --wait
+kp image delete --all -n my-namespace
+kp image delete --all --selector team=my-team --yes
+kp image delete --all --dry-run`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if all {
+				if len(args) > 0 {
+					return errors.New("image names cannot be provided with --all")
+				}
+				return commands.ValidateLabelSelector(selector)
+			}
+
+			if selector != "" {
+				return errors.New("--selector can only be used with --all")
+			}
+			return commands.ExactArgsWithUsage(1)(cmd, args)
+		},
+		ValidArgsFunction: commands.ImageNameCompletion(clientSetProvider),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet(namespace)
 			if err != nil {
 				return err
 			}
 
-			err = cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).Delete(cmd.Context(), args[0], metav1.DeleteOptions{})
+			ch, err := commands.NewCommandHelper(cmd)
 			if err != nil {
 				return err
 			}
 
-			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Image %q deleted\n", args[0])
-			return err
+			ctx := cmd.Context()
+
+			var names []string
+			if all {
+				images, err := cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).List(ctx, metav1.ListOptions{
+					LabelSelector: selector,
+				})
+				if err != nil {
+					return err
+				}
+
+				if len(images.Items) == 0 {
+					return ch.Printlnf("No images found in namespace %q", cs.Namespace)
+				}
+
+				for _, img := range images.Items {
+					names = append(names, img.Name)
+				}
+				sort.Strings(names)
+
+				if !ch.IsDryRun() && !yes {
+					message := fmt.Sprintf("Delete %d images in namespace %q?\nPlease confirm image deletion by typing 'y': ", len(names), cs.Namespace)
+					confirmed, err := confirmationProvider.Confirm(message)
+					if err != nil {
+						return err
+					}
+
+					if !confirmed {
+						return ch.Printlnf("Skipping Image deletion")
+					}
+				}
+			} else {
+				names = args
+			}
+
+			for _, name := range names {
+				if err := deleteImage(ctx, cs, ch, name); err != nil {
+					return err
+				}
+
+				if ch.ShouldWait() {
+					if err := ch.PrintStatus("Waiting for Image %q to be deleted...", name); err != nil {
+						return err
+					}
+
+					if err := waitForImageDeletion(ctx, cs, name, ch.WaitTimeout()); err != nil {
+						return err
+					}
+				}
+
+				if err := ch.PrintResult("Image %q deleted", name); err != nil {
+					return err
+				}
+			}
+			return nil
 		},
 		SilenceUsage: true,
 	}
 
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	cmd.Flags().BoolVar(&all, "all", false, "delete all images in the namespace")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "label selector to filter the images deleted with --all")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "delete all images without confirmation")
+	cmd.Flags().Bool(commands.DryRunFlag, false, "print the images that would be deleted without deleting them")
+	cmd.Flags().BoolP(commands.WaitFlag, "w", false, "wait for the image and its builds to be deleted")
+	commands.SetWaitTimeoutFlag(cmd)
 
 	return cmd
 }
+
+// deleteImage checks that the image exists without deleting it for dry runs
+func deleteImage(ctx context.Context, cs k8s.ClientSet, ch *commands.CommandHelper, name string) error {
+	if ch.IsDryRun() {
+		_, err := cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).Get(ctx, name, metav1.GetOptions{})
+		return err
+	}
+	return cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+func waitForImageDeletion(ctx context.Context, cs k8s.ClientSet, name string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := wait.PollImmediateUntil(deletePollInterval, func() (bool, error) {
+		_, err := cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			return false, nil
+		} else if !k8serrors.IsNotFound(err) {
+			return false, err
+		}
+
+		builds, err := cs.KpackClient.KpackV1alpha1().Builds(cs.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: v1alpha1.ImageLabel + "=" + name,
+		})
+		if err != nil {
+			return false, err
+		}
+		return len(builds.Items) == 0, nil
+	}, ctx.Done())
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return commands.NewTimeoutError("timed out after %v waiting for Image %q to be deleted", timeout, name)
+	}
+	return err
+}
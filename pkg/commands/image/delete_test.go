@@ -10,10 +10,12 @@ import (
 	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
 	"github.com/sclevine/spec"
 	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgotesting "k8s.io/client-go/testing"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands/image"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
 )
@@ -25,11 +27,17 @@ func TestImageDeleteCommand(t *testing.T) {
 func testImageDeleteCommand(t *testing.T, when spec.G, it spec.S) {
 	const defaultNamespace = "some-default-namespace"
 
+	var fakeConfirmationProvider *fakes.FakeConfirmationProvider
+
 	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
 		clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
-		return image.NewDeleteCommand(clientSetProvider)
+		return image.NewDeleteCommand(clientSetProvider, fakeConfirmationProvider)
 	}
 
+	it.Before(func() {
+		fakeConfirmationProvider = fakes.NewFakeConfirmationProvider(true, nil)
+	})
+
 	when("a namespace is provided", func() {
 		when("an image is available", func() {
 			it("deletes the image", func() {
@@ -125,4 +133,145 @@ func testImageDeleteCommand(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 	})
+
+	when("the wait flag is used", func() {
+		it("waits for the image and its builds to be deleted", func() {
+			image := &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "some-image",
+					Namespace: defaultNamespace,
+				},
+			}
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					image,
+				},
+				Args: []string{"some-image", "--wait"},
+				ExpectedOutput: `Waiting for Image "some-image" to be deleted...
+Image "some-image" deleted
+`,
+				ExpectDeletes: []clientgotesting.DeleteActionImpl{
+					{
+						ActionImpl: clientgotesting.ActionImpl{
+							Namespace: defaultNamespace,
+						},
+						Name: image.Name,
+					},
+				},
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("times out when builds of the image remain", func() {
+			image := &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "some-image",
+					Namespace: defaultNamespace,
+				},
+			}
+			testhelpers.CommandTest{
+				Objects: append([]runtime.Object{image}, testhelpers.MakeTestBuilds("some-image", defaultNamespace)...),
+				Args:    []string{"some-image", "--wait", "--wait-timeout", "10ms"},
+				ExpectedOutput: `Waiting for Image "some-image" to be deleted...
+Error: timed out after 10ms waiting for Image "some-image" to be deleted
+`,
+				ExpectDeletes: []clientgotesting.DeleteActionImpl{
+					{
+						ActionImpl: clientgotesting.ActionImpl{
+							Namespace: defaultNamespace,
+						},
+						Name: image.Name,
+					},
+				},
+				ExpectErr: true,
+			}.TestKpack(t, cmdFunc)
+		})
+	})
+
+	when("the all flag is used", func() {
+		makeImage := func(name string, labels map[string]string) *v1alpha1.Image {
+			return &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      name,
+					Namespace: defaultNamespace,
+					Labels:    labels,
+				},
+			}
+		}
+
+		images := []runtime.Object{
+			makeImage("image-one", map[string]string{"team": "a"}),
+			makeImage("image-two", map[string]string{"team": "b"}),
+			makeImage("image-three", map[string]string{"team": "a"}),
+		}
+
+		deleteAction := func(name string) clientgotesting.DeleteActionImpl {
+			return clientgotesting.DeleteActionImpl{
+				ActionImpl: clientgotesting.ActionImpl{
+					Namespace: defaultNamespace,
+				},
+				Name: name,
+			}
+		}
+
+		it("deletes every image in the namespace after confirmation", func() {
+			testhelpers.CommandTest{
+				Objects: images,
+				Args:    []string{"--all"},
+				ExpectedOutput: `Image "image-one" deleted
+Image "image-three" deleted
+Image "image-two" deleted
+`,
+				ExpectDeletes: []clientgotesting.DeleteActionImpl{
+					deleteAction("image-one"),
+					deleteAction("image-three"),
+					deleteAction("image-two"),
+				},
+			}.TestKpack(t, cmdFunc)
+			require.NoError(t, fakeConfirmationProvider.WasRequestedWithMsg("Delete 3 images in namespace \"some-default-namespace\"?\nPlease confirm image deletion by typing 'y': "))
+		})
+
+		it("deletes only the images matching the selector", func() {
+			testhelpers.CommandTest{
+				Objects: images,
+				Args:    []string{"--all", "--selector", "team=a", "--yes"},
+				ExpectedOutput: `Image "image-one" deleted
+Image "image-three" deleted
+`,
+				ExpectDeletes: []clientgotesting.DeleteActionImpl{
+					deleteAction("image-one"),
+					deleteAction("image-three"),
+				},
+			}.TestKpack(t, cmdFunc)
+			require.False(t, fakeConfirmationProvider.WasRequested())
+		})
+
+		it("does not delete anything with dry-run", func() {
+			testhelpers.CommandTest{
+				Objects: images,
+				Args:    []string{"--all", "-l", "team=a", "--dry-run"},
+				ExpectedOutput: `Image "image-one" deleted (dry run)
+Image "image-three" deleted (dry run)
+`,
+			}.TestKpack(t, cmdFunc)
+			require.False(t, fakeConfirmationProvider.WasRequested())
+		})
+
+		it("skips deletion when not confirmed", func() {
+			fakeConfirmationProvider = fakes.NewFakeConfirmationProvider(false, nil)
+
+			testhelpers.CommandTest{
+				Objects:        images,
+				Args:           []string{"--all"},
+				ExpectedOutput: "Skipping Image deletion\n",
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("returns an error when image names are provided", func() {
+			testhelpers.CommandTest{
+				Args:           []string{"some-image", "--all"},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: image names cannot be provided with --all\n",
+			}.TestKpack(t, cmdFunc)
+		})
+	})
 }
@@ -0,0 +1,286 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+const (
+	recentBuildsLimit = 5
+	timeFormat        = "2006-01-02 15:04:05"
+)
+
+func NewDescribeCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	var (
+		namespace string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "describe <name>",
+		Short: "Describe an image",
+		Long: `Prints the full configuration, status conditions and most recent builds of a specific image in the provided namespace.
+
+The namespace defaults to the kubernetes current-context namespace.
+
+Use "--output yaml" or "--output json" to print the image resource instead.`,
+		Example:           "kp image describe my-image\nkp image describe my-other-image -n my-namespace\nkp image describe my-image -o yaml",
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ImageNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cs, err := clientSetProvider.GetClientSet(namespace)
+			if err != nil {
+				return err
+			}
+
+			ch, err := commands.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+
+			image, err := cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).Get(ctx, args[0], metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			if ch.IsOutput() {
+				return ch.PrintObj(image)
+			}
+
+			buildList, err := cs.KpackClient.KpackV1alpha1().Builds(cs.Namespace).List(ctx, metav1.ListOptions{
+				LabelSelector: v1alpha1.ImageLabel + "=" + args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			sort.Slice(buildList.Items, build.Sort(buildList.Items))
+			return describeImage(cmd, image, buildList.Items)
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	cmd.Flags().StringP(commands.OutputFlag, "o", "", "print the image resource in the specified format instead of a description; supported formats are: yaml, json")
+
+	return cmd
+}
+
+func describeImage(cmd *cobra.Command, image *v1alpha1.Image, builds []v1alpha1.Build) error {
+	statusWriter := commands.NewStatusWriter(cmd.OutOrStdout())
+
+	err := statusWriter.AddBlock(
+		"",
+		"Name", image.Name,
+		"Namespace", image.Namespace,
+		"Tag", image.Spec.Tag,
+		"Service Account", image.Spec.ServiceAccount,
+		"Builder Ref", " ",
+		"  Name", image.Spec.Builder.Name,
+		"  Kind", image.Spec.Builder.Kind,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := statusWriter.AddBlock("Source", getSourceItems(image.Spec.Source)...); err != nil {
+		return err
+	}
+
+	buildItems := []string{"Cache Size", getCacheSize(image)}
+	buildItems = append(buildItems, getBuildHistoryLimits(image)...)
+	buildItems = append(buildItems, getBuildResourceItems(image.Spec.Build)...)
+	if err := statusWriter.AddBlock("Build", buildItems...); err != nil {
+		return err
+	}
+
+	if image.Spec.Build != nil && len(image.Spec.Build.Env) > 0 {
+		tableWriter, err := newSectionTableWriter(cmd, statusWriter, "Environment Variables", "Name", "Value")
+		if err != nil {
+			return err
+		}
+
+		for _, env := range image.Spec.Build.Env {
+			if err := tableWriter.AddRow(env.Name, getEnvValue(env)); err != nil {
+				return err
+			}
+		}
+
+		if err := tableWriter.Write(); err != nil {
+			return err
+		}
+	}
+
+	err = statusWriter.AddBlock(
+		"Status",
+		"Latest Image", image.Status.LatestImage,
+		"Latest Build", image.Status.LatestBuildRef,
+		"Latest Build Reason", image.Status.LatestBuildReason,
+		"Latest Stack", image.Status.LatestStack,
+	)
+	if err != nil {
+		return err
+	}
+
+	tableWriter, err := newSectionTableWriter(cmd, statusWriter, "Conditions", "Type", "Status", "Reason", "Message", "Last Transition Time")
+	if err != nil {
+		return err
+	}
+
+	for _, cond := range image.Status.Conditions {
+		err := tableWriter.AddRow(string(cond.Type), string(cond.Status), cond.Reason, cond.Message, formatTime(cond.LastTransitionTime.Inner))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tableWriter.Write(); err != nil {
+		return err
+	}
+
+	if len(builds) > recentBuildsLimit {
+		builds = builds[len(builds)-recentBuildsLimit:]
+	}
+
+	tableWriter, err = newSectionTableWriter(cmd, statusWriter, "Recent Builds", "Build", "Status", "Started", "Duration", "Reason")
+	if err != nil {
+		return err
+	}
+
+	for _, bld := range builds {
+		err := tableWriter.AddRow(
+			bld.Labels[v1alpha1.BuildNumberLabel],
+			getBuildStatus(bld),
+			formatTime(bld.CreationTimestamp),
+			getBuildDuration(bld),
+			bld.Annotations[v1alpha1.BuildReasonAnnotation],
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tableWriter.Write()
+}
+
+// newSectionTableWriter flushes the status blocks and writes the section title above the table
+func newSectionTableWriter(cmd *cobra.Command, statusWriter *commands.StatusWriter, title string, headers ...string) (*commands.TableWriter, error) {
+	if err := statusWriter.Write(); err != nil {
+		return nil, err
+	}
+
+	if _, err := fmt.Fprintln(cmd.OutOrStdout(), title); err != nil {
+		return nil, err
+	}
+	return commands.NewTableWriter(cmd.OutOrStdout(), headers...)
+}
+
+func getSourceItems(source v1alpha1.SourceConfig) []string {
+	var items []string
+	switch {
+	case source.Git != nil:
+		items = append(items, "Type", "Git", "Url", source.Git.URL, "Revision", source.Git.Revision)
+	case source.Blob != nil:
+		items = append(items, "Type", "Blob", "Url", source.Blob.URL)
+	case source.Registry != nil:
+		items = append(items, "Type", "Local Source", "Image", source.Registry.Image)
+	}
+	return append(items, "Sub Path", source.SubPath)
+}
+
+func getCacheSize(image *v1alpha1.Image) string {
+	if image.Spec.CacheSize == nil {
+		return ""
+	}
+	return image.Spec.CacheSize.String()
+}
+
+func getBuildResourceItems(imageBuild *v1alpha1.ImageBuild) []string {
+	if imageBuild == nil {
+		return nil
+	}
+
+	var items []string
+	for _, r := range []struct {
+		label string
+		list  corev1.ResourceList
+		name  corev1.ResourceName
+	}{
+		{"Request CPU", imageBuild.Resources.Requests, corev1.ResourceCPU},
+		{"Request Memory", imageBuild.Resources.Requests, corev1.ResourceMemory},
+		{"Limit CPU", imageBuild.Resources.Limits, corev1.ResourceCPU},
+		{"Limit Memory", imageBuild.Resources.Limits, corev1.ResourceMemory},
+	} {
+		if q, ok := r.list[r.name]; ok {
+			items = append(items, r.label, q.String())
+		}
+	}
+
+	for _, binding := range imageBuild.Bindings {
+		items = append(items, "Service Binding", binding.Name)
+	}
+	return items
+}
+
+func getEnvValue(env corev1.EnvVar) string {
+	if env.ValueFrom == nil {
+		return env.Value
+	}
+
+	switch {
+	case env.ValueFrom.SecretKeyRef != nil:
+		return fmt.Sprintf("<secret %s key %s>", env.ValueFrom.SecretKeyRef.Name, env.ValueFrom.SecretKeyRef.Key)
+	case env.ValueFrom.ConfigMapKeyRef != nil:
+		return fmt.Sprintf("<config map %s key %s>", env.ValueFrom.ConfigMapKeyRef.Name, env.ValueFrom.ConfigMapKeyRef.Key)
+	default:
+		return "<set from a reference>"
+	}
+}
+
+func getBuildStatus(bld v1alpha1.Build) string {
+	cond := bld.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+	switch {
+	case cond.IsTrue():
+		return "SUCCESS"
+	case cond.IsFalse():
+		return "FAILURE"
+	case cond.IsUnknown():
+		return "BUILDING"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func getBuildDuration(bld v1alpha1.Build) string {
+	if bld.IsRunning() || bld.CreationTimestamp.IsZero() {
+		return ""
+	}
+
+	finished := bld.Status.GetCondition(corev1alpha1.ConditionSucceeded).LastTransitionTime.Inner
+	if finished.IsZero() {
+		return ""
+	}
+	return duration.HumanDuration(finished.Sub(bld.CreationTimestamp.Time))
+}
+
+func formatTime(t metav1.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(timeFormat)
+}
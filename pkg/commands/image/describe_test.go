@@ -0,0 +1,314 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/image"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestImageDescribeCommand(t *testing.T) {
+	spec.Run(t, "TestImageDescribeCommand", testImageDescribeCommand)
+}
+
+func testImageDescribeCommand(t *testing.T, when spec.G, it spec.S) {
+	const (
+		defaultNamespace = "some-default-namespace"
+		namespace        = "test-namespace"
+		imageName        = "test-image"
+	)
+
+	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
+		clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+		return image.NewDescribeCommand(clientSetProvider)
+	}
+
+	when("the image has a full configuration and builds", func() {
+		it("prints the spec, status conditions and the five most recent builds", func() {
+			cacheSize := resource.MustParse("2G")
+			historyLimit := int64(5)
+			transitionTime := metav1.NewTime(time.Date(2021, 6, 1, 16, 0, 0, 0, time.UTC))
+
+			img := &v1alpha1.Image{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      imageName,
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.ImageSpec{
+					Tag: "some-registry.io/some-repo",
+					Builder: corev1.ObjectReference{
+						Kind: v1alpha1.ClusterBuilderKind,
+						Name: "some-cluster-builder",
+					},
+					ServiceAccount: "default",
+					Source: v1alpha1.SourceConfig{
+						Git: &v1alpha1.Git{
+							URL:      "https://github.com/some/repo",
+							Revision: "main",
+						},
+						SubPath: "some-path",
+					},
+					CacheSize:                &cacheSize,
+					SuccessBuildHistoryLimit: &historyLimit,
+					Build: &v1alpha1.ImageBuild{
+						Env: []corev1.EnvVar{
+							{Name: "some-key", Value: "some-val"},
+							{
+								Name: "secret-key",
+								ValueFrom: &corev1.EnvVarSource{
+									SecretKeyRef: &corev1.SecretKeySelector{
+										LocalObjectReference: corev1.LocalObjectReference{Name: "some-secret"},
+										Key:                  "token",
+									},
+								},
+							},
+						},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+							Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1G")},
+						},
+					},
+				},
+				Status: v1alpha1.ImageStatus{
+					Status: corev1alpha1.Status{
+						Conditions: corev1alpha1.Conditions{
+							{
+								Type:               corev1alpha1.ConditionReady,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: corev1alpha1.VolatileTime{Inner: transitionTime},
+							},
+							{
+								Type:               v1alpha1.ConditionBuilderReady,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: corev1alpha1.VolatileTime{Inner: transitionTime},
+							},
+						},
+					},
+					LatestImage:       "some-registry.io/some-repo@sha256:abc123",
+					LatestBuildRef:    "test-image-build-6",
+					LatestBuildReason: "COMMIT",
+					LatestStack:       "io.buildpacks.stacks.bionic",
+				},
+			}
+
+			objects := []runtime.Object{img}
+			for n := 1; n <= 6; n++ {
+				created := time.Date(2021, 6, 1, 9+n, 0, 0, 0, time.UTC)
+				cond := corev1alpha1.Condition{
+					Type:               corev1alpha1.ConditionSucceeded,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: corev1alpha1.VolatileTime{Inner: metav1.NewTime(created.Add(time.Duration(3*n) * time.Minute))},
+				}
+				if n == 6 {
+					cond = corev1alpha1.Condition{
+						Type:   corev1alpha1.ConditionSucceeded,
+						Status: corev1.ConditionUnknown,
+					}
+				}
+
+				objects = append(objects, &v1alpha1.Build{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              fmt.Sprintf("test-image-build-%d", n),
+						Namespace:         namespace,
+						CreationTimestamp: metav1.NewTime(created),
+						Labels: map[string]string{
+							v1alpha1.ImageLabel:       imageName,
+							v1alpha1.BuildNumberLabel: fmt.Sprint(n),
+						},
+						Annotations: map[string]string{
+							v1alpha1.BuildReasonAnnotation: "COMMIT",
+						},
+					},
+					Status: v1alpha1.BuildStatus{
+						Status: corev1alpha1.Status{
+							Conditions: corev1alpha1.Conditions{cond},
+						},
+					},
+				})
+			}
+
+			const expectedOutput = `Name:               test-image
+Namespace:          test-namespace
+Tag:                some-registry.io/some-repo
+Service Account:    default
+Builder Ref:         
+  Name:             some-cluster-builder
+  Kind:             ClusterBuilder
+
+Source
+Type:        Git
+Url:         https://github.com/some/repo
+Revision:    main
+Sub Path:    some-path
+
+Build
+Cache Size:                     2G
+Success Build History Limit:    5
+Request CPU:                    500m
+Limit Memory:                   1G
+
+Environment Variables
+NAME          VALUE
+some-key      some-val
+secret-key    <secret some-secret key token>
+
+Status
+Latest Image:           some-registry.io/some-repo@sha256:abc123
+Latest Build:           test-image-build-6
+Latest Build Reason:    COMMIT
+Latest Stack:           io.buildpacks.stacks.bionic
+
+Conditions
+TYPE            STATUS    REASON    MESSAGE    LAST TRANSITION TIME
+Ready           True                           2021-06-01 16:00:00
+BuilderReady    True                           2021-06-01 16:00:00
+
+Recent Builds
+BUILD    STATUS      STARTED                DURATION    REASON
+2        SUCCESS     2021-06-01 11:00:00    6m          COMMIT
+3        SUCCESS     2021-06-01 12:00:00    9m          COMMIT
+4        SUCCESS     2021-06-01 13:00:00    12m         COMMIT
+5        SUCCESS     2021-06-01 14:00:00    15m         COMMIT
+6        BUILDING    2021-06-01 15:00:00                COMMIT
+
+`
+
+			testhelpers.CommandTest{
+				Objects:        objects,
+				Args:           []string{imageName, "-n", namespace},
+				ExpectedOutput: expectedOutput,
+			}.TestKpack(t, cmdFunc)
+		})
+	})
+
+	when("the image has a minimal configuration and no builds", func() {
+		it("prints the empty sections", func() {
+			img := &v1alpha1.Image{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      imageName,
+					Namespace: defaultNamespace,
+				},
+				Spec: v1alpha1.ImageSpec{
+					Tag: "some-registry.io/some-repo",
+					Builder: corev1.ObjectReference{
+						Kind: v1alpha1.BuilderKind,
+						Name: "some-builder",
+					},
+					ServiceAccount: "default",
+					Source: v1alpha1.SourceConfig{
+						Blob: &v1alpha1.Blob{
+							URL: "https://some-blob-host.com/some-blob.tgz",
+						},
+					},
+				},
+			}
+
+			const expectedOutput = `Name:               test-image
+Namespace:          some-default-namespace
+Tag:                some-registry.io/some-repo
+Service Account:    default
+Builder Ref:         
+  Name:             some-builder
+  Kind:             Builder
+
+Source
+Type:        Blob
+Url:         https://some-blob-host.com/some-blob.tgz
+Sub Path:    --
+
+Build
+Cache Size:    --
+
+Status
+Latest Image:           --
+Latest Build:           --
+Latest Build Reason:    --
+Latest Stack:           --
+
+Conditions
+TYPE    STATUS    REASON    MESSAGE    LAST TRANSITION TIME
+
+Recent Builds
+BUILD    STATUS    STARTED    DURATION    REASON
+
+`
+
+			testhelpers.CommandTest{
+				Objects:        []runtime.Object{img},
+				Args:           []string{imageName},
+				ExpectedOutput: expectedOutput,
+			}.TestKpack(t, cmdFunc)
+		})
+	})
+
+	when("the output flag is used", func() {
+		it("prints the image resource", func() {
+			img := &v1alpha1.Image{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      imageName,
+					Namespace: defaultNamespace,
+				},
+				Spec: v1alpha1.ImageSpec{
+					Tag: "some-registry.io/some-repo",
+					Builder: corev1.ObjectReference{
+						Kind: v1alpha1.ClusterBuilderKind,
+						Name: "some-cluster-builder",
+					},
+					ServiceAccount: "default",
+					Source: v1alpha1.SourceConfig{
+						Git: &v1alpha1.Git{
+							URL:      "some-git-url",
+							Revision: "main",
+						},
+					},
+				},
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{img},
+				Args:    []string{imageName, "-o", "yaml"},
+				ExpectedOutput: `apiVersion: kpack.io/v1alpha1
+kind: Image
+metadata:
+  creationTimestamp: null
+  name: test-image
+  namespace: some-default-namespace
+spec:
+  builder:
+    kind: ClusterBuilder
+    name: some-cluster-builder
+  serviceAccount: default
+  source:
+    git:
+      revision: main
+      url: some-git-url
+  tag: some-registry.io/some-repo
+status: {}
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+	})
+
+	it("returns an error when the image does not exist", func() {
+		testhelpers.CommandTest{
+			Args:           []string{imageName},
+			ExpectErr:      true,
+			ExpectedOutput: "Error: images.kpack.io \"test-image\" not found\n",
+		}.TestKpack(t, cmdFunc)
+	})
+}
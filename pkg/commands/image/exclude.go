@@ -0,0 +1,37 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// excludeValue appends the patterns of both exclude flags to the same slice
+// so that their command line order is kept for "!" negation
+type excludeValue struct {
+	patterns *[]string
+}
+
+func (v excludeValue) String() string {
+	if v.patterns == nil || len(*v.patterns) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(*v.patterns, ",") + "]"
+}
+
+func (v excludeValue) Set(pattern string) error {
+	*v.patterns = append(*v.patterns, pattern)
+	return nil
+}
+
+func (v excludeValue) Type() string {
+	return "stringArray"
+}
+
+func setExcludeFlags(cmd *cobra.Command, patterns *[]string) {
+	cmd.Flags().Var(excludeValue{patterns}, "exclude", "gitignore pattern of local source files to exclude from the upload")
+	cmd.Flags().Var(excludeValue{patterns}, "local-path-exclude", "gitignore pattern of local source files to exclude from the upload, same as --exclude")
+}
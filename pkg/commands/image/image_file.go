@@ -0,0 +1,234 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path"
+
+	"github.com/ghodss/yaml"
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/image"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+const stdinPath = "-"
+
+func imageFileArgs(cmd *cobra.Command, args []string) error {
+	if cmd.Flags().Changed("file") {
+		if len(args) > 0 {
+			return errors.New("image name cannot be provided with --file")
+		}
+		return nil
+	}
+	return commands.ExactArgsWithUsage(1)(cmd, args)
+}
+
+func readImageResources(filePath string, stdin io.Reader) ([]*v1alpha1.Image, error) {
+	reader := stdin
+	if filePath != stdinPath {
+		file, err := os.Open(filePath)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	yamlReader := utilyaml.NewYAMLReader(bufio.NewReader(reader))
+
+	var images []*v1alpha1.Image
+	for {
+		doc, err := yamlReader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "failed to read image resources from %q", filePath)
+		}
+
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		img := &v1alpha1.Image{}
+		if err := yaml.Unmarshal(doc, img); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse image resource from %q", filePath)
+		}
+
+		if img.Kind == "" && img.Name == "" {
+			continue
+		}
+
+		if img.Kind != "Image" {
+			return nil, errors.Errorf("unsupported kind %q in %q, only Image resources are supported", img.Kind, filePath)
+		}
+
+		images = append(images, img)
+	}
+
+	if len(images) == 0 {
+		return nil, errors.Errorf("no image resources found in %q", filePath)
+	}
+
+	return images, nil
+}
+
+// applyImageFile creates the images from the file, or patches them when they already exist
+// and patchExisting is set, and returns the images that should be waited on
+func applyImageFile(ctx context.Context, cmd *cobra.Command, filePath, tag string, patchExisting bool, factory *image.Factory, ch *commands.CommandHelper, cs k8s.ClientSet) ([]*v1alpha1.Image, error) {
+	if filePath == stdinPath && factory.EnvFile == stdinPath {
+		return nil, errors.New("--file and --env-file cannot both read from stdin")
+	}
+
+	resources, err := readImageResources(filePath, cmd.InOrStdin())
+	if err != nil {
+		return nil, err
+	}
+
+	if tag != "" && len(resources) > 1 {
+		return nil, errors.New("--tag cannot be used with multiple image resources")
+	}
+
+	var images []*v1alpha1.Image
+	for _, resource := range resources {
+		imgCs := cs
+		if resource.Namespace != "" {
+			imgCs.Namespace = resource.Namespace
+		}
+
+		if patchExisting {
+			existing, err := imgCs.KpackClient.KpackV1alpha1().Images(imgCs.Namespace).Get(ctx, resource.Name, metav1.GetOptions{})
+			if err == nil {
+				patched, img, err := patchFromResource(ctx, existing, resource, factory, ch, imgCs)
+				if err != nil {
+					return nil, err
+				}
+				if patched {
+					images = append(images, img)
+				}
+				continue
+			} else if !k8serrors.IsNotFound(err) {
+				return nil, err
+			}
+		}
+
+		img, err := createFromResource(ctx, resource, tag, factory, ch, imgCs)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, img)
+	}
+
+	return images, nil
+}
+
+func createFromResource(ctx context.Context, resource *v1alpha1.Image, tag string, factory *image.Factory, ch *commands.CommandHelper, cs k8s.ClientSet) (*v1alpha1.Image, error) {
+	if err := ch.PrintStatus("Creating Image..."); err != nil {
+		return nil, err
+	}
+
+	if !ch.IsDryRun() {
+		if err := validateServiceBindings(ctx, cs, factory.Bindings); err != nil {
+			return nil, err
+		}
+	}
+
+	if tag == "" && resource.Spec.Tag == "" {
+		repository, err := k8s.DefaultConfigHelper(cs).GetCanonicalRepository(ctx)
+		if err != nil {
+			return nil, err
+		}
+		tag = path.Join(repository, resource.Name)
+	}
+
+	img, err := factory.MakeImageFromResource(resource, cs.Namespace, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setupBlobAuth(ctx, factory.BlobAuthSecret, img, ch, cs); err != nil {
+		return nil, err
+	}
+
+	return submitCreate(ctx, img, ch, cs)
+}
+
+func patchFromResource(ctx context.Context, existing, resource *v1alpha1.Image, factory *image.Factory, ch *commands.CommandHelper, cs k8s.ClientSet) (bool, *v1alpha1.Image, error) {
+	if err := ch.PrintStatus("Patching Image..."); err != nil {
+		return false, nil, err
+	}
+
+	if !ch.IsDryRun() {
+		if err := validateServiceBindings(ctx, cs, factory.Bindings); err != nil {
+			return false, nil, err
+		}
+	}
+
+	// the tag is immutable so the existing tag is kept
+	desired, err := factory.MakeImageFromResource(resource, cs.Namespace, existing.Spec.Tag)
+	if err != nil {
+		return false, nil, err
+	}
+
+	// fields that are not set in the file keep their existing values so that server
+	// defaults do not cause a patch when the file matches the existing image
+	patchedImage := existing.DeepCopy()
+	patchedImage.Spec.Builder = desired.Spec.Builder
+	patchedImage.Spec.ServiceAccount = desired.Spec.ServiceAccount
+	patchedImage.Spec.Source = desired.Spec.Source
+	if existing.Spec.Build != nil || !equality.Semantic.DeepEqual(desired.Spec.Build, &v1alpha1.ImageBuild{}) {
+		patchedImage.Spec.Build = desired.Spec.Build
+	}
+	if desired.Spec.CacheSize != nil {
+		patchedImage.Spec.CacheSize = desired.Spec.CacheSize
+	}
+	if desired.Spec.SuccessBuildHistoryLimit != nil {
+		patchedImage.Spec.SuccessBuildHistoryLimit = desired.Spec.SuccessBuildHistoryLimit
+	}
+	if desired.Spec.FailedBuildHistoryLimit != nil {
+		patchedImage.Spec.FailedBuildHistoryLimit = desired.Spec.FailedBuildHistoryLimit
+	}
+
+	for k, v := range desired.Labels {
+		if patchedImage.Labels == nil {
+			patchedImage.Labels = map[string]string{}
+		}
+		patchedImage.Labels[k] = v
+	}
+
+	for k, v := range desired.Annotations {
+		if patchedImage.Annotations == nil {
+			patchedImage.Annotations = map[string]string{}
+		}
+		patchedImage.Annotations[k] = v
+	}
+
+	// deleted labels and annotations are only known by the factory
+	if err := factory.Metadata.Apply(patchedImage); err != nil {
+		return false, nil, err
+	}
+
+	if err := setupBlobAuth(ctx, factory.BlobAuthSecret, patchedImage, ch, cs); err != nil {
+		return false, nil, err
+	}
+
+	patch, err := k8s.CreatePatch(existing, patchedImage)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return submitPatch(ctx, existing, patchedImage, patch, ch, cs, true)
+}
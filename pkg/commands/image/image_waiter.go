@@ -5,11 +5,65 @@ package image
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"sort"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 )
 
 type ImageWaiter interface {
 	Wait(ctx context.Context, writer io.Writer, image *v1alpha1.Image) (string, error)
 }
+
+func setLogsFlag(cmd *cobra.Command, logs *bool) {
+	cmd.Flags().BoolVar(logs, "logs", true, "stream the build logs when used with --wait")
+}
+
+// buildLogsWriter returns the writer that receives the build logs while waiting on an image, the logs
+// are written to stderr so that they do not mix with the resource printed with --output
+func buildLogsWriter(cmd *cobra.Command, logs bool) io.Writer {
+	if !logs {
+		return ioutil.Discard
+	}
+	return cmd.ErrOrStderr()
+}
+
+func waitForImage(ctx context.Context, writer io.Writer, ch *commands.CommandHelper, cs k8s.ClientSet, waiter ImageWaiter, img *v1alpha1.Image) error {
+	ctx, cancel := context.WithTimeout(ctx, ch.WaitTimeout())
+	defer cancel()
+
+	_, err := waiter.Wait(ctx, writer, img)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return waitTimeoutError(cs, img.Name, ch.WaitTimeout())
+	}
+	return err
+}
+
+func waitTimeoutError(cs k8s.ClientSet, name string, timeout time.Duration) error {
+	msg := fmt.Sprintf("timed out after %v waiting for Image %q to be ready", timeout, name)
+
+	buildList, err := cs.KpackClient.KpackV1alpha1().Builds(cs.Namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: v1alpha1.ImageLabel + "=" + name,
+	})
+	if err != nil || len(buildList.Items) == 0 {
+		return commands.NewTimeoutError("%s", msg)
+	}
+
+	sort.Slice(buildList.Items, build.Sort(buildList.Items))
+	latest := buildList.Items[len(buildList.Items)-1]
+
+	if cond := latest.Status.GetCondition(corev1alpha1.ConditionSucceeded); cond != nil && cond.Message != "" {
+		return commands.NewTimeoutError("%s: %s", msg, cond.Message)
+	}
+	return commands.NewTimeoutError("%s", msg)
+}
@@ -4,13 +4,17 @@
 package image
 
 import (
+	"context"
 	"sort"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
@@ -21,6 +25,10 @@ func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 		namespace     string
 		allNamespaces bool
 		filters       []string
+		labelSelector string
+		sortBy        string
+		watch         bool
+		maxWidth      int
 	)
 
 	cmd := &cobra.Command{
@@ -28,28 +36,122 @@ func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 		Short: "List images",
 		Long: `Prints a table of the most important information about images in the provided namespace.
 
-The namespace defaults to the kubernetes current-context namespace.`,
+The namespace defaults to the kubernetes current-context namespace.
+Use the --all-namespaces flag to list images in all namespaces, this requires permission to list images cluster-wide.
+Use the --watch flag to keep the table updated as images change until interrupted.
+Use "--output wide" to add the builder, latest stack, latest build, source and last build age columns to the table,
+use the --max-column-width flag to truncate long values such as the latest image digest so that the table fits the terminal.
+Use "--output yaml" or "--output json" to print the ImageList resource, an empty list is printed when no images are found.
+Use the --filter flag to only list the images matching all the filters, for example "--filter ready=false" to find broken images.
+Use the --sort-by flag to sort the images by name, by ready status or by the creation time of their latest build.
+The ready status is colored when the output is a terminal, use the --no-color flag or set NO_COLOR to turn the colors off.`,
 		Example: `kp image list
 kp image list -A
 kp image list -n my-namespace
-kp image list --filter ready=true --filter latest-reason=commit,trigger`,
+kp image list -l 'app=my-app,team in (a,b)'
+kp image list --watch
+kp image list -o wide
+kp image list -o wide --max-column-width 64
+kp image list -o json
+kp image list --filter ready=false
+kp image list --sort-by latest-build
+kp image list --no-color
+kp image list --filter ready=true --filter latest-reason=commit,trigger
+kp image list -o custom-columns=NAME:.metadata.name,IMAGE:.status.latestImage`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := commands.ValidateAllNamespacesFlag(cmd); err != nil {
+				return err
+			}
+
+			if maxWidth < 0 {
+				return errors.New("--max-column-width must not be negative")
+			}
+
+			if err := commands.ValidateLabelSelector(labelSelector); err != nil {
+				return err
+			}
+
+			if err := commands.ValidateSortBy(sortBy, imageSortKeys...); err != nil {
+				return err
+			}
+
 			cs, err := clientSetProvider.GetClientSet(namespace)
 			if err != nil {
 				return err
 			}
 
-			var imagesNamespace string
+			ch, err := commands.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
 
+			imagesNamespace := cs.Namespace
 			if allNamespaces {
-				imagesNamespace = ""
-			} else {
-				imagesNamespace = cs.Namespace
+				imagesNamespace = metav1.NamespaceAll
 			}
 
-			imageList, err := cs.KpackClient.KpackV1alpha1().Images(imagesNamespace).List(cmd.Context(), metav1.ListOptions{})
+			if watch && ch.IsOutput() {
+				return errors.New("--watch cannot be used with --output")
+			}
+
+			imageList, err := cs.KpackClient.KpackV1alpha1().Images(imagesNamespace).List(cmd.Context(), metav1.ListOptions{
+				LabelSelector: labelSelector,
+			})
 			if err != nil {
-				return err
+				return commands.AllNamespacesListError(err, "images", allNamespaces)
+			}
+
+			needBuildTimes := ch.IsWide() || sortBy == "latest-build"
+
+			if watch {
+				if _, err := parseFilters(filters); err != nil {
+					return err
+				}
+
+				watcher, err := cs.KpackClient.KpackV1alpha1().Images(imagesNamespace).Watch(cmd.Context(), metav1.ListOptions{
+					LabelSelector:   labelSelector,
+					ResourceVersion: imageList.ResourceVersion,
+				})
+				if err != nil {
+					return commands.AllNamespacesListError(err, "images", allNamespaces)
+				}
+				defer watcher.Stop()
+
+				var objs []runtime.Object
+				for i := range imageList.Items {
+					objs = append(objs, &imageList.Items[i])
+				}
+
+				buildTimes, err := listBuildTimes(cmd.Context(), cs, imagesNamespace, needBuildTimes)
+				if err != nil {
+					return err
+				}
+
+				return commands.WatchTable(cmd.Context(), cmd.OutOrStdout(), objs, watcher, func(objs []runtime.Object) error {
+					watchedList := &v1alpha1.ImageList{}
+					for _, obj := range objs {
+						if img, ok := obj.(*v1alpha1.Image); ok {
+							watchedList.Items = append(watchedList.Items, *img)
+						}
+					}
+
+					watchedList, err := filterImageList(watchedList, filters)
+					if err != nil {
+						return err
+					}
+
+					// the builds are only listed again when an image has a latest build that was created
+					// after they were listed
+					if needBuildTimes && missingBuildTime(watchedList, buildTimes) {
+						buildTimes, err = listBuildTimes(cmd.Context(), cs, imagesNamespace, needBuildTimes)
+						if err != nil {
+							return err
+						}
+					}
+
+					sortImages(watchedList, sortBy, buildTimes)
+					return displayImagesTable(cmd, watchedList, allNamespaces, ch.IsWide(), maxWidth, buildTimes)
+				})
 			}
 
 			imageList, err = filterImageList(imageList, filters)
@@ -57,21 +159,38 @@ kp image list --filter ready=true --filter latest-reason=commit,trigger`,
 				return err
 			}
 
-			sort.SliceStable(imageList.Items, func(i, j int) bool {
-				return imageList.Items[i].Name < imageList.Items[j].Name
-			})
+			buildTimes, err := listBuildTimes(cmd.Context(), cs, imagesNamespace, needBuildTimes)
+			if err != nil {
+				return err
+			}
+
+			sortImages(imageList, sortBy, buildTimes)
+
+			if ch.IsOutput() {
+				if imageList.Items == nil {
+					imageList.Items = []v1alpha1.Image{}
+				}
+				return ch.PrintObj(imageList)
+			}
 
-			if len(imageList.Items) == 0 {
+			if len(imageList.Items) == 0 && len(filters) > 0 {
+				return errors.New("no images matched the filter")
+			} else if len(imageList.Items) == 0 {
 				return errors.New("no images found")
 			} else {
-				return displayImagesTable(cmd, imageList)
+				return displayImagesTable(cmd, imageList, allNamespaces, ch.IsWide(), maxWidth, buildTimes)
 			}
-
 		},
 		SilenceUsage: true,
 	}
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
-	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Return objects found in all namespaces")
+	commands.SetAllNamespacesFlag(cmd, &allNamespaces)
+	commands.SetLabelSelectorFlag(cmd, &labelSelector)
+	commands.SetSortByFlag(cmd, &sortBy, imageSortKeys...)
+	commands.SetWideListOutputFlag(cmd)
+	commands.SetWatchFlag(cmd, &watch)
+	commands.SetNoColorFlag(cmd)
+	cmd.Flags().IntVar(&maxWidth, "max-column-width", 0, "truncate the table values longer than the width, 0 prints the values in full")
 	cmd.Flags().StringArrayVar(&filters, "filter", nil,
 		`Each new filter argument requires an additional filter flag.
 Multiple values can be provided using comma separation.
@@ -84,14 +203,103 @@ Supported filters and values:
 	return cmd
 }
 
-func displayImagesTable(cmd *cobra.Command, imageList *v1alpha1.ImageList) error {
-	writer, err := commands.NewTableWriter(cmd.OutOrStdout(), "NAME", "READY", "LATEST REASON", "LATEST IMAGE", "NAMESPACE")
+var imageSortKeys = []string{"name", "ready", "latest-build"}
+
+// listBuildTimes maps the namespace and name of the builds to their creation time, the builds are only
+// listed when the time of the latest build is needed to sort the images or to display their age
+func listBuildTimes(ctx context.Context, cs k8s.ClientSet, namespace string, needed bool) (map[string]metav1.Time, error) {
+	created := map[string]metav1.Time{}
+	if !needed {
+		return created, nil
+	}
+
+	buildList, err := cs.KpackClient.KpackV1alpha1().Builds(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bld := range buildList.Items {
+		created[bld.Namespace+"/"+bld.Name] = bld.CreationTimestamp
+	}
+	return created, nil
+}
+
+// missingBuildTime returns whether the latest build of an image is not in buildTimes
+func missingBuildTime(imageList *v1alpha1.ImageList, buildTimes map[string]metav1.Time) bool {
+	for _, img := range imageList.Items {
+		if img.Status.LatestBuildRef == "" {
+			continue
+		}
+		if _, ok := buildTimes[img.Namespace+"/"+img.Status.LatestBuildRef]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+func latestBuildTime(img v1alpha1.Image, buildTimes map[string]metav1.Time) metav1.Time {
+	return buildTimes[img.Namespace+"/"+img.Status.LatestBuildRef]
+}
+
+// sortImages sorts the images by namespace and name before sorting them by the sort key
+func sortImages(imageList *v1alpha1.ImageList, sortBy string, buildTimes map[string]metav1.Time) {
+	items := imageList.Items
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Namespace != items[j].Namespace {
+			return items[i].Namespace < items[j].Namespace
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	switch sortBy {
+	case "ready":
+		sort.SliceStable(items, func(i, j int) bool {
+			return getReadyText(items[i]) < getReadyText(items[j])
+		})
+	case "latest-build":
+		sort.SliceStable(items, func(i, j int) bool {
+			ti := latestBuildTime(items[i], buildTimes)
+			tj := latestBuildTime(items[j], buildTimes)
+			return ti.Before(&tj)
+		})
+	}
+}
+
+func displayImagesTable(cmd *cobra.Command, imageList *v1alpha1.ImageList, allNamespaces, wide bool, maxWidth int, buildTimes map[string]metav1.Time) error {
+	headers := []string{"NAME", "READY", "LATEST REASON", "LATEST IMAGE"}
+	if allNamespaces {
+		headers = append([]string{"NAMESPACE"}, headers...)
+	}
+
+	writer, err := commands.NewWideTableWriter(cmd.OutOrStdout(), wide, headers, "BUILDER", "STACK", "LATEST BUILD", "SOURCE", "LAST BUILD AGE")
+	if err != nil {
+		return err
+	}
+
+	writer.SetMaxColumnWidth(maxWidth)
+
+	color, err := commands.ColorEnabled(cmd)
 	if err != nil {
 		return err
 	}
 
+	if color {
+		writer.ColorizeStatusColumn("READY")
+	}
+
 	for _, img := range imageList.Items {
-		err := writer.AddRow(img.Name, getReadyText(img), img.Status.LatestBuildReason, img.Status.LatestImage, img.Namespace)
+		row := []string{img.Name, getReadyText(img), img.Status.LatestBuildReason, img.Status.LatestImage}
+		if allNamespaces {
+			row = append([]string{img.Namespace}, row...)
+		}
+
+		err := writer.AddWideRow(row,
+			getBuilderText(img),
+			img.Status.LatestStack,
+			img.Status.LatestBuildRef,
+			getSourceText(img),
+			getLastBuildAge(img, buildTimes),
+		)
 		if err != nil {
 			return err
 		}
@@ -107,3 +315,33 @@ func getReadyText(img v1alpha1.Image) string {
 	}
 	return string(cond.Status)
 }
+
+func getBuilderText(img v1alpha1.Image) string {
+	if img.Spec.Builder.Kind == "" {
+		return img.Spec.Builder.Name
+	}
+	return img.Spec.Builder.Kind + "/" + img.Spec.Builder.Name
+}
+
+func getSourceText(img v1alpha1.Image) string {
+	source := img.Spec.Source
+	switch {
+	case source.Git != nil:
+		return source.Git.URL + "@" + source.Git.Revision
+	case source.Blob != nil:
+		return source.Blob.URL
+	case source.Registry != nil:
+		return source.Registry.Image
+	default:
+		return ""
+	}
+}
+
+// getLastBuildAge is empty for the images that have never built
+func getLastBuildAge(img v1alpha1.Image, buildTimes map[string]metav1.Time) string {
+	created := latestBuildTime(img, buildTimes)
+	if img.Status.LatestBuildRef == "" || created.IsZero() {
+		return ""
+	}
+	return duration.HumanDuration(time.Since(created.Time))
+}
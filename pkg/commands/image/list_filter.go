@@ -77,7 +77,7 @@ func parseFilters(flags []string) ([]filter, error) {
 			continue
 		}
 
-		return nil, fmt.Errorf(`invalid filter argument "%s"`, flag)
+		return nil, fmt.Errorf(`invalid filter argument "%s", supported filters are builder, clusterbuilder, latest-reason and ready`, flag)
 	}
 
 	return filters, nil
@@ -161,7 +161,7 @@ func testFilter(t *testing.T, when spec.G, it spec.S) {
 	when("an invalid filter is specified", func() {
 		it("returns a helpful error message", func() {
 			_, err := filterImageList(imagesWithSameBuilder, []string{"some-invalid-filter=some-value"})
-			require.Error(t, err, "invalid filter argument \"some-invalid-filter=some-value\"")
+			require.EqualError(t, err, `invalid filter argument "some-invalid-filter=some-value", supported filters are builder, clusterbuilder, latest-reason and ready`)
 		})
 	})
 
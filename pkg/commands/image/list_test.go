@@ -4,17 +4,28 @@
 package image_test
 
 import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
 	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
 	"github.com/sclevine/spec"
 	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	clientgotesting "k8s.io/client-go/testing"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands/image"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
 )
@@ -25,6 +36,7 @@ func TestImageListCommand(t *testing.T) {
 
 func testImageListCommand(t *testing.T, when spec.G, it spec.S) {
 	const defaultNamespace = "some-default-namespace"
+	isTerminal := commands.IsTerminal
 
 	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
 		clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
@@ -116,10 +128,10 @@ func testImageListCommand(t *testing.T, when spec.G, it spec.S) {
 						notInNamespaceImage,
 					},
 					Args: []string{"-n", "test-namespace"},
-					ExpectedOutput: `NAME            READY      LATEST REASON    LATEST IMAGE                                      NAMESPACE
-test-image-1    False      COMMIT           test-registry.io/test-image-1@sha256:abcdef123    test-namespace
-test-image-2    Unknown    COMMIT           test-registry.io/test-image-2@sha256:abcdef123    test-namespace
-test-image-3    True       COMMIT           test-registry.io/test-image-3@sha256:abcdef123    test-namespace
+					ExpectedOutput: `NAME            READY      LATEST REASON    LATEST IMAGE
+test-image-1    False      COMMIT           test-registry.io/test-image-1@sha256:abcdef123
+test-image-2    Unknown    COMMIT           test-registry.io/test-image-2@sha256:abcdef123
+test-image-3    True       COMMIT           test-registry.io/test-image-3@sha256:abcdef123
 
 `,
 				}.TestKpack(t, cmdFunc)
@@ -138,6 +150,91 @@ test-image-3    True       COMMIT           test-registry.io/test-image-3@sha256
 		})
 	})
 
+	when("the ready status is colored", func() {
+		readyImage := func(name string, status corev1.ConditionStatus) *v1alpha1.Image {
+			return &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      name,
+					Namespace: defaultNamespace,
+				},
+				Status: v1alpha1.ImageStatus{
+					LatestBuildReason: "COMMIT",
+					Status: corev1alpha1.Status{
+						Conditions: []corev1alpha1.Condition{
+							{
+								Type:   corev1alpha1.ConditionReady,
+								Status: status,
+							},
+						},
+					},
+					LatestImage: "test-registry.io/" + name + "@sha256:abcdef123",
+				},
+			}
+		}
+
+		images := []runtime.Object{
+			readyImage("test-image-1", corev1.ConditionFalse),
+			readyImage("test-image-2", corev1.ConditionUnknown),
+			readyImage("test-image-3", corev1.ConditionTrue),
+		}
+
+		const uncoloredOutput = `NAME            READY      LATEST REASON    LATEST IMAGE
+test-image-1    False      COMMIT           test-registry.io/test-image-1@sha256:abcdef123
+test-image-2    Unknown    COMMIT           test-registry.io/test-image-2@sha256:abcdef123
+test-image-3    True       COMMIT           test-registry.io/test-image-3@sha256:abcdef123
+
+`
+
+		it("does not color the output when it is not a terminal", func() {
+			commands.IsTerminal = func(io.Writer) bool { return false }
+			defer func() { commands.IsTerminal = isTerminal }()
+
+			testhelpers.CommandTest{
+				Objects:        images,
+				ExpectedOutput: uncoloredOutput,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		when("the output is a terminal", func() {
+			it.Before(func() {
+				commands.IsTerminal = func(io.Writer) bool { return true }
+			})
+
+			it.After(func() {
+				commands.IsTerminal = isTerminal
+			})
+
+			it("colors True green, False red and Unknown yellow", func() {
+				testhelpers.CommandTest{
+					Objects: images,
+					ExpectedOutput: "NAME            \x1b[39mREADY\x1b[0m      LATEST REASON    LATEST IMAGE\n" +
+						"test-image-1    \x1b[31mFalse\x1b[0m      COMMIT           test-registry.io/test-image-1@sha256:abcdef123\n" +
+						"test-image-2    \x1b[33mUnknown\x1b[0m    COMMIT           test-registry.io/test-image-2@sha256:abcdef123\n" +
+						"test-image-3    \x1b[32mTrue\x1b[0m       COMMIT           test-registry.io/test-image-3@sha256:abcdef123\n" +
+						"\n",
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("does not color the output with the no-color flag", func() {
+				testhelpers.CommandTest{
+					Objects:        images,
+					Args:           []string{"--no-color"},
+					ExpectedOutput: uncoloredOutput,
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("does not color the output when NO_COLOR is set", func() {
+				require.NoError(t, os.Setenv("NO_COLOR", "1"))
+				defer os.Unsetenv("NO_COLOR")
+
+				testhelpers.CommandTest{
+					Objects:        images,
+					ExpectedOutput: uncoloredOutput,
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+	})
+
 	when("a namespace is not provided", func() {
 		when("the namespaces has images", func() {
 			it("returns a table of image details", func() {
@@ -225,10 +322,10 @@ test-image-3    True       COMMIT           test-registry.io/test-image-3@sha256
 						image3,
 						notDefaultNamespaceImage,
 					},
-					ExpectedOutput: `NAME            READY      LATEST REASON    LATEST IMAGE                                      NAMESPACE
-test-image-1    False      COMMIT           test-registry.io/test-image-1@sha256:abcdef123    some-default-namespace
-test-image-2    Unknown    COMMIT           test-registry.io/test-image-2@sha256:abcdef123    some-default-namespace
-test-image-3    True       COMMIT           test-registry.io/test-image-3@sha256:abcdef123    some-default-namespace
+					ExpectedOutput: `NAME            READY      LATEST REASON    LATEST IMAGE
+test-image-1    False      COMMIT           test-registry.io/test-image-1@sha256:abcdef123
+test-image-2    Unknown    COMMIT           test-registry.io/test-image-2@sha256:abcdef123
+test-image-3    True       COMMIT           test-registry.io/test-image-3@sha256:abcdef123
 
 `,
 				}.TestKpack(t, cmdFunc)
@@ -334,11 +431,11 @@ test-image-3    True       COMMIT           test-registry.io/test-image-3@sha256
 						notDefaultNamespaceImage,
 					},
 					Args: []string{"-A"},
-					ExpectedOutput: `NAME            READY      LATEST REASON    LATEST IMAGE                                      NAMESPACE
-test-image-1    False      COMMIT           test-registry.io/test-image-1@sha256:abcdef123    some-default-namespace
-test-image-2    Unknown    COMMIT           test-registry.io/test-image-2@sha256:abcdef123    some-default-namespace
-test-image-3    True       COMMIT           test-registry.io/test-image-3@sha256:abcdef123    some-default-namespace
-test-image-4    False      COMMIT           test-registry.io/test-image-4@sha256:abcdef123    not-default-namespace
+					ExpectedOutput: `NAMESPACE                 NAME            READY      LATEST REASON    LATEST IMAGE
+not-default-namespace     test-image-4    False      COMMIT           test-registry.io/test-image-4@sha256:abcdef123
+some-default-namespace    test-image-1    False      COMMIT           test-registry.io/test-image-1@sha256:abcdef123
+some-default-namespace    test-image-2    Unknown    COMMIT           test-registry.io/test-image-2@sha256:abcdef123
+some-default-namespace    test-image-3    True       COMMIT           test-registry.io/test-image-3@sha256:abcdef123
 
 `,
 				}.TestKpack(t, cmdFunc)
@@ -354,5 +451,582 @@ test-image-4    False      COMMIT           test-registry.io/test-image-4@sha256
 				})
 			})
 		})
+
+		it("returns an error when a namespace is also provided", func() {
+			testhelpers.CommandTest{
+				Args:           []string{"-A", "-n", "test-namespace"},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: --namespace and --all-namespaces are mutually exclusive\n",
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("returns a permission hint when the images cannot be listed in all namespaces", func() {
+			forbiddenCmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
+				clientSet.PrependReactor("list", "images", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+					if action.GetNamespace() != "" {
+						return false, nil, nil
+					}
+					return true, nil, k8serrors.NewForbidden(schema.GroupResource{Group: "kpack.io", Resource: "images"}, "", errors.New(`User "some-user" cannot list resource "images" in API group "kpack.io" at the cluster scope`))
+				})
+				return cmdFunc(clientSet)
+			}
+
+			testhelpers.CommandTest{
+				Args:      []string{"-A"},
+				ExpectErr: true,
+				ExpectedOutput: `Error: images.kpack.io is forbidden: User "some-user" cannot list resource "images" in API group "kpack.io" at the cluster scope
+listing images with --all-namespaces requires permission to list them cluster-wide, use --namespace to list a single namespace instead
+`,
+			}.TestKpack(t, forbiddenCmdFunc)
+		})
+	})
+
+	when("output flag is used", func() {
+		it("prints custom columns for the images", func() {
+			image1 := &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-image-1",
+					Namespace: defaultNamespace,
+				},
+				Status: v1alpha1.ImageStatus{
+					LatestImage: "test-registry.io/test-image-1@sha256:abcdef123",
+				},
+			}
+			image2 := &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-image-2",
+					Namespace: defaultNamespace,
+				},
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					image2,
+					image1,
+				},
+				Args: []string{"-o", "custom-columns=NAME:.metadata.name,IMAGE:.status.latestImage"},
+				ExpectedOutput: `NAME            IMAGE
+test-image-1    test-registry.io/test-image-1@sha256:abcdef123
+test-image-2    <none>
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("prints the images as a typed image list", func() {
+			image1 := &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-image-1",
+					Namespace: defaultNamespace,
+				},
+			}
+			image2 := &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-image-2",
+					Namespace: defaultNamespace,
+				},
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					image2,
+					image1,
+				},
+				Args:           []string{"-o", "jsonpath={.apiVersion}/{.kind}{range .items[*]} {.metadata.name}{end}"},
+				ExpectedOutput: "kpack.io/v1alpha1/ImageList test-image-1 test-image-2\n",
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("prints an empty image list when there are no images", func() {
+			testhelpers.CommandTest{
+				Args: []string{"-o", "json"},
+				ExpectedOutput: `{
+    "kind": "ImageList",
+    "apiVersion": "kpack.io/v1alpha1",
+    "metadata": {},
+    "items": []
+}
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		when("sorting the images", func() {
+			readyCondition := func(status corev1.ConditionStatus) corev1alpha1.Status {
+				return corev1alpha1.Status{Conditions: []corev1alpha1.Condition{{Type: corev1alpha1.ConditionReady, Status: status}}}
+			}
+
+			images := []runtime.Object{
+				&v1alpha1.Image{
+					ObjectMeta: v1.ObjectMeta{Name: "image-a", Namespace: defaultNamespace},
+					Status:     v1alpha1.ImageStatus{Status: readyCondition(corev1.ConditionTrue), LatestBuildRef: "image-a-build-2"},
+				},
+				&v1alpha1.Image{
+					ObjectMeta: v1.ObjectMeta{Name: "image-c", Namespace: defaultNamespace},
+					Status:     v1alpha1.ImageStatus{Status: readyCondition(corev1.ConditionFalse), LatestBuildRef: "image-c-build-1"},
+				},
+				&v1alpha1.Image{
+					ObjectMeta: v1.ObjectMeta{Name: "image-b", Namespace: defaultNamespace},
+					Status:     v1alpha1.ImageStatus{Status: readyCondition(corev1.ConditionTrue), LatestBuildRef: "image-b-build-1"},
+				},
+			}
+
+			builds := []runtime.Object{
+				&v1alpha1.Build{ObjectMeta: v1.ObjectMeta{Name: "image-a-build-2", Namespace: defaultNamespace, CreationTimestamp: v1.Unix(300, 0)}},
+				&v1alpha1.Build{ObjectMeta: v1.ObjectMeta{Name: "image-b-build-1", Namespace: defaultNamespace, CreationTimestamp: v1.Unix(100, 0)}},
+				&v1alpha1.Build{ObjectMeta: v1.ObjectMeta{Name: "image-c-build-1", Namespace: defaultNamespace, CreationTimestamp: v1.Unix(200, 0)}},
+			}
+
+			it("sorts by name by default", func() {
+				testhelpers.CommandTest{
+					Objects:        images,
+					Args:           []string{"-o", "jsonpath={.items[*].metadata.name}"},
+					ExpectedOutput: "image-a image-b image-c\n",
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("sorts by ready status and keeps the name order within a status", func() {
+				testhelpers.CommandTest{
+					Objects:        images,
+					Args:           []string{"--sort-by", "ready", "-o", "jsonpath={.items[*].metadata.name}"},
+					ExpectedOutput: "image-c image-a image-b\n",
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("sorts by the creation time of the latest build", func() {
+				testhelpers.CommandTest{
+					Objects:        append(images, builds...),
+					Args:           []string{"--sort-by", "latest-build", "-o", "jsonpath={.items[*].metadata.name}"},
+					ExpectedOutput: "image-b image-c image-a\n",
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("returns an error for an unsupported sort key", func() {
+				testhelpers.CommandTest{
+					Objects:        images,
+					Args:           []string{"--sort-by", "age"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: invalid --sort-by \"age\", must be one of name, ready, latest-build\n",
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
+		it("prints the kind and name of each image with name output", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					&v1alpha1.Image{ObjectMeta: v1.ObjectMeta{Name: "test-image-2", Namespace: defaultNamespace}},
+					&v1alpha1.Image{ObjectMeta: v1.ObjectMeta{Name: "test-image-1", Namespace: defaultNamespace}},
+				},
+				Args:           []string{"-o", "name"},
+				ExpectedOutput: "image.kpack.io/test-image-1\nimage.kpack.io/test-image-2\n",
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("prints an empty image list when no images match the filters", func() {
+			image1 := &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-image-1",
+					Namespace: defaultNamespace,
+				},
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					image1,
+				},
+				Args:           []string{"--filter", "builder=other-builder", "-o", "jsonpath={.kind} {.items}"},
+				ExpectedOutput: "ImageList []\n",
+			}.TestKpack(t, cmdFunc)
+		})
+
+		when("filtering by readiness", func() {
+			readyImage := &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-image-1",
+					Namespace: defaultNamespace,
+				},
+				Status: v1alpha1.ImageStatus{
+					LatestBuildReason: "COMMIT",
+					Status: corev1alpha1.Status{
+						Conditions: []corev1alpha1.Condition{
+							{
+								Type:   corev1alpha1.ConditionReady,
+								Status: corev1.ConditionTrue,
+							},
+						},
+					},
+					LatestImage: "test-registry.io/test-image-1@sha256:abcdef123",
+				},
+			}
+			brokenImage := &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-image-2",
+					Namespace: defaultNamespace,
+				},
+				Status: v1alpha1.ImageStatus{
+					LatestBuildReason: "COMMIT",
+					Status: corev1alpha1.Status{
+						Conditions: []corev1alpha1.Condition{
+							{
+								Type:   corev1alpha1.ConditionReady,
+								Status: corev1.ConditionFalse,
+							},
+						},
+					},
+					LatestImage: "test-registry.io/test-image-2@sha256:abcdef123",
+				},
+			}
+
+			it("only lists the images matching all the filters", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						readyImage,
+						brokenImage,
+					},
+					Args: []string{"--filter", "ready=false", "--filter", "latest-reason=commit"},
+					ExpectedOutput: `NAME            READY    LATEST REASON    LATEST IMAGE
+test-image-2    False    COMMIT           test-registry.io/test-image-2@sha256:abcdef123
+
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("returns an error when no images match the filters", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						readyImage,
+					},
+					Args:           []string{"--filter", "ready=unknown"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: no images matched the filter\n",
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("returns an error listing the supported filters for an unknown filter", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						readyImage,
+					},
+					Args:           []string{"--filter", "healthy=true"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: invalid filter argument \"healthy=true\", supported filters are builder, clusterbuilder, latest-reason and ready\n",
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
+		when("the output is wide", func() {
+			image1 := &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-image-1",
+					Namespace: defaultNamespace,
+				},
+				Spec: v1alpha1.ImageSpec{
+					Builder: corev1.ObjectReference{
+						Kind: v1alpha1.ClusterBuilderKind,
+						Name: "some-builder",
+					},
+					Source: v1alpha1.SourceConfig{
+						Git: &v1alpha1.Git{
+							URL:      "https://github.com/some/repo",
+							Revision: "main",
+						},
+					},
+				},
+				Status: v1alpha1.ImageStatus{
+					LatestBuildReason: "COMMIT",
+					LatestBuildRef:    "test-image-1-build-1",
+					LatestStack:       "io.buildpacks.stacks.bionic",
+					LatestImage:       "test-registry.io/test-image-1@sha256:abcdef123",
+					Status: corev1alpha1.Status{
+						Conditions: []corev1alpha1.Condition{
+							{
+								Type:   corev1alpha1.ConditionReady,
+								Status: corev1.ConditionTrue,
+							},
+						},
+					},
+				},
+			}
+
+			image2 := &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-image-2",
+					Namespace: defaultNamespace,
+				},
+				Spec: v1alpha1.ImageSpec{
+					Builder: corev1.ObjectReference{
+						Kind: v1alpha1.BuilderKind,
+						Name: "some-other-builder",
+					},
+					Source: v1alpha1.SourceConfig{
+						Registry: &v1alpha1.Registry{
+							Image: "test-registry.io/some-source",
+						},
+					},
+				},
+			}
+
+			build1 := &v1alpha1.Build{
+				ObjectMeta: v1.ObjectMeta{
+					Name:              "test-image-1-build-1",
+					Namespace:         defaultNamespace,
+					CreationTimestamp: v1.NewTime(time.Now().Add(-30 * time.Minute)),
+				},
+			}
+
+			it("adds the builder, stack, latest build, source and last build age columns", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						image1,
+						image2,
+						build1,
+					},
+					Args: []string{"-o", "wide"},
+					ExpectedOutput: `NAME            READY      LATEST REASON    LATEST IMAGE                                      BUILDER                        STACK                          LATEST BUILD            SOURCE                               LAST BUILD AGE
+test-image-1    True       COMMIT           test-registry.io/test-image-1@sha256:abcdef123    ClusterBuilder/some-builder    io.buildpacks.stacks.bionic    test-image-1-build-1    https://github.com/some/repo@main    30m
+test-image-2    Unknown                                                                       Builder/some-other-builder                                                            test-registry.io/some-source         
+
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("prints long values in full", func() {
+				longImage := image1.DeepCopy()
+				longImage.Status.LatestImage = "test-registry.io/test-image-1@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						longImage,
+					},
+					Args: []string{"-o", "wide"},
+					ExpectedOutput: `NAME            READY    LATEST REASON    LATEST IMAGE                                                                                             BUILDER                        STACK                          LATEST BUILD            SOURCE                               LAST BUILD AGE
+test-image-1    True     COMMIT           test-registry.io/test-image-1@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef    ClusterBuilder/some-builder    io.buildpacks.stacks.bionic    test-image-1-build-1    https://github.com/some/repo@main    
+
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("prints long values in full by default", func() {
+				longImage := image1.DeepCopy()
+				longImage.Status.LatestImage = "test-registry.io/test-image-1@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						longImage,
+					},
+					ExpectedOutput: `NAME            READY    LATEST REASON    LATEST IMAGE
+test-image-1    True     COMMIT           test-registry.io/test-image-1@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef
+
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("truncates long values with the max-column-width flag", func() {
+				longImage := image1.DeepCopy()
+				longImage.Status.LatestImage = "test-registry.io/test-image-1@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						longImage,
+					},
+					Args: []string{"--max-column-width", "64"},
+					ExpectedOutput: `NAME            READY    LATEST REASON    LATEST IMAGE
+test-image-1    True     COMMIT           test-registry.io/test-image-1@sha256:0123456789abcdef01234567...
+
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("returns an error for a negative max-column-width", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						image1,
+					},
+					Args:           []string{"--max-column-width", "-1"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: --max-column-width must not be negative\n",
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+	})
+
+	when("a label selector is provided", func() {
+		it("passes the selector to the list call and only displays matching images", func() {
+			image1 := &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-image-1",
+					Namespace: defaultNamespace,
+					Labels:    map[string]string{"team": "a"},
+				},
+				Status: v1alpha1.ImageStatus{
+					LatestBuildReason: "COMMIT",
+					LatestImage:       "test-registry.io/test-image-1@sha256:abcdef123",
+				},
+			}
+			image2 := &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-image-2",
+					Namespace: defaultNamespace,
+					Labels:    map[string]string{"team": "b"},
+				},
+			}
+
+			var client *fake.Clientset
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					image1,
+					image2,
+				},
+				Args: []string{"-l", "team in (a,c)"},
+				ExpectedOutput: `NAME            READY      LATEST REASON    LATEST IMAGE
+test-image-1    Unknown    COMMIT           test-registry.io/test-image-1@sha256:abcdef123
+
+`,
+			}.TestKpack(t, func(clientSet *fake.Clientset) *cobra.Command {
+				client = clientSet
+				return cmdFunc(clientSet)
+			})
+
+			require.Len(t, client.Actions(), 1)
+			listAction := client.Actions()[0].(clientgotesting.ListAction)
+			require.Equal(t, "team in (a,c)", listAction.GetListRestrictions().Labels.String())
+		})
+
+		it("accepts the deprecated --label-selector flag", func() {
+			client := fake.NewSimpleClientset()
+			cmd := cmdFunc(client)
+			cmd.SetOut(ioutil.Discard)
+			cmd.SetErr(ioutil.Discard)
+			cmd.SetArgs([]string{"--label-selector", "team=a"})
+
+			require.EqualError(t, cmd.Execute(), "no images found")
+			require.Len(t, client.Actions(), 1)
+			listAction := client.Actions()[0].(clientgotesting.ListAction)
+			require.Equal(t, "team=a", listAction.GetListRestrictions().Labels.String())
+		})
+
+		it("rejects an invalid selector before listing the images", func() {
+			client := fake.NewSimpleClientset()
+			cmd := cmdFunc(client)
+			cmd.SetOut(ioutil.Discard)
+			cmd.SetErr(ioutil.Discard)
+			cmd.SetArgs([]string{"-l", "=a"})
+
+			err := cmd.Execute()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), `invalid label selector "=a"`)
+			require.Len(t, client.Actions(), 0)
+		})
+	})
+
+	when("the watch flag is used", func() {
+		makeImage := func(name string, ready corev1.ConditionStatus) *v1alpha1.Image {
+			return &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      name,
+					Namespace: defaultNamespace,
+				},
+				Status: v1alpha1.ImageStatus{
+					LatestBuildReason: "COMMIT",
+					Status: corev1alpha1.Status{
+						Conditions: []corev1alpha1.Condition{
+							{
+								Type:   corev1alpha1.ConditionReady,
+								Status: ready,
+							},
+						},
+					},
+					LatestImage: "test-registry.io/" + name + "@sha256:abcdef123",
+				},
+			}
+		}
+
+		it("re-renders the table for each event without duplicating rows", func() {
+			watcher := watch.NewRaceFreeFake()
+			watcher.Modify(makeImage("test-image-1", corev1.ConditionTrue))
+			watcher.Add(makeImage("test-image-3", corev1.ConditionTrue))
+			watcher.Delete(makeImage("test-image-2", corev1.ConditionUnknown))
+			watcher.Stop()
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					makeImage("test-image-1", corev1.ConditionFalse),
+					makeImage("test-image-2", corev1.ConditionUnknown),
+				},
+				Args: []string{"--watch"},
+				ExpectedOutput: "\033[H\033[2J" + `NAME            READY      LATEST REASON    LATEST IMAGE
+test-image-1    False      COMMIT           test-registry.io/test-image-1@sha256:abcdef123
+test-image-2    Unknown    COMMIT           test-registry.io/test-image-2@sha256:abcdef123
+
+` + "\033[H\033[2J" + `NAME            READY      LATEST REASON    LATEST IMAGE
+test-image-1    True       COMMIT           test-registry.io/test-image-1@sha256:abcdef123
+test-image-2    Unknown    COMMIT           test-registry.io/test-image-2@sha256:abcdef123
+
+` + "\033[H\033[2J" + `NAME            READY      LATEST REASON    LATEST IMAGE
+test-image-1    True       COMMIT           test-registry.io/test-image-1@sha256:abcdef123
+test-image-2    Unknown    COMMIT           test-registry.io/test-image-2@sha256:abcdef123
+test-image-3    True       COMMIT           test-registry.io/test-image-3@sha256:abcdef123
+
+` + "\033[H\033[2J" + `NAME            READY    LATEST REASON    LATEST IMAGE
+test-image-1    True     COMMIT           test-registry.io/test-image-1@sha256:abcdef123
+test-image-3    True     COMMIT           test-registry.io/test-image-3@sha256:abcdef123
+
+`,
+			}.TestKpack(t, func(clientSet *fake.Clientset) *cobra.Command {
+				clientSet.PrependWatchReactor("images", func(action clientgotesting.Action) (bool, watch.Interface, error) {
+					return true, watcher, nil
+				})
+				return cmdFunc(clientSet)
+			})
+		})
+
+		it("only lists the builds again when an image has a latest build that was not listed", func() {
+			withBuild := func(img *v1alpha1.Image, build string) *v1alpha1.Image {
+				img.Status.LatestBuildRef = build
+				return img
+			}
+			makeBuild := func(name string) *v1alpha1.Build {
+				return &v1alpha1.Build{
+					ObjectMeta: v1.ObjectMeta{
+						Name:      name,
+						Namespace: defaultNamespace,
+					},
+				}
+			}
+
+			watcher := watch.NewRaceFreeFake()
+			watcher.Modify(withBuild(makeImage("test-image-1", corev1.ConditionTrue), "test-image-1-build-1"))
+			watcher.Add(withBuild(makeImage("test-image-2", corev1.ConditionUnknown), "test-image-2-build-1"))
+			watcher.Modify(withBuild(makeImage("test-image-2", corev1.ConditionTrue), "test-image-2-build-1"))
+			watcher.Stop()
+
+			client := fake.NewSimpleClientset(
+				withBuild(makeImage("test-image-1", corev1.ConditionFalse), "test-image-1-build-1"),
+				makeBuild("test-image-1-build-1"),
+				makeBuild("test-image-2-build-1"),
+			)
+			client.PrependWatchReactor("images", func(action clientgotesting.Action) (bool, watch.Interface, error) {
+				return true, watcher, nil
+			})
+			buildLists := 0
+			client.PrependReactor("list", "builds", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+				buildLists++
+				return false, nil, nil
+			})
+
+			cmd := cmdFunc(client)
+			cmd.SetOut(ioutil.Discard)
+			cmd.SetErr(ioutil.Discard)
+			cmd.SetArgs([]string{"--watch", "--sort-by", "latest-build"})
+
+			require.NoError(t, cmd.Execute())
+			require.Equal(t, 2, buildLists)
+		})
+
+		it("errors when used with the output flag", func() {
+			testhelpers.CommandTest{
+				Args:           []string{"--watch", "-o", "yaml"},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: --watch cannot be used with --output\n",
+			}.TestKpack(t, cmdFunc)
+		})
 	})
 }
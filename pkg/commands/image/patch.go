@@ -6,24 +6,34 @@ package image
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/image"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
 )
 
-func NewPatchCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newImageWaiter func(k8s.ClientSet) ImageWaiter) *cobra.Command {
+func NewPatchCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newImageWaiter func(k8s.ClientSet) ImageWaiter, newRevisionResolver func(k8s.ClientSet) image.RevisionResolver) *cobra.Command {
 	var (
-		namespace string
-		subPath   string
-		factory   image.Factory
+		namespace     string
+		subPath       string
+		sourceSubPath string
+		factory       image.Factory
 		tlsCfg        registry.TLSConfig
+		resources     buildResourceFlags
+		limits        buildHistoryLimitFlags
+		quiet         bool
+		logs          bool
+		force         bool
+		revert        bool
 	)
 
 	cmd := &cobra.Command{
@@ -40,26 +50,75 @@ The flags for this command determine how the build will retrieve source code:
   "--blob" to use source code hosted in a blob store
   "--local-path" to use source code from the local machine
 
+Use "--blob-auth-secret" with a blob source to download the blob with the credentials of a "kubernetes.io/basic-auth" secret.
+The blob is checked to be readable with the credentials and the secret is added to the image service account.
+
+Use "--source-revision" and "--source-sub-path" to update the revision or sub path of an existing Git based source,
+for example to pick up a hotfix commit. These flags fail if the image source is not Git based.
+
+Use "--revert" to patch the source back to the source of the last successful build, for example the commit it was
+built from, when a newer revision does not build. The builds of the image are listed to find the last successful build.
+
+Use "--pin-revision" with Git based source to replace a branch or tag with the commit it currently points to.
+The commit is resolved with git ls-remote using the git secrets of the image service account.
+
+Use "--builder" or "--cluster-builder" to move the image to another builder, for example from a namespaced
+Builder to a ClusterBuilder. The builder is checked to exist unless "--force" or "--dry-run" is provided.
+
 Local source code will be pushed to the same registry as the existing image tag.
 Therefore, you must have credentials to access the registry on your machine.
 
+If a "project.toml" project descriptor exists at the root of the local path, or one is provided
+with the "--project-descriptor" flag, its "build.env" entries are added to the build environment
+and its "build.include" or "build.exclude" patterns are used to filter the uploaded source code.
+Values provided with the "--env" and "--env-file" flags take precedence over the descriptor.
+
+Files may be excluded from the uploaded local source code with ".gitignore" and ".kpignore" files at the root
+of the local path or with the "--exclude" or "--local-path-exclude" flags. All use gitignore syntax, including "**"
+and "!" negation, and ".kpignore" patterns take precedence over ".gitignore" patterns.
+Use "--verbose" to list the excluded files, for example with "--dry-run" to check the patterns.
+
 Environment variables may be provided by using the "--env" flag.
 For each environment variable, supply the "--env" flag followed by the key value pair.
 For example, "--env key1=value1 --env key2=value2 ...".
 
+Environment variables may also be loaded from a file by using the "--env-file" flag.
+The file must contain one "key=value" pair per line. Blank lines and lines starting with "#" are ignored.
+Use "--env-file -" to read the file from stdin.
+Values provided with the "--env" flag take precedence over values from the file.
+
+Service bindings may be provided by using the "--service-binding" flag.
+For each binding, supply the "--service-binding" flag followed by a name. The name must refer to
+a secret and a metadata config map of the same name in the image namespace.
+Use "--service-binding name=secret-name" to bind a secret with a different name than the binding.
+The binding secret is checked to exist before the image is submitted, with "--dry-run" a missing secret is a warning.
+Existing service bindings may be removed by using the "--delete-service-binding" flag.
+
 Existing environment variables may be deleted by using the "--delete-env" flag.
 For each environment variable, supply the "--delete-env" flag followed by the variable name.
 For example, "--delete-env key1 --delete-env key2 ...".
+Deleting a variable that does not exist prints a warning. A variable cannot be both set and deleted.
 
 The --cache-size flag can only be used to increase the size of the existing cache.
+The cache size is a kubernetes quantity such as "4Gi" and is validated before the image is patched.
+Use "--cache-size 0" to remove the cache size from the image and use the cluster default.
+
+Build pod resources may be set by using the "--build-request-cpu", "--build-request-memory",
+"--build-limit-cpu" and "--build-limit-memory" flags with kubernetes quantities.
+Existing values are kept unless replaced. Pass an empty string to remove a value, for example "--build-limit-cpu ''".
 `,
 		Example: `kp image patch my-image --git-revision my-other-branch
+kp image patch my-image --source-revision 1a2b3c4 --source-sub-path services/api
 kp image patch my-image --blob https://my-blob-host.com/my-blob
 kp image patch my-image --local-path /path/to/local/source/code
 kp image patch my-image --local-path /path/to/local/source/code --builder my-builder
-kp image patch my-image --env foo=bar --env color=red --delete-env apple --delete-env potato`,
-		Args:         commands.ExactArgsWithUsage(1),
-		SilenceUsage: true,
+kp image patch my-image --cluster-builder my-cluster-builder
+kp image patch my-image --env foo=bar --env color=red --delete-env apple --delete-env potato
+kp image patch my-image --cache-size 4Gi
+kp image patch my-image --revert`,
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ImageNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet(namespace)
 			if err != nil {
@@ -73,63 +132,166 @@ kp image patch my-image --env foo=bar --env color=red --delete-env apple --delet
 
 			ctx := cmd.Context()
 
-			img, err := cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).Get(ctx, args[0], metav1.GetOptions{})
-			if err != nil {
+			factory.SourceUploader = rup.SourceUploader(uploadWriter(ch, quiet), tlsCfg, ch.CanChangeState())
+			factory.Printer = ch
+			factory.RevisionResolver = newRevisionResolver(cs)
+			factory.Stdin = cmd.InOrStdin()
+			factory.BuildResources = resources.buildResources(cmd)
+			limits.apply(cmd, &factory)
+
+			if err := validateBuilderRef(ctx, cs, ch, force, factory.Builder, factory.ClusterBuilder); err != nil {
 				return err
 			}
 
-			factory.SourceUploader = rup.SourceUploader(ch.Writer(), tlsCfg, ch.CanChangeState())
-			factory.Printer = ch
-
 			if cmd.Flag("sub-path").Changed {
 				factory.SubPath = &subPath
 			}
 
-			patched, img, err := patch(ctx, img, &factory, ch, cs)
+			if cmd.Flag("source-sub-path").Changed {
+				factory.SourceSubPath = &sourceSubPath
+			}
+
+			img, err := cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).Get(ctx, args[0], metav1.GetOptions{})
 			if err != nil {
 				return err
 			}
 
-			if patched && ch.ShouldWait() {
-				_, err = newImageWaiter(cs).Wait(cmd.Context(), cmd.OutOrStdout(), img)
+			if revert {
+				factory.RevertSource, err = lastSuccessfulSource(ctx, cs, img)
 				if err != nil {
 					return err
 				}
 			}
 
+			patched, img, err := patch(ctx, img, &factory, ch, cs, false)
+			if err != nil {
+				return err
+			}
+
+			if patched && ch.ShouldWait() {
+				if err := waitForImage(ctx, buildLogsWriter(cmd, logs), ch, cs, newImageWaiter(cs), img); err != nil {
+					return err
+				}
+			}
+
 			return nil
 		},
 	}
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
 	cmd.Flags().StringVar(&factory.GitRepo, "git", "", "git repository url")
 	cmd.Flags().StringVar(&factory.GitRevision, "git-revision", "", "git revision such as commit, tag, or branch (default \"main\")")
+	cmd.Flags().BoolVar(&factory.PinRevision, "pin-revision", false, "resolve the git revision to the commit it currently points to")
 	cmd.Flags().StringVar(&factory.Blob, "blob", "", "source code blob url")
+	cmd.Flags().StringVar(&factory.BlobAuthSecret, "blob-auth-secret", "", "name of a basic-auth secret used to download the source code blob")
 	cmd.Flags().StringVar(&factory.LocalPath, "local-path", "", "path to local source code")
+	cmd.Flags().StringVar(&factory.ProjectDescriptor, "project-descriptor", "", "path to a project.toml descriptor for local source code (default \"<local-path>/project.toml\" if present)")
+	setExcludeFlags(cmd, &factory.Exclude)
+	cmd.Flags().BoolVarP(&factory.Verbose, "verbose", "v", false, "list the local source files excluded from the upload")
+	setQuietFlag(cmd, &quiet)
 	cmd.Flags().StringVar(&subPath, "sub-path", "", "build code at the sub path located within the source code directory")
+	cmd.Flags().StringVar(&factory.SourceRevision, "source-revision", "", "git revision of the existing git source such as commit, tag, or branch")
+	cmd.Flags().StringVar(&sourceSubPath, "source-sub-path", "", "sub path within the existing git source to build")
+	cmd.Flags().BoolVar(&revert, "revert", false, "patch the source back to the source of the last successful build")
 	cmd.Flags().StringVar(&factory.Builder, "builder", "", "builder name")
 	cmd.Flags().StringVar(&factory.ClusterBuilder, "cluster-builder", "", "cluster builder name")
+	cmd.Flags().BoolVar(&force, "force", false, "skip checking that the builder or cluster builder exists")
 	cmd.Flags().StringArrayVarP(&factory.Env, "env", "e", []string{}, "build time environment variables to add/replace")
+	cmd.Flags().StringVar(&factory.EnvFile, "env-file", "", "path to a file of build time environment variables, or \"-\" to read from stdin")
 	cmd.Flags().StringArrayVarP(&factory.DeleteEnv, "delete-env", "d", []string{}, "build time environment variables to remove")
-	cmd.Flags().StringVar(&factory.CacheSize, "cache-size", "", "cache size as a kubernetes quantity")
+	cmd.Flags().StringArrayVar(&factory.Bindings, "service-binding", []string{}, "name of a service binding secret and metadata config map to add/replace (format: name or name=secret-name)")
+	cmd.Flags().StringArrayVar(&factory.DeleteBindings, "delete-service-binding", []string{}, "name of a service binding to remove")
+	cmd.Flags().StringVar(&factory.CacheSize, "cache-size", "", "cache size as a kubernetes quantity, 0 removes the cache size")
+	cmd.Flags().BoolVar(&factory.AllowCacheShrink, "allow-cache-shrink", false, "allow the cache size to be decreased")
+	setBuildResourceFlags(cmd, &resources)
+	setBuildHistoryLimitFlags(cmd, &limits)
+	commands.SetMetadataPatchFlags(cmd, &factory.Metadata)
 	cmd.Flags().BoolP("wait", "w", false, "wait for image patch to be reconciled and tail resulting build logs")
+	setLogsFlag(cmd, &logs)
+	commands.SetWaitTimeoutFlag(cmd)
 	commands.SetImgUploadDryRunOutputFlags(cmd)
+	commands.SetServerDryRunFlag(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
 	return cmd
 }
 
-func patch(ctx context.Context, img *v1alpha1.Image, factory *image.Factory, ch *commands.CommandHelper, cs k8s.ClientSet) (bool, *v1alpha1.Image, error) {
+// lastSuccessfulSource returns the source that the last successful build of the image was built from,
+// the git revision of the build is the commit that the image revision resolved to at the time
+func lastSuccessfulSource(ctx context.Context, cs k8s.ClientSet, img *v1alpha1.Image) (*v1alpha1.SourceConfig, error) {
+	buildList, err := cs.KpackClient.KpackV1alpha1().Builds(img.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: v1alpha1.ImageLabel + "=" + img.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(buildList.Items, build.Sort(buildList.Items))
+
+	successfulBuild := getLastSuccessfulBuild(buildList.Items)
+	if successfulBuild == nil {
+		return nil, errors.Errorf("image %q has no successful build to revert to", img.Name)
+	}
+	return &successfulBuild.Spec.Source, nil
+}
+
+// patch submits the patch with the resource version of img. On a conflict the image is read again
+// and the patch is computed again, the local source uploaded by the first attempt is reused and
+// the blob auth secret is only set up once.
+func patch(ctx context.Context, img *v1alpha1.Image, factory *image.Factory, ch *commands.CommandHelper, cs k8s.ClientSet, reportUnchanged bool) (bool, *v1alpha1.Image, error) {
 	if err := ch.PrintStatus("Patching Image..."); err != nil {
 		return false, nil, err
 	}
 
+	if err := validateServiceBindings(ctx, cs, ch, factory.Bindings); err != nil {
+		return false, nil, err
+	}
+
 	patchedImage, patch, err := factory.MakePatch(img)
 	if err != nil {
 		return false, nil, err
 	}
 
+	if err := setupBlobAuth(ctx, factory.BlobAuthSecret, patchedImage, ch, cs); err != nil {
+		return false, nil, err
+	}
+
+	var (
+		patched bool
+		retry   bool
+		current = img
+	)
+	err = commands.RetryOnConflict(func() error {
+		var err error
+		if retry {
+			current, err = cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).Get(ctx, img.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			patchedImage, patch, err = factory.MakePatch(current)
+			if err != nil {
+				return err
+			}
+		}
+		retry = true
+
+		conditionalPatch, err := k8s.AddResourceVersion(patch, current)
+		if err != nil {
+			return err
+		}
+
+		patched, current, err = submitPatch(ctx, current, patchedImage, conditionalPatch, ch, cs, reportUnchanged)
+		return err
+	})
+	return patched, current, err
+}
+
+// submitPatch skips the api call when the patch is empty, reportUnchanged is used by
+// save so that repeated runs with the same configuration report the image as unchanged
+func submitPatch(ctx context.Context, img, patchedImage *v1alpha1.Image, patch []byte, ch *commands.CommandHelper, cs k8s.ClientSet, reportUnchanged bool) (bool, *v1alpha1.Image, error) {
+	var err error
 	hasPatch := len(patch) > 0
-	if hasPatch && !ch.IsDryRun() {
-		patchedImage, err = cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).Patch(ctx, img.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if hasPatch && ch.ShouldSubmit() {
+		patchedImage, err = cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).Patch(ctx, img.Name, types.MergePatchType, patch, ch.PatchOptions())
 		if err != nil {
 			return hasPatch, nil, err
 		}
@@ -139,5 +301,9 @@ func patch(ctx context.Context, img *v1alpha1.Image, factory *image.Factory, ch
 		return hasPatch, nil, err
 	}
 
+	if !hasPatch && reportUnchanged && !ch.IsDryRun() {
+		return hasPatch, patchedImage, ch.PrintResult("Image %q unchanged", img.Name)
+	}
+
 	return hasPatch, patchedImage, ch.PrintChangeResult(hasPatch, fmt.Sprintf("Image %q patched", img.Name))
 }
@@ -4,19 +4,33 @@
 package image_test
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
 	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
 	"github.com/sclevine/spec"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgotesting "k8s.io/client-go/testing"
 
 	cmdFakes "github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
 	imgcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/image"
+	"github.com/vmware-tanzu/kpack-cli/pkg/image"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 	registryfakes "github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
@@ -29,13 +43,28 @@ func TestImagePatchCommand(t *testing.T) {
 func testImagePatchCommand(t *testing.T, when spec.G, it spec.S) {
 	const defaultNamespace = "some-default-namespace"
 
+	someBuilder := &v1alpha1.Builder{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "some-builder",
+			Namespace: defaultNamespace,
+		},
+	}
+	someClusterBuilder := &v1alpha1.ClusterBuilder{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "some-builder",
+		},
+	}
+
 	registryUtilProvider := registryfakes.UtilProvider{}
 	fakeImageWaiter := &cmdFakes.FakeImageWaiter{}
+	fakeRevisionResolver := &cmdFakes.FakeRevisionResolver{}
 
 	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
 		clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
 		return imgcmds.NewPatchCommand(clientSetProvider, registryUtilProvider, func(set k8s.ClientSet) imgcmds.ImageWaiter {
 			return fakeImageWaiter
+		}, func(set k8s.ClientSet) image.RevisionResolver {
+			return fakeRevisionResolver
 		})
 	}
 
@@ -82,197 +111,839 @@ func testImagePatchCommand(t *testing.T, when spec.G, it spec.S) {
 					"some-image",
 				},
 				ExpectedOutput: `Patching Image...
-Image "some-image" patched (no change)
+Image "some-image" patched (no change)
+`,
+			}.TestKpack(t, cmdFunc)
+			assert.Len(t, fakeImageWaiter.Calls, 0)
+		})
+	})
+
+	when("patching source", func() {
+		when("patching the sub path", func() {
+			it("can patch it with an empty string", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						existingImage,
+					},
+					Args: []string{
+						"some-image",
+						"--sub-path", "",
+					},
+					ExpectedOutput: `Patching Image...
+Image "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"source":{"subPath":null}}}`,
+					},
+				}.TestKpack(t, cmdFunc)
+				assert.Len(t, fakeImageWaiter.Calls, 0)
+			})
+
+			it("warns when the image does not have a source", func() {
+				existingImage.Spec.Source = v1alpha1.SourceConfig{}
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						existingImage,
+					},
+					Args: []string{
+						"some-image",
+						"--sub-path", "app/backend",
+					},
+					ExpectedOutput: `Patching Image...
+Warning: sub-path 'app/backend' has no effect as the image does not have a git, blob, or local-path source
+Image "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"source":{"subPath":"app/backend"}}}`,
+					},
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("can patch it with a non-empty string", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						existingImage,
+					},
+					Args: []string{
+						"some-image",
+						"--sub-path", "a-new-path",
+					},
+					ExpectedOutput: `Patching Image...
+Image "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"source":{"subPath":"a-new-path"}}}`,
+					},
+				}.TestKpack(t, cmdFunc)
+				assert.Len(t, fakeImageWaiter.Calls, 0)
+			})
+		})
+
+		it("can change source types", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+				},
+				Args: []string{
+					"some-image",
+					"--blob", "some-blob",
+				},
+				ExpectedOutput: `Patching Image...
+Image "some-image" patched
+`,
+				ExpectPatches: []string{
+					`{"spec":{"source":{"blob":{"url":"some-blob"},"git":null}}}`,
+				},
+			}.TestKpack(t, cmdFunc)
+			assert.Len(t, fakeImageWaiter.Calls, 0)
+		})
+
+		it("can change git revision if existing source is git", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+				},
+				Args: []string{
+					"some-image",
+					"--git-revision", "some-new-revision",
+				},
+				ExpectedOutput: `Patching Image...
+Image "some-image" patched
+`,
+				ExpectPatches: []string{
+					`{"spec":{"source":{"git":{"revision":"some-new-revision"}}}}`,
+				},
+			}.TestKpack(t, cmdFunc)
+			assert.Len(t, fakeImageWaiter.Calls, 0)
+		})
+
+		it("git revision defaults to main if not provided with git", func() {
+			existingImage.Spec.Source = v1alpha1.SourceConfig{
+				Blob: &v1alpha1.Blob{
+					URL: "some-blob",
+				},
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+				},
+				Args: []string{
+					"some-image",
+					"--git", "some-new-git-url",
+				},
+				ExpectedOutput: `Patching Image...
+Image "some-image" patched
+`,
+				ExpectPatches: []string{
+					`{"spec":{"source":{"blob":null,"git":{"revision":"main","url":"some-new-git-url"}}}}`,
+				},
+			}.TestKpack(t, cmdFunc)
+			assert.Len(t, fakeImageWaiter.Calls, 0)
+		})
+
+		when("switching between source types", func() {
+			registrySource := v1alpha1.SourceConfig{
+				Registry: &v1alpha1.Registry{
+					Image: "some-registry.io/some-source:some-id",
+				},
+			}
+
+			it("can change from git to local path", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						existingImage,
+					},
+					Args: []string{
+						"some-image",
+						"--local-path", "some-local-path",
+					},
+					ExpectedOutput: `Patching Image...
+	Uploading 'index.docker.io/library/some-tag-source:source-id'
+Image "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"source":{"git":null,"registry":{"image":"index.docker.io/library/some-tag-source:source-id"}}}}`,
+					},
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("can change from blob to local path", func() {
+				existingImage.Spec.Source = v1alpha1.SourceConfig{
+					Blob: &v1alpha1.Blob{
+						URL: "some-blob",
+					},
+				}
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						existingImage,
+					},
+					Args: []string{
+						"some-image",
+						"--local-path", "some-local-path",
+					},
+					ExpectedOutput: `Patching Image...
+	Uploading 'index.docker.io/library/some-tag-source:source-id'
+Image "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"source":{"blob":null,"registry":{"image":"index.docker.io/library/some-tag-source:source-id"}}}}`,
+					},
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("can change from local path to git", func() {
+				existingImage.Spec.Source = registrySource
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						existingImage,
+					},
+					Args: []string{
+						"some-image",
+						"--git", "some-new-git-url",
+						"--git-revision", "some-revision",
+					},
+					ExpectedOutput: `Patching Image...
+Image "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"source":{"git":{"revision":"some-revision","url":"some-new-git-url"},"registry":null}}}`,
+					},
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("can change from local path to blob", func() {
+				existingImage.Spec.Source = registrySource
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						existingImage,
+					},
+					Args: []string{
+						"some-image",
+						"--blob", "some-blob",
+					},
+					ExpectedOutput: `Patching Image...
+Image "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"source":{"blob":{"url":"some-blob"},"registry":null}}}`,
+					},
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("returns an error naming the conflicting source flags", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						existingImage,
+					},
+					Args: []string{
+						"some-image",
+						"--git", "some-new-git-url",
+						"--local-path", "some-local-path",
+					},
+					ExpectErr: true,
+					ExpectedOutput: `Patching Image...
+Error: conflicting source flags --git, --local-path: image source must be one of git, blob, or local-path
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
+		when("patching the source revision and sub path", func() {
+			it("updates the existing git source", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						existingImage,
+					},
+					Args: []string{
+						"some-image",
+						"--source-revision", "some-hotfix-commit",
+						"--source-sub-path", "some-other-path",
+					},
+					ExpectedOutput: `Patching Image...
+Image "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"source":{"git":{"revision":"some-hotfix-commit"},"subPath":"some-other-path"}}}`,
+					},
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("does not patch the image with dry-run", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						existingImage,
+					},
+					Args: []string{
+						"some-image",
+						"--source-revision", "some-hotfix-commit",
+						"--dry-run",
+					},
+					ExpectedOutput: `Patching Image... (dry run)
+Image "some-image" patched (dry run)
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("returns an error when the existing source is not git", func() {
+				existingImage.Spec.Source = v1alpha1.SourceConfig{
+					Blob: &v1alpha1.Blob{
+						URL: "some-blob",
+					},
+				}
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						existingImage,
+					},
+					Args: []string{
+						"some-image",
+						"--source-revision", "some-hotfix-commit",
+					},
+					ExpectErr: true,
+					ExpectedOutput: `Patching Image...
+Error: source-revision can only be used with an existing git source, image source is blob
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+	})
+
+	when("the pin-revision flag is used", func() {
+		it("patches the git revision with its current commit", func() {
+			fakeRevisionResolver.Commits = map[string]string{"some-git-url@some-new-revision": "0123456789abcdef0123456789abcdef01234567"}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+				},
+				Args: []string{
+					"some-image",
+					"--git-revision", "some-new-revision",
+					"--pin-revision",
+				},
+				ExpectedOutput: `Patching Image...
+Image "some-image" patched
+`,
+				ExpectPatches: []string{
+					`{"spec":{"source":{"git":{"revision":"0123456789abcdef0123456789abcdef01234567"}}}}`,
+				},
+			}.TestKpack(t, cmdFunc)
+
+			assert.Equal(t, []cmdFakes.ResolveCall{{
+				Namespace:      defaultNamespace,
+				ServiceAccount: "default",
+				URL:            "some-git-url",
+				Revision:       "some-new-revision",
+			}}, fakeRevisionResolver.Calls)
+		})
+	})
+
+	when("patching the builder", func() {
+		it("can patch the builder", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+					someBuilder,
+				},
+				Args: []string{
+					"some-image",
+					"--builder", "some-builder",
+				},
+				ExpectedOutput: `Patching Image...
+Image "some-image" patched
+`,
+				ExpectPatches: []string{
+					`{"spec":{"builder":{"kind":"Builder","name":"some-builder","namespace":"some-default-namespace"}}}`,
+				},
+			}.TestKpack(t, cmdFunc)
+			assert.Len(t, fakeImageWaiter.Calls, 0)
+		})
+
+		it("switches the kind when a namespaced builder is replaced by a cluster builder", func() {
+			img := existingImage.DeepCopy()
+			img.Spec.Builder = corev1.ObjectReference{
+				Kind:      v1alpha1.BuilderKind,
+				Namespace: defaultNamespace,
+				Name:      "some-builder",
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					img,
+					someClusterBuilder,
+				},
+				Args: []string{
+					"some-image",
+					"--cluster-builder", "some-builder",
+				},
+				ExpectedOutput: `Patching Image...
+Image "some-image" patched
+`,
+				ExpectPatches: []string{
+					`{"spec":{"builder":{"kind":"ClusterBuilder","namespace":null}}}`,
+				},
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("prints the patched builder with dry-run and output", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+				},
+				Args: []string{
+					"some-image",
+					"--builder", "some-builder",
+					"--dry-run",
+					"--output", "jsonpath={.spec.builder.kind}/{.spec.builder.namespace}/{.spec.builder.name}",
+				},
+				ExpectedOutput: "Builder/some-default-namespace/some-builder\n",
+				ExpectedErrorOutput: `Patching Image... (dry run)
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("returns an error when both builder flags are provided", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+					someBuilder,
+					someClusterBuilder,
+				},
+				Args: []string{
+					"some-image",
+					"--builder", "some-builder",
+					"--cluster-builder", "some-builder",
+				},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: must provide one of builder or cluster-builder\n",
+			}.TestKpack(t, cmdFunc)
+		})
+	})
+
+	when("the image is modified after it is read", func() {
+		// modifiedAfterGet returns stale for the first get of the image and rejects patches whose
+		// resource version is not the version of the stored image, like the api server does
+		modifiedAfterGet := func(stale *v1alpha1.Image) func(clientSet *fake.Clientset) *cobra.Command {
+			return func(clientSet *fake.Clientset) *cobra.Command {
+				clientSet.PrependReactor("get", "images", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+					if stale == nil {
+						return false, nil, nil
+					}
+					img := stale
+					stale = nil
+					return true, img, nil
+				})
+				clientSet.PrependReactor("patch", "images", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+					patchAction := action.(clientgotesting.PatchAction)
+					stored, err := clientSet.Tracker().Get(action.GetResource(), action.GetNamespace(), patchAction.GetName())
+					if err != nil {
+						return true, nil, err
+					}
+
+					var patch struct {
+						Metadata metav1.ObjectMeta `json:"metadata"`
+					}
+					if err := json.Unmarshal(patchAction.GetPatch(), &patch); err != nil {
+						return true, nil, err
+					}
+
+					if patch.Metadata.ResourceVersion != stored.(*v1alpha1.Image).ResourceVersion {
+						return true, nil, k8serrors.NewConflict(schema.GroupResource{Group: "kpack.io", Resource: "images"}, patchAction.GetName(), errors.New("the object has been modified"))
+					}
+					return false, nil, nil
+				})
+				return cmdFunc(clientSet)
+			}
+		}
+
+		var stale, modified *v1alpha1.Image
+
+		it.Before(func() {
+			stale = existingImage.DeepCopy()
+			stale.ResourceVersion = "1"
+
+			modified = existingImage.DeepCopy()
+			modified.ResourceVersion = "2"
+			modified.Spec.Build.Env = append(modified.Spec.Build.Env, corev1.EnvVar{Name: "key3", Value: "value3"})
+		})
+
+		it("gets the image again and recomputes the patch on the conflict", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					modified,
+				},
+				Args: []string{
+					"some-image",
+					"--env", "key4=value4",
+				},
+				ExpectedOutput: `Patching Image...
+Image "some-image" patched
+`,
+				ExpectPatches: []string{
+					`{"metadata":{"resourceVersion":"1"},"spec":{"build":{"env":[{"name":"key1","value":"value1"},{"name":"key2","value":"value2"},{"name":"key4","value":"value4"}]}}}`,
+					`{"metadata":{"resourceVersion":"2"},"spec":{"build":{"env":[{"name":"key1","value":"value1"},{"name":"key2","value":"value2"},{"name":"key3","value":"value3"},{"name":"key4","value":"value4"}]}}}`,
+				},
+			}.TestKpack(t, modifiedAfterGet(stale))
+		})
+
+		it("does not upload the local source again", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					modified,
+				},
+				Args: []string{
+					"some-image",
+					"--local-path", "some-local-path",
+				},
+				ExpectedOutput: `Patching Image...
+	Uploading 'index.docker.io/library/some-tag-source:source-id'
+Image "some-image" patched
+`,
+				ExpectPatches: []string{
+					`{"metadata":{"resourceVersion":"1"},"spec":{"source":{"git":null,"registry":{"image":"index.docker.io/library/some-tag-source:source-id"}}}}`,
+					`{"metadata":{"resourceVersion":"2"},"spec":{"source":{"git":null,"registry":{"image":"index.docker.io/library/some-tag-source:source-id"}}}}`,
+				},
+			}.TestKpack(t, modifiedAfterGet(stale))
+		})
+
+		it("returns the conflict once the retries are exhausted", func() {
+			patches := 0
+			alwaysConflict := func(clientSet *fake.Clientset) *cobra.Command {
+				clientSet.PrependReactor("patch", "images", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+					patches++
+					return true, nil, k8serrors.NewConflict(schema.GroupResource{Group: "kpack.io", Resource: "images"}, "some-image", errors.New("the object has been modified"))
+				})
+				return cmdFunc(clientSet)
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					modified,
+				},
+				Args: []string{
+					"some-image",
+					"--git-revision", "some-new-revision",
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Patching Image...
+Error: Operation cannot be fulfilled on images.kpack.io "some-image": the object has been modified
+`,
+				ExpectPatches: []string{
+					`{"metadata":{"resourceVersion":"2"},"spec":{"source":{"git":{"revision":"some-new-revision"}}}}`,
+				},
+			}.TestKpack(t, alwaysConflict)
+			require.Equal(t, 5, patches)
+		})
+	})
+
+	when("the builder does not exist", func() {
+		it("returns an error listing the available builders", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+					someBuilder,
+				},
+				Args: []string{
+					"some-image",
+					"--builder", "nonexistent",
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Error: builder 'nonexistent' not found in namespace 'some-default-namespace'; available: [some-builder]
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("returns an error listing the available cluster builders", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+					someClusterBuilder,
+				},
+				Args: []string{
+					"some-image",
+					"--cluster-builder", "nonexistent",
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Error: clusterbuilder 'nonexistent' not found; available: [some-builder]
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("patches the builder with the force flag", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+				},
+				Args: []string{
+					"some-image",
+					"--cluster-builder", "nonexistent",
+					"--force",
+				},
+				ExpectedOutput: `Patching Image...
+Image "some-image" patched
+`,
+				ExpectPatches: []string{
+					`{"spec":{"builder":{"name":"nonexistent"}}}`,
+				},
+			}.TestKpack(t, cmdFunc)
+		})
+	})
+
+	when("patching env vars", func() {
+		it("can delete env vars", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+				},
+				Args: []string{
+					"some-image",
+					"-d", "key2",
+				},
+				ExpectedOutput: `Patching Image...
+Image "some-image" patched
 `,
+				ExpectPatches: []string{
+					`{"spec":{"build":{"env":[{"name":"key1","value":"value1"}]}}}`,
+				},
 			}.TestKpack(t, cmdFunc)
 			assert.Len(t, fakeImageWaiter.Calls, 0)
 		})
-	})
 
-	when("patching source", func() {
-		when("patching the sub path", func() {
-			it("can patch it with an empty string", func() {
-				testhelpers.CommandTest{
-					Objects: []runtime.Object{
-						existingImage,
-					},
-					Args: []string{
-						"some-image",
-						"--sub-path", "",
-					},
-					ExpectedOutput: `Patching Image...
+		it("warns when deleting an env var that does not exist", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+				},
+				Args: []string{
+					"some-image",
+					"-d", "key3",
+					"-d", "key2",
+				},
+				ExpectedOutput: `Patching Image...
+Warning: delete-env parameter 'key3' not found in existing image configuration
 Image "some-image" patched
 `,
-					ExpectPatches: []string{
-						`{"spec":{"source":{"subPath":null}}}`,
-					},
-				}.TestKpack(t, cmdFunc)
-				assert.Len(t, fakeImageWaiter.Calls, 0)
-			})
+				ExpectPatches: []string{
+					`{"spec":{"build":{"env":[{"name":"key1","value":"value1"}]}}}`,
+				},
+			}.TestKpack(t, cmdFunc)
+		})
 
-			it("can patch it with a non-empty string", func() {
-				testhelpers.CommandTest{
-					Objects: []runtime.Object{
-						existingImage,
-					},
-					Args: []string{
-						"some-image",
-						"--sub-path", "a-new-path",
-					},
-					ExpectedOutput: `Patching Image...
-Image "some-image" patched
+		it("errors when the same env var is set and deleted", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+				},
+				Args: []string{
+					"some-image",
+					"-e", "key1=some-value",
+					"-d", "key1",
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Patching Image...
+Error: duplicate delete-env and env-var parameter 'key1'
 `,
-					ExpectPatches: []string{
-						`{"spec":{"source":{"subPath":"a-new-path"}}}`,
-					},
-				}.TestKpack(t, cmdFunc)
-				assert.Len(t, fakeImageWaiter.Calls, 0)
-			})
+			}.TestKpack(t, cmdFunc)
 		})
 
-		it("can change source types", func() {
+		it("can update existing env vars", func() {
 			testhelpers.CommandTest{
 				Objects: []runtime.Object{
 					existingImage,
 				},
 				Args: []string{
 					"some-image",
-					"--blob", "some-blob",
+					"-e", "key1=some-other-value",
 				},
 				ExpectedOutput: `Patching Image...
 Image "some-image" patched
 `,
 				ExpectPatches: []string{
-					`{"spec":{"source":{"blob":{"url":"some-blob"},"git":null}}}`,
+					`{"spec":{"build":{"env":[{"name":"key1","value":"some-other-value"},{"name":"key2","value":"value2"}]}}}`,
 				},
 			}.TestKpack(t, cmdFunc)
 			assert.Len(t, fakeImageWaiter.Calls, 0)
 		})
 
-		it("can change git revision if existing source is git", func() {
+		it("can add new env vars", func() {
 			testhelpers.CommandTest{
 				Objects: []runtime.Object{
 					existingImage,
 				},
 				Args: []string{
 					"some-image",
-					"--git-revision", "some-new-revision",
+					"-e", "key3=value3",
 				},
 				ExpectedOutput: `Patching Image...
 Image "some-image" patched
 `,
 				ExpectPatches: []string{
-					`{"spec":{"source":{"git":{"revision":"some-new-revision"}}}}`,
+					`{"spec":{"build":{"env":[{"name":"key1","value":"value1"},{"name":"key2","value":"value2"},{"name":"key3","value":"value3"}]}}}`,
 				},
 			}.TestKpack(t, cmdFunc)
+
 			assert.Len(t, fakeImageWaiter.Calls, 0)
 		})
 
-		it("git revision defaults to main if not provided with git", func() {
-			existingImage.Spec.Source = v1alpha1.SourceConfig{
-				Blob: &v1alpha1.Blob{
-					URL: "some-blob",
-				},
-			}
-
+		it("sets a variable provided twice only once", func() {
 			testhelpers.CommandTest{
 				Objects: []runtime.Object{
 					existingImage,
 				},
 				Args: []string{
 					"some-image",
-					"--git", "some-new-git-url",
+					"-e", "key3=value3",
+					"-e", "key3=value3",
 				},
 				ExpectedOutput: `Patching Image...
 Image "some-image" patched
 `,
 				ExpectPatches: []string{
-					`{"spec":{"source":{"blob":null,"git":{"revision":"main","url":"some-new-git-url"}}}}`,
+					`{"spec":{"build":{"env":[{"name":"key1","value":"value1"},{"name":"key2","value":"value2"},{"name":"key3","value":"value3"}]}}}`,
 				},
 			}.TestKpack(t, cmdFunc)
-			assert.Len(t, fakeImageWaiter.Calls, 0)
 		})
 
-	})
+		it("does not patch when an existing variable is set to the same value", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+				},
+				Args: []string{
+					"some-image",
+					"-e", "key1=value1",
+				},
+				ExpectedOutput: `Patching Image...
+Image "some-image" patched (no change)
+`,
+			}.TestKpack(t, cmdFunc)
+			assert.Len(t, fakeImageWaiter.Calls, 0)
+		})
 
-	when("patching the builder", func() {
-		it("can patch the builder", func() {
+		it("can add env vars from stdin with env vars from flags taking precedence", func() {
 			testhelpers.CommandTest{
 				Objects: []runtime.Object{
 					existingImage,
 				},
+				StdIn: "# some comment\nkey1=from-file\nkey3=value3\n",
 				Args: []string{
 					"some-image",
-					"--builder", "some-builder",
+					"--env-file", "-",
+					"-e", "key3=from-flag",
 				},
 				ExpectedOutput: `Patching Image...
 Image "some-image" patched
 `,
 				ExpectPatches: []string{
-					`{"spec":{"builder":{"kind":"Builder","name":"some-builder","namespace":"some-default-namespace"}}}`,
+					`{"spec":{"build":{"env":[{"name":"key1","value":"from-file"},{"name":"key2","value":"value2"},{"name":"key3","value":"from-flag"}]}}}`,
 				},
 			}.TestKpack(t, cmdFunc)
 			assert.Len(t, fakeImageWaiter.Calls, 0)
 		})
+
+		it("errors when an env file line is improperly formatted", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+				},
+				StdIn: "key1=value1\nnot-an-env-var\n",
+				Args: []string{
+					"some-image",
+					"--env-file", "-",
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Patching Image...
+Error: env file "-" is improperly formatted on line 2
+`,
+			}.TestKpack(t, cmdFunc)
+		})
 	})
 
-	when("patching env vars", func() {
-		it("can delete env vars", func() {
+	when("patching labels and annotations", func() {
+		it("can set labels and annotations", func() {
 			testhelpers.CommandTest{
 				Objects: []runtime.Object{
 					existingImage,
 				},
 				Args: []string{
 					"some-image",
-					"-d", "key2",
+					"--label", "team=some-team",
+					"--annotation", "description=some description",
 				},
 				ExpectedOutput: `Patching Image...
 Image "some-image" patched
 `,
 				ExpectPatches: []string{
-					`{"spec":{"build":{"env":[{"name":"key1","value":"value1"}]}}}`,
+					`{"metadata":{"annotations":{"description":"some description"},"labels":{"team":"some-team"}}}`,
 				},
 			}.TestKpack(t, cmdFunc)
-			assert.Len(t, fakeImageWaiter.Calls, 0)
 		})
 
-		it("can update existing env vars", func() {
+		it("can delete labels and annotations", func() {
+			labeledImage := existingImage.DeepCopy()
+			labeledImage.Labels = map[string]string{"team": "some-team", "cost-center": "123"}
+			labeledImage.Annotations = map[string]string{"description": "some description"}
+
 			testhelpers.CommandTest{
 				Objects: []runtime.Object{
-					existingImage,
+					labeledImage,
 				},
 				Args: []string{
 					"some-image",
-					"-e", "key1=some-other-value",
+					"--delete-label", "cost-center",
+					"--delete-annotation", "description",
 				},
 				ExpectedOutput: `Patching Image...
 Image "some-image" patched
 `,
 				ExpectPatches: []string{
-					`{"spec":{"build":{"env":[{"name":"key1","value":"some-other-value"},{"name":"key2","value":"value2"}]}}}`,
+					`{"metadata":{"annotations":null,"labels":{"cost-center":null}}}`,
 				},
 			}.TestKpack(t, cmdFunc)
-			assert.Len(t, fakeImageWaiter.Calls, 0)
 		})
 
-		it("can add new env vars", func() {
+		it("can delete labels and annotations provided in the form key-", func() {
+			labeledImage := existingImage.DeepCopy()
+			labeledImage.Labels = map[string]string{"team": "some-team", "cost-center": "123"}
+			labeledImage.Annotations = map[string]string{"description": "some description"}
+
 			testhelpers.CommandTest{
 				Objects: []runtime.Object{
-					existingImage,
+					labeledImage,
 				},
 				Args: []string{
 					"some-image",
-					"-e", "key3=value3",
+					"--label", "cost-center-",
+					"--annotation", "description-",
 				},
 				ExpectedOutput: `Patching Image...
 Image "some-image" patched
 `,
 				ExpectPatches: []string{
-					`{"spec":{"build":{"env":[{"name":"key1","value":"value1"},{"name":"key2","value":"value2"},{"name":"key3","value":"value3"}]}}}`,
+					`{"metadata":{"annotations":null,"labels":{"cost-center":null}}}`,
 				},
 			}.TestKpack(t, cmdFunc)
+		})
 
-			assert.Len(t, fakeImageWaiter.Calls, 0)
+		it("errors when a label is invalid", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+				},
+				Args: []string{
+					"some-image",
+					"--label", "team",
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Patching Image...
+Error: invalid label "team", expected key=value
+`,
+			}.TestKpack(t, cmdFunc)
 		})
 	})
 
@@ -295,6 +966,128 @@ Image "some-image" patched
 		assert.Len(t, fakeImageWaiter.Calls, 0)
 	})
 
+	it("does not patch the image with an invalid cache size", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				existingImage,
+			},
+			Args: []string{
+				"some-image",
+				"--cache-size", "2GiB",
+			},
+			ExpectErr: true,
+			ExpectedOutput: `Patching Image...
+Error: invalid cache size "2GiB", must be valid quantity ex. 2G or 500Mi
+`,
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("does not patch the cache size with dry-run", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				existingImage,
+			},
+			Args: []string{
+				"some-image",
+				"--cache-size", "3Gi",
+				"--dry-run",
+			},
+			ExpectedOutput: `Patching Image... (dry run)
+Image "some-image" patched (dry run)
+`,
+		}.TestKpack(t, cmdFunc)
+	})
+
+	when("the revert flag is used", func() {
+		makeBuild := func(number int, revision string, status corev1.ConditionStatus) *v1alpha1.Build {
+			return &v1alpha1.Build{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              fmt.Sprintf("some-image-build-%d", number),
+					Namespace:         defaultNamespace,
+					CreationTimestamp: metav1.NewTime(time.Time{}.Add(time.Duration(number) * time.Hour)),
+					Labels: map[string]string{
+						v1alpha1.ImageLabel:       "some-image",
+						v1alpha1.BuildNumberLabel: strconv.Itoa(number),
+					},
+				},
+				Spec: v1alpha1.BuildSpec{
+					Source: v1alpha1.SourceConfig{
+						Git: &v1alpha1.Git{
+							URL:      "some-git-url",
+							Revision: revision,
+						},
+						SubPath: "some-path",
+					},
+				},
+				Status: v1alpha1.BuildStatus{
+					Status: corev1alpha1.Status{
+						Conditions: corev1alpha1.Conditions{
+							{
+								Type:   corev1alpha1.ConditionSucceeded,
+								Status: status,
+							},
+						},
+					},
+				},
+			}
+		}
+
+		it("patches the source back to the revision of the last successful build", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+					makeBuild(1, "some-old-sha", corev1.ConditionTrue),
+					makeBuild(2, "some-good-sha", corev1.ConditionTrue),
+					makeBuild(3, "some-broken-sha", corev1.ConditionFalse),
+				},
+				Args: []string{
+					"some-image",
+					"--revert",
+				},
+				ExpectedOutput: `Patching Image...
+Image "some-image" patched
+`,
+				ExpectPatches: []string{
+					`{"spec":{"source":{"git":{"revision":"some-good-sha"}}}}`,
+				},
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("errors when the image has no successful build", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+					makeBuild(1, "some-broken-sha", corev1.ConditionFalse),
+				},
+				Args: []string{
+					"some-image",
+					"--revert",
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Error: image "some-image" has no successful build to revert to
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("cannot be used with the source flags", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+					makeBuild(1, "some-good-sha", corev1.ConditionTrue),
+				},
+				Args: []string{
+					"some-image",
+					"--revert",
+					"--git-revision", "some-other-revision",
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Patching Image...
+Error: revert cannot be used with the git, git-revision, blob, local-path, source-revision, sub-path or source-sub-path flags
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+	})
+
 	it("will wait on the image update if requested", func() {
 		testhelpers.CommandTest{
 			Objects: []runtime.Object{
@@ -495,6 +1288,67 @@ Image "some-image" patched (dry run)
 			assert.Len(t, fakeImageWaiter.Calls, 0)
 		})
 
+		it("lists the local source files that are excluded with the verbose flag", func() {
+			localPath, err := ioutil.TempDir("", "local-path")
+			require.NoError(t, err)
+			defer os.RemoveAll(localPath)
+
+			require.NoError(t, ioutil.WriteFile(filepath.Join(localPath, ".kpignore"), []byte("*.log\n"), 0644))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(localPath, "app.go"), nil, 0644))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(localPath, "debug.log"), nil, 0644))
+			require.NoError(t, os.MkdirAll(filepath.Join(localPath, "node_modules"), 0755))
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+				},
+				Args: []string{
+					"some-image",
+					"--local-path", localPath,
+					"--exclude", "node_modules/",
+					"--dry-run",
+					"--verbose",
+				},
+				ExpectedOutput: `Patching Image... (dry run)
+	Excluding 'debug.log'
+	Excluding 'node_modules/'
+	Skipping 'index.docker.io/library/some-tag-source:source-id'
+Image "some-image" patched (dry run)
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("applies --exclude and --local-path-exclude patterns in order", func() {
+			localPath, err := ioutil.TempDir("", "local-path")
+			require.NoError(t, err)
+			defer os.RemoveAll(localPath)
+
+			require.NoError(t, ioutil.WriteFile(filepath.Join(localPath, ".gitignore"), []byte("bin/\n"), 0644))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(localPath, "debug.log"), nil, 0644))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(localPath, "keep.log"), nil, 0644))
+			require.NoError(t, os.MkdirAll(filepath.Join(localPath, "bin"), 0755))
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+				},
+				Args: []string{
+					"some-image",
+					"--local-path", localPath,
+					"--local-path-exclude", "*.log",
+					"--exclude", "!keep.log",
+					"--dry-run",
+					"--verbose",
+				},
+				ExpectedOutput: `Patching Image... (dry run)
+	Excluding 'bin/'
+	Excluding 'debug.log'
+	Skipping 'index.docker.io/library/some-tag-source:source-id'
+Image "some-image" patched (dry run)
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
 		when("there are no changes in the patch", func() {
 			it("does not patch and informs of no change", func() {
 				testhelpers.CommandTest{
@@ -4,6 +4,7 @@
 package image_test
 
 import (
+	"io/ioutil"
 	"testing"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
@@ -18,6 +19,7 @@ import (
 
 	cmdFakes "github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
 	imgcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/image"
+	"github.com/vmware-tanzu/kpack-cli/pkg/image"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 	registryfakes "github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
@@ -30,15 +32,30 @@ func TestImageSaveCommand(t *testing.T) {
 func testImageSaveCommand(t *testing.T, when spec.G, it spec.S) {
 	const defaultNamespace = "some-default-namespace"
 
+	someBuilder := &v1alpha1.Builder{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "some-builder",
+			Namespace: defaultNamespace,
+		},
+	}
+	someClusterBuilder := &v1alpha1.ClusterBuilder{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "some-builder",
+		},
+	}
+
 	registryUtilProvider := registryfakes.UtilProvider{}
 
 	fakeImageWaiter := &cmdFakes.FakeImageWaiter{}
+	fakeRevisionResolver := &cmdFakes.FakeRevisionResolver{}
 
 	when("creating", func() {
 		cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
 			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
 			return imgcmds.NewSaveCommand(clientSetProvider, registryUtilProvider, func(set k8s.ClientSet) imgcmds.ImageWaiter {
 				return fakeImageWaiter
+			}, func(set k8s.ClientSet) image.RevisionResolver {
+				return fakeRevisionResolver
 			})
 		}
 
@@ -86,6 +103,8 @@ func testImageSaveCommand(t *testing.T, when spec.G, it spec.S) {
 				}
 
 				it("creates the image and wait on the image", func() {
+					fakeImageWaiter.Logs = "some build logs\n"
+
 					testhelpers.CommandTest{
 						Args: []string{
 							"some-image",
@@ -103,6 +122,7 @@ func testImageSaveCommand(t *testing.T, when spec.G, it spec.S) {
 						ExpectedOutput: `Creating Image...
 Image "some-image" created
 `,
+						ExpectedErrorOutput: "some build logs\n",
 						ExpectCreates: []runtime.Object{
 							expectedImage,
 						},
@@ -112,6 +132,34 @@ Image "some-image" created
 					assert.Equal(t, fakeImageWaiter.Calls[0], expectedImage)
 				})
 
+				it("waits on the image without streaming the build logs with --logs=false", func() {
+					fakeImageWaiter.Logs = "some build logs\n"
+
+					testhelpers.CommandTest{
+						Args: []string{
+							"some-image",
+							"--tag", "some-registry.io/some-repo",
+							"--git", "some-git-url",
+							"--git-revision", "some-git-rev",
+							"--sub-path", "some-sub-path",
+							"--env", "some-key=some-val",
+							"--cache-size", "2G",
+							"-n", namespace,
+							"--wait",
+							"--logs=false",
+						},
+						ExpectedOutput: `Creating Image...
+Image "some-image" created
+`,
+						ExpectCreates: []runtime.Object{
+							expectedImage,
+						},
+					}.TestKpack(t, cmdFunc)
+
+					assert.Len(t, fakeImageWaiter.Calls, 1)
+					assert.Equal(t, ioutil.Discard, fakeImageWaiter.Writers[0])
+				})
+
 				it("defaults the git revision to main", func() {
 					expectedImage.Spec.Source.Git.Revision = "main"
 					expectedImage.ObjectMeta.Annotations["kubectl.kubernetes.io/last-applied-configuration"] = `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"default"},"serviceAccount":"default","source":{"git":{"url":"some-git-url","revision":"main"},"subPath":"some-sub-path"},"cacheSize":"2G","build":{"env":[{"name":"some-key","value":"some-val"}],"resources":{}}},"status":{}}`
@@ -296,6 +344,52 @@ Image "some-image" created
 
 				assert.Len(t, fakeImageWaiter.Calls, 0)
 			})
+
+			it("does not print the upload progress with --quiet", func() {
+				expectedImage := &v1alpha1.Image{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Image",
+						APIVersion: "kpack.io/v1alpha1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "some-image",
+						Namespace: defaultNamespace,
+						Annotations: map[string]string{
+							"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Image","apiVersion":"kpack.io/v1alpha1","metadata":{"name":"some-image","namespace":"some-default-namespace","creationTimestamp":null},"spec":{"tag":"some-registry.io/some-repo","builder":{"kind":"ClusterBuilder","name":"default"},"serviceAccount":"default","source":{"registry":{"image":"some-registry.io/some-repo-source:source-id"}},"build":{"resources":{}}},"status":{}}`,
+						},
+					},
+					Spec: v1alpha1.ImageSpec{
+						Tag: "some-registry.io/some-repo",
+						Builder: corev1.ObjectReference{
+							Kind: v1alpha1.ClusterBuilderKind,
+							Name: "default",
+						},
+						ServiceAccount: "default",
+						Source: v1alpha1.SourceConfig{
+							Registry: &v1alpha1.Registry{
+								Image: "some-registry.io/some-repo-source:source-id",
+							},
+						},
+						Build: &v1alpha1.ImageBuild{},
+					},
+				}
+
+				testhelpers.CommandTest{
+					Args: []string{
+						"some-image",
+						"--tag", "some-registry.io/some-repo",
+						"--local-path", "some-local-path",
+						"--quiet",
+					},
+					ExpectedOutput: `Creating Image...
+Uploading to 'some-registry.io/some-repo-source'...
+Image "some-image" created
+`,
+					ExpectCreates: []runtime.Object{
+						expectedImage,
+					},
+				}.TestKpack(t, cmdFunc)
+			})
 		})
 
 		when("the image uses a non-default builder", func() {
@@ -330,6 +424,9 @@ Image "some-image" created
 				}
 
 				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						someBuilder,
+					},
 					Args: []string{
 						"some-image",
 						"--tag", "some-registry.io/some-repo",
@@ -379,6 +476,9 @@ Image "some-image" created
 				}
 
 				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						someClusterBuilder,
+					},
 					Args: []string{
 						"some-image",
 						"--tag", "some-registry.io/some-repo",
@@ -752,6 +852,8 @@ status: {}
 			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
 			return imgcmds.NewPatchCommand(clientSetProvider, registryUtilProvider, func(set k8s.ClientSet) imgcmds.ImageWaiter {
 				return fakeImageWaiter
+			}, func(set k8s.ClientSet) image.RevisionResolver {
+				return fakeRevisionResolver
 			})
 		}
 
@@ -916,6 +1018,7 @@ Image "some-image" patched
 				testhelpers.CommandTest{
 					Objects: []runtime.Object{
 						existingImage,
+						someBuilder,
 					},
 					Args: []string{
 						"some-image",
@@ -932,6 +1035,144 @@ Image "some-image" patched
 			})
 		})
 
+		when("deleting env vars with save", func() {
+			saveCmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
+				clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+				return imgcmds.NewSaveCommand(clientSetProvider, registryUtilProvider, func(set k8s.ClientSet) imgcmds.ImageWaiter {
+					return fakeImageWaiter
+				}, func(set k8s.ClientSet) image.RevisionResolver {
+					return fakeRevisionResolver
+				})
+			}
+
+			it("removes the env var from the existing image", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						existingImage,
+					},
+					Args: []string{
+						"some-image",
+						"--delete-env", "key1",
+					},
+					ExpectedOutput: `Patching Image...
+Image "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"build":{"env":[{"name":"key2","value":"value2"}]}}}`,
+					},
+				}.TestKpack(t, saveCmdFunc)
+			})
+
+			it("warns when the env var does not exist", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						existingImage,
+					},
+					Args: []string{
+						"some-image",
+						"--delete-env", "key3",
+					},
+					ExpectedOutput: `Patching Image...
+Warning: delete-env parameter 'key3' not found in existing image configuration
+Image "some-image" unchanged
+`,
+				}.TestKpack(t, saveCmdFunc)
+			})
+		})
+
+		when("an image file is provided", func() {
+			saveCmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
+				clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+				return imgcmds.NewSaveCommand(clientSetProvider, registryUtilProvider, func(set k8s.ClientSet) imgcmds.ImageWaiter {
+					return fakeImageWaiter
+				}, func(set k8s.ClientSet) image.RevisionResolver {
+					return fakeRevisionResolver
+				})
+			}
+
+			it("patches the existing image with the file and flags but keeps the existing tag", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						existingImage,
+					},
+					StdIn: `apiVersion: kpack.io/v1alpha1
+kind: Image
+metadata:
+  name: some-image
+spec:
+  tag: some-other-tag
+  builder:
+    kind: ClusterBuilder
+    name: some-ccb
+  source:
+    git:
+      url: some-git-url
+      revision: some-other-revision
+    subPath: some-path
+  build:
+    env:
+    - name: key1
+      value: value1
+    - name: key2
+      value: value2
+`,
+					Args: []string{
+						"-f", "-",
+						"--env", "key2=some-other-value",
+					},
+					ExpectedOutput: `Patching Image...
+Image "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"build":{"env":[{"name":"key1","value":"value1"},{"name":"key2","value":"some-other-value"}]},"serviceAccount":"default","source":{"git":{"revision":"some-other-revision"}}}}`,
+					},
+				}.TestKpack(t, saveCmdFunc)
+			})
+
+			it("does not patch when the file matches the existing image", func() {
+				historyLimit := int64(10)
+				reconciledImage := existingImage.DeepCopy()
+				reconciledImage.Spec.ServiceAccount = "default"
+				reconciledImage.Spec.SuccessBuildHistoryLimit = &historyLimit
+				reconciledImage.Spec.FailedBuildHistoryLimit = &historyLimit
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						reconciledImage,
+					},
+					StdIn: `apiVersion: kpack.io/v1alpha1
+kind: Image
+metadata:
+  name: some-image
+spec:
+  tag: some-tag
+  builder:
+    kind: ClusterBuilder
+    name: some-ccb
+  source:
+    git:
+      url: some-git-url
+      revision: some-revision
+    subPath: some-path
+  build:
+    env:
+    - name: key1
+      value: value1
+    - name: key2
+      value: value2
+`,
+					Args: []string{
+						"-f", "-",
+						"--wait",
+					},
+					ExpectedOutput: `Patching Image...
+Image "some-image" unchanged
+`,
+				}.TestKpack(t, saveCmdFunc)
+				assert.Len(t, fakeImageWaiter.Calls, 0)
+			})
+		})
+
 		when("patching env vars", func() {
 			it("can delete env vars", func() {
 				testhelpers.CommandTest{
@@ -0,0 +1,39 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/image"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+// validateServiceBindings checks that the secret of each binding exists. With dry-run a missing secret is only a warning.
+func validateServiceBindings(ctx context.Context, cs k8s.ClientSet, ch *commands.CommandHelper, bindings []string) error {
+	for _, b := range bindings {
+		binding, err := image.ParseBinding(b)
+		if err != nil {
+			return err
+		}
+
+		secretName := binding.SecretRef.Name
+		_, err = cs.K8sClient.CoreV1().Secrets(cs.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) && ch.IsDryRun() {
+			if err := ch.Printlnf("Warning: service binding secret %q not found in namespace %q", secretName, cs.Namespace); err != nil {
+				return err
+			}
+		} else if k8serrors.IsNotFound(err) {
+			return errors.Errorf("service binding secret %q not found in namespace %q", secretName, cs.Namespace)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
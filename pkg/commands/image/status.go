@@ -6,21 +6,29 @@ package image
 import (
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/build"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 )
 
 func NewStatusCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	var (
-		namespace string
+		namespace  string
+		verbose    bool
+		buildLimit int
 	)
 
 	cmd := &cobra.Command{
@@ -28,16 +36,31 @@ func NewStatusCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 		Short: "Display status of an image",
 		Long: `Prints detailed information about the status of a specific image in the provided namespace.
 
-The namespace defaults to the kubernetes current-context namespace.`,
-		Example:      "kp image status my-image\nkp image status my-other-image -n my-namespace",
-		Args:         commands.ExactArgsWithUsage(1),
-		SilenceUsage: true,
+The namespace defaults to the kubernetes current-context namespace.
+
+The most recent builds are listed below the status, newest first. Use the --build-limit flag to change the
+number of builds that are listed, "--build-limit 0" hides the build history.
+Use the --verbose flag to add the buildpack ids and versions of the last successful build.
+Use "--output yaml" or "--output json" to print the image resource instead of the status, the build history is not printed.`,
+		Example:           "kp image status my-image\nkp image status my-other-image -n my-namespace\nkp image status my-image --verbose\nkp image status my-image -o yaml",
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.ImageNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if buildLimit < 0 {
+				return errors.New("--build-limit must not be negative")
+			}
+
 			cs, err := clientSetProvider.GetClientSet(namespace)
 			if err != nil {
 				return err
 			}
 
+			ch, err := commands.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
 			ctx := cmd.Context()
 
 			image, err := cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).Get(ctx, args[0], metav1.GetOptions{})
@@ -45,6 +68,10 @@ The namespace defaults to the kubernetes current-context namespace.`,
 				return err
 			}
 
+			if ch.IsOutput() {
+				return ch.PrintObj(image)
+			}
+
 			buildList, err := cs.KpackClient.KpackV1alpha1().Builds(cs.Namespace).List(ctx, metav1.ListOptions{
 				LabelSelector: v1alpha1.ImageLabel + "=" + args[0],
 			})
@@ -53,16 +80,23 @@ The namespace defaults to the kubernetes current-context namespace.`,
 			}
 
 			sort.Slice(buildList.Items, build.Sort(buildList.Items))
-			return displayImageStatus(cmd, image, buildList.Items)
+
+			return displayImageStatus(cmd, image, buildList.Items, verbose, buildLimit)
 		},
 	}
 
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "display the buildpack ids and versions of the last successful build")
+	cmd.Flags().IntVar(&buildLimit, "build-limit", defaultStatusBuildLimit, "number of recent builds to display, 0 hides the build history")
+	cmd.Flags().StringP(commands.OutputFlag, "o", "", "print the image resource in the specified format instead of the status; supported formats are: yaml, json")
+	commands.SetConfigDefault(cmd, commands.OutputFlag, config.OutputFormatKey)
 
 	return cmd
 }
 
-func displayImageStatus(cmd *cobra.Command, image *v1alpha1.Image, builds []v1alpha1.Build) error {
+const defaultStatusBuildLimit = 10
+
+func displayImageStatus(cmd *cobra.Command, image *v1alpha1.Image, builds []v1alpha1.Build, verbose bool, buildLimit int) error {
 	statusWriter := commands.NewStatusWriter(cmd.OutOrStdout())
 	imgDetails := getImageDetails(image)
 	failedBuild := getLastFailedBuild(builds)
@@ -88,6 +122,23 @@ func displayImageStatus(cmd *cobra.Command, image *v1alpha1.Image, builds []v1al
 		return err
 	}
 
+	if image.Spec.CacheSize != nil {
+		err = statusWriter.AddBlock(
+			"",
+			"Cache Size", image.Spec.CacheSize.String(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if limits := getBuildHistoryLimits(image); len(limits) > 0 {
+		err = statusWriter.AddBlock("", limits...)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = statusWriter.AddBlock(
 		"Last Successful Build",
 		"Id", getId(successfulBuild),
@@ -97,7 +148,7 @@ func displayImageStatus(cmd *cobra.Command, image *v1alpha1.Image, builds []v1al
 		return err
 	}
 
-	if successfulBuild != nil {
+	if successfulBuild != nil && verbose {
 		tableWriter, err := commands.NewTableWriter(cmd.OutOrStdout(), "Buildpack Id", "Buildpack Version", "Homepage")
 		if err != nil {
 			return err
@@ -124,9 +175,77 @@ func displayImageStatus(cmd *cobra.Command, image *v1alpha1.Image, builds []v1al
 		return err
 	}
 
+	if len(builds) > 0 && buildLimit > 0 {
+		return displayBuildHistory(cmd, statusWriter, builds, buildLimit)
+	}
+
 	return statusWriter.Write()
 }
 
+// displayBuildHistory lists the builds with the highest build numbers first, the duration
+// of a running build is the time elapsed since it was created
+func displayBuildHistory(cmd *cobra.Command, statusWriter *commands.StatusWriter, builds []v1alpha1.Build, buildLimit int) error {
+	recent := append([]v1alpha1.Build{}, builds...)
+	sort.SliceStable(recent, func(i, j int) bool {
+		return getBuildNumber(recent[i]) > getBuildNumber(recent[j])
+	})
+	if len(recent) > buildLimit {
+		recent = recent[:buildLimit]
+	}
+
+	tableWriter, err := newSectionTableWriter(cmd, statusWriter, "Recent Builds", "Build", "Status", "Reason", "Started", "Duration", "Image")
+	if err != nil {
+		return err
+	}
+
+	for _, bld := range recent {
+		err := tableWriter.AddRow(
+			bld.Labels[v1alpha1.BuildNumberLabel],
+			getBuildStatus(bld),
+			bld.Annotations[v1alpha1.BuildReasonAnnotation],
+			formatTime(bld.CreationTimestamp),
+			getElapsedBuildDuration(bld),
+			getImageDigest(bld.Status.LatestImage),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tableWriter.Write()
+}
+
+func getBuildNumber(bld v1alpha1.Build) int {
+	n, _ := strconv.Atoi(bld.Labels[v1alpha1.BuildNumberLabel])
+	return n
+}
+
+func getElapsedBuildDuration(bld v1alpha1.Build) string {
+	if bld.IsRunning() && !bld.CreationTimestamp.IsZero() {
+		return duration.HumanDuration(time.Since(bld.CreationTimestamp.Time))
+	}
+	return getBuildDuration(bld)
+}
+
+// getImageDigest shortens the built image to its digest, images without a digest are shown in full
+func getImageDigest(ref string) string {
+	if i := strings.LastIndex(ref, "@"); i >= 0 {
+		return ref[i+1:]
+	}
+	return ref
+}
+
+func getBuildHistoryLimits(image *v1alpha1.Image) []string {
+	var limits []string
+	if image.Spec.SuccessBuildHistoryLimit != nil {
+		limits = append(limits, "Success Build History Limit", strconv.FormatInt(*image.Spec.SuccessBuildHistoryLimit, 10))
+	}
+	if image.Spec.FailedBuildHistoryLimit != nil {
+		limits = append(limits, "Failed Build History Limit", strconv.FormatInt(*image.Spec.FailedBuildHistoryLimit, 10))
+	}
+	return limits
+}
+
 func getLastSuccessfulBuild(builds []v1alpha1.Build) *v1alpha1.Build {
 	for i, _ := range builds {
 		if builds[len(builds)-1-i].IsSuccess() {
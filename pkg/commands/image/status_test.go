@@ -4,7 +4,9 @@
 package image_test
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
@@ -12,8 +14,10 @@ import (
 	"github.com/sclevine/spec"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands/image"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
@@ -33,6 +37,9 @@ func testImageStatusCommand(t *testing.T, when spec.G, it spec.S) {
 	testBuilds := testhelpers.MakeTestBuilds(imageName, defaultNamespace)
 	testNamespacedBuilds := testhelpers.MakeTestBuilds(imageName, namespace)
 
+	// the third test build is still running and was created at the start of year one
+	runningBuildDuration := duration.HumanDuration(time.Since(time.Time{}.Add(5 * time.Hour)))
+
 	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
 		clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
 		return image.NewStatusCommand(clientSetProvider)
@@ -40,7 +47,7 @@ func testImageStatusCommand(t *testing.T, when spec.G, it spec.S) {
 
 	when("a namespace is provided", func() {
 		when("the namespaces has images", func() {
-			it("returns a table of image details", func() {
+			it("returns a table of image details with the buildpacks when verbose", func() {
 				image := &v1alpha1.Image{
 					ObjectMeta: v1.ObjectMeta{
 						Name:      imageName,
@@ -65,7 +72,7 @@ func testImageStatusCommand(t *testing.T, when spec.G, it spec.S) {
 					},
 				}
 
-				const expectedOutput = `Status:         Not Ready
+				expectedOutput := fmt.Sprintf(`Status:         Not Ready
 Message:        --
 LatestImage:    test-registry.io/test-image-1@sha256:abcdef123
 
@@ -85,11 +92,17 @@ Last Failed Build
 Id:              2
 Build Reason:    COMMIT,BUILDPACK
 
-`
+Recent Builds
+BUILD    STATUS      REASON              STARTED                DURATION    IMAGE
+3        BUILDING    TRIGGER             0001-01-01 05:00:00    %-12srepo.com/image-3:tag
+2        FAILURE     COMMIT,BUILDPACK    0001-01-01 01:00:00                repo.com/image-2:tag
+1        SUCCESS     CONFIG                                                 repo.com/image-1:tag
+
+`, runningBuildDuration)
 
 				testhelpers.CommandTest{
 					Objects:        append([]runtime.Object{image}, testNamespacedBuilds...),
-					Args:           []string{imageName, "-n", namespace},
+					Args:           []string{imageName, "-n", namespace, "--verbose"},
 					ExpectedOutput: expectedOutput,
 				}.TestKpack(t, cmdFunc)
 			})
@@ -134,7 +147,7 @@ Build Reason:    COMMIT,BUILDPACK
 					},
 				}
 
-				const expectedOutput = `Status:         Not Ready
+				expectedOutput := fmt.Sprintf(`Status:         Not Ready
 Message:        --
 LatestImage:    test-registry.io/test-image-1@sha256:abcdef123
 
@@ -146,15 +159,17 @@ Last Successful Build
 Id:              1
 Build Reason:    CONFIG
 
-BUILDPACK ID    BUILDPACK VERSION    HOMEPAGE
-bp-id-1         bp-version-1         mysupercoolsite.com
-bp-id-2         bp-version-2         mysupercoolsite2.com
-
 Last Failed Build
 Id:              2
 Build Reason:    COMMIT,BUILDPACK
 
-`
+Recent Builds
+BUILD    STATUS      REASON              STARTED                DURATION    IMAGE
+3        BUILDING    TRIGGER             0001-01-01 05:00:00    %-12srepo.com/image-3:tag
+2        FAILURE     COMMIT,BUILDPACK    0001-01-01 01:00:00                repo.com/image-2:tag
+1        SUCCESS     CONFIG                                                 repo.com/image-1:tag
+
+`, runningBuildDuration)
 
 				testhelpers.CommandTest{
 					Objects:        append([]runtime.Object{image}, testBuilds...),
@@ -176,6 +191,96 @@ Build Reason:    COMMIT,BUILDPACK
 		})
 	})
 
+	when("the output flag is used", func() {
+		image := &v1alpha1.Image{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      imageName,
+				Namespace: defaultNamespace,
+			},
+			Spec: v1alpha1.ImageSpec{
+				Tag: "some-registry.io/test-image",
+				Builder: corev1.ObjectReference{
+					Kind: "ClusterBuilder",
+					Name: "some-cluster-builder",
+				},
+				Source: v1alpha1.SourceConfig{
+					Git: &v1alpha1.Git{
+						URL:      "some-git-url",
+						Revision: "some-revision",
+					},
+				},
+			},
+		}
+
+		it("prints the image in yaml format without the build history", func() {
+			const resourceYAML = `apiVersion: kpack.io/v1alpha1
+kind: Image
+metadata:
+  creationTimestamp: null
+  name: test-image
+  namespace: some-default-namespace
+spec:
+  builder:
+    kind: ClusterBuilder
+    name: some-cluster-builder
+  source:
+    git:
+      revision: some-revision
+      url: some-git-url
+  tag: some-registry.io/test-image
+status: {}
+`
+
+			testhelpers.CommandTest{
+				Objects:        append([]runtime.Object{image}, testBuilds...),
+				Args:           []string{imageName, "--output", "yaml"},
+				ExpectedOutput: resourceYAML,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("prints the image in json format without the build history", func() {
+			const resourceJSON = `{
+    "kind": "Image",
+    "apiVersion": "kpack.io/v1alpha1",
+    "metadata": {
+        "name": "test-image",
+        "namespace": "some-default-namespace",
+        "creationTimestamp": null
+    },
+    "spec": {
+        "tag": "some-registry.io/test-image",
+        "builder": {
+            "kind": "ClusterBuilder",
+            "name": "some-cluster-builder"
+        },
+        "source": {
+            "git": {
+                "url": "some-git-url",
+                "revision": "some-revision"
+            }
+        }
+    },
+    "status": {}
+}
+`
+
+			testhelpers.CommandTest{
+				Objects:        append([]runtime.Object{image}, testBuilds...),
+				Args:           []string{imageName, "-o", "json"},
+				ExpectedOutput: resourceJSON,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("returns an error for an unsupported output format", func() {
+			testhelpers.CommandTest{
+				Objects:        []runtime.Object{image},
+				Args:           []string{imageName, "-o", "table"},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: unsupported output format: \"table\", supported formats are yaml, json, name, jsonpath=<template>, jsonpath-file=<path>, go-template=<template>, go-template-file=<path>, custom-columns=<spec>, custom-columns-file=<path>\n",
+			}.TestKpack(t, cmdFunc)
+		})
+	})
+
 	when("an image has no successful builds", func() {
 		it("does not display buildpack metadata heading", func() {
 			image := &v1alpha1.Image{
@@ -226,4 +331,209 @@ Build Reason:    --
 			}.TestKpack(t, cmdFunc)
 		})
 	})
+
+	when("an image has build history", func() {
+		image := &v1alpha1.Image{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      imageName,
+				Namespace: defaultNamespace,
+			},
+			Spec: v1alpha1.ImageSpec{
+				Builder: corev1.ObjectReference{
+					Kind: "ClusterBuilder",
+					Name: "some-cluster-builder",
+				},
+			},
+		}
+
+		makeBuild := func(number int, status corev1.ConditionStatus, created, finished time.Time) *v1alpha1.Build {
+			return &v1alpha1.Build{
+				ObjectMeta: v1.ObjectMeta{
+					Name:              fmt.Sprintf("%s-build-%d", imageName, number),
+					Namespace:         defaultNamespace,
+					CreationTimestamp: v1.NewTime(created),
+					Labels: map[string]string{
+						v1alpha1.ImageLabel:       imageName,
+						v1alpha1.BuildNumberLabel: fmt.Sprint(number),
+					},
+					Annotations: map[string]string{
+						v1alpha1.BuildReasonAnnotation: "COMMIT",
+					},
+				},
+				Status: v1alpha1.BuildStatus{
+					Status: corev1alpha1.Status{
+						Conditions: corev1alpha1.Conditions{
+							{
+								Type:               corev1alpha1.ConditionSucceeded,
+								Status:             status,
+								LastTransitionTime: corev1alpha1.VolatileTime{Inner: v1.NewTime(finished)},
+							},
+						},
+					},
+					LatestImage: fmt.Sprintf("test-registry.io/test-image@sha256:digest-%d", number),
+				},
+			}
+		}
+
+		start := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+		runningStart := time.Now().Add(-20 * time.Minute)
+		builds := []runtime.Object{
+			image,
+			makeBuild(9, corev1.ConditionTrue, start, start.Add(3*time.Minute)),
+			makeBuild(10, corev1.ConditionFalse, start.Add(time.Hour), start.Add(time.Hour+90*time.Second)),
+			makeBuild(11, corev1.ConditionUnknown, runningStart, time.Time{}),
+		}
+
+		it("lists the most recent builds by build number with the elapsed time of running builds", func() {
+			testhelpers.CommandTest{
+				Objects: builds,
+				Args:    []string{imageName, "--build-limit", "2"},
+				ExpectedOutput: `Status:         Unknown
+Message:        --
+LatestImage:    --
+
+Builder Ref:     
+  Name:         some-cluster-builder
+  Kind:         ClusterBuilder
+
+Last Successful Build
+Id:              9
+Build Reason:    COMMIT
+
+Last Failed Build
+Id:              10
+Build Reason:    COMMIT
+
+Recent Builds
+BUILD    STATUS      REASON    STARTED                DURATION    IMAGE
+11       BUILDING    COMMIT    ` + runningStart.Format("2006-01-02 15:04:05") + `    20m         sha256:digest-11
+10       FAILURE     COMMIT    2021-03-01 13:00:00    90s         sha256:digest-10
+
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("hides the build history with a build limit of 0", func() {
+			testhelpers.CommandTest{
+				Objects: builds,
+				Args:    []string{imageName, "--build-limit", "0"},
+				ExpectedOutput: `Status:         Unknown
+Message:        --
+LatestImage:    --
+
+Builder Ref:     
+  Name:         some-cluster-builder
+  Kind:         ClusterBuilder
+
+Last Successful Build
+Id:              9
+Build Reason:    COMMIT
+
+Last Failed Build
+Id:              10
+Build Reason:    COMMIT
+
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("errors with a negative build limit", func() {
+			testhelpers.CommandTest{
+				Objects:        builds,
+				Args:           []string{imageName, "--build-limit", "-1"},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: --build-limit must not be negative\n",
+			}.TestKpack(t, cmdFunc)
+		})
+	})
+
+	when("an image has a cache size", func() {
+		it("displays the cache size", func() {
+			cacheSize := resource.MustParse("2G")
+			image := &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      imageName,
+					Namespace: defaultNamespace,
+				},
+				Spec: v1alpha1.ImageSpec{
+					Builder: corev1.ObjectReference{
+						Kind: "ClusterBuilder",
+						Name: "some-cluster-builder",
+					},
+					CacheSize: &cacheSize,
+				},
+			}
+
+			const expectedOutput = `Status:         Unknown
+Message:        --
+LatestImage:    --
+
+Builder Ref:     
+  Name:         some-cluster-builder
+  Kind:         ClusterBuilder
+
+Cache Size:    2G
+
+Last Successful Build
+Id:              --
+Build Reason:    --
+
+Last Failed Build
+Id:              --
+Build Reason:    --
+
+`
+			testhelpers.CommandTest{
+				Objects:        []runtime.Object{image},
+				Args:           []string{imageName},
+				ExpectedOutput: expectedOutput,
+			}.TestKpack(t, cmdFunc)
+		})
+	})
+
+	when("an image has build history limits", func() {
+		it("displays the build history limits", func() {
+			successLimit, failedLimit := int64(3), int64(0)
+			image := &v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      imageName,
+					Namespace: defaultNamespace,
+				},
+				Spec: v1alpha1.ImageSpec{
+					Builder: corev1.ObjectReference{
+						Kind: "ClusterBuilder",
+						Name: "some-cluster-builder",
+					},
+					SuccessBuildHistoryLimit: &successLimit,
+					FailedBuildHistoryLimit:  &failedLimit,
+				},
+			}
+
+			const expectedOutput = `Status:         Unknown
+Message:        --
+LatestImage:    --
+
+Builder Ref:     
+  Name:         some-cluster-builder
+  Kind:         ClusterBuilder
+
+Success Build History Limit:    3
+Failed Build History Limit:     0
+
+Last Successful Build
+Id:              --
+Build Reason:    --
+
+Last Failed Build
+Id:              --
+Build Reason:    --
+
+`
+			testhelpers.CommandTest{
+				Objects:        []runtime.Object{image},
+				Args:           []string{imageName},
+				ExpectedOutput: expectedOutput,
+			}.TestKpack(t, cmdFunc)
+		})
+	})
 }
@@ -4,8 +4,10 @@
 package image
 
 import (
-	"fmt"
+	"context"
+	"io"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
@@ -20,54 +22,209 @@ import (
 
 const BuildNeededAnnotation = "image.kpack.io/additionalBuildNeeded"
 
-func NewTriggerCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+func NewTriggerCommand(clientSetProvider k8s.ClientSetProvider, newBuildLogsTailer func(k8s.ClientSet) commands.BuildLogsTailer) *cobra.Command {
 	var (
-		namespace string
+		namespace   string
+		buildNumber string
+		all         bool
 	)
 
 	cmd := &cobra.Command{
-		Use:   "trigger <name>",
-		Short: "Trigger an image build",
-		Long: `Trigger a build using current inputs for a specific image in the provided namespace.
+		Use:   "trigger <name>...",
+		Short: "Trigger image builds",
+		Long: `Trigger a build using current inputs for one or more images in the provided namespace.
 
-The namespace defaults to the kubernetes current-context namespace.`,
-		Example: "kp image trigger my-image",
-		Args:    commands.ExactArgsWithUsage(1),
+The namespace defaults to the kubernetes current-context namespace.
+
+Use the "--all" flag to trigger a build for every image in the namespace.
+When multiple images are provided, a build is triggered for each image even if triggering
+another image fails. The command exits with a non-zero status if any trigger failed.
+
+The "--wait" flag tails the logs of the triggered build until it completes.
+Use the "--build" flag with "--wait" to wait for a specific build number instead.
+The "--wait" flag can only be used with a single image.`,
+		Example: `kp image trigger my-image
+kp image trigger my-image my-other-image
+kp image trigger --all -n my-namespace
+kp image trigger my-image --wait
+kp image trigger my-image --wait --build 5`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if all {
+				if len(args) > 0 {
+					return errors.New("image names cannot be provided with --all")
+				}
+				return nil
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
+		ValidArgsFunction: commands.ImageNamesCompletion(clientSetProvider),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet(namespace)
 			if err != nil {
 				return err
 			}
 
-			ctx := cmd.Context()
-
-			buildList, err := cs.KpackClient.KpackV1alpha1().Builds(cs.Namespace).List(ctx, metav1.ListOptions{
-				LabelSelector: v1alpha1.ImageLabel + "=" + args[0],
-			})
+			ch, err := commands.NewCommandHelper(cmd)
 			if err != nil {
 				return err
 			}
 
-			if len(buildList.Items) == 0 {
-				return errors.New("no builds found")
-			} else {
-				sort.Slice(buildList.Items, build.Sort(buildList.Items))
+			if cmd.Flags().Changed("build") {
+				if err := validateBuildNumber(cmd, buildNumber); err != nil {
+					return err
+				}
+			}
+
+			ctx := cmd.Context()
 
-				build := buildList.Items[len(buildList.Items)-1].DeepCopy()
-				build.Annotations[BuildNeededAnnotation] = time.Now().String()
-				_, err := cs.KpackClient.KpackV1alpha1().Builds(cs.Namespace).Update(ctx, build, metav1.UpdateOptions{})
+			names := args
+			if all {
+				names, err = imageNames(ctx, cs)
 				if err != nil {
 					return err
 				}
+			}
 
-				_, err = fmt.Fprintf(cmd.OutOrStderr(), "Triggered build for Image %q\n", args[0])
-				return err
+			if len(names) > 1 && ch.ShouldWait() {
+				return errors.New("--wait can only be used with a single image")
+			}
+
+			if len(names) == 1 {
+				return triggerAndWait(ctx, cmd.OutOrStdout(), ch, cs, newBuildLogsTailer, names[0], buildNumber)
 			}
+
+			var failed int
+			for _, name := range names {
+				if _, err := triggerBuild(ctx, ch, cs, name); err != nil {
+					failed++
+					if err := ch.Printlnf("Failed to trigger build for Image %q: %s", name, err); err != nil {
+						return err
+					}
+				}
+			}
+
+			if failed > 0 {
+				return errors.Errorf("failed to trigger builds for %d of %d images", failed, len(names))
+			}
+			return nil
 		},
 		SilenceUsage: true,
 	}
 
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	cmd.Flags().BoolVar(&all, "all", false, "trigger builds for all images in the namespace")
+	cmd.Flags().BoolP("wait", "w", false, "wait for the triggered build to complete and tail its logs")
+	cmd.Flags().StringVarP(&buildNumber, "build", "b", "", "build number to wait for when used with --wait (default next build number)")
+	commands.SetWaitTimeoutFlag(cmd)
+	commands.SetDryRunOutputFlags(cmd)
+	commands.SetServerDryRunFlag(cmd)
 
 	return cmd
 }
+
+func imageNames(ctx context.Context, cs k8s.ClientSet) ([]string, error) {
+	imageList, err := cs.KpackClient.KpackV1alpha1().Images(cs.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(imageList.Items) == 0 {
+		return nil, errors.New("no images found")
+	}
+
+	var names []string
+	for _, img := range imageList.Items {
+		names = append(names, img.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func triggerAndWait(ctx context.Context, writer io.Writer, ch *commands.CommandHelper, cs k8s.ClientSet, newBuildLogsTailer func(k8s.ClientSet) commands.BuildLogsTailer, name, buildNumber string) error {
+	bld, err := triggerBuild(ctx, ch, cs, name)
+	if err != nil {
+		return err
+	}
+
+	if !ch.ShouldWait() {
+		return nil
+	}
+
+	if buildNumber == "" {
+		buildNumber, err = nextBuildNumber(bld)
+		if err != nil {
+			return err
+		}
+	}
+
+	return waitForBuild(ctx, writer, ch, cs, newBuildLogsTailer(cs), name, buildNumber)
+}
+
+func triggerBuild(ctx context.Context, ch *commands.CommandHelper, cs k8s.ClientSet, name string) (*v1alpha1.Build, error) {
+	buildList, err := cs.KpackClient.KpackV1alpha1().Builds(cs.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: v1alpha1.ImageLabel + "=" + name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(buildList.Items) == 0 {
+		return nil, errors.New("no builds found")
+	}
+
+	sort.Slice(buildList.Items, build.Sort(buildList.Items))
+
+	bld := buildList.Items[len(buildList.Items)-1].DeepCopy()
+	if bld.Annotations == nil {
+		bld.Annotations = map[string]string{}
+	}
+	bld.Annotations[BuildNeededAnnotation] = time.Now().String()
+
+	if ch.ShouldSubmit() {
+		bld, err = cs.KpackClient.KpackV1alpha1().Builds(cs.Namespace).Update(ctx, bld, ch.UpdateOptions())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ch.PrintObj(bld); err != nil {
+		return nil, err
+	}
+
+	return bld, ch.PrintResult("Triggered build for Image %q", name)
+}
+
+func validateBuildNumber(cmd *cobra.Command, buildNumber string) error {
+	wait, err := commands.GetBoolFlag(commands.WaitFlag, cmd)
+	if err != nil {
+		return err
+	}
+
+	if !wait {
+		return errors.New("--build can only be used with --wait")
+	}
+
+	if n, err := strconv.Atoi(buildNumber); err != nil || n < 1 {
+		return errors.Errorf("invalid build number %q, must be a positive integer", buildNumber)
+	}
+	return nil
+}
+
+func nextBuildNumber(bld *v1alpha1.Build) (string, error) {
+	n, err := strconv.Atoi(bld.Labels[v1alpha1.BuildNumberLabel])
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to determine build number of build %q", bld.Name)
+	}
+	return strconv.Itoa(n + 1), nil
+}
+
+func waitForBuild(ctx context.Context, writer io.Writer, ch *commands.CommandHelper, cs k8s.ClientSet, tailer commands.BuildLogsTailer, name, buildNumber string) error {
+	ctx, cancel := context.WithTimeout(ctx, ch.WaitTimeout())
+	defer cancel()
+
+	err := tailer.Tail(ctx, writer, name, buildNumber, cs.Namespace)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return commands.NewTimeoutError("timed out after %v waiting for build %s of Image %q", ch.WaitTimeout(), buildNumber, name)
+	}
+	return err
+}
@@ -11,8 +11,13 @@ import (
 	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
 	"github.com/sclevine/spec"
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	cmdFakes "github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands/image"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
 )
 
@@ -29,12 +34,21 @@ func testImageTrigger(t *testing.T, when spec.G, it spec.S) {
 	testBuilds := testhelpers.MakeTestBuilds("some-image", defaultNamespace)
 	testNamespacedBuilds := testhelpers.MakeTestBuilds("some-image", namespace)
 
+	fakeBuildLogsTailer := &cmdFakes.FakeBuildLogsTailer{}
+	newBuildLogsTailer := func(k8s.ClientSet) commands.BuildLogsTailer {
+		return fakeBuildLogsTailer
+	}
+
+	it.After(func() {
+		fakeBuildLogsTailer.Calls = nil
+	})
+
 	when("a namespace is provided", func() {
 		when("an image build is available", func() {
 			it("triggers the latest build", func() {
 				clientSet := fake.NewSimpleClientset(testNamespacedBuilds...)
 				clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
-				cmd := image.NewTriggerCommand(clientSetProvider)
+				cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
 
 				out := &bytes.Buffer{}
 				cmd.SetOut(out)
@@ -58,7 +72,7 @@ func testImageTrigger(t *testing.T, when spec.G, it spec.S) {
 			it("returns an error", func() {
 				clientSet := fake.NewSimpleClientset()
 				clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
-				cmd := image.NewTriggerCommand(clientSetProvider)
+				cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
 
 				out := &bytes.Buffer{}
 				cmd.SetOut(out)
@@ -75,7 +89,7 @@ func testImageTrigger(t *testing.T, when spec.G, it spec.S) {
 			it("triggers the latest build", func() {
 				clientSet := fake.NewSimpleClientset(testBuilds...)
 				clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
-				cmd := image.NewTriggerCommand(clientSetProvider)
+				cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
 
 				out := &bytes.Buffer{}
 				cmd.SetOut(out)
@@ -99,7 +113,7 @@ func testImageTrigger(t *testing.T, when spec.G, it spec.S) {
 			it("returns an error", func() {
 				clientSet := fake.NewSimpleClientset()
 				clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
-				cmd := image.NewTriggerCommand(clientSetProvider)
+				cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
 
 				out := &bytes.Buffer{}
 				cmd.SetOut(out)
@@ -110,4 +124,232 @@ func testImageTrigger(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 	})
+
+	when("multiple images are provided", func() {
+		otherImageBuild := testBuilds[2].(*v1alpha1.Build).DeepCopy()
+		otherImageBuild.Name = "other-image-build"
+		otherImageBuild.Labels[v1alpha1.ImageLabel] = "other-image"
+
+		it("triggers the latest build of each image", func() {
+			clientSet := fake.NewSimpleClientset(append(testBuilds, otherImageBuild)...)
+			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+			cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
+
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+			cmd.SetArgs([]string{"some-image", "other-image"})
+
+			err := cmd.Execute()
+			require.NoError(t, err)
+			require.Equal(t, "Triggered build for Image \"some-image\"\nTriggered build for Image \"other-image\"\n", out.String())
+
+			actions, err := testhelpers.ActionRecorderList{clientSet}.ActionsByVerb()
+			require.NoError(t, err)
+			require.Len(t, actions.Updates, 2)
+			require.Equal(t, "build-three", actions.Updates[0].GetObject().(*v1alpha1.Build).Name)
+			require.Equal(t, "other-image-build", actions.Updates[1].GetObject().(*v1alpha1.Build).Name)
+		})
+
+		it("attempts every image and errors if any failed", func() {
+			clientSet := fake.NewSimpleClientset(append(testBuilds, otherImageBuild)...)
+			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+			cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
+
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+			cmd.SetArgs([]string{"missing-image", "some-image", "other-image"})
+
+			err := cmd.Execute()
+			require.EqualError(t, err, "failed to trigger builds for 1 of 3 images")
+			require.Equal(t, `Failed to trigger build for Image "missing-image": no builds found
+Triggered build for Image "some-image"
+Triggered build for Image "other-image"
+Error: failed to trigger builds for 1 of 3 images
+`, out.String())
+
+			actions, err := testhelpers.ActionRecorderList{clientSet}.ActionsByVerb()
+			require.NoError(t, err)
+			require.Len(t, actions.Updates, 2)
+		})
+
+		it("errors with the wait flag", func() {
+			clientSet := fake.NewSimpleClientset(append(testBuilds, otherImageBuild)...)
+			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+			cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
+
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetArgs([]string{"some-image", "other-image", "--wait"})
+
+			err := cmd.Execute()
+			require.EqualError(t, err, "--wait can only be used with a single image")
+			require.Len(t, clientSet.Actions(), 0)
+		})
+	})
+
+	when("the all flag is provided", func() {
+		images := []runtime.Object{
+			&v1alpha1.Image{ObjectMeta: metav1.ObjectMeta{Name: "some-image", Namespace: namespace}},
+			&v1alpha1.Image{ObjectMeta: metav1.ObjectMeta{Name: "other-image", Namespace: namespace}},
+			&v1alpha1.Image{ObjectMeta: metav1.ObjectMeta{Name: "some-image", Namespace: defaultNamespace}},
+		}
+
+		it("triggers builds for all images in the namespace", func() {
+			otherImageBuild := testNamespacedBuilds[2].(*v1alpha1.Build).DeepCopy()
+			otherImageBuild.Name = "other-image-build"
+			otherImageBuild.Labels[v1alpha1.ImageLabel] = "other-image"
+
+			objects := append(append(images, testNamespacedBuilds...), otherImageBuild)
+			clientSet := fake.NewSimpleClientset(objects...)
+			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+			cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
+
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+			cmd.SetArgs([]string{"--all", "-n", namespace})
+
+			err := cmd.Execute()
+			require.NoError(t, err)
+			require.Equal(t, "Triggered build for Image \"other-image\"\nTriggered build for Image \"some-image\"\n", out.String())
+		})
+
+		it("errors when image names are also provided", func() {
+			clientSet := fake.NewSimpleClientset(images...)
+			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+			cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
+
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetArgs([]string{"some-image", "--all"})
+
+			err := cmd.Execute()
+			require.EqualError(t, err, "image names cannot be provided with --all")
+		})
+
+		it("errors when the namespace has no images", func() {
+			clientSet := fake.NewSimpleClientset()
+			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+			cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
+
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetArgs([]string{"--all"})
+
+			err := cmd.Execute()
+			require.EqualError(t, err, "no images found")
+		})
+	})
+
+	when("the wait flag is provided", func() {
+		it("tails the logs of the next build", func() {
+			clientSet := fake.NewSimpleClientset(testBuilds...)
+			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+			cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
+
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+			cmd.SetArgs([]string{"some-image", "--wait"})
+
+			err := cmd.Execute()
+			require.NoError(t, err)
+			require.Equal(t, "Triggered build for Image \"some-image\"\n", out.String())
+
+			require.Equal(t, []cmdFakes.TailCall{{Image: "some-image", Build: "4", Namespace: defaultNamespace}}, fakeBuildLogsTailer.Calls)
+		})
+
+		it("tails the logs of the build provided with --build", func() {
+			clientSet := fake.NewSimpleClientset(testBuilds...)
+			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+			cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
+
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+			cmd.SetArgs([]string{"some-image", "--wait", "--build", "6"})
+
+			err := cmd.Execute()
+			require.NoError(t, err)
+
+			require.Equal(t, []cmdFakes.TailCall{{Image: "some-image", Build: "6", Namespace: defaultNamespace}}, fakeBuildLogsTailer.Calls)
+		})
+	})
+
+	when("the build flag is provided", func() {
+		it("errors without --wait", func() {
+			clientSet := fake.NewSimpleClientset(testBuilds...)
+			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+			cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
+
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetArgs([]string{"some-image", "--build", "6"})
+
+			err := cmd.Execute()
+			require.EqualError(t, err, "--build can only be used with --wait")
+			require.Len(t, clientSet.Actions(), 0)
+		})
+
+		it("errors when the build number is invalid", func() {
+			clientSet := fake.NewSimpleClientset(testBuilds...)
+			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+			cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
+
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetArgs([]string{"some-image", "--wait", "--build", "zero"})
+
+			err := cmd.Execute()
+			require.EqualError(t, err, `invalid build number "zero", must be a positive integer`)
+		})
+	})
+
+	when("the dry-run flag is provided", func() {
+		it("does not update the build or wait", func() {
+			clientSet := fake.NewSimpleClientset(testBuilds...)
+			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+			cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
+
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+			cmd.SetArgs([]string{"some-image", "--dry-run", "--wait"})
+
+			err := cmd.Execute()
+			require.NoError(t, err)
+			require.Equal(t, "Triggered build for Image \"some-image\" (dry run)\n", out.String())
+
+			actions, err := testhelpers.ActionRecorderList{clientSet}.ActionsByVerb()
+			require.NoError(t, err)
+			require.Len(t, actions.Updates, 0)
+			require.Len(t, fakeBuildLogsTailer.Calls, 0)
+		})
+
+		it("outputs the annotated build", func() {
+			clientSet := fake.NewSimpleClientset(testBuilds...)
+			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+			cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
+
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+			cmd.SetArgs([]string{"some-image", "--dry-run", "--output", "jsonpath={.metadata.name}"})
+
+			err := cmd.Execute()
+			require.NoError(t, err)
+			require.Equal(t, "build-three\n", out.String())
+		})
+	})
+
+	when("the server-side-dry-run flag is provided", func() {
+		it("submits the update to the server and does not wait", func() {
+			clientSet := fake.NewSimpleClientset(testBuilds...)
+			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+			cmd := image.NewTriggerCommand(clientSetProvider, newBuildLogsTailer)
+
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+			cmd.SetArgs([]string{"some-image", "--server-side-dry-run", "--wait"})
+
+			err := cmd.Execute()
+			require.NoError(t, err)
+			require.Equal(t, "Triggered build for Image \"some-image\" (server dry run)\n", out.String())
+
+			actions, err := testhelpers.ActionRecorderList{clientSet}.ActionsByVerb()
+			require.NoError(t, err)
+			require.Len(t, actions.Updates, 1)
+			require.Len(t, fakeBuildLogsTailer.Calls, 0)
+		})
+	})
 }
@@ -0,0 +1,25 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+)
+
+func setQuietFlag(cmd *cobra.Command, quiet *bool) {
+	cmd.Flags().BoolVarP(quiet, "quiet", "q", false, "do not print the progress of local source uploads")
+}
+
+// uploadWriter returns the writer that receives local source upload progress
+func uploadWriter(ch *commands.CommandHelper, quiet bool) io.Writer {
+	if quiet {
+		return ioutil.Discard
+	}
+	return ch.Writer()
+}
@@ -4,11 +4,13 @@
 package _import
 
 import (
+	"context"
 	"io"
 	"io/ioutil"
 	"os"
 
 	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
@@ -37,6 +39,7 @@ func NewImportCommand(
 		filename    string
 		showChanges bool
 		force       bool
+		summary     bool
 		tlsConfig   registry.TLSConfig
 	)
 
@@ -56,9 +59,27 @@ func NewImportCommand(
 		Long: `This operation will create or update clusterstores, clusterstacks, and clusterbuilders defined in the dependency descriptor.
 
 kp import will always attempt to upload the stack, store, and builder images, even if the resources have not changed.
-This can be used as a way to repair resources when registry images have been unexpectedly removed.`,
+This can be used as a way to repair resources when registry images have been unexpectedly removed.
+
+The dependency descriptor must use apiVersion "kp.kpack.io/v1alpha3" or the older "kp.kpack.io/v1alpha1".
+
+The file may instead contain kpack resources separated by "---", such as the output of "kp export".
+Documents with apiVersion "kpack.io/v1alpha1" and kind ClusterStore, ClusterStack, ClusterBuilder, Builder, or Image
+are created if they do not exist, otherwise their spec is replaced. Builders and images without a namespace are imported
+into the kubernetes current-context namespace. Images referenced by these resources are used as-is and are not relocated.
+Documents of any other kind are skipped with a warning.
+
+Use the "--summary" flag with "--output" to print a summary of the imported resources instead of the resources.
+The summary lists each lifecycle, clusterstore, clusterstack, and clusterbuilder processed, whether it was created,
+updated, or unchanged, and the source and relocated references of its images. Progress is printed to stderr.
+
+Registries with certificates signed by a private certificate authority can be trusted with the "--registry-ca-cert" flag.
+Supply the flag once for each PEM encoded CA certificate file. The certificates are added to the system root certificates.`,
 		Example: `kp import -f dependencies.yaml
-cat dependencies.yaml | kp import -f -`,
+cat dependencies.yaml | kp import -f -
+kp import -f resources.yaml --dry-run --output yaml
+kp import -f dependencies.yaml --summary --output json
+kp import -f dependencies.yaml --registry-ca-cert /tmp/ca.crt --registry-ca-cert /tmp/other-ca.crt`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet("")
@@ -71,15 +92,12 @@ cat dependencies.yaml | kp import -f -`,
 				return err
 			}
 
-			ctx := cmd.Context()
-
-			configHelper := k8s.DefaultConfigHelper(cs)
-
-			kpConfig, err := configHelper.GetKpConfig(ctx)
-			if err != nil {
-				return err
+			if summary && !ch.IsOutput() {
+				return errors.New("--summary can only be used with --output")
 			}
 
+			ctx := cmd.Context()
+
 			imgFetcher := rup.Fetcher(tlsConfig)
 			imgRelocator := rup.Relocator(ch.Writer(), tlsConfig, ch.CanChangeState())
 
@@ -89,7 +107,7 @@ cat dependencies.yaml | kp import -f -`,
 				cs.KpackClient,
 				imgFetcher,
 				imgRelocator,
-				newWaiter(cs.DynamicClient),
+				commands.WithWaitTimeout(newWaiter(cs.DynamicClient), ch.WaitTimeout()),
 				timestampProvider,
 			)
 
@@ -98,6 +116,25 @@ cat dependencies.yaml | kp import -f -`,
 				return err
 			}
 
+			if importpkg.IsResourceManifest(rawDescriptor) {
+				if showChanges {
+					return errors.New("--show-changes can only be used with a dependency descriptor")
+				}
+
+				if summary {
+					return errors.New("--summary can only be used with a dependency descriptor")
+				}
+
+				return importResources(ctx, importer, ch, cs.Namespace, rawDescriptor)
+			}
+
+			configHelper := k8s.DefaultConfigHelper(cs)
+
+			kpConfig, err := configHelper.GetKpConfig(ctx)
+			if err != nil {
+				return err
+			}
+
 			descriptor, err := importer.ReadDescriptor(rawDescriptor)
 			if err != nil {
 				return err
@@ -150,22 +187,51 @@ cat dependencies.yaml | kp import -f -`,
 				}
 			}
 
-			if err := ch.PrintObjs(objs); err != nil {
+			if summary {
+				if err := ch.PrintObj(importer.Summary()); err != nil {
+					return err
+				}
+			} else if err := ch.PrintObjs(objs); err != nil {
 				return err
 			}
 
 			return ch.PrintResult("Imported resources")
 		},
 	}
-	cmd.Flags().StringVarP(&filename, "filename", "f", "", "dependency descriptor filename")
+	cmd.Flags().StringVarP(&filename, "filename", "f", "", "dependency descriptor or kpack resources filename")
 	cmd.Flags().BoolVar(&showChanges, "show-changes", false, "show a summary of resource changes before importing")
 	cmd.Flags().BoolVar(&force, "force", false, "import without confirmation when showing changes")
+	cmd.Flags().BoolVar(&summary, "summary", false, "with --output, print a summary of the imported resources and relocated images instead of the resources")
 	commands.SetImgUploadDryRunOutputFlags(cmd)
+	commands.SetReadyTimeoutFlag(cmd)
 	commands.SetTLSFlags(cmd, &tlsConfig)
+	cmd.Flags().StringArrayVar(&tlsConfig.CaCertPaths, "registry-ca-cert", []string{}, "add a PEM encoded CA certificate file for registry API, may be repeated")
 	_ = cmd.MarkFlagRequired("filename")
 	return cmd
 }
 
+func importResources(ctx context.Context, importer *importpkg.Importer, ch *commands.CommandHelper, namespace, rawResources string) error {
+	var (
+		objs []runtime.Object
+		err  error
+	)
+
+	if ch.IsDryRun() {
+		objs, err = importer.ImportResourcesDryRun(namespace, rawResources)
+	} else {
+		objs, err = importer.ImportResources(ctx, namespace, rawResources)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := ch.PrintObjs(objs); err != nil {
+		return err
+	}
+
+	return ch.PrintResult("Imported resources")
+}
+
 func readDescriptor(cmd *cobra.Command, filename string) (string, error) {
 	var (
 		reader io.ReadCloser
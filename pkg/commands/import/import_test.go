@@ -22,6 +22,7 @@ import (
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	commandsfakes "github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
 	importcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/import"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 	registryfakes "github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
 )
@@ -570,7 +571,7 @@ Imported resources
 	Uploading 'canonical-registry.io/canonical-repo/lifecycle@sha256:lifecycle-image-digest'
 Importing ClusterStore 'store-name'...
 	Uploading 'canonical-registry.io/canonical-repo/buildpack-id@sha256:buildpack-image-digest'
-	Buildpackage already exists in the store
+	Buildpackage already present, skipping
 Importing ClusterStack 'stack-name'...
 Uploading to 'canonical-registry.io/canonical-repo'...
 	Uploading 'canonical-registry.io/canonical-repo/build@sha256:build-image-digest'
@@ -627,7 +628,7 @@ Imported resources
 	Uploading 'canonical-registry.io/canonical-repo/lifecycle@sha256:lifecycle-image-digest'
 Importing ClusterStore 'store-name'...
 	Uploading 'canonical-registry.io/canonical-repo/buildpack-id@sha256:buildpack-image-digest'
-	Buildpackage already exists in the store
+	Buildpackage already present, skipping
 Importing ClusterStack 'stack-name'...
 Uploading to 'canonical-registry.io/canonical-repo'...
 	Uploading 'canonical-registry.io/canonical-repo/build@sha256:build-image-digest'
@@ -738,6 +739,120 @@ Uploading to 'canonical-registry.io/canonical-repo'...
 Importing ClusterBuilder 'clusterbuilder-name'...
 Importing ClusterBuilder 'default'...
 Imported resources
+`,
+					ExpectUpdates: []clientgotesting.UpdateActionImpl{
+						{Object: expectedLifecycleImageConfig},
+						{Object: expectedStore},
+						{Object: expectedStack},
+						{Object: expectedDefaultStack},
+						{Object: expectedBuilder},
+						{Object: expectedDefaultBuilder},
+					},
+				}.TestK8sAndKpack(t, cmdFunc)
+			})
+
+			it("prints a summary of the imported resources and relocated images with the summary flag", func() {
+				const summaryJSON = `{
+    "kind": "ImportSummary",
+    "apiVersion": "kp.kpack.io/v1alpha3",
+    "resources": [
+        {
+            "kind": "ConfigMap",
+            "name": "lifecycle-image",
+            "action": "updated",
+            "images": [
+                {
+                    "source": "some-registry.io/repo/another-lifecycle-image",
+                    "relocated": "canonical-registry.io/canonical-repo/lifecycle@sha256:another-lifecycle-image-digest"
+                }
+            ]
+        },
+        {
+            "kind": "ClusterStore",
+            "name": "store-name",
+            "action": "updated",
+            "images": [
+                {
+                    "source": "some-registry.io/repo/another-buildpack-image",
+                    "relocated": "canonical-registry.io/canonical-repo/another-buildpack-id@sha256:another-buildpack-image-digest"
+                }
+            ]
+        },
+        {
+            "kind": "ClusterStack",
+            "name": "stack-name",
+            "action": "updated",
+            "images": [
+                {
+                    "source": "some-registry.io/repo/another-build-image",
+                    "relocated": "canonical-registry.io/canonical-repo/build@sha256:another-build-image-digest"
+                },
+                {
+                    "source": "some-registry.io/repo/another-run-image",
+                    "relocated": "canonical-registry.io/canonical-repo/run@sha256:another-run-image-digest"
+                }
+            ]
+        },
+        {
+            "kind": "ClusterStack",
+            "name": "default",
+            "action": "updated",
+            "images": [
+                {
+                    "source": "some-registry.io/repo/another-build-image",
+                    "relocated": "canonical-registry.io/canonical-repo/build@sha256:another-build-image-digest"
+                },
+                {
+                    "source": "some-registry.io/repo/another-run-image",
+                    "relocated": "canonical-registry.io/canonical-repo/run@sha256:another-run-image-digest"
+                }
+            ]
+        },
+        {
+            "kind": "ClusterBuilder",
+            "name": "clusterbuilder-name",
+            "action": "updated"
+        },
+        {
+            "kind": "ClusterBuilder",
+            "name": "default",
+            "action": "updated"
+        }
+    ]
+}
+`
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						kpConfig,
+						lifecycleImageConfig,
+						store,
+						stack,
+						defaultStack,
+						builder,
+						defaultBuilder,
+					},
+					Args: []string{
+						"-f", "./testdata/updated-deps.yaml",
+						"--summary",
+						"--output", "json",
+					},
+					ExpectedOutput: summaryJSON,
+					ExpectedErrorOutput: `Importing Lifecycle...
+	Uploading 'canonical-registry.io/canonical-repo/lifecycle@sha256:another-lifecycle-image-digest'
+Importing ClusterStore 'store-name'...
+	Uploading 'canonical-registry.io/canonical-repo/another-buildpack-id@sha256:another-buildpack-image-digest'
+	Added Buildpackage
+Importing ClusterStack 'stack-name'...
+Uploading to 'canonical-registry.io/canonical-repo'...
+	Uploading 'canonical-registry.io/canonical-repo/build@sha256:another-build-image-digest'
+	Uploading 'canonical-registry.io/canonical-repo/run@sha256:another-run-image-digest'
+Importing ClusterStack 'default'...
+Uploading to 'canonical-registry.io/canonical-repo'...
+	Uploading 'canonical-registry.io/canonical-repo/build@sha256:another-build-image-digest'
+	Uploading 'canonical-registry.io/canonical-repo/run@sha256:another-run-image-digest'
+Importing ClusterBuilder 'clusterbuilder-name'...
+Importing ClusterBuilder 'default'...
 `,
 					ExpectUpdates: []clientgotesting.UpdateActionImpl{
 						{Object: expectedLifecycleImageConfig},
@@ -752,6 +867,18 @@ Imported resources
 		})
 	})
 
+	it("errors when the summary flag is used without the output flag", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{kpConfig},
+			Args: []string{
+				"-f", "./testdata/deps.yaml",
+				"--summary",
+			},
+			ExpectedOutput: "Error: --summary can only be used with --output\n",
+			ExpectErr:      true,
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
 	it("errors when the descriptor apiVersion is unexpected", func() {
 		testhelpers.CommandTest{
 			Objects: []runtime.Object{kpConfig},
@@ -1468,6 +1595,240 @@ Importing ClusterBuilder 'default'... (dry run with image upload)
 			})
 		})
 	})
+
+	when("the file contains kpack resources", func() {
+		makeResources := func() (*v1alpha1.ClusterStore, *v1alpha1.ClusterStack, *v1alpha1.ClusterBuilder, *v1alpha1.Builder) {
+			order := []v1alpha1.OrderEntry{
+				{
+					Group: []v1alpha1.BuildpackRef{
+						{
+							BuildpackInfo: v1alpha1.BuildpackInfo{
+								Id: "buildpack-id",
+							},
+						},
+					},
+				},
+			}
+
+			resourceStore := &v1alpha1.ClusterStore{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       v1alpha1.ClusterStoreKind,
+					APIVersion: "kpack.io/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "store-name",
+				},
+				Spec: v1alpha1.ClusterStoreSpec{
+					Sources: []v1alpha1.StoreImage{
+						{Image: "some-registry.io/repo/buildpack-image"},
+					},
+				},
+			}
+
+			resourceStack := &v1alpha1.ClusterStack{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       v1alpha1.ClusterStackKind,
+					APIVersion: "kpack.io/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "stack-name",
+				},
+				Spec: v1alpha1.ClusterStackSpec{
+					Id: "stack-id",
+					BuildImage: v1alpha1.ClusterStackSpecImage{
+						Image: "some-registry.io/repo/build-image",
+					},
+					RunImage: v1alpha1.ClusterStackSpecImage{
+						Image: "some-registry.io/repo/run-image",
+					},
+				},
+			}
+
+			resourceClusterBuilder := &v1alpha1.ClusterBuilder{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       v1alpha1.ClusterBuilderKind,
+					APIVersion: "kpack.io/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "clusterbuilder-name",
+				},
+				Spec: v1alpha1.ClusterBuilderSpec{
+					BuilderSpec: v1alpha1.BuilderSpec{
+						Tag: "some-registry.io/repo/clusterbuilder-name",
+						Stack: corev1.ObjectReference{
+							Name: "stack-name",
+							Kind: v1alpha1.ClusterStackKind,
+						},
+						Store: corev1.ObjectReference{
+							Name: "store-name",
+							Kind: v1alpha1.ClusterStoreKind,
+						},
+						Order: order,
+					},
+					ServiceAccountRef: corev1.ObjectReference{
+						Namespace: "kpack",
+						Name:      "some-serviceaccount",
+					},
+				},
+			}
+
+			resourceBuilder := &v1alpha1.Builder{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       v1alpha1.BuilderKind,
+					APIVersion: "kpack.io/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "builder-name",
+					Namespace: "some-namespace",
+				},
+				Spec: v1alpha1.NamespacedBuilderSpec{
+					BuilderSpec: v1alpha1.BuilderSpec{
+						Tag: "some-registry.io/repo/builder-name",
+						Stack: corev1.ObjectReference{
+							Name: "stack-name",
+							Kind: v1alpha1.ClusterStackKind,
+						},
+						Store: corev1.ObjectReference{
+							Name: "store-name",
+							Kind: v1alpha1.ClusterStoreKind,
+						},
+						Order: order,
+					},
+					ServiceAccount: "default",
+				},
+			}
+
+			for _, obj := range []k8s.Annotatable{resourceStore, resourceStack, resourceClusterBuilder, resourceBuilder} {
+				require.NoError(t, k8s.SetLastAppliedCfg(obj))
+			}
+
+			return resourceStore, resourceStack, resourceClusterBuilder, resourceBuilder
+		}
+
+		it("creates the resources and skips unsupported kinds", func() {
+			resourceStore, resourceStack, resourceClusterBuilder, resourceBuilder := makeResources()
+
+			testhelpers.CommandTest{
+				Args: []string{
+					"-f", "./testdata/resources.yaml",
+				},
+				ExpectedOutput: `Importing ClusterStore 'store-name'...
+Importing ClusterStack 'stack-name'...
+Skipping unsupported resource "ConfigMap" with apiVersion "v1" in document 3
+Importing ClusterBuilder 'clusterbuilder-name'...
+Importing Builder 'builder-name'...
+Imported resources
+`,
+				ExpectCreates: []runtime.Object{
+					resourceStore,
+					resourceStack,
+					resourceClusterBuilder,
+					resourceBuilder,
+				},
+			}.TestK8sAndKpack(t, cmdFunc)
+			require.Len(t, fakeWaiter.WaitCalls, 4)
+		})
+
+		it("creates the resources provided by stdin", func() {
+			resourceStore, resourceStack, resourceClusterBuilder, resourceBuilder := makeResources()
+
+			resources, err := ioutil.ReadFile("./testdata/resources.yaml")
+			require.NoError(t, err)
+
+			testhelpers.CommandTest{
+				StdIn: string(resources),
+				Args: []string{
+					"-f", "-",
+				},
+				ExpectedOutput: `Importing ClusterStore 'store-name'...
+Importing ClusterStack 'stack-name'...
+Skipping unsupported resource "ConfigMap" with apiVersion "v1" in document 3
+Importing ClusterBuilder 'clusterbuilder-name'...
+Importing Builder 'builder-name'...
+Imported resources
+`,
+				ExpectCreates: []runtime.Object{
+					resourceStore,
+					resourceStack,
+					resourceClusterBuilder,
+					resourceBuilder,
+				},
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("replaces the spec of existing resources", func() {
+			resourceStore, resourceStack, resourceClusterBuilder, resourceBuilder := makeResources()
+
+			existingStore := resourceStore.DeepCopy()
+			existingStore.Annotations = map[string]string{"some-key": "some-value"}
+			existingStore.Spec.Sources = []v1alpha1.StoreImage{{Image: "some-registry.io/repo/old-buildpack-image"}}
+
+			existingStack := resourceStack.DeepCopy()
+			existingStack.Annotations = nil
+			existingStack.Spec.Id = "old-stack-id"
+
+			expectedStore := resourceStore.DeepCopy()
+			expectedStore.Annotations["some-key"] = "some-value"
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingStore,
+					existingStack,
+				},
+				Args: []string{
+					"-f", "./testdata/resources.yaml",
+				},
+				ExpectedOutput: `Importing ClusterStore 'store-name'...
+Importing ClusterStack 'stack-name'...
+Skipping unsupported resource "ConfigMap" with apiVersion "v1" in document 3
+Importing ClusterBuilder 'clusterbuilder-name'...
+Importing Builder 'builder-name'...
+Imported resources
+`,
+				ExpectUpdates: []clientgotesting.UpdateActionImpl{
+					{
+						Object: expectedStore,
+					},
+					{
+						Object: resourceStack,
+					},
+				},
+				ExpectCreates: []runtime.Object{
+					resourceClusterBuilder,
+					resourceBuilder,
+				},
+			}.TestK8sAndKpack(t, cmdFunc)
+			require.Len(t, fakeWaiter.WaitCalls, 4)
+		})
+
+		it("does not create any resources when dry-run flag is used", func() {
+			testhelpers.CommandTest{
+				Args: []string{
+					"-f", "./testdata/resources.yaml",
+					"--dry-run",
+				},
+				ExpectedOutput: `Importing ClusterStore 'store-name'... (dry run)
+Importing ClusterStack 'stack-name'... (dry run)
+Skipping unsupported resource "ConfigMap" with apiVersion "v1" in document 3
+Importing ClusterBuilder 'clusterbuilder-name'... (dry run)
+Importing Builder 'builder-name'... (dry run)
+Imported resources (dry run)
+`,
+			}.TestK8sAndKpack(t, cmdFunc)
+			require.Len(t, fakeWaiter.WaitCalls, 0)
+		})
+
+		it("errors when show-changes flag is used", func() {
+			testhelpers.CommandTest{
+				Args: []string{
+					"-f", "./testdata/resources.yaml",
+					"--show-changes",
+				},
+				ExpectedOutput: "Error: --show-changes can only be used with a dependency descriptor\n",
+				ExpectErr:      true,
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+	})
 }
 
 type FakeTimestampProvider struct {
@@ -0,0 +1,54 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"bytes"
+	"io"
+
+	"k8s.io/client-go/util/retry"
+)
+
+// RetryOnConflict runs fn again with a backoff while it returns a conflict error, fn must get the
+// resource and recompute its patch on every run. A merge patch only conflicts when it contains the
+// resource version that was read, see k8s.AddResourceVersion, and fn should not repeat side effects
+// such as uploads. The retries are capped by retry.DefaultRetry and the last conflict error is
+// returned once they are exhausted.
+func RetryOnConflict(fn func() error) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, fn)
+}
+
+// ReplayReader reads from r the first time it is read to the end and replays the
+// same content afterwards, so that stdin can be read again when a patch is retried
+type ReplayReader struct {
+	r    io.Reader
+	buf  bytes.Buffer
+	read *bytes.Reader
+}
+
+func NewReplayReader(r io.Reader) *ReplayReader {
+	return &ReplayReader{r: r}
+}
+
+// Source returns the wrapped reader, it is used to check whether stdin is a terminal
+func (r *ReplayReader) Source() io.Reader {
+	return r.r
+}
+
+func (r *ReplayReader) Read(p []byte) (int, error) {
+	if r.read != nil {
+		n, err := r.read.Read(p)
+		if err == io.EOF {
+			r.read = bytes.NewReader(r.buf.Bytes())
+		}
+		return n, err
+	}
+
+	n, err := r.r.Read(p)
+	r.buf.Write(p[:n])
+	if err == io.EOF {
+		r.read = bytes.NewReader(r.buf.Bytes())
+	}
+	return n, err
+}
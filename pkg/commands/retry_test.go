@@ -0,0 +1,75 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+)
+
+func TestRetry(t *testing.T) {
+	spec.Run(t, "TestRetry", testRetry)
+}
+
+func testRetry(t *testing.T, when spec.G, it spec.S) {
+	conflict := k8serrors.NewConflict(schema.GroupResource{Resource: "images"}, "some-image", errors.New("the object has been modified"))
+
+	when("RetryOnConflict", func() {
+		it("retries until there is no conflict", func() {
+			calls := 0
+			err := commands.RetryOnConflict(func() error {
+				calls++
+				if calls < 3 {
+					return conflict
+				}
+				return nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, 3, calls)
+		})
+
+		it("does not retry other errors", func() {
+			calls := 0
+			err := commands.RetryOnConflict(func() error {
+				calls++
+				return errors.New("some-error")
+			})
+			require.EqualError(t, err, "some-error")
+			require.Equal(t, 1, calls)
+		})
+
+		it("returns the conflict once the retries are exhausted", func() {
+			calls := 0
+			err := commands.RetryOnConflict(func() error {
+				calls++
+				return conflict
+			})
+			require.True(t, k8serrors.IsConflict(err))
+			require.Equal(t, 5, calls)
+		})
+	})
+
+	when("ReplayReader", func() {
+		it("replays the content once it has been read to the end", func() {
+			r := commands.NewReplayReader(strings.NewReader("some-content"))
+
+			buf, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, "some-content", string(buf))
+
+			buf, err = ioutil.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, "some-content", string(buf))
+		})
+	})
+}
@@ -4,8 +4,10 @@
 package secret
 
 import (
+	"context"
 	"os"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,13 +19,16 @@ import (
 
 func NewCreateCommand(clientSetProvider k8s.ClientSetProvider, secretFactory *secret.Factory) *cobra.Command {
 	var (
-		namespace string
+		namespace            string
+		serviceAccountName   string
+		dockerConfigJsonFile string
+		metadata             k8s.Metadata
 	)
 
 	cmd := &cobra.Command{
 		Use:   "create <name>",
 		Short: "Create a secret configuration",
-		Long: `Create a secret configuration using registry or git credentials in the provided namespace.
+		Long: `Create a secret configuration using registry or git credentials, or generic key/value pairs in the provided namespace.
 
 The namespace defaults to the kubernetes current-context namespace.
 
@@ -44,12 +49,27 @@ The flags for this command determine the type of secret that will be created:
 
   "--git-url" and "--git-user" to create Basic Auth based git credentials.
   "--git-url" should not contain the repository path (eg. https://github.com not https://github.com/my/repo) 
-  Use the "GIT_PASSWORD" env var to bypass the password prompt.`,
+  Use the "GIT_PASSWORD" env var to bypass the password prompt.
+
+  "--from-literal" and "--from-file" to create a generic secret from key/value pairs.
+  Both flags can be repeated and the key of "--from-file" defaults to the file name.
+
+  "--dockerconfig" to import the registry credentials of an existing docker config file.
+  One secret is created per registry and named "<name>-<registry>", use "--combine" to create a single secret instead.
+
+  "--dockerconfigjson" to create a single "kubernetes.io/dockerconfigjson" secret with all the registry credentials
+  of an existing docker config file, it is the same as "--dockerconfig" with "--combine".
+  The docker config file of both flags must be valid JSON with an "auths" key.
+
+The secrets are added to the "default" service account unless "--service-account" is provided.`,
 		Example: `kp secret create my-docker-hub-creds --dockerhub dockerhub-id
 kp secret create my-gcr-creds --gcr /path/to/gcr/service-account.json
 kp secret create my-registry-cred --registry example-registry.io --registry-user my-registry-user
 kp secret create my-git-ssh-cred --git-url git@github.com --git-ssh-key /path/to/git/ssh-private-key.pem
-kp secret create my-git-cred --git-url https://github.com --git-user my-git-user`,
+kp secret create my-git-cred --git-url https://github.com --git-user my-git-user
+kp secret create my-generic-secret --from-literal api-token=some-token --from-file config.json=/path/to/config.json
+kp secret create my-registry-creds --dockerconfig ~/.docker/config.json --combine
+kp secret create regcred --dockerconfigjson ~/.docker/config.json`,
 		Args:         commands.ExactArgsWithUsage(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -71,42 +91,62 @@ kp secret create my-git-cred --git-url https://github.com --git-user my-git-user
 				secretFactory.GitSshKeyFile = val
 			}
 
-			secret, target, err := secretFactory.MakeSecret(args[0], cs.Namespace)
-			if err != nil {
+			// --dockerconfigjson is a shorthand for --dockerconfig with --combine
+			if dockerConfigJsonFile != "" {
+				if secretFactory.DockerConfigFile != "" {
+					return errors.New("--dockerconfigjson cannot be used with --dockerconfig")
+				}
+				secretFactory.DockerConfigFile = dockerConfigJsonFile
+				secretFactory.Combine = true
+			}
+
+			if err := metadata.Validate(); err != nil {
 				return err
 			}
 
-			ctx := cmd.Context()
+			secrets, targets, err := secretFactory.MakeSecrets(args[0], cs.Namespace)
+			if err != nil {
+				return err
+			}
 
-			if !ch.IsDryRun() {
-				secret, err = cs.K8sClient.CoreV1().Secrets(cs.Namespace).Create(ctx, secret, metav1.CreateOptions{})
-				if err != nil {
+			for _, secret := range secrets {
+				if err := metadata.Apply(secret); err != nil {
 					return err
 				}
 			}
 
-			if err = ch.PrintObj(secret); err != nil {
-				return err
-			}
+			ctx := cmd.Context()
 
-			serviceAccount, err := cs.K8sClient.CoreV1().ServiceAccounts(cs.Namespace).Get(ctx, "default", metav1.GetOptions{})
+			serviceAccount, err := cs.K8sClient.CoreV1().ServiceAccounts(cs.Namespace).Get(ctx, serviceAccountName, metav1.GetOptions{})
 			if err != nil {
 				return err
 			}
 
-			serviceAccount.Secrets = append(serviceAccount.Secrets, corev1.ObjectReference{Name: args[0]})
+			for i, secret := range secrets {
+				serviceAccount.Secrets = append(serviceAccount.Secrets, corev1.ObjectReference{Name: secret.Name})
 
-			if secret.Type == corev1.SecretTypeDockerConfigJson {
-				serviceAccount.ImagePullSecrets = append(serviceAccount.ImagePullSecrets, corev1.LocalObjectReference{Name: args[0]})
-			}
+				if secret.Type == corev1.SecretTypeDockerConfigJson {
+					serviceAccount.ImagePullSecrets = append(serviceAccount.ImagePullSecrets, corev1.LocalObjectReference{Name: secret.Name})
+				}
 
-			if err = updateManagedSecretsAnnotation(err, serviceAccount, args[0], target); err != nil {
-				return err
+				// generic secrets do not hold credentials for a registry or git server
+				if secret.Type == corev1.SecretTypeOpaque {
+					continue
+				}
+
+				if err = updateManagedSecretsAnnotation(err, serviceAccount, secret.Name, targets[i]); err != nil {
+					return err
+				}
 			}
 
 			if !ch.IsDryRun() {
-				serviceAccount, err = cs.K8sClient.CoreV1().ServiceAccounts(cs.Namespace).Update(ctx, serviceAccount, metav1.UpdateOptions{})
-				if err != nil {
+				if serviceAccount, err = createSecrets(ctx, cs, secrets, serviceAccount); err != nil {
+					return err
+				}
+			}
+
+			for _, secret := range secrets {
+				if err = ch.PrintObj(secret); err != nil {
 					return err
 				}
 			}
@@ -115,7 +155,12 @@ kp secret create my-git-cred --git-url https://github.com --git-user my-git-user
 				return err
 			}
 
-			return ch.PrintResult("Secret %q created", secret.Name)
+			for _, secret := range secrets {
+				if err = ch.PrintResult("Secret %q created", secret.Name); err != nil {
+					return err
+				}
+			}
+			return nil
 		},
 	}
 
@@ -127,10 +172,44 @@ kp secret create my-git-cred --git-url https://github.com --git-user my-git-user
 	cmd.Flags().StringVarP(&secretFactory.GitUrl, "git-url", "", "", "git url")
 	cmd.Flags().StringVarP(&secretFactory.GitSshKeyFile, "git-ssh-key", "", "", "path to a file containing the GitUrl SSH private key")
 	cmd.Flags().StringVarP(&secretFactory.GitUser, "git-user", "", "", "git user")
+	cmd.Flags().StringArrayVar(&secretFactory.FromLiteral, "from-literal", nil, "key and literal value to add to a generic secret (format: KEY=VALUE)")
+	cmd.Flags().StringArrayVar(&secretFactory.FromFile, "from-file", nil, "key and file path to add to a generic secret, the key defaults to the file name (format: KEY=/path/to/file)")
+	cmd.Flags().StringVar(&secretFactory.DockerConfigFile, "dockerconfig", "", "path to a docker config file to import registry credentials from")
+	cmd.Flags().StringVar(&dockerConfigJsonFile, "dockerconfigjson", "", "path to a docker config file to create a single dockerconfigjson secret from, same as --dockerconfig with --combine")
+	cmd.Flags().BoolVar(&secretFactory.Combine, "combine", false, "create a single secret for all the registries of the docker config file")
+	cmd.Flags().StringVar(&serviceAccountName, "service-account", "default", "service account to add the secrets to")
+	commands.SetMetadataFlags(cmd, &metadata)
 	commands.SetDryRunOutputFlags(cmd)
 	return cmd
 }
 
+// createSecrets creates the secrets and updates the service account, the secrets that were created
+// are deleted again when a secret or the service account cannot be written
+func createSecrets(ctx context.Context, cs k8s.ClientSet, secrets []*corev1.Secret, serviceAccount *corev1.ServiceAccount) (*corev1.ServiceAccount, error) {
+	var created []string
+	rollback := func(err error) error {
+		for _, name := range created {
+			_ = cs.K8sClient.CoreV1().Secrets(cs.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		}
+		return err
+	}
+
+	for i, secret := range secrets {
+		s, err := cs.K8sClient.CoreV1().Secrets(cs.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+		if err != nil {
+			return nil, rollback(err)
+		}
+		secrets[i] = s
+		created = append(created, s.Name)
+	}
+
+	serviceAccount, err := cs.K8sClient.CoreV1().ServiceAccounts(cs.Namespace).Update(ctx, serviceAccount, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, rollback(err)
+	}
+	return serviceAccount, nil
+}
+
 func updateManagedSecretsAnnotation(err error, sa *corev1.ServiceAccount, name, target string) error {
 	managedSecrets, err := readManagedSecrets(sa)
 	if err != nil {
@@ -343,6 +343,71 @@ func testSecretCreateCommand(t *testing.T, when spec.G, it spec.S) {
 				}.TestK8s(t, cmdFunc)
 			})
 		})
+
+		when("creating a generic secret", func() {
+			const secretName = "my-generic-secret"
+
+			it("creates an opaque secret from literals and files and adds it to the service account without a managed secret entry", func() {
+				expectedGenericSecret := &corev1.Secret{
+					ObjectMeta: v1.ObjectMeta{
+						Name:      secretName,
+						Namespace: namespace,
+					},
+					Data: map[string][]byte{
+						"api-token":   []byte("some-token"),
+						"api-user":    []byte("some=user"),
+						"ssh-key":     []byte("some git ssh key"),
+						"git-ssh.pem": []byte("some git ssh key"),
+					},
+					Type: corev1.SecretTypeOpaque,
+				}
+
+				expectedServiceAccount := &corev1.ServiceAccount{
+					ObjectMeta: v1.ObjectMeta{
+						Name:      "default",
+						Namespace: namespace,
+					},
+					Secrets: []corev1.ObjectReference{
+						{Name: secretName},
+					},
+				}
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						defaultNamespacedServiceAccount,
+					},
+					Args: []string{
+						secretName,
+						"--from-literal", "api-token=some-token",
+						"--from-literal", "api-user=some=user",
+						"--from-file", "ssh-key=./testdata/git-ssh.pem",
+						"--from-file", "./testdata/git-ssh.pem",
+						"-n", namespace,
+					},
+					ExpectedOutput: `Secret "my-generic-secret" created
+`,
+					ExpectCreates: []runtime.Object{
+						expectedGenericSecret,
+					},
+					ExpectUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: expectedServiceAccount,
+						},
+					},
+				}.TestK8s(t, cmdFunc)
+			})
+
+			it("errors when a key is provided more than once", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						defaultNamespacedServiceAccount,
+					},
+					Args:           []string{secretName, "--from-literal", "api-token=some-token", "--from-file", "api-token=./testdata/git-ssh.pem", "-n", namespace},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: duplicate key \"api-token\"\n",
+				}.TestK8s(t, cmdFunc)
+			})
+		})
 	})
 
 	when("namespace is not provided", func() {
@@ -632,6 +697,395 @@ func testSecretCreateCommand(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("creating secrets from a docker config file", func() {
+		const (
+			dockerConfigFile = "./testdata/docker-config.json"
+			gcrAuth          = `"gcr.io":{"auth":"Z2NyLXVzZXI6Z2NyLXBhc3M="}`
+			dockerhubAuth    = `"https://index.docker.io/v1/":{"username":"docker-user","password":"docker-pass"}`
+		)
+
+		it("creates a secret per registry with credentials and adds them to the service account", func() {
+			expectedGcrSecret := &corev1.Secret{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "my-creds-gcr-io",
+					Namespace: defaultNamespace,
+				},
+				Data: map[string][]byte{
+					corev1.DockerConfigJsonKey: []byte(`{"auths":{` + gcrAuth + `}}`),
+				},
+				Type: corev1.SecretTypeDockerConfigJson,
+			}
+
+			expectedDockerhubSecret := &corev1.Secret{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "my-creds-index-docker-io",
+					Namespace: defaultNamespace,
+				},
+				Data: map[string][]byte{
+					corev1.DockerConfigJsonKey: []byte(`{"auths":{` + dockerhubAuth + `}}`),
+				},
+				Type: corev1.SecretTypeDockerConfigJson,
+			}
+
+			expectedServiceAccount := &corev1.ServiceAccount{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "default",
+					Namespace: defaultNamespace,
+					Annotations: map[string]string{
+						secretcmds.ManagedSecretAnnotationKey: `{"my-creds-gcr-io":"gcr.io","my-creds-index-docker-io":"https://index.docker.io/v1/"}`,
+					},
+				},
+				ImagePullSecrets: []corev1.LocalObjectReference{
+					{Name: "my-creds-gcr-io"},
+					{Name: "my-creds-index-docker-io"},
+				},
+				Secrets: []corev1.ObjectReference{
+					{Name: "my-creds-gcr-io"},
+					{Name: "my-creds-index-docker-io"},
+				},
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					defaultServiceAccount,
+				},
+				Args: []string{"my-creds", "--dockerconfig", dockerConfigFile},
+				ExpectedOutput: `Secret "my-creds-gcr-io" created
+Secret "my-creds-index-docker-io" created
+`,
+				ExpectCreates: []runtime.Object{
+					expectedGcrSecret,
+					expectedDockerhubSecret,
+				},
+				ExpectUpdates: []clientgotesting.UpdateActionImpl{
+					{
+						Object: expectedServiceAccount,
+					},
+				},
+			}.TestK8s(t, cmdFunc)
+		})
+
+		it("creates a single secret with --combine and adds it to the provided service account", func() {
+			builderServiceAccount := &corev1.ServiceAccount{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "builder",
+					Namespace: defaultNamespace,
+				},
+			}
+
+			expectedSecret := &corev1.Secret{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "my-creds",
+					Namespace: defaultNamespace,
+				},
+				Data: map[string][]byte{
+					corev1.DockerConfigJsonKey: []byte(`{"auths":{` + gcrAuth + `,` + dockerhubAuth + `}}`),
+				},
+				Type: corev1.SecretTypeDockerConfigJson,
+			}
+
+			expectedServiceAccount := &corev1.ServiceAccount{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "builder",
+					Namespace: defaultNamespace,
+					Annotations: map[string]string{
+						secretcmds.ManagedSecretAnnotationKey: `{"my-creds":"gcr.io,https://index.docker.io/v1/"}`,
+					},
+				},
+				ImagePullSecrets: []corev1.LocalObjectReference{
+					{Name: "my-creds"},
+				},
+				Secrets: []corev1.ObjectReference{
+					{Name: "my-creds"},
+				},
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					defaultServiceAccount,
+					builderServiceAccount,
+				},
+				Args: []string{"my-creds", "--dockerconfig", dockerConfigFile, "--combine", "--service-account", "builder"},
+				ExpectedOutput: `Secret "my-creds" created
+`,
+				ExpectCreates: []runtime.Object{
+					expectedSecret,
+				},
+				ExpectUpdates: []clientgotesting.UpdateActionImpl{
+					{
+						Object: expectedServiceAccount,
+					},
+				},
+			}.TestK8s(t, cmdFunc)
+		})
+
+		when("a secret or the service account cannot be written", func() {
+			failing := func(verb, resource, name string) func(k8sClient *fake.Clientset) *cobra.Command {
+				return func(k8sClient *fake.Clientset) *cobra.Command {
+					k8sClient.PrependReactor(verb, resource, func(action clientgotesting.Action) (bool, runtime.Object, error) {
+						obj := action.(interface{ GetObject() runtime.Object }).GetObject().(v1.Object)
+						if obj.GetName() != name {
+							return false, nil, nil
+						}
+						return true, nil, errors.New("some-error")
+					})
+					return cmdFunc(k8sClient)
+				}
+			}
+
+			it("deletes the secrets that were created when a later secret cannot be created", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						defaultServiceAccount,
+					},
+					Args:           []string{"my-creds", "--dockerconfig", dockerConfigFile},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: some-error\n",
+					ExpectCreates: []runtime.Object{
+						&corev1.Secret{
+							ObjectMeta: v1.ObjectMeta{Name: "my-creds-gcr-io", Namespace: defaultNamespace},
+							Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{` + gcrAuth + `}}`)},
+							Type:       corev1.SecretTypeDockerConfigJson,
+						},
+						&corev1.Secret{
+							ObjectMeta: v1.ObjectMeta{Name: "my-creds-index-docker-io", Namespace: defaultNamespace},
+							Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{` + dockerhubAuth + `}}`)},
+							Type:       corev1.SecretTypeDockerConfigJson,
+						},
+					},
+					ExpectDeletes: []clientgotesting.DeleteActionImpl{
+						{
+							ActionImpl: clientgotesting.ActionImpl{
+								Namespace: defaultNamespace,
+							},
+							Name: "my-creds-gcr-io",
+						},
+					},
+				}.TestK8s(t, failing("create", "secrets", "my-creds-index-docker-io"))
+			})
+
+			it("deletes the secrets that were created when the service account cannot be updated", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						defaultServiceAccount,
+					},
+					Args:           []string{"my-creds", "--dockerconfig", dockerConfigFile, "--combine"},
+					ExpectErr:      true,
+					ExpectedOutput: "Error: some-error\n",
+					ExpectCreates: []runtime.Object{
+						&corev1.Secret{
+							ObjectMeta: v1.ObjectMeta{Name: "my-creds", Namespace: defaultNamespace},
+							Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{` + gcrAuth + `,` + dockerhubAuth + `}}`)},
+							Type:       corev1.SecretTypeDockerConfigJson,
+						},
+					},
+					ExpectUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &corev1.ServiceAccount{
+								ObjectMeta: v1.ObjectMeta{
+									Name:      "default",
+									Namespace: defaultNamespace,
+									Annotations: map[string]string{
+										secretcmds.ManagedSecretAnnotationKey: `{"my-creds":"gcr.io,https://index.docker.io/v1/"}`,
+									},
+								},
+								ImagePullSecrets: []corev1.LocalObjectReference{{Name: "my-creds"}},
+								Secrets:          []corev1.ObjectReference{{Name: "my-creds"}},
+							},
+						},
+					},
+					ExpectDeletes: []clientgotesting.DeleteActionImpl{
+						{
+							ActionImpl: clientgotesting.ActionImpl{
+								Namespace: defaultNamespace,
+							},
+							Name: "my-creds",
+						},
+					},
+				}.TestK8s(t, failing("update", "serviceaccounts", "default"))
+			})
+		})
+
+		it("returns an error when the docker config file is malformed", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					defaultServiceAccount,
+				},
+				Args:           []string{"my-creds", "--dockerconfig", "./testdata/malformed-docker-config.json"},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: invalid docker config \"./testdata/malformed-docker-config.json\": unexpected end of JSON input\n",
+			}.TestK8s(t, cmdFunc)
+		})
+
+		it("sets the provided labels and annotations on every secret", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					defaultServiceAccount,
+				},
+				Args: []string{"my-creds", "--dockerconfig", dockerConfigFile, "--label", "team=some-team", "--annotation", "description=some description"},
+				ExpectedOutput: `Secret "my-creds-gcr-io" created
+Secret "my-creds-index-docker-io" created
+`,
+				ExpectCreates: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: v1.ObjectMeta{
+							Name:        "my-creds-gcr-io",
+							Namespace:   defaultNamespace,
+							Labels:      map[string]string{"team": "some-team"},
+							Annotations: map[string]string{"description": "some description"},
+						},
+						Data: map[string][]byte{
+							corev1.DockerConfigJsonKey: []byte(`{"auths":{` + gcrAuth + `}}`),
+						},
+						Type: corev1.SecretTypeDockerConfigJson,
+					},
+					&corev1.Secret{
+						ObjectMeta: v1.ObjectMeta{
+							Name:        "my-creds-index-docker-io",
+							Namespace:   defaultNamespace,
+							Labels:      map[string]string{"team": "some-team"},
+							Annotations: map[string]string{"description": "some description"},
+						},
+						Data: map[string][]byte{
+							corev1.DockerConfigJsonKey: []byte(`{"auths":{` + dockerhubAuth + `}}`),
+						},
+						Type: corev1.SecretTypeDockerConfigJson,
+					},
+				},
+				ExpectUpdates: []clientgotesting.UpdateActionImpl{
+					{
+						Object: &corev1.ServiceAccount{
+							ObjectMeta: v1.ObjectMeta{
+								Name:      "default",
+								Namespace: defaultNamespace,
+								Annotations: map[string]string{
+									secretcmds.ManagedSecretAnnotationKey: `{"my-creds-gcr-io":"gcr.io","my-creds-index-docker-io":"https://index.docker.io/v1/"}`,
+								},
+							},
+							ImagePullSecrets: []corev1.LocalObjectReference{
+								{Name: "my-creds-gcr-io"},
+								{Name: "my-creds-index-docker-io"},
+							},
+							Secrets: []corev1.ObjectReference{
+								{Name: "my-creds-gcr-io"},
+								{Name: "my-creds-index-docker-io"},
+							},
+						},
+					},
+				},
+			}.TestK8s(t, cmdFunc)
+		})
+
+		it("returns an error when a label is invalid", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					defaultServiceAccount,
+				},
+				Args:           []string{"my-creds", "--dockerconfig", dockerConfigFile, "--label", "team"},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: invalid label \"team\", expected key=value\n",
+			}.TestK8s(t, cmdFunc)
+		})
+
+		it("returns an error when --combine is used without --dockerconfig", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					defaultServiceAccount,
+				},
+				Args:           []string{"my-creds", "--dockerhub", "my-dockerhub-id", "--combine"},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: parameter combine can only be used with dockerconfig\n",
+			}.TestK8s(t, cmdFunc)
+		})
+	})
+
+	when("creating a secret with --dockerconfigjson", func() {
+		const (
+			dockerConfigFile = "./testdata/docker-config.json"
+			gcrAuth          = `"gcr.io":{"auth":"Z2NyLXVzZXI6Z2NyLXBhc3M="}`
+			dockerhubAuth    = `"https://index.docker.io/v1/":{"username":"docker-user","password":"docker-pass"}`
+		)
+
+		it("creates a single secret with all the registry credentials and adds it to the service account", func() {
+			expectedSecret := &corev1.Secret{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "regcred",
+					Namespace: defaultNamespace,
+				},
+				Data: map[string][]byte{
+					corev1.DockerConfigJsonKey: []byte(`{"auths":{` + gcrAuth + `,` + dockerhubAuth + `}}`),
+				},
+				Type: corev1.SecretTypeDockerConfigJson,
+			}
+
+			expectedServiceAccount := &corev1.ServiceAccount{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "default",
+					Namespace: defaultNamespace,
+					Annotations: map[string]string{
+						secretcmds.ManagedSecretAnnotationKey: `{"regcred":"gcr.io,https://index.docker.io/v1/"}`,
+					},
+				},
+				ImagePullSecrets: []corev1.LocalObjectReference{
+					{Name: "regcred"},
+				},
+				Secrets: []corev1.ObjectReference{
+					{Name: "regcred"},
+				},
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					defaultServiceAccount,
+				},
+				Args: []string{"regcred", "--dockerconfigjson", dockerConfigFile},
+				ExpectedOutput: `Secret "regcred" created
+`,
+				ExpectCreates: []runtime.Object{
+					expectedSecret,
+				},
+				ExpectUpdates: []clientgotesting.UpdateActionImpl{
+					{
+						Object: expectedServiceAccount,
+					},
+				},
+			}.TestK8s(t, cmdFunc)
+		})
+
+		it("returns an error when the docker config file is not valid JSON", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					defaultServiceAccount,
+				},
+				Args:           []string{"regcred", "--dockerconfigjson", "./testdata/malformed-docker-config.json"},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: invalid docker config \"./testdata/malformed-docker-config.json\": unexpected end of JSON input\n",
+			}.TestK8s(t, cmdFunc)
+		})
+
+		it("returns an error when the docker config file does not have an auths key", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					defaultServiceAccount,
+				},
+				Args:           []string{"regcred", "--dockerconfigjson", "./testdata/docker-config-without-auths.json"},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: docker config \"./testdata/docker-config-without-auths.json\" does not contain an \"auths\" key\n",
+			}.TestK8s(t, cmdFunc)
+		})
+
+		it("returns an error when --dockerconfigjson is used with --dockerconfig", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					defaultServiceAccount,
+				},
+				Args:           []string{"regcred", "--dockerconfigjson", dockerConfigFile, "--dockerconfig", dockerConfigFile},
+				ExpectErr:      true,
+				ExpectedOutput: "Error: --dockerconfigjson cannot be used with --dockerconfig\n",
+			}.TestK8s(t, cmdFunc)
+		})
+	})
+
 	when("output flag is used", func() {
 		var (
 			dockerhubId          = "my-dockerhub-id"
@@ -25,9 +25,10 @@ func NewDeleteCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 		Long: `Deletes a specific secret in the provided namespace.
 
 The namespace defaults to the kubernetes current-context namespace.`,
-		Example:      "kp secret delete my-secret",
-		Args:         commands.ExactArgsWithUsage(1),
-		SilenceUsage: true,
+		Example:           "kp secret delete my-secret",
+		Args:              commands.ExactArgsWithUsage(1),
+		ValidArgsFunction: commands.SecretNameCompletion(clientSetProvider),
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet(namespace)
 			if err != nil {
@@ -0,0 +1,267 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+const (
+	kpackNamespace = "kpack"
+
+	statusReady    = "Ready"
+	statusNotReady = "Not Ready"
+	statusNotFound = "Not Found"
+	statusUnknown  = "Unknown"
+)
+
+var kpackDeployments = []string{"kpack-controller", "kpack-webhook"}
+
+type kpackStatus struct {
+	Deployments   []deploymentStatus `json:"deployments"`
+	ClusterStores []resourceStatus   `json:"clusterStores"`
+	ClusterStacks []resourceStatus   `json:"clusterStacks"`
+	Images        imageCounts        `json:"images"`
+}
+
+type deploymentStatus struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Ready    int32  `json:"readyReplicas"`
+	Replicas int32  `json:"replicas"`
+}
+
+type resourceStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+type imageCounts struct {
+	Ready    int `json:"ready"`
+	Building int `json:"building"`
+	NotReady int `json:"notReady"`
+	Unknown  int `json:"unknown"`
+}
+
+func NewStatusCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	var (
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Display the health of the kpack installation",
+		Long: `Prints a summary of the health of the kpack installation.
+
+The summary contains the readiness of the kpack controller and webhook deployments in the "kpack" namespace,
+the Ready condition of each ClusterStore and ClusterStack, and the number of images in all namespaces
+grouped by the status of their Ready condition.
+
+Use "--output json" to print the summary as a JSON document.`,
+		Example:      "kp status\nkp status -o json",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "" && output != k8s.FormatJSON {
+				return errors.Errorf("unsupported output format: %q, supported formats are json", output)
+			}
+
+			cs, err := clientSetProvider.GetClientSet("")
+			if err != nil {
+				return err
+			}
+
+			status, err := getStatus(cmd.Context(), cs)
+			if err != nil {
+				return err
+			}
+
+			if output == k8s.FormatJSON {
+				return printJSON(cmd, status)
+			}
+			return displayStatus(cmd, status)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, commands.OutputFlag, "o", "", "print the summary in the specified format instead of tables; supported formats are: json")
+
+	return cmd
+}
+
+func getStatus(ctx context.Context, cs k8s.ClientSet) (kpackStatus, error) {
+	status := kpackStatus{
+		Deployments:   []deploymentStatus{},
+		ClusterStores: []resourceStatus{},
+		ClusterStacks: []resourceStatus{},
+	}
+
+	for _, name := range kpackDeployments {
+		deployment, err := cs.K8sClient.AppsV1().Deployments(kpackNamespace).Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			status.Deployments = append(status.Deployments, deploymentStatus{Name: name, Status: statusNotFound})
+			continue
+		} else if err != nil {
+			return status, err
+		}
+
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+
+		depStatus := deploymentStatus{
+			Name:     name,
+			Status:   statusNotReady,
+			Ready:    deployment.Status.ReadyReplicas,
+			Replicas: replicas,
+		}
+		if replicas > 0 && deployment.Status.ReadyReplicas >= replicas {
+			depStatus.Status = statusReady
+		}
+		status.Deployments = append(status.Deployments, depStatus)
+	}
+
+	storeList, err := cs.KpackClient.KpackV1alpha1().ClusterStores().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return status, err
+	}
+	for _, store := range storeList.Items {
+		status.ClusterStores = append(status.ClusterStores, resourceStatus{Name: store.Name, Status: getReadyStatus(store.Status.Status)})
+	}
+
+	stackList, err := cs.KpackClient.KpackV1alpha1().ClusterStacks().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return status, err
+	}
+	for _, stack := range stackList.Items {
+		status.ClusterStacks = append(status.ClusterStacks, resourceStatus{Name: stack.Name, Status: getReadyStatus(stack.Status.Status)})
+	}
+
+	for _, resources := range [][]resourceStatus{status.ClusterStores, status.ClusterStacks} {
+		sort.Slice(resources, func(i, j int) bool {
+			return resources[i].Name < resources[j].Name
+		})
+	}
+
+	imageList, err := cs.KpackClient.KpackV1alpha1().Images(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return status, err
+	}
+	for _, img := range imageList.Items {
+		countImage(&status.Images, img)
+	}
+
+	return status, nil
+}
+
+func getReadyStatus(status corev1alpha1.Status) string {
+	cond := status.GetCondition(corev1alpha1.ConditionReady)
+	switch {
+	case cond.IsTrue():
+		return statusReady
+	case cond.IsFalse():
+		return statusNotReady
+	default:
+		return statusUnknown
+	}
+}
+
+func countImage(counts *imageCounts, img v1alpha1.Image) {
+	cond := img.Status.GetCondition(corev1alpha1.ConditionReady)
+	switch {
+	case cond.IsTrue():
+		counts.Ready++
+	case cond.IsFalse():
+		counts.NotReady++
+	case cond.IsUnknown():
+		counts.Building++
+	default:
+		counts.Unknown++
+	}
+}
+
+func printJSON(cmd *cobra.Command, status kpackStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "    "); err != nil {
+		return err
+	}
+	buf.WriteRune('\n')
+
+	_, err = cmd.OutOrStdout().Write(buf.Bytes())
+	return err
+}
+
+func displayStatus(cmd *cobra.Command, status kpackStatus) error {
+	writer, err := commands.NewTableWriter(cmd.OutOrStdout(), "Deployment", "Ready", "Status")
+	if err != nil {
+		return err
+	}
+	for _, d := range status.Deployments {
+		ready := fmt.Sprintf("%d/%d", d.Ready, d.Replicas)
+		if d.Status == statusNotFound {
+			ready = ""
+		}
+		if err := writer.AddRow(d.Name, ready, d.Status); err != nil {
+			return err
+		}
+	}
+	if err := writer.Write(); err != nil {
+		return err
+	}
+
+	for _, section := range []struct {
+		kind      string
+		resources []resourceStatus
+	}{
+		{"ClusterStore", status.ClusterStores},
+		{"ClusterStack", status.ClusterStacks},
+	} {
+		writer, err := commands.NewTableWriter(cmd.OutOrStdout(), section.kind, "Status")
+		if err != nil {
+			return err
+		}
+		for _, r := range section.resources {
+			if err := writer.AddRow(r.Name, r.Status); err != nil {
+				return err
+			}
+		}
+		if err := writer.Write(); err != nil {
+			return err
+		}
+	}
+
+	writer, err = commands.NewTableWriter(cmd.OutOrStdout(), "Images", "Ready", "Building", "Not Ready", "Unknown")
+	if err != nil {
+		return err
+	}
+	if err := writer.AddRow(
+		fmt.Sprint(status.Images.Ready+status.Images.Building+status.Images.NotReady+status.Images.Unknown),
+		fmt.Sprint(status.Images.Ready),
+		fmt.Sprint(status.Images.Building),
+		fmt.Sprint(status.Images.NotReady),
+		fmt.Sprint(status.Images.Unknown),
+	); err != nil {
+		return err
+	}
+	return writer.Write()
+}
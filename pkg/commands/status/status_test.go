@@ -0,0 +1,169 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package status_test
+
+import (
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfakes "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/status"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestStatusCommand(t *testing.T) {
+	spec.Run(t, "TestStatusCommand", testStatusCommand)
+}
+
+func testStatusCommand(t *testing.T, when spec.G, it spec.S) {
+	cmdFunc := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *kpackfakes.Clientset) *cobra.Command {
+		clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClientSet, kpackClientSet)
+		return status.NewStatusCommand(clientSetProvider)
+	}
+
+	conditionStatus := func(status corev1.ConditionStatus) corev1alpha1.Status {
+		return corev1alpha1.Status{
+			Conditions: corev1alpha1.Conditions{
+				{Type: corev1alpha1.ConditionReady, Status: status},
+			},
+		}
+	}
+
+	deployment := func(name string, replicas, ready int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kpack"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: ready},
+		}
+	}
+
+	image := func(name string, status corev1alpha1.Status) *v1alpha1.Image {
+		return &v1alpha1.Image{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "some-namespace"},
+			Status:     v1alpha1.ImageStatus{Status: status},
+		}
+	}
+
+	objects := []runtime.Object{
+		deployment("kpack-controller", 1, 1),
+		deployment("kpack-webhook", 2, 0),
+		&v1alpha1.ClusterStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "some-store"},
+			Status:     v1alpha1.ClusterStoreStatus{Status: conditionStatus(corev1.ConditionTrue)},
+		},
+		&v1alpha1.ClusterStack{
+			ObjectMeta: metav1.ObjectMeta{Name: "some-stack"},
+			Status:     v1alpha1.ClusterStackStatus{Status: conditionStatus(corev1.ConditionTrue)},
+		},
+		&v1alpha1.ClusterStack{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-stack"},
+			Status:     v1alpha1.ClusterStackStatus{Status: conditionStatus(corev1.ConditionFalse)},
+		},
+		image("image-1", conditionStatus(corev1.ConditionTrue)),
+		image("image-2", conditionStatus(corev1.ConditionTrue)),
+		image("image-3", conditionStatus(corev1.ConditionUnknown)),
+		image("image-4", corev1alpha1.Status{}),
+	}
+
+	it("prints the deployments, cluster stores, cluster stacks and image counts", func() {
+		testhelpers.CommandTest{
+			Objects: objects,
+			ExpectedOutput: `DEPLOYMENT          READY    STATUS
+kpack-controller    1/1      Ready
+kpack-webhook       0/2      Not Ready
+
+CLUSTERSTORE    STATUS
+some-store      Ready
+
+CLUSTERSTACK    STATUS
+other-stack     Not Ready
+some-stack      Ready
+
+IMAGES    READY    BUILDING    NOT READY    UNKNOWN
+4         2        1           0            1
+
+`,
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("prints the summary as json", func() {
+		testhelpers.CommandTest{
+			Objects: objects,
+			Args:    []string{"-o", "json"},
+			ExpectedOutput: `{
+    "deployments": [
+        {
+            "name": "kpack-controller",
+            "status": "Ready",
+            "readyReplicas": 1,
+            "replicas": 1
+        },
+        {
+            "name": "kpack-webhook",
+            "status": "Not Ready",
+            "readyReplicas": 0,
+            "replicas": 2
+        }
+    ],
+    "clusterStores": [
+        {
+            "name": "some-store",
+            "status": "Ready"
+        }
+    ],
+    "clusterStacks": [
+        {
+            "name": "other-stack",
+            "status": "Not Ready"
+        },
+        {
+            "name": "some-stack",
+            "status": "Ready"
+        }
+    ],
+    "images": {
+        "ready": 2,
+        "building": 1,
+        "notReady": 0,
+        "unknown": 1
+    }
+}
+`,
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("reports missing deployments as not found", func() {
+		testhelpers.CommandTest{
+			ExpectedOutput: `DEPLOYMENT          READY    STATUS
+kpack-controller             Not Found
+kpack-webhook                Not Found
+
+CLUSTERSTORE    STATUS
+
+CLUSTERSTACK    STATUS
+
+IMAGES    READY    BUILDING    NOT READY    UNKNOWN
+0         0        0           0            0
+
+`,
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("errors for an unsupported output format", func() {
+		testhelpers.CommandTest{
+			Args:           []string{"-o", "yaml"},
+			ExpectErr:      true,
+			ExpectedOutput: "Error: unsupported output format: \"yaml\", supported formats are json\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+}
@@ -12,7 +12,8 @@ import (
 )
 
 type StatusWriter struct {
-	writer *tabwriter.Writer
+	writer    *tabwriter.Writer
+	colorKeys map[string]bool
 }
 
 const StatusWriterTabWidth = 4
@@ -20,10 +21,17 @@ const StatusWriterPadding = 4
 
 func NewStatusWriter(out io.Writer) *StatusWriter {
 	return &StatusWriter{
-		writer: tabwriter.NewWriter(out, 0, StatusWriterTabWidth, StatusWriterPadding, ' ', 0),
+		writer:    tabwriter.NewWriter(out, 0, StatusWriterTabWidth, StatusWriterPadding, ' ', 0),
+		colorKeys: map[string]bool{},
 	}
 }
 
+// ColorizeStatus colors the status values of the items with the key added afterwards, see colorize.
+// Callers only color the status when ColorEnabled reports that the output of the command can be colored.
+func (s *StatusWriter) ColorizeStatus(key string) {
+	s.colorKeys[key] = true
+}
+
 func (s *StatusWriter) AddBlock(header string, items ...string) error {
 	if len(items)%2 != 0 {
 		return errors.Errorf("block must contain an equal number of items")
@@ -42,6 +50,10 @@ func (s *StatusWriter) AddBlock(header string, items ...string) error {
 			value = "--"
 		}
 
+		if s.colorKeys[items[i]] {
+			value = colorize(value)
+		}
+
 		_, err := fmt.Fprintf(s.writer, "%s:\t%s\n", items[i], value)
 		if err != nil {
 			return err
@@ -0,0 +1,129 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const clearScreen = "\033[H\033[2J"
+
+// WatchTable renders the objects and re-renders them on every watch event until the
+// watch is closed or the command is interrupted. Objects are tracked by namespace and
+// name so that modified objects replace their previous version instead of adding rows.
+func WatchTable(ctx context.Context, out io.Writer, objs []runtime.Object, watcher watch.Interface, render func([]runtime.Object) error) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	tracked := &trackedObjects{index: map[string]int{}}
+	for _, obj := range objs {
+		if err := tracked.upsert(obj); err != nil {
+			return err
+		}
+	}
+
+	for {
+		if _, err := io.WriteString(out, clearScreen); err != nil {
+			return err
+		}
+
+		if err := render(tracked.objs); err != nil {
+			return err
+		}
+
+		if err := tracked.next(ctx, signals, watcher); err != nil {
+			if err == errStopWatching {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+var errStopWatching = errors.New("stop watching")
+
+type trackedObjects struct {
+	objs  []runtime.Object
+	index map[string]int
+}
+
+// next blocks until an event changes the tracked objects
+func (t *trackedObjects) next(ctx context.Context, signals <-chan os.Signal, watcher watch.Interface) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return errStopWatching
+		case <-signals:
+			return errStopWatching
+		case e, ok := <-watcher.ResultChan():
+			if !ok {
+				return errStopWatching
+			}
+
+			switch e.Type {
+			case watch.Added, watch.Modified:
+				return t.upsert(e.Object)
+			case watch.Deleted:
+				return t.remove(e.Object)
+			case watch.Error:
+				return k8serrors.FromObject(e.Object)
+			}
+		}
+	}
+}
+
+func (t *trackedObjects) upsert(obj runtime.Object) error {
+	key, err := objectKey(obj)
+	if err != nil {
+		return err
+	}
+
+	if i, ok := t.index[key]; ok {
+		t.objs[i] = obj
+		return nil
+	}
+
+	t.index[key] = len(t.objs)
+	t.objs = append(t.objs, obj)
+	return nil
+}
+
+func (t *trackedObjects) remove(obj runtime.Object) error {
+	key, err := objectKey(obj)
+	if err != nil {
+		return err
+	}
+
+	i, ok := t.index[key]
+	if !ok {
+		return nil
+	}
+
+	t.objs = append(t.objs[:i], t.objs[i+1:]...)
+	delete(t.index, key)
+	for k, v := range t.index {
+		if v > i {
+			t.index[k] = v - 1
+		}
+	}
+	return nil
+}
+
+func objectKey(obj runtime.Object) (string, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", err
+	}
+	return accessor.GetNamespace() + "/" + accessor.GetName(), nil
+}
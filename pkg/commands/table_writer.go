@@ -13,37 +13,140 @@ import (
 )
 
 type TableWriter struct {
-	numColumns int
-	writer     *tabwriter.Writer
+	headers        []string
+	headersWritten bool
+	numColumns     int
+	numWideColumns int
+	wide           bool
+	maxWidth       int
+	colorColumns   map[int]bool
+	writer         *tabwriter.Writer
 }
 
 func NewTableWriter(out io.Writer, headers ...string) (*TableWriter, error) {
+	return NewWideTableWriter(out, false, headers)
+}
+
+// NewWideTableWriter appends the wide headers to the table when wide is true
+func NewWideTableWriter(out io.Writer, wide bool, headers []string, wideHeaders ...string) (*TableWriter, error) {
 	writer := tabwriter.NewWriter(out, 0, 4, 4, ' ', 0)
 
-	_, err := fmt.Fprintln(writer, strings.ToUpper(strings.Join(headers, "\t")))
-	if err != nil {
-		return nil, err
+	if wide {
+		headers = append(append([]string{}, headers...), wideHeaders...)
 	}
 
 	return &TableWriter{
-		numColumns: len(headers),
-		writer:     writer,
+		headers:        headers,
+		numColumns:     len(headers),
+		numWideColumns: len(wideHeaders),
+		wide:           wide,
+		colorColumns:   map[int]bool{},
+		writer:         writer,
 	}, nil
 }
 
+// SetMaxColumnWidth truncates the values of the rows added afterwards to width characters so that a single
+// long value such as an image digest does not push the other columns out of the terminal. Tables are not
+// truncated by default, a width of 0 turns the truncation off again.
+func (w *TableWriter) SetMaxColumnWidth(width int) {
+	w.maxWidth = width
+}
+
+// ColorizeStatusColumn colors the status values of the column with the header, see colorize. Callers
+// only color the table when ColorEnabled reports that the output of the command can be colored
+func (w *TableWriter) ColorizeStatusColumn(header string) {
+	for i, h := range w.headers {
+		if strings.EqualFold(h, header) {
+			w.colorColumns[i] = true
+		}
+	}
+}
+
 func (w *TableWriter) AddRow(columns ...string) error {
 	if len(columns) != w.numColumns {
 		return errors.New("incorrect number of columns for row")
 	}
 
-	_, err := fmt.Fprintln(w.writer, strings.Join(columns, "\t"))
+	if err := w.writeHeaders(); err != nil {
+		return err
+	}
+
+	if w.maxWidth > 0 {
+		columns = truncateColumns(columns, w.maxWidth)
+	}
+
+	_, err := fmt.Fprintln(w.writer, strings.Join(w.colorizeColumns(columns, colorize), "\t"))
 	return err
 }
 
+// AddWideRow adds a row with the wide columns only included when the table is wide
+func (w *TableWriter) AddWideRow(columns []string, wideColumns ...string) error {
+	if len(wideColumns) != w.numWideColumns {
+		return errors.New("incorrect number of wide columns for row")
+	}
+
+	if w.wide {
+		columns = append(append([]string{}, columns...), wideColumns...)
+	}
+	return w.AddRow(columns...)
+}
+
 func (w *TableWriter) Write() error {
+	if err := w.writeHeaders(); err != nil {
+		return err
+	}
+
 	_, err := fmt.Fprintln(w.writer, "")
 	if err != nil {
 		return err
 	}
 	return w.writer.Flush()
 }
+
+// writeHeaders writes the headers before the first row so that the header of a colored column has
+// the same escape sequences as its values, the header itself keeps the default color
+func (w *TableWriter) writeHeaders() error {
+	if w.headersWritten {
+		return nil
+	}
+	w.headersWritten = true
+
+	headers := make([]string, len(w.headers))
+	for i, header := range w.headers {
+		headers[i] = strings.ToUpper(header)
+	}
+
+	_, err := fmt.Fprintln(w.writer, strings.Join(w.colorizeColumns(headers, uncolored), "\t"))
+	return err
+}
+
+// colorizeColumns applies the color function to the values of the colored columns
+func (w *TableWriter) colorizeColumns(columns []string, color func(string) string) []string {
+	if len(w.colorColumns) == 0 {
+		return columns
+	}
+
+	colorized := make([]string, len(columns))
+	for i, column := range columns {
+		if w.colorColumns[i] {
+			column = color(column)
+		}
+		colorized[i] = column
+	}
+	return colorized
+}
+
+const truncatedSuffix = "..."
+
+func truncateColumns(columns []string, width int) []string {
+	truncated := make([]string, len(columns))
+	for i, column := range columns {
+		runes := []rune(column)
+		if len(runes) <= width || width <= len(truncatedSuffix) {
+			truncated[i] = column
+			continue
+		}
+		truncated[i] = string(runes[:width-len(truncatedSuffix)]) + truncatedSuffix
+	}
+	return truncated
+}
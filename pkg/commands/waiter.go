@@ -14,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 	watchTools "k8s.io/client-go/tools/watch"
@@ -23,7 +24,30 @@ import (
 	"knative.dev/pkg/kmeta"
 )
 
-const defaultWaitTimeout = 10 * time.Minute
+const (
+	defaultWaitTimeout = 10 * time.Minute
+
+	// TimeoutExitCode is the exit code used when a resource is not ready before the wait timeout
+	TimeoutExitCode = 124
+)
+
+// TimeoutError is returned when a resource is not ready before the wait timeout
+type TimeoutError struct {
+	msg string
+}
+
+func NewTimeoutError(format string, args ...interface{}) error {
+	return &TimeoutError{msg: fmt.Sprintf(format, args...)}
+}
+
+func (e *TimeoutError) Error() string {
+	return e.msg
+}
+
+func IsTimeoutError(err error) bool {
+	_, ok := errors.Cause(err).(*TimeoutError)
+	return ok
+}
 
 type ResourceWaiter interface {
 	Wait(ctx context.Context, object runtime.Object, extraChecks ...watchTools.ConditionFunc) error
@@ -43,6 +67,14 @@ func NewWaiter(dc dynamic.Interface, timeout time.Duration) *Waiter {
 	return &Waiter{dynamicClient: dc, timeout: timeout}
 }
 
+// WithWaitTimeout returns a copy of a Waiter that uses the provided timeout, other waiters are returned as is
+func WithWaitTimeout(w ResourceWaiter, timeout time.Duration) ResourceWaiter {
+	if waiter, ok := w.(*Waiter); ok && timeout > 0 {
+		return NewWaiter(waiter.dynamicClient, timeout)
+	}
+	return w
+}
+
 func (w *Waiter) Wait(ctx context.Context, ob runtime.Object, extraConditions ...watchTools.ConditionFunc) error {
 	m, ok := ob.(kmeta.OwnerRefable)
 	if !ok {
@@ -65,15 +97,22 @@ func (w *Waiter) wait(ctx context.Context, ob runtime.Object, condition watchToo
 			return errors.New("unexpected type")
 		}
 
+		ctx, cancel := context.WithTimeout(ctx, w.timeout)
+		defer cancel()
+
 		rv := refable.GetObjectMeta().GetResourceVersion()
 		watchOne := newWatchOneWatcher(ctx, refable, w.dynamicClient)
 
-		ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
-		defer cancel()
-		e, err = watchTools.Until(ctx, rv, watchOne, filterErrors(cfs)...)
-		if err != nil {
+		lastEvent, err := watchTools.Until(ctx, rv, watchOne, filterErrors(cfs)...)
+		if err == wait.ErrWaitTimeout {
+			if lastEvent == nil {
+				lastEvent = e
+			}
+			return waitTimeoutError(lastEvent, w.timeout)
+		} else if err != nil {
 			return err
 		}
+		e = lastEvent
 	}
 
 	conditionCheckable, err := eventToDuck(e)
@@ -91,6 +130,20 @@ func (w *Waiter) wait(ctx context.Context, ob runtime.Object, condition watchToo
 	return nil
 }
 
+func waitTimeoutError(e *watch.Event, timeout time.Duration) error {
+	conditionCheckable, err := eventToDuck(e)
+	if err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("timed out after %v waiting for %v %q to be ready", timeout, conditionCheckable.Kind, conditionCheckable.Name)
+	if cond := conditionCheckable.Status.GetCondition(apis.ConditionReady); cond != nil && cond.Message != "" {
+		return NewTimeoutError("%s: %v", msg, cond.Message)
+	}
+
+	return NewTimeoutError("%s", msg)
+}
+
 func runChecks(e watch.Event, cfs []watchTools.ConditionFunc) (bool, error) {
 	for _, cf := range cfs {
 		done, err := cf(e)
@@ -93,6 +93,41 @@ func testWaiter(t *testing.T, when spec.G, it spec.S) {
 			require.NoError(t, waiter.Wait(context.Background(), resourceToWatch))
 		})
 
+		it("returns a timeout error with the last observed condition when the resource never becomes ready", func() {
+			resourceToWatch.Status = v1alpha1.BuilderStatus{
+				Status: conditionReady(corev1.ConditionUnknown, generation),
+			}
+
+			shortWaiter := NewWaiter(dynamicClient, 10*time.Millisecond)
+
+			err := shortWaiter.Wait(context.Background(), resourceToWatch)
+			require.EqualError(t, err, `timed out after 10ms waiting for Builder "some-name" to be ready: some-message`)
+			require.True(t, IsTimeoutError(err))
+		})
+
+		it("uses the timeout provided with WithWaitTimeout", func() {
+			resourceToWatch.Status = v1alpha1.BuilderStatus{
+				Status: conditionReady(corev1.ConditionUnknown, generation),
+			}
+
+			shortWaiter := WithWaitTimeout(waiter, 10*time.Millisecond)
+
+			err := shortWaiter.Wait(context.Background(), resourceToWatch)
+			require.EqualError(t, err, `timed out after 10ms waiting for Builder "some-name" to be ready: some-message`)
+			require.True(t, IsTimeoutError(err))
+		})
+
+		it("stops waiting when the provided context is cancelled", func() {
+			resourceToWatch.Status = v1alpha1.BuilderStatus{
+				Status: conditionReady(corev1.ConditionUnknown, generation),
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			require.Error(t, waiter.Wait(ctx, resourceToWatch))
+		})
+
 		it("runs extra condition checks", func() {
 			fakeConditionChecker := fakeConditionChecker{}
 			resourceToWatch.Status = v1alpha1.BuilderStatus{
@@ -0,0 +1,126 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+const (
+	ConfigPathEnv = "KP_CONFIG_PATH"
+
+	DefaultNamespaceKey      = "default-namespace"
+	DefaultServiceAccountKey = "default-service-account"
+	DefaultStackKey          = "default-stack"
+	DefaultStoreKey          = "default-store"
+	OutputFormatKey          = "output-format"
+)
+
+type CLIConfig struct {
+	DefaultNamespace      string `json:"default-namespace,omitempty"`
+	DefaultServiceAccount string `json:"default-service-account,omitempty"`
+	DefaultStack          string `json:"default-stack,omitempty"`
+	DefaultStore          string `json:"default-store,omitempty"`
+	OutputFormat          string `json:"output-format,omitempty"`
+}
+
+func Keys() []string {
+	keys := []string{
+		DefaultNamespaceKey,
+		DefaultServiceAccountKey,
+		DefaultStackKey,
+		DefaultStoreKey,
+		OutputFormatKey,
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func DefaultCLIConfigPath() (string, error) {
+	if path := os.Getenv(ConfigPathEnv); path != "" {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".kp", "config.yaml"), nil
+}
+
+func LoadCLIConfig(path string) (CLIConfig, error) {
+	var cfg CLIConfig
+	if path == "" {
+		return cfg, nil
+	}
+
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	} else if err != nil {
+		return cfg, err
+	}
+
+	if err := yaml.Unmarshal(buf, &cfg); err != nil {
+		return cfg, errors.Wrapf(err, "failed to parse kp config %q", path)
+	}
+	return cfg, nil
+}
+
+func (c CLIConfig) Save(path string) error {
+	if path == "" {
+		return errors.Errorf("kp config path could not be determined, set the %s environment variable", ConfigPathEnv)
+	}
+
+	buf, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf, 0600)
+}
+
+func (c CLIConfig) Get(key string) (string, error) {
+	field, err := c.field(key)
+	if err != nil {
+		return "", err
+	}
+	return *field, nil
+}
+
+func (c *CLIConfig) Set(key, value string) error {
+	field, err := c.field(key)
+	if err != nil {
+		return err
+	}
+	*field = value
+	return nil
+}
+
+func (c *CLIConfig) field(key string) (*string, error) {
+	switch key {
+	case DefaultNamespaceKey:
+		return &c.DefaultNamespace, nil
+	case DefaultServiceAccountKey:
+		return &c.DefaultServiceAccount, nil
+	case DefaultStackKey:
+		return &c.DefaultStack, nil
+	case DefaultStoreKey:
+		return &c.DefaultStore, nil
+	case OutputFormatKey:
+		return &c.OutputFormat, nil
+	default:
+		return nil, errors.Errorf("unknown config key %q, supported keys are: %s", key, strings.Join(Keys(), ", "))
+	}
+}
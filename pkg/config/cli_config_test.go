@@ -0,0 +1,89 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+)
+
+func TestCLIConfig(t *testing.T) {
+	spec.Run(t, "TestCLIConfig", testCLIConfig)
+}
+
+func testCLIConfig(t *testing.T, when spec.G, it spec.S) {
+	var tempDir string
+
+	it.Before(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "kp-config-test")
+		require.NoError(t, err)
+	})
+
+	it.After(func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	})
+
+	when("the config file does not exist", func() {
+		it("returns an empty config", func() {
+			cfg, err := config.LoadCLIConfig(filepath.Join(tempDir, "missing.yaml"))
+			require.NoError(t, err)
+			require.Equal(t, config.CLIConfig{}, cfg)
+		})
+	})
+
+	it("saves and loads the config", func() {
+		path := filepath.Join(tempDir, "nested", "config.yaml")
+
+		cfg := config.CLIConfig{}
+		require.NoError(t, cfg.Set(config.DefaultNamespaceKey, "some-namespace"))
+		require.NoError(t, cfg.Set(config.OutputFormatKey, "yaml"))
+		require.NoError(t, cfg.Save(path))
+
+		buf, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, "default-namespace: some-namespace\noutput-format: yaml\n", string(buf))
+
+		loaded, err := config.LoadCLIConfig(path)
+		require.NoError(t, err)
+		require.Equal(t, cfg, loaded)
+
+		value, err := loaded.Get(config.DefaultNamespaceKey)
+		require.NoError(t, err)
+		require.Equal(t, "some-namespace", value)
+	})
+
+	it("errors on unknown keys", func() {
+		cfg := config.CLIConfig{}
+		err := cfg.Set("unknown", "value")
+		require.EqualError(t, err, `unknown config key "unknown", supported keys are: default-namespace, default-service-account, default-stack, default-store, output-format`)
+	})
+
+	it("errors when the config file cannot be parsed", func() {
+		path := filepath.Join(tempDir, "config.yaml")
+		require.NoError(t, ioutil.WriteFile(path, []byte("not: [valid"), 0600))
+
+		_, err := config.LoadCLIConfig(path)
+		require.Error(t, err)
+	})
+
+	when("KP_CONFIG_PATH is set", func() {
+		it("is used as the config path", func() {
+			path := filepath.Join(tempDir, "custom.yaml")
+			require.NoError(t, os.Setenv(config.ConfigPathEnv, path))
+			defer os.Unsetenv(config.ConfigPathEnv)
+
+			configPath, err := config.DefaultCLIConfigPath()
+			require.NoError(t, err)
+			require.Equal(t, path, configPath)
+		})
+	})
+}
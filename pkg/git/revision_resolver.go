@@ -0,0 +1,139 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/secret"
+)
+
+var commitSHA = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// RevisionResolver resolves branches and tags with git ls-remote using the git
+// secrets of the image service account
+type RevisionResolver struct {
+	k8sClient kubernetes.Interface
+}
+
+func NewRevisionResolver(k8sClient kubernetes.Interface) *RevisionResolver {
+	return &RevisionResolver{k8sClient: k8sClient}
+}
+
+func (r *RevisionResolver) Resolve(namespace, serviceAccount, repo, revision string) (string, error) {
+	if commitSHA.MatchString(revision) {
+		return revision, nil
+	}
+
+	remote, env, cleanup, err := r.remoteWithCredentials(context.Background(), namespace, serviceAccount, repo)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", "ls-remote", remote, revision)
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", errors.Errorf("failed to list refs of %q: %s", repo, strings.TrimSpace(stderr.String()))
+	}
+
+	return matchRevision(stdout.String(), revision)
+}
+
+// matchRevision prefers branches over tags and the commit of an annotated tag over the tag object
+func matchRevision(lsRemoteOutput, revision string) (string, error) {
+	refs := map[string]string{}
+	for _, line := range strings.Split(lsRemoteOutput, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		refs[fields[1]] = fields[0]
+	}
+
+	for _, ref := range []string{
+		"refs/heads/" + revision,
+		"refs/tags/" + revision + "^{}",
+		"refs/tags/" + revision,
+		revision,
+	} {
+		if sha, ok := refs[ref]; ok {
+			return sha, nil
+		}
+	}
+
+	return "", errors.Errorf("revision %q not found", revision)
+}
+
+func (r *RevisionResolver) remoteWithCredentials(ctx context.Context, namespace, serviceAccount, repo string) (string, []string, func(), error) {
+	noop := func() {}
+
+	sa, err := r.k8sClient.CoreV1().ServiceAccounts(namespace).Get(ctx, serviceAccount, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return repo, nil, noop, nil
+	} else if err != nil {
+		return "", nil, noop, err
+	}
+
+	for _, ref := range sa.Secrets {
+		s, err := r.k8sClient.CoreV1().Secrets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return "", nil, noop, err
+		}
+
+		gitURL := s.Annotations[secret.GitAnnotation]
+		if gitURL == "" || !strings.HasPrefix(repo, gitURL) {
+			continue
+		}
+
+		switch s.Type {
+		case corev1.SecretTypeBasicAuth:
+			u, err := url.Parse(repo)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+				continue
+			}
+			u.User = url.UserPassword(string(s.Data[corev1.BasicAuthUsernameKey]), string(s.Data[corev1.BasicAuthPasswordKey]))
+			return u.String(), nil, noop, nil
+		case corev1.SecretTypeSSHAuth:
+			keyFile, err := ioutil.TempFile("", "kp-git-key")
+			if err != nil {
+				return "", nil, noop, err
+			}
+			cleanup := func() { _ = os.Remove(keyFile.Name()) }
+
+			_, err = keyFile.Write(s.Data[corev1.SSHAuthPrivateKey])
+			if closeErr := keyFile.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				cleanup()
+				return "", nil, noop, err
+			}
+
+			sshCommand := fmt.Sprintf("GIT_SSH_COMMAND=ssh -i %s -o IdentitiesOnly=yes -o StrictHostKeyChecking=no", keyFile.Name())
+			return repo, []string{sshCommand}, cleanup, nil
+		}
+	}
+
+	return repo, nil, noop, nil
+}
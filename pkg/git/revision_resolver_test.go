@@ -0,0 +1,56 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevisionResolver(t *testing.T) {
+	spec.Run(t, "TestRevisionResolver", testRevisionResolver)
+}
+
+func testRevisionResolver(t *testing.T, when spec.G, it spec.S) {
+	const lsRemoteOutput = `1111111111111111111111111111111111111111	refs/heads/main
+2222222222222222222222222222222222222222	refs/tags/main
+3333333333333333333333333333333333333333	refs/tags/v1.0.0
+4444444444444444444444444444444444444444	refs/tags/v1.0.0^{}
+5555555555555555555555555555555555555555	refs/tags/v0.9.0
+`
+
+	when("matching a revision", func() {
+		it("prefers branches over tags", func() {
+			sha, err := matchRevision(lsRemoteOutput, "main")
+			require.NoError(t, err)
+			require.Equal(t, "1111111111111111111111111111111111111111", sha)
+		})
+
+		it("uses the commit of an annotated tag", func() {
+			sha, err := matchRevision(lsRemoteOutput, "v1.0.0")
+			require.NoError(t, err)
+			require.Equal(t, "4444444444444444444444444444444444444444", sha)
+		})
+
+		it("uses the commit of a lightweight tag", func() {
+			sha, err := matchRevision(lsRemoteOutput, "v0.9.0")
+			require.NoError(t, err)
+			require.Equal(t, "5555555555555555555555555555555555555555", sha)
+		})
+
+		it("errors when the revision does not exist", func() {
+			_, err := matchRevision(lsRemoteOutput, "some-branch")
+			require.EqualError(t, err, `revision "some-branch" not found`)
+		})
+	})
+
+	it("does not contact the remote for a commit sha", func() {
+		const sha = "abcdefabcdefabcdefabcdefabcdefabcdefabcd"
+		resolved, err := NewRevisionResolver(nil).Resolve("some-namespace", "default", "https://some-git-url", sha)
+		require.NoError(t, err)
+		require.Equal(t, sha, resolved)
+	})
+}
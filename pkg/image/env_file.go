@@ -0,0 +1,67 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func readEnvFile(path string, stdin io.Reader) ([]corev1.EnvVar, error) {
+	var (
+		file io.ReadCloser
+		err  error
+	)
+
+	if path == "-" {
+		if stdin == nil {
+			return nil, errors.New("stdin is not available for --env-file -")
+		}
+		file = ioutil.NopCloser(stdin)
+	} else {
+		file, err = os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer file.Close()
+
+	var envVars []corev1.EnvVar
+
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		idx := strings.Index(line, "=")
+		if idx <= 0 {
+			return nil, errors.Errorf("env file %q is improperly formatted on line %d", path, lineNum)
+		}
+
+		envVars = upsertEnvVar(envVars, corev1.EnvVar{
+			Name:  strings.TrimSpace(line[:idx]),
+			Value: line[idx+1:],
+		})
+	}
+
+	return envVars, scanner.Err()
+}
+
+func upsertEnvVar(envVars []corev1.EnvVar, envVar corev1.EnvVar) []corev1.EnvVar {
+	for i, e := range envVars {
+		if e.Name == envVar.Name {
+			envVars[i].Value = envVar.Value
+			return envVars
+		}
+	}
+	return append(envVars, envVar)
+}
@@ -5,6 +5,7 @@ package image
 
 import (
 	"io"
+	"os"
 	"sort"
 	"strings"
 
@@ -15,10 +16,14 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/archive"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 )
 
 const (
-	defaultRevision = "main"
+	defaultRevision       = "main"
+	defaultServiceAccount = "default"
 )
 
 var (
@@ -26,7 +31,7 @@ var (
 )
 
 type SourceUploader interface {
-	Upload(keychain authn.Keychain, ref, path string) (string, error)
+	Upload(keychain authn.Keychain, ref, path string, filter archive.FileFilter) (string, error)
 }
 
 type Printer interface {
@@ -36,18 +41,53 @@ type Printer interface {
 }
 
 type Factory struct {
-	SourceUploader SourceUploader
-	GitRepo        string
-	GitRevision    string
-	Blob           string
-	LocalPath      string
-	SubPath        *string
-	Builder        string
-	ClusterBuilder string
-	Env            []string
-	CacheSize      string
-	DeleteEnv      []string
-	Printer        Printer
+	SourceUploader           SourceUploader
+	RevisionResolver         RevisionResolver
+	GitRepo                  string
+	GitRevision              string
+	PinRevision              bool
+	Blob                     string
+	BlobAuthSecret           string
+	LocalPath                string
+	ProjectDescriptor        string
+	Exclude                  []string
+	Verbose                  bool
+	SubPath                  *string
+	SourceRevision           string
+	SourceSubPath            *string
+	RevertSource             *v1alpha1.SourceConfig
+	Builder                  string
+	ClusterBuilder           string
+	ServiceAccount           string
+	Env                      []string
+	EnvFile                  string
+	Stdin                    io.Reader
+	CacheSize                string
+	AllowCacheShrink         bool
+	DeleteEnv                []string
+	Bindings                 []string
+	DeleteBindings           []string
+	BuildResources           BuildResources
+	SuccessBuildHistoryLimit *int64
+	FailedBuildHistoryLimit  *int64
+	Metadata                 k8s.Metadata
+	Printer                  Printer
+
+	envFileVars []corev1.EnvVar
+	envFileRead bool
+
+	descriptor     *projectDescriptor
+	descriptorRead bool
+
+	// uploadedSource is reused when a patch is computed again after a conflict
+	uploadedSource string
+}
+
+type BuildResources struct {
+	RequestCPU    *string
+	RequestMemory *string
+	LimitCPU      *string
+	LimitMemory   *string
 }
 
 func (f *Factory) MakeImage(name, namespace, tag string) (*v1alpha1.Image, error) {
@@ -71,9 +111,19 @@ func (f *Factory) MakeImage(name, namespace, tag string) (*v1alpha1.Image, error
 		return nil, err
 	}
 
+	resources, err := f.makeBuildResources(corev1.ResourceRequirements{})
+	if err != nil {
+		return nil, err
+	}
+
+	bindings, err := f.makeBindings()
+	if err != nil {
+		return nil, err
+	}
+
 	builder := f.makeBuilder(namespace)
 
-	return &v1alpha1.Image{
+	img := &v1alpha1.Image{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Image",
 			APIVersion: v1alpha1.SchemeGroupVersion.String(),
@@ -85,14 +135,28 @@ func (f *Factory) MakeImage(name, namespace, tag string) (*v1alpha1.Image, error
 		Spec: v1alpha1.ImageSpec{
 			Tag:            tag,
 			Builder:        builder,
-			ServiceAccount: "default",
+			ServiceAccount: f.makeServiceAccount(),
 			Source:         source,
 			Build: &v1alpha1.ImageBuild{
-				Env: envVars,
+				Env:       envVars,
+				Bindings:  bindings,
+				Resources: resources,
 			},
-			CacheSize: cacheSize,
+			CacheSize:                cacheSize,
+			SuccessBuildHistoryLimit: f.SuccessBuildHistoryLimit,
+			FailedBuildHistoryLimit:  f.FailedBuildHistoryLimit,
 		},
-	}, nil
+	}
+
+	if err := f.Metadata.Apply(img); err != nil {
+		return nil, err
+	}
+
+	if err := f.pinRevision(img); err != nil {
+		return nil, err
+	}
+
+	return img, nil
 }
 
 func (f *Factory) validateCreate() error {
@@ -113,24 +177,215 @@ func (f *Factory) validateCreate() error {
 		return errors.New("must provide one of builder or cluster-builder")
 	}
 
+	if len(f.Exclude) > 0 && f.LocalPath == "" {
+		return errors.New("exclude can only be used with local-path")
+	}
+
+	envVars, err := f.makeEnvVars()
+	if err != nil {
+		return err
+	}
+
+	if err := f.validateDeleteEnv(nil, envVars); err != nil {
+		return err
+	}
+
+	if err := f.Metadata.Validate(); err != nil {
+		return err
+	}
+
+	return f.validateBuildHistoryLimits()
+}
+
+// validateDeleteEnv rejects variables that are both set and deleted and warns about
+// variables that cannot be deleted because they do not exist
+func (f *Factory) validateDeleteEnv(existing, envVars []corev1.EnvVar) error {
+	for _, varName := range f.DeleteEnv {
+		for _, envVar := range envVars {
+			if envVar.Name == varName {
+				return errors.Errorf("duplicate delete-env and env-var parameter '%s'", varName)
+			}
+		}
+
+		found := false
+		for _, envVar := range existing {
+			if envVar.Name == varName {
+				found = true
+				break
+			}
+		}
+
+		if !found && f.Printer != nil {
+			if err := f.Printer.Printlnf("Warning: delete-env parameter '%s' not found in existing image configuration", varName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *Factory) validateBuildHistoryLimits() error {
+	if f.SuccessBuildHistoryLimit != nil && *f.SuccessBuildHistoryLimit < 0 {
+		return errors.New("success-build-history-limit must be 0 or greater")
+	}
+
+	if f.FailedBuildHistoryLimit != nil && *f.FailedBuildHistoryLimit < 0 {
+		return errors.New("failed-build-history-limit must be 0 or greater")
+	}
+
 	return nil
 }
 
 func (f *Factory) makeEnvVars() ([]corev1.EnvVar, error) {
 	var envVars []corev1.EnvVar
+
+	descriptor, err := f.loadProjectDescriptor()
+	if err != nil {
+		return nil, err
+	}
+	if descriptor != nil {
+		envVars = descriptor.envVars()
+	}
+
+	if f.EnvFile != "" {
+		// the env file is only read once so that stdin can be used during both validation and patching
+		if !f.envFileRead {
+			f.envFileVars, err = readEnvFile(f.EnvFile, f.Stdin)
+			if err != nil {
+				return nil, err
+			}
+			f.envFileRead = true
+		}
+		for _, e := range f.envFileVars {
+			envVars = upsertEnvVar(envVars, e)
+		}
+	}
+
 	for _, e := range f.Env {
-		idx := strings.Index(e, "=")
-		if idx == -1 {
-			return nil, errors.Errorf("env vars are improperly formatted")
+		envVar, err := parseEnvVar(e)
+		if err != nil {
+			return nil, err
 		}
-		envVars = append(envVars, corev1.EnvVar{
-			Name:  e[:idx],
-			Value: e[idx+1:],
-		})
+		envVars = upsertEnvVar(envVars, envVar)
 	}
 	return envVars, nil
 }
 
+// parseEnvVar splits an env var on the first '=' only so that the value is kept verbatim
+func parseEnvVar(e string) (corev1.EnvVar, error) {
+	idx := strings.Index(e, "=")
+	if idx <= 0 {
+		return corev1.EnvVar{}, errors.Errorf("env var %q is improperly formatted, must be NAME=VALUE", e)
+	}
+	return corev1.EnvVar{
+		Name:  e[:idx],
+		Value: e[idx+1:],
+	}, nil
+}
+
+func (f *Factory) makeBindings() (v1alpha1.Bindings, error) {
+	var bindings v1alpha1.Bindings
+	for _, b := range f.Bindings {
+		binding, err := ParseBinding(b)
+		if err != nil {
+			return nil, err
+		}
+		bindings = upsertBinding(bindings, binding)
+	}
+	return bindings, nil
+}
+
+// ParseBinding parses a service binding flag of the form "name" or "name=secret-name".
+// The metadata config map of the binding always has the binding name.
+func ParseBinding(b string) (v1alpha1.Binding, error) {
+	name, secret := b, b
+	if idx := strings.Index(b, "="); idx != -1 {
+		name, secret = b[:idx], b[idx+1:]
+	}
+
+	if name == "" || secret == "" {
+		return v1alpha1.Binding{}, errors.Errorf("service binding %q is improperly formatted, must be NAME or NAME=SECRET", b)
+	}
+
+	return v1alpha1.Binding{
+		Name:        name,
+		MetadataRef: &corev1.LocalObjectReference{Name: name},
+		SecretRef:   &corev1.LocalObjectReference{Name: secret},
+	}, nil
+}
+
+func upsertBinding(bindings v1alpha1.Bindings, binding v1alpha1.Binding) v1alpha1.Bindings {
+	for i, b := range bindings {
+		if b.Name == binding.Name {
+			bindings[i] = binding
+			return bindings
+		}
+	}
+	return append(bindings, binding)
+}
+
+func (f *Factory) makeBuildResources(resources corev1.ResourceRequirements) (corev1.ResourceRequirements, error) {
+	var err error
+	resources.Requests, err = setResourceQuantity(resources.Requests, corev1.ResourceCPU, f.BuildResources.RequestCPU, "build-request-cpu")
+	if err != nil {
+		return resources, err
+	}
+
+	resources.Requests, err = setResourceQuantity(resources.Requests, corev1.ResourceMemory, f.BuildResources.RequestMemory, "build-request-memory")
+	if err != nil {
+		return resources, err
+	}
+
+	resources.Limits, err = setResourceQuantity(resources.Limits, corev1.ResourceCPU, f.BuildResources.LimitCPU, "build-limit-cpu")
+	if err != nil {
+		return resources, err
+	}
+
+	resources.Limits, err = setResourceQuantity(resources.Limits, corev1.ResourceMemory, f.BuildResources.LimitMemory, "build-limit-memory")
+	if err != nil {
+		return resources, err
+	}
+
+	for _, r := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		request, hasRequest := resources.Requests[r]
+		limit, hasLimit := resources.Limits[r]
+		if hasRequest && hasLimit && request.Cmp(limit) > 0 {
+			return resources, errors.Errorf("build %s request %s cannot be greater than limit %s", r, request.String(), limit.String())
+		}
+	}
+
+	return resources, nil
+}
+
+func setResourceQuantity(list corev1.ResourceList, name corev1.ResourceName, value *string, flag string) (corev1.ResourceList, error) {
+	if value == nil {
+		return list, nil
+	}
+
+	if *value == "" {
+		delete(list, name)
+		if len(list) == 0 {
+			return nil, nil
+		}
+		return list, nil
+	}
+
+	q, err := resource.ParseQuantity(*value)
+	if err != nil {
+		return list, errors.Errorf("invalid %s %q, must be valid quantity ex. 500m or 1Gi", flag, *value)
+	}
+
+	if q.Sign() <= 0 {
+		return list, errors.Errorf("%s must be greater than 0", flag)
+	}
+
+	if list == nil {
+		list = corev1.ResourceList{}
+	}
+	list[name] = q
+	return list, nil
+}
+
 func (f *Factory) makeCacheSize() (*resource.Quantity, error) {
 	if f.CacheSize == "" {
 		return nil, nil
@@ -142,7 +397,7 @@ func (f *Factory) makeCacheSize() (*resource.Quantity, error) {
 func (f *Factory) getCacheSize() (*resource.Quantity, error) {
 	c, err := resource.ParseQuantity(f.CacheSize)
 	if err != nil {
-		return nil, errors.New("invalid cache size, must be valid quantity ex. 2G")
+		return nil, errors.Errorf("invalid cache size %q, must be valid quantity ex. 2G or 500Mi", f.CacheSize)
 	}
 
 	if c.Sign() <= 0 {
@@ -187,7 +442,12 @@ func (f *Factory) makeSource(tag string) (v1alpha1.SourceConfig, error) {
 			return v1alpha1.SourceConfig{}, err
 		}
 
-		sourceRef, err := f.SourceUploader.Upload(keychain, imgRepo, f.LocalPath)
+		filter, err := f.sourceFileFilter()
+		if err != nil {
+			return v1alpha1.SourceConfig{}, err
+		}
+
+		sourceRef, err := f.SourceUploader.Upload(keychain, imgRepo, f.LocalPath, filter)
 		if err != nil {
 			return v1alpha1.SourceConfig{}, err
 		}
@@ -201,6 +461,53 @@ func (f *Factory) makeSource(tag string) (v1alpha1.SourceConfig, error) {
 	}
 }
 
+// sourceFileFilter combines the project descriptor and ignore patterns for local source uploads
+func (f *Factory) sourceFileFilter() (archive.FileFilter, error) {
+	var filters []archive.FileFilter
+
+	descriptor, err := f.loadProjectDescriptor()
+	if err != nil {
+		return nil, err
+	}
+	if descriptor != nil && descriptor.hasFilter() {
+		filters = append(filters, descriptor.fileFilter())
+	}
+
+	rules, err := f.ignoreRules()
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) > 0 {
+		filters = append(filters, rules.fileFilter())
+	}
+
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	return func(path string, fi os.FileInfo) bool {
+		for _, filter := range filters {
+			if !filter(path, fi) {
+				if f.Verbose && f.Printer != nil {
+					if fi.IsDir() {
+						path += "/"
+					}
+					_ = f.Printer.Printlnf("\tExcluding '%s'", path)
+				}
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func (f *Factory) makeServiceAccount() string {
+	if f.ServiceAccount == "" {
+		return defaultServiceAccount
+	}
+	return f.ServiceAccount
+}
+
 func (f *Factory) makeBuilder(namespace string) corev1.ObjectReference {
 	if f.Builder != "" {
 		return corev1.ObjectReference{
@@ -245,3 +552,12 @@ func (p paramSet) getExtraParamsError(keys ...string) error {
 	sort.Strings(v)
 	return errors.Errorf("extraneous parameters: %s", strings.Join(v, ", "))
 }
+
+func (p paramSet) flags() string {
+	var v []string
+	for k := range p {
+		v = append(v, "--"+k)
+	}
+	sort.Strings(v)
+	return strings.Join(v, ", ")
+}
@@ -4,11 +4,15 @@
 package image_test
 
 import (
+	"fmt"
 	"io/ioutil"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/sclevine/spec"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/image"
@@ -78,6 +82,121 @@ func testImageFactory(t *testing.T, when spec.G, it spec.S) {
 			require.Equal(t, "BP_MAVEN_BUILD_ARGUMENTS", img.Env()[0].Name)
 			require.Equal(t, `"-Dmaven.test.skip=true -Pk8s package"`, img.Env()[0].Value)
 		})
+
+		it("keeps everything after the first equal sign verbatim", func() {
+			factory.Blob = "some-blob"
+			factory.Env = []string{"JAVA_TOOL_OPTIONS=-Dfoo=bar -Dbaz==qux "}
+			img, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.NoError(t, err)
+			require.Equal(t, []corev1.EnvVar{{Name: "JAVA_TOOL_OPTIONS", Value: "-Dfoo=bar -Dbaz==qux "}}, img.Env())
+		})
+	})
+
+	when("an env var has an empty value", func() {
+		it("sets the env var to an empty string", func() {
+			factory.Blob = "some-blob"
+			factory.Env = []string{"FOO="}
+			img, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.NoError(t, err)
+			require.Equal(t, []corev1.EnvVar{{Name: "FOO", Value: ""}}, img.Env())
+		})
+	})
+
+	when("an env var has commas in the value", func() {
+		it("does not split the value", func() {
+			factory.Blob = "some-blob"
+			factory.Env = []string{"BP_JVM_VERSION=11,17", "OTHER=a,b=c"}
+			img, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.NoError(t, err)
+			require.Equal(t, []corev1.EnvVar{
+				{Name: "BP_JVM_VERSION", Value: "11,17"},
+				{Name: "OTHER", Value: "a,b=c"},
+			}, img.Env())
+		})
+	})
+
+	when("an env var does not have an equal sign", func() {
+		it("returns an error naming the env var", func() {
+			factory.Blob = "some-blob"
+			factory.Env = []string{"FOO=bar", "BAR"}
+			_, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.EqualError(t, err, `env var "BAR" is improperly formatted, must be NAME=VALUE`)
+		})
+
+		it("returns an error when the name is empty", func() {
+			factory.Blob = "some-blob"
+			factory.Env = []string{"=bar"}
+			_, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.EqualError(t, err, `env var "=bar" is improperly formatted, must be NAME=VALUE`)
+		})
+	})
+
+	when("an env file is provided", func() {
+		var envFile string
+
+		it.Before(func() {
+			factory.Blob = "some-blob"
+
+			f, err := ioutil.TempFile("", "env-file")
+			require.NoError(t, err)
+			envFile = f.Name()
+			factory.EnvFile = envFile
+		})
+
+		it.After(func() {
+			require.NoError(t, os.Remove(envFile))
+		})
+
+		it("reads env vars and skips comments and blank lines", func() {
+			require.NoError(t, ioutil.WriteFile(envFile, []byte("# a comment\nfoo=bar\n\nbaz=a=b\n"), 0644))
+
+			img, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.NoError(t, err)
+			require.Equal(t, []corev1.EnvVar{
+				{Name: "foo", Value: "bar"},
+				{Name: "baz", Value: "a=b"},
+			}, img.Env())
+		})
+
+		it("prefers values provided with --env", func() {
+			require.NoError(t, ioutil.WriteFile(envFile, []byte("foo=bar\nbaz=qux\n"), 0644))
+			factory.Env = []string{"foo=override"}
+
+			img, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.NoError(t, err)
+			require.Equal(t, []corev1.EnvVar{
+				{Name: "foo", Value: "override"},
+				{Name: "baz", Value: "qux"},
+			}, img.Env())
+		})
+
+		it("uses the last value for keys repeated in the file", func() {
+			require.NoError(t, ioutil.WriteFile(envFile, []byte("foo=bar\nfoo=baz\n"), 0644))
+
+			img, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.NoError(t, err)
+			require.Equal(t, []corev1.EnvVar{
+				{Name: "foo", Value: "baz"},
+			}, img.Env())
+		})
+
+		it("reads env vars from stdin when the path is -", func() {
+			factory.EnvFile = "-"
+			factory.Stdin = strings.NewReader("foo=bar\n")
+
+			img, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.NoError(t, err)
+			require.Equal(t, []corev1.EnvVar{
+				{Name: "foo", Value: "bar"},
+			}, img.Env())
+		})
+
+		it("errors when a line is improperly formatted", func() {
+			require.NoError(t, ioutil.WriteFile(envFile, []byte("foo=bar\nnot-an-env-var\n"), 0644))
+
+			_, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.EqualError(t, err, fmt.Sprintf("env file %q is improperly formatted on line 2", envFile))
+		})
 	})
 
 	when("cache size", func() {
@@ -100,7 +219,7 @@ func testImageFactory(t *testing.T, when spec.G, it spec.S) {
 		it("errors with invalid cache size", func() {
 			factory.CacheSize = "invalid"
 			_, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
-			require.EqualError(t, err, "invalid cache size, must be valid quantity ex. 2G")
+			require.EqualError(t, err, `invalid cache size "invalid", must be valid quantity ex. 2G or 500Mi`)
 		})
 
 		it("errors with non-positive cache size", func() {
@@ -113,4 +232,67 @@ func testImageFactory(t *testing.T, when spec.G, it spec.S) {
 			require.EqualError(t, err, "cache size must be greater than 0")
 		})
 	})
+
+	when("build resources", func() {
+		factory.Blob = "some-blob"
+
+		it("can be set", func() {
+			requestCPU, limitMemory := "500m", "1Gi"
+			factory.BuildResources = image.BuildResources{
+				RequestCPU:  &requestCPU,
+				LimitMemory: &limitMemory,
+			}
+			img, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.NoError(t, err)
+			require.Equal(t, corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			}, img.Spec.Build.Resources)
+		})
+
+		it("errors with an invalid quantity", func() {
+			limitCPU := "lots"
+			factory.BuildResources = image.BuildResources{LimitCPU: &limitCPU}
+			_, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.EqualError(t, err, `invalid build-limit-cpu "lots", must be valid quantity ex. 500m or 1Gi`)
+		})
+
+		it("errors when a request is greater than its limit", func() {
+			requestMemory, limitMemory := "2Gi", "1Gi"
+			factory.BuildResources = image.BuildResources{
+				RequestMemory: &requestMemory,
+				LimitMemory:   &limitMemory,
+			}
+			_, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.EqualError(t, err, "build memory request 2Gi cannot be greater than limit 1Gi")
+		})
+	})
+
+	when("build history limits", func() {
+		factory.Blob = "some-blob"
+
+		it("can be set", func() {
+			successLimit, failedLimit := int64(3), int64(1)
+			factory.SuccessBuildHistoryLimit = &successLimit
+			factory.FailedBuildHistoryLimit = &failedLimit
+			img, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.NoError(t, err)
+			require.Equal(t, &successLimit, img.Spec.SuccessBuildHistoryLimit)
+			require.Equal(t, &failedLimit, img.Spec.FailedBuildHistoryLimit)
+		})
+
+		it("defaults to nil", func() {
+			img, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.NoError(t, err)
+			require.Nil(t, img.Spec.SuccessBuildHistoryLimit)
+			require.Nil(t, img.Spec.FailedBuildHistoryLimit)
+		})
+
+		it("errors if a limit is negative", func() {
+			successLimit := int64(-1)
+			factory.SuccessBuildHistoryLimit = &successLimit
+			_, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.EqualError(t, err, "success-build-history-limit must be 0 or greater")
+		})
+	})
 }
@@ -0,0 +1,180 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/archive"
+)
+
+// ignoreFiles are read in order so that .kpignore patterns take precedence over .gitignore patterns
+var ignoreFiles = []string{".gitignore", ".kpignore"}
+
+type ignorePattern struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+type ignoreRules []*ignorePattern
+
+// excluded follows gitignore semantics where the last matching pattern wins
+func (r ignoreRules) excluded(path string, isDir bool) bool {
+	excluded := false
+	for _, p := range r {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if p.re.MatchString(path) {
+			excluded = !p.negate
+		}
+	}
+	return excluded
+}
+
+func (r ignoreRules) fileFilter() archive.FileFilter {
+	return func(path string, fi os.FileInfo) bool {
+		return !r.excluded(path, fi.IsDir())
+	}
+}
+
+func (f *Factory) ignoreRules() (ignoreRules, error) {
+	var rules ignoreRules
+
+	for _, ignoreFile := range ignoreFiles {
+		p := filepath.Join(f.LocalPath, ignoreFile)
+		if fi, err := os.Stat(p); err == nil && !fi.IsDir() {
+			fileRules, err := readIgnoreFile(p)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, fileRules...)
+		}
+	}
+
+	for _, e := range f.Exclude {
+		pattern, err := parseIgnorePattern(e)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid exclude pattern %q", e)
+		}
+		if pattern != nil {
+			rules = append(rules, pattern)
+		}
+	}
+
+	if len(rules) > 0 {
+		if fi, err := os.Stat(f.LocalPath); err == nil && !fi.IsDir() {
+			return nil, errors.New("exclude patterns require local-path to be a directory")
+		}
+	}
+
+	return rules, nil
+}
+
+func readIgnoreFile(path string) (ignoreRules, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var rules ignoreRules
+
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		pattern, err := parseIgnorePattern(scanner.Text())
+		if err != nil {
+			return nil, errors.Wrapf(err, "ignore file %q has an invalid pattern on line %d", path, lineNum)
+		}
+		if pattern != nil {
+			rules = append(rules, pattern)
+		}
+	}
+
+	return rules, scanner.Err()
+}
+
+// parseIgnorePattern converts a gitignore pattern into a regular expression matched against
+// slash separated paths relative to the local path. Blank lines and comments return nil.
+func parseIgnorePattern(line string) (*ignorePattern, error) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil, nil
+	}
+
+	p := &ignorePattern{}
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	// patterns with a slash are relative to the root, others match at any depth
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return nil, nil
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case strings.HasPrefix(line[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(line[i:], "**") && i+2 == len(line):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(line[i+1:], ']')
+			if end == -1 {
+				expr.WriteString(`\[`)
+				continue
+			}
+			class := line[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(line):
+			i++
+			expr.WriteString(regexp.QuoteMeta(string(line[i])))
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, err
+	}
+	p.re = re
+
+	return p, nil
+}
@@ -4,16 +4,30 @@
 package image
 
 import (
+	"fmt"
+
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 	v1alpha12 "github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 )
 
+// SourceTypeError is returned when a flag that only applies to an existing git source
+// is used with an image that has a different source type
+type SourceTypeError struct {
+	Param      string
+	SourceType string
+}
+
+func (e *SourceTypeError) Error() string {
+	return fmt.Sprintf("%s can only be used with an existing git source, image source is %s", e.Param, e.SourceType)
+}
+
 func (f *Factory) MakePatch(img *v1alpha1.Image) (*v1alpha1.Image, []byte, error) {
 	if img.Spec.Build == nil {
 		img.Spec.Build = &v1alpha1.ImageBuild{}
@@ -26,25 +40,38 @@ func (f *Factory) MakePatch(img *v1alpha1.Image) (*v1alpha1.Image, []byte, error
 
 	patchedImage := img.DeepCopy()
 
-	err = f.setSource(patchedImage)
+	err = f.applyFlags(patchedImage)
 	if err != nil {
 		return patchedImage, nil, err
 	}
 
-	err = f.setCacheSize(patchedImage)
+	patch, err := k8s.CreatePatch(img, patchedImage)
+	return patchedImage, patch, err
+}
+
+func (f *Factory) applyFlags(img *v1alpha1.Image) error {
+	err := f.setSource(img)
 	if err != nil {
-		return patchedImage, nil, err
+		return err
 	}
 
-	err = f.setBuild(patchedImage)
+	err = f.setCacheSize(img)
 	if err != nil {
-		return patchedImage, nil, err
+		return err
 	}
 
-	f.setBuilder(patchedImage)
+	err = f.setBuild(img)
+	if err != nil {
+		return err
+	}
 
-	patch, err := k8s.CreatePatch(img, patchedImage)
-	return patchedImage, patch, err
+	f.setBuilder(img)
+	f.setBuildHistoryLimits(img)
+
+	if err := f.Metadata.Apply(img); err != nil {
+		return err
+	}
+	return f.pinRevision(img)
 }
 
 func (f *Factory) validatePatch(img *v1alpha1.Image) error {
@@ -54,7 +81,7 @@ func (f *Factory) validatePatch(img *v1alpha1.Image) error {
 	sourceSet.add("local-path", f.LocalPath)
 
 	if len(sourceSet) > 1 {
-		return errors.New("image source must be one of git, blob, or local-path")
+		return errors.Errorf("conflicting source flags %s: image source must be one of git, blob, or local-path", sourceSet.flags())
 	}
 
 	if (sourceSet.contains("blob") || sourceSet.contains("local-path")) && f.GitRevision != "" {
@@ -65,6 +92,18 @@ func (f *Factory) validatePatch(img *v1alpha1.Image) error {
 		return errors.New("git-revision is incompatible with existing image source")
 	}
 
+	if f.RevertSource != nil && (len(sourceSet) > 0 || f.GitRevision != "" || f.SourceRevision != "" || f.SubPath != nil || f.SourceSubPath != nil) {
+		return errors.New("revert cannot be used with the git, git-revision, blob, local-path, source-revision, sub-path or source-sub-path flags")
+	}
+
+	if err := f.validateSourceRevision(img, sourceSet); err != nil {
+		return err
+	}
+
+	if err := f.warnSubPathWithoutSource(img, sourceSet); err != nil {
+		return err
+	}
+
 	builderSet := paramSet{}
 	builderSet.add("builder", f.Builder)
 	builderSet.add("cluster-builder", f.ClusterBuilder)
@@ -73,47 +112,115 @@ func (f *Factory) validatePatch(img *v1alpha1.Image) error {
 		return errors.New("must provide one of builder or cluster-builder")
 	}
 
+	if err := f.Metadata.Validate(); err != nil {
+		return err
+	}
+
+	if len(f.Exclude) > 0 && f.LocalPath == "" {
+		return errors.New("exclude can only be used with local-path")
+	}
+
 	envVars, err := f.makeEnvVars()
 	if err != nil {
 		return err
 	}
 
-	for _, varName := range f.DeleteEnv {
+	if err := f.validateDeleteEnv(img.Spec.Build.Env, envVars); err != nil {
+		return err
+	}
+
+	for _, bindingName := range f.DeleteBindings {
 		found := false
 
-		for _, envVar := range img.Spec.Build.Env {
-			if envVar.Name == varName {
+		for _, binding := range img.Spec.Build.Bindings {
+			if binding.Name == bindingName {
 				found = true
 				break
 			}
 		}
 
 		if !found {
-			return errors.Errorf("delete-env parameter '%s' not found in existing image configuration", varName)
+			return errors.Errorf("delete-service-binding parameter '%s' not found in existing image configuration", bindingName)
 		}
 
-		found = false
-
-		for _, envVar := range envVars {
-			if envVar.Name == varName {
-				found = true
-				break
+		for _, b := range f.Bindings {
+			if binding, err := ParseBinding(b); err == nil && binding.Name == bindingName {
+				return errors.Errorf("duplicate delete-service-binding and service-binding parameter '%s'", bindingName)
 			}
 		}
+	}
 
-		if found {
-			return errors.Errorf("duplicate delete-env and env-var parameter '%s'", varName)
-		}
+	return f.validateBuildHistoryLimits()
+}
+
+func (f *Factory) validateSourceRevision(img *v1alpha1.Image, sourceSet paramSet) error {
+	if f.SourceRevision == "" && f.SourceSubPath == nil {
+		return nil
 	}
 
-	return nil
+	if len(sourceSet) > 0 {
+		return errors.New("source-revision and source-sub-path cannot be used with git, blob, or local-path")
+	}
+
+	if f.GitRevision != "" || f.SubPath != nil {
+		return errors.New("source-revision and source-sub-path cannot be used with git-revision or sub-path")
+	}
+
+	if img.Spec.Source.Git != nil {
+		return nil
+	}
+
+	param := "source-revision"
+	if f.SourceRevision == "" {
+		param = "source-sub-path"
+	}
+	return &SourceTypeError{Param: param, SourceType: sourceType(img.Spec.Source)}
+}
+
+// warnSubPathWithoutSource warns that a sub path only applies to a git, blob or local path source
+func (f *Factory) warnSubPathWithoutSource(img *v1alpha1.Image, sourceSet paramSet) error {
+	if f.SubPath == nil || *f.SubPath == "" || len(sourceSet) > 0 {
+		return nil
+	}
+
+	source := img.Spec.Source
+	if source.Git != nil || source.Blob != nil || source.Registry != nil {
+		return nil
+	}
+	return f.Printer.Printlnf("Warning: sub-path '%s' has no effect as the image does not have a git, blob, or local-path source", *f.SubPath)
+}
+
+func sourceType(source v1alpha1.SourceConfig) string {
+	switch {
+	case source.Git != nil:
+		return "git"
+	case source.Blob != nil:
+		return "blob"
+	case source.Registry != nil:
+		return "local-path"
+	default:
+		return "unknown"
+	}
 }
 
 func (f *Factory) setSource(image *v1alpha1.Image) error {
+	if f.RevertSource != nil {
+		image.Spec.Source = *f.RevertSource.DeepCopy()
+		return nil
+	}
+
 	if f.SubPath != nil {
 		image.Spec.Source.SubPath = *f.SubPath
 	}
 
+	if f.SourceSubPath != nil {
+		image.Spec.Source.SubPath = *f.SourceSubPath
+	}
+
+	if f.SourceRevision != "" {
+		image.Spec.Source.Git.Revision = f.SourceRevision
+	}
+
 	if f.GitRepo != "" || f.GitRevision != "" {
 		if f.GitRepo != "" {
 			image.Spec.Source.Blob = nil
@@ -132,12 +239,7 @@ func (f *Factory) setSource(image *v1alpha1.Image) error {
 		image.Spec.Source.Registry = nil
 		image.Spec.Source.Blob = &v1alpha1.Blob{URL: f.Blob}
 	} else if f.LocalPath != "" {
-		ref, err := name.ParseReference(image.Spec.Tag)
-		if err != nil {
-			return err
-		}
-
-		sourceRef, err := f.SourceUploader.Upload(authn.DefaultKeychain, ref.Context().Name()+"-source", f.LocalPath)
+		sourceRef, err := f.uploadSource(image)
 		if err != nil {
 			return err
 		}
@@ -150,18 +252,44 @@ func (f *Factory) setSource(image *v1alpha1.Image) error {
 	return nil
 }
 
+// uploadSource uploads the local source once, the patch of an image that is retried after
+// a conflict uses the source uploaded by the first attempt
+func (f *Factory) uploadSource(image *v1alpha1.Image) (string, error) {
+	if f.uploadedSource != "" {
+		return f.uploadedSource, nil
+	}
+
+	ref, err := name.ParseReference(image.Spec.Tag)
+	if err != nil {
+		return "", err
+	}
+
+	filter, err := f.sourceFileFilter()
+	if err != nil {
+		return "", err
+	}
+
+	f.uploadedSource, err = f.SourceUploader.Upload(authn.DefaultKeychain, ref.Context().Name()+"-source", f.LocalPath, filter)
+	return f.uploadedSource, err
+}
+
 func (f *Factory) setCacheSize(image *v1alpha1.Image) error {
 	if f.CacheSize == "" {
 		return nil
 	}
 
+	if q, err := resource.ParseQuantity(f.CacheSize); err == nil && q.IsZero() {
+		image.Spec.CacheSize = nil
+		return nil
+	}
+
 	c, err := f.getCacheSize()
 	if err != nil {
 		return err
 	}
 
-	if image.Spec.CacheSize != nil && c.Cmp(*image.Spec.CacheSize) < 0 {
-		return errors.Errorf("cache size cannot be decreased, current: %v, requested: %v", image.Spec.CacheSize, c)
+	if !f.AllowCacheShrink && image.Spec.CacheSize != nil && c.Cmp(*image.Spec.CacheSize) < 0 {
+		return errors.Errorf("cache size cannot be decreased, current: %v, requested: %v, use --allow-cache-shrink to decrease it", image.Spec.CacheSize, c)
 	}
 
 	image.Spec.CacheSize = c
@@ -185,22 +313,38 @@ func (f *Factory) setBuild(image *v1alpha1.Image) error {
 	}
 
 	for _, env := range envsToSave {
-		updated := false
+		image.Spec.Build.Env = upsertEnvVar(image.Spec.Build.Env, corev1.EnvVar{Name: env.Name, Value: env.Value})
+	}
 
-		for i, e := range image.Spec.Build.Env {
-			if e.Name == env.Name {
-				image.Spec.Build.Env[i].Value = env.Value
-				updated = true
+	for _, bindingToDelete := range f.DeleteBindings {
+		for i, b := range image.Spec.Build.Bindings {
+			if b.Name == bindingToDelete {
+				image.Spec.Build.Bindings = append(image.Spec.Build.Bindings[:i], image.Spec.Build.Bindings[i+1:]...)
 				break
 			}
 		}
+	}
 
-		if !updated {
-			image.Spec.Build.Env = append(image.Spec.Build.Env, corev1.EnvVar{Name: env.Name, Value: env.Value})
+	for _, b := range f.Bindings {
+		binding, err := ParseBinding(b)
+		if err != nil {
+			return err
 		}
+		image.Spec.Build.Bindings = upsertBinding(image.Spec.Build.Bindings, binding)
 	}
 
-	return nil
+	image.Spec.Build.Resources, err = f.makeBuildResources(image.Spec.Build.Resources)
+	return err
+}
+
+func (f *Factory) setBuildHistoryLimits(image *v1alpha1.Image) {
+	if f.SuccessBuildHistoryLimit != nil {
+		image.Spec.SuccessBuildHistoryLimit = f.SuccessBuildHistoryLimit
+	}
+
+	if f.FailedBuildHistoryLimit != nil {
+		image.Spec.FailedBuildHistoryLimit = f.FailedBuildHistoryLimit
+	}
 }
 
 func (f *Factory) setBuilder(image *v1alpha1.Image) {
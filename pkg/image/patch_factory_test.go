@@ -4,7 +4,12 @@
 package image_test
 
 import (
+	"archive/tar"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
@@ -14,6 +19,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/archive"
 	"github.com/vmware-tanzu/kpack-cli/pkg/image"
 	"github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
 )
@@ -23,8 +29,9 @@ func TestPatchFactory(t *testing.T) {
 }
 
 func testPatchFactory(t *testing.T, when spec.G, it spec.S) {
+	sourceUploader := fakes.NewFakeSourceUploader(ioutil.Discard, true)
 	factory := image.Factory{
-		SourceUploader: fakes.NewFakeSourceUploader(ioutil.Discard, true),
+		SourceUploader: sourceUploader,
 	}
 
 	img := &v1alpha1.Image{
@@ -69,7 +76,14 @@ func testPatchFactory(t *testing.T, when spec.G, it spec.S) {
 			factory.Blob = "some-blob"
 			factory.LocalPath = "some-local-path"
 			_, _, err := factory.MakePatch(img)
-			require.EqualError(t, err, "image source must be one of git, blob, or local-path")
+			require.EqualError(t, err, "conflicting source flags --blob, --git, --local-path: image source must be one of git, blob, or local-path")
+		})
+
+		it("names the conflicting flags", func() {
+			factory.GitRepo = "some-git-repo"
+			factory.Blob = "some-blob"
+			_, _, err := factory.MakePatch(img)
+			require.EqualError(t, err, "conflicting source flags --blob, --git: image source must be one of git, blob, or local-path")
 		})
 	})
 
@@ -109,10 +123,11 @@ func testPatchFactory(t *testing.T, when spec.G, it spec.S) {
 	})
 
 	when("delete-env does not exist in the current image", func() {
-		it("returns an error message", func() {
+		it("does not change the env vars", func() {
 			factory.DeleteEnv = []string{"bar"}
-			_, _, err := factory.MakePatch(img)
-			require.EqualError(t, err, "delete-env parameter 'bar' not found in existing image configuration")
+			_, patch, err := factory.MakePatch(img)
+			require.NoError(t, err)
+			require.Empty(t, patch)
 		})
 	})
 
@@ -133,6 +148,68 @@ func testPatchFactory(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("an env file is provided", func() {
+		it("merges the file env vars with the existing env vars", func() {
+			f, err := ioutil.TempFile("", "env-file")
+			require.NoError(t, err)
+			defer os.Remove(f.Name())
+
+			require.NoError(t, ioutil.WriteFile(f.Name(), []byte("# a comment\nfoo=from-file\nbar=baz\n"), 0644))
+			factory.EnvFile = f.Name()
+
+			_, patch, err := factory.MakePatch(img)
+			require.NoError(t, err)
+			require.Equal(t, `{"spec":{"build":{"env":[{"name":"foo","value":"from-file"},{"name":"bar","value":"baz"}]}}}`, string(patch))
+		})
+	})
+
+	when("patching service bindings", func() {
+		it.Before(func() {
+			img.Spec.Build.Bindings = v1alpha1.Bindings{
+				{
+					Name:        "some-binding",
+					MetadataRef: &corev1.LocalObjectReference{Name: "some-binding"},
+					SecretRef:   &corev1.LocalObjectReference{Name: "some-binding"},
+				},
+			}
+		})
+
+		it("can add and remove bindings", func() {
+			factory.Bindings = []string{"other-binding"}
+			factory.DeleteBindings = []string{"some-binding"}
+			_, patch, err := factory.MakePatch(img)
+			require.NoError(t, err)
+			require.Equal(t, `{"spec":{"build":{"bindings":[{"metadataRef":{"name":"other-binding"},"name":"other-binding","secretRef":{"name":"other-binding"}}]}}}`, string(patch))
+		})
+
+		it("can add a binding to a secret with a different name", func() {
+			factory.Bindings = []string{"other-binding=other-secret"}
+			_, patch, err := factory.MakePatch(img)
+			require.NoError(t, err)
+			require.Equal(t, `{"spec":{"build":{"bindings":[{"metadataRef":{"name":"some-binding"},"name":"some-binding","secretRef":{"name":"some-binding"}},{"metadataRef":{"name":"other-binding"},"name":"other-binding","secretRef":{"name":"other-secret"}}]}}}`, string(patch))
+		})
+
+		it("errors if the same binding is added with a secret name and deleted", func() {
+			factory.Bindings = []string{"some-binding=other-secret"}
+			factory.DeleteBindings = []string{"some-binding"}
+			_, _, err := factory.MakePatch(img)
+			require.EqualError(t, err, "duplicate delete-service-binding and service-binding parameter 'some-binding'")
+		})
+
+		it("errors if the binding to delete does not exist", func() {
+			factory.DeleteBindings = []string{"other-binding"}
+			_, _, err := factory.MakePatch(img)
+			require.EqualError(t, err, "delete-service-binding parameter 'other-binding' not found in existing image configuration")
+		})
+
+		it("errors if the same binding is added and deleted", func() {
+			factory.Bindings = []string{"some-binding"}
+			factory.DeleteBindings = []string{"some-binding"}
+			_, _, err := factory.MakePatch(img)
+			require.EqualError(t, err, "duplicate delete-service-binding and service-binding parameter 'some-binding'")
+		})
+	})
+
 	when("patching cache size", func() {
 		it("can set a new cache size", func() {
 			factory.CacheSize = "3G"
@@ -146,13 +223,359 @@ func testPatchFactory(t *testing.T, when spec.G, it spec.S) {
 			img.Spec.CacheSize = &cache
 			factory.CacheSize = "1G"
 			_, _, err := factory.MakePatch(img)
-			require.EqualError(t, err, "cache size cannot be decreased, current: 2G, requested: 1G")
+			require.EqualError(t, err, "cache size cannot be decreased, current: 2G, requested: 1G, use --allow-cache-shrink to decrease it")
+		})
+
+		it("can decrease the cache size when shrinking is allowed", func() {
+			cache := resource.MustParse("2G")
+			img.Spec.CacheSize = &cache
+			factory.CacheSize = "1G"
+			factory.AllowCacheShrink = true
+			_, patch, err := factory.MakePatch(img)
+			require.NoError(t, err)
+			require.Equal(t, `{"spec":{"cacheSize":"1G"}}`, string(patch))
+		})
+
+		it("removes the cache size when set to 0", func() {
+			cache := resource.MustParse("2G")
+			img.Spec.CacheSize = &cache
+			factory.CacheSize = "0"
+			_, patch, err := factory.MakePatch(img)
+			require.NoError(t, err)
+			require.Equal(t, `{"spec":{"cacheSize":null}}`, string(patch))
 		})
 
 		it("errors if cache size is invalid", func() {
 			factory.CacheSize = "invalid"
 			_, _, err := factory.MakePatch(img)
-			require.EqualError(t, err, "invalid cache size, must be valid quantity ex. 2G")
+			require.EqualError(t, err, `invalid cache size "invalid", must be valid quantity ex. 2G or 500Mi`)
 		})
 	})
+
+	when("patching build resources", func() {
+		it("can set build resources", func() {
+			requestCPU, limitMemory := "500m", "1Gi"
+			factory.BuildResources = image.BuildResources{
+				RequestCPU:  &requestCPU,
+				LimitMemory: &limitMemory,
+			}
+			_, patch, err := factory.MakePatch(img)
+			require.NoError(t, err)
+			require.Equal(t, `{"spec":{"build":{"resources":{"limits":{"memory":"1Gi"},"requests":{"cpu":"500m"}}}}}`, string(patch))
+		})
+
+		it("merges with and clears existing build resources", func() {
+			img.Spec.Build.Resources = corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("250m"),
+					corev1.ResourceMemory: resource.MustParse("512Mi"),
+				},
+			}
+			requestCPU, limitCPU := "", "1"
+			factory.BuildResources = image.BuildResources{
+				RequestCPU: &requestCPU,
+				LimitCPU:   &limitCPU,
+			}
+			patchedImage, patch, err := factory.MakePatch(img)
+			require.NoError(t, err)
+			require.Equal(t, `{"spec":{"build":{"resources":{"limits":{"cpu":"1"},"requests":{"cpu":null}}}}}`, string(patch))
+			require.Equal(t, corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")}, patchedImage.Spec.Build.Resources.Requests)
+		})
+
+		it("errors if the patched request is greater than the existing limit", func() {
+			img.Spec.Build.Resources = corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			}
+			requestCPU := "2"
+			factory.BuildResources = image.BuildResources{RequestCPU: &requestCPU}
+			_, _, err := factory.MakePatch(img)
+			require.EqualError(t, err, "build cpu request 2 cannot be greater than limit 1")
+		})
+	})
+
+	when("patching build history limits", func() {
+		it("sets the limits that were provided", func() {
+			successLimit := int64(3)
+			factory.SuccessBuildHistoryLimit = &successLimit
+			_, patch, err := factory.MakePatch(img)
+			require.NoError(t, err)
+			require.Equal(t, `{"spec":{"successBuildHistoryLimit":3}}`, string(patch))
+		})
+
+		it("can set a limit to 0", func() {
+			existingLimit, failedLimit := int64(5), int64(0)
+			img.Spec.FailedBuildHistoryLimit = &existingLimit
+			factory.FailedBuildHistoryLimit = &failedLimit
+			_, patch, err := factory.MakePatch(img)
+			require.NoError(t, err)
+			require.Equal(t, `{"spec":{"failedBuildHistoryLimit":0}}`, string(patch))
+		})
+
+		it("keeps existing limits that were not provided", func() {
+			existingLimit := int64(5)
+			img.Spec.FailedBuildHistoryLimit = &existingLimit
+			factory.Env = []string{"foo=bar"}
+			patchedImage, _, err := factory.MakePatch(img)
+			require.NoError(t, err)
+			require.Equal(t, &existingLimit, patchedImage.Spec.FailedBuildHistoryLimit)
+			require.Nil(t, patchedImage.Spec.SuccessBuildHistoryLimit)
+		})
+
+		it("errors if a limit is negative", func() {
+			failedLimit := int64(-1)
+			factory.FailedBuildHistoryLimit = &failedLimit
+			_, _, err := factory.MakePatch(img)
+			require.EqualError(t, err, "failed-build-history-limit must be 0 or greater")
+		})
+	})
+
+	when("a local path directory is provided", func() {
+		var localPath string
+
+		writeFile := func(name, content string) {
+			p := filepath.Join(localPath, name)
+			require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+			require.NoError(t, ioutil.WriteFile(p, []byte(content), 0644))
+		}
+
+		includes := func(name string, isDir bool) bool {
+			fi, err := os.Stat(filepath.Join(localPath, name))
+			require.NoError(t, err)
+			require.Equal(t, isDir, fi.IsDir())
+			return sourceUploader.Filter(name, fi)
+		}
+
+		it.Before(func() {
+			var err error
+			localPath, err = ioutil.TempDir("", "local-path")
+			require.NoError(t, err)
+			factory.LocalPath = localPath
+		})
+
+		it.After(func() {
+			require.NoError(t, os.RemoveAll(localPath))
+		})
+
+		when("the local path contains a project descriptor", func() {
+			it("adds the descriptor env vars with lower precedence than --env", func() {
+				writeFile("project.toml", `
+[[build.env]]
+name = "foo"
+value = "descriptor"
+
+[[build.env]]
+name = "bar"
+value = "baz"
+`)
+				factory.Env = []string{"foo=override"}
+
+				patchedImage, _, err := factory.MakePatch(img)
+				require.NoError(t, err)
+				require.Equal(t, []corev1.EnvVar{
+					{Name: "foo", Value: "override"},
+					{Name: "bar", Value: "baz"},
+				}, patchedImage.Spec.Build.Env)
+			})
+
+			it("filters the uploaded source with build.exclude", func() {
+				writeFile("project.toml", `
+[build]
+exclude = ["node_modules", "*.log", "/docs/private"]
+`)
+				writeFile("node_modules/lib/index.js", "")
+				writeFile("src/app.js", "")
+				writeFile("src/debug.log", "")
+				writeFile("docs/private/notes.md", "")
+				writeFile("docs/readme.md", "")
+
+				_, _, err := factory.MakePatch(img)
+				require.NoError(t, err)
+
+				require.False(t, includes("node_modules", true))
+				require.False(t, includes("src/debug.log", false))
+				require.False(t, includes("docs/private", true))
+				require.True(t, includes("src", true))
+				require.True(t, includes("src/app.js", false))
+				require.True(t, includes("docs/readme.md", false))
+			})
+
+			it("filters the uploaded source with build.include", func() {
+				writeFile("project.toml", `
+[build]
+include = ["src", "go.mod"]
+`)
+				writeFile("src/main.go", "")
+				writeFile("go.mod", "")
+				writeFile("test/main_test.go", "")
+
+				_, _, err := factory.MakePatch(img)
+				require.NoError(t, err)
+
+				require.True(t, includes("src/main.go", false))
+				require.True(t, includes("go.mod", false))
+				require.True(t, includes("test", true))
+				require.False(t, includes("test/main_test.go", false))
+				require.False(t, includes("project.toml", false))
+			})
+
+			it("uses the descriptor provided with --project-descriptor", func() {
+				descriptorDir, err := ioutil.TempDir("", "descriptor")
+				require.NoError(t, err)
+				defer os.RemoveAll(descriptorDir)
+
+				writeFile("project.toml", "[[build.env]]\nname = \"foo\"\nvalue = \"ignored\"\n")
+				factory.ProjectDescriptor = filepath.Join(descriptorDir, "other.toml")
+				require.NoError(t, ioutil.WriteFile(factory.ProjectDescriptor, []byte("[[build.env]]\nname = \"foo\"\nvalue = \"bar\"\n"), 0644))
+
+				patchedImage, _, err := factory.MakePatch(img)
+				require.NoError(t, err)
+				require.Equal(t, []corev1.EnvVar{{Name: "foo", Value: "bar"}}, patchedImage.Spec.Build.Env)
+			})
+
+			it("errors with the path when the descriptor cannot be parsed", func() {
+				writeFile("project.toml", "[build\n")
+
+				_, _, err := factory.MakePatch(img)
+				require.Error(t, err)
+				require.Contains(t, err.Error(), fmt.Sprintf("failed to parse project descriptor %q", filepath.Join(localPath, "project.toml")))
+				require.Nil(t, sourceUploader.Filter)
+			})
+
+			it("errors when both build.include and build.exclude are set", func() {
+				writeFile("project.toml", "[build]\ninclude = [\"src\"]\nexclude = [\"test\"]\n")
+
+				_, _, err := factory.MakePatch(img)
+				require.EqualError(t, err, fmt.Sprintf("project descriptor %q cannot contain both build.include and build.exclude", filepath.Join(localPath, "project.toml")))
+			})
+		})
+
+		when("files are excluded with ignore patterns", func() {
+			it("excludes files matching the .kpignore file at any depth", func() {
+				writeFile(".kpignore", `
+# dependencies
+node_modules/
+*.log
+!important.log
+/build
+docs/**/*.tmp
+`)
+				writeFile("node_modules/lib/index.js", "")
+				writeFile("src/node_modules/lib/index.js", "")
+				writeFile("src/debug.log", "")
+				writeFile("src/important.log", "")
+				writeFile("build/app.jar", "")
+				writeFile("src/build/app.go", "")
+				writeFile("docs/a/b/notes.tmp", "")
+				writeFile("docs/readme.md", "")
+
+				_, _, err := factory.MakePatch(img)
+				require.NoError(t, err)
+
+				require.False(t, includes("node_modules", true))
+				require.False(t, includes("src/node_modules", true))
+				require.False(t, includes("src/debug.log", false))
+				require.True(t, includes("src/important.log", false))
+				require.False(t, includes("build", true))
+				require.True(t, includes("src/build", true))
+				require.False(t, includes("docs/a/b/notes.tmp", false))
+				require.True(t, includes("docs/readme.md", false))
+				require.True(t, includes(".kpignore", false))
+			})
+
+			it("excludes files matching the .gitignore file unless negated by the .kpignore file", func() {
+				writeFile(".gitignore", "*.log\nbin/\n")
+				writeFile(".kpignore", "!keep.log\n")
+				writeFile("bin/app", "")
+				writeFile("debug.log", "")
+				writeFile("keep.log", "")
+
+				_, _, err := factory.MakePatch(img)
+				require.NoError(t, err)
+
+				require.False(t, includes("bin", true))
+				require.False(t, includes("debug.log", false))
+				require.True(t, includes("keep.log", false))
+			})
+
+			it("writes a source tarball without the excluded paths", func() {
+				writeFile(".gitignore", "bin/\n")
+				writeFile(".kpignore", "*.log\n")
+				writeFile("main.go", "")
+				writeFile("bin/app", "")
+				writeFile("src/lib.go", "")
+				writeFile("src/debug.log", "")
+				writeFile("tmp/cache", "")
+				factory.Exclude = []string{"tmp"}
+
+				_, _, err := factory.MakePatch(img)
+				require.NoError(t, err)
+
+				tarPath, err := archive.CreateTar(localPath, sourceUploader.Filter)
+				require.NoError(t, err)
+				defer os.Remove(tarPath)
+
+				require.ElementsMatch(t, []string{"/.gitignore", "/.kpignore", "/main.go", "/src", "/src/lib.go"}, tarEntries(t, tarPath))
+			})
+
+			it("applies --exclude patterns after the .kpignore file", func() {
+				writeFile(".kpignore", "*.log\n")
+				writeFile("app.log", "")
+				writeFile(".git/config", "")
+				factory.Exclude = []string{".git", "!app.log"}
+
+				_, _, err := factory.MakePatch(img)
+				require.NoError(t, err)
+
+				require.False(t, includes(".git", true))
+				require.True(t, includes("app.log", false))
+			})
+
+			it("combines the patterns with the project descriptor", func() {
+				writeFile("project.toml", "[build]\nexclude = [\"test\"]\n")
+				writeFile("test/main_test.go", "")
+				writeFile("tmp/cache", "")
+				factory.Exclude = []string{"tmp"}
+
+				_, _, err := factory.MakePatch(img)
+				require.NoError(t, err)
+
+				require.False(t, includes("test", true))
+				require.False(t, includes("tmp", true))
+			})
+
+			it("errors with an invalid pattern", func() {
+				factory.Exclude = []string{"[z-a]"}
+
+				_, _, err := factory.MakePatch(img)
+				require.Error(t, err)
+				require.Contains(t, err.Error(), `invalid exclude pattern "[z-a]"`)
+			})
+		})
+	})
+
+	when("a project descriptor is provided without a local path", func() {
+		it("returns an error message", func() {
+			factory.ProjectDescriptor = "project.toml"
+			_, _, err := factory.MakePatch(img)
+			require.EqualError(t, err, "project-descriptor can only be used with local-path")
+		})
+	})
+}
+
+func tarEntries(t *testing.T, tarPath string) []string {
+	f, err := os.Open(tarPath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var names []string
+	tr := tar.NewReader(f)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, header.Name)
+	}
+	return names
 }
@@ -0,0 +1,149 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/archive"
+)
+
+const projectDescriptorFile = "project.toml"
+
+type projectDescriptor struct {
+	Build struct {
+		Include []string `toml:"include"`
+		Exclude []string `toml:"exclude"`
+		Env     []struct {
+			Name  string `toml:"name"`
+			Value string `toml:"value"`
+		} `toml:"env"`
+	} `toml:"build"`
+}
+
+func readProjectDescriptor(path string) (*projectDescriptor, error) {
+	descriptor := &projectDescriptor{}
+	if _, err := toml.DecodeFile(path, descriptor); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse project descriptor %q", path)
+	}
+
+	if len(descriptor.Build.Include) > 0 && len(descriptor.Build.Exclude) > 0 {
+		return nil, errors.Errorf("project descriptor %q cannot contain both build.include and build.exclude", path)
+	}
+
+	for _, e := range descriptor.Build.Env {
+		if e.Name == "" {
+			return nil, errors.Errorf("project descriptor %q contains a build.env entry without a name", path)
+		}
+	}
+
+	return descriptor, nil
+}
+
+func (d *projectDescriptor) envVars() []corev1.EnvVar {
+	var envVars []corev1.EnvVar
+	for _, e := range d.Build.Env {
+		envVars = upsertEnvVar(envVars, corev1.EnvVar{Name: e.Name, Value: e.Value})
+	}
+	return envVars
+}
+
+func (d *projectDescriptor) hasFilter() bool {
+	return len(d.Build.Include) > 0 || len(d.Build.Exclude) > 0
+}
+
+// fileFilter follows the pack conventions: a path is matched if it or one of its parent
+// directories matches a pattern, and patterns without a slash match at any depth
+func (d *projectDescriptor) fileFilter() archive.FileFilter {
+	if len(d.Build.Include) > 0 {
+		return func(p string, fi os.FileInfo) bool {
+			// directories are always walked so that included files in them are found
+			return fi.IsDir() || matchesAny(d.Build.Include, p)
+		}
+	} else if len(d.Build.Exclude) > 0 {
+		return func(p string, fi os.FileInfo) bool {
+			return !matchesAny(d.Build.Exclude, p)
+		}
+	}
+	return nil
+}
+
+func matchesAny(patterns []string, p string) bool {
+	for dir := p; dir != "." && dir != "/"; dir = path.Dir(dir) {
+		for _, pattern := range patterns {
+			if matchesPattern(pattern, dir) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesPattern(pattern, p string) bool {
+	pattern = strings.TrimSuffix(pattern, "/")
+	if strings.HasPrefix(pattern, "/") {
+		matched, _ := path.Match(strings.TrimPrefix(pattern, "/"), p)
+		return matched
+	}
+
+	if !strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, path.Base(p))
+		return matched
+	}
+
+	matched, _ := path.Match(pattern, p)
+	return matched
+}
+
+func (f *Factory) projectDescriptorPath() string {
+	if f.ProjectDescriptor != "" {
+		return f.ProjectDescriptor
+	}
+
+	if f.LocalPath == "" {
+		return ""
+	}
+
+	p := filepath.Join(f.LocalPath, projectDescriptorFile)
+	if fi, err := os.Stat(p); err != nil || fi.IsDir() {
+		return ""
+	}
+	return p
+}
+
+// the descriptor is only read once because it is used during both validation and patching
+func (f *Factory) loadProjectDescriptor() (*projectDescriptor, error) {
+	if f.descriptorRead {
+		return f.descriptor, nil
+	}
+
+	if f.ProjectDescriptor != "" && f.LocalPath == "" {
+		return nil, errors.New("project-descriptor can only be used with local-path")
+	}
+
+	if p := f.projectDescriptorPath(); p != "" {
+		descriptor, err := readProjectDescriptor(p)
+		if err != nil {
+			return nil, err
+		}
+
+		if descriptor.hasFilter() {
+			if fi, err := os.Stat(f.LocalPath); err == nil && !fi.IsDir() {
+				return nil, errors.Errorf("build.include and build.exclude in project descriptor %q require local-path to be a directory", p)
+			}
+		}
+
+		f.descriptor = descriptor
+	}
+
+	f.descriptorRead = true
+	return f.descriptor, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MakeImageFromResource defaults an image read from a resource file and applies the
+// factory flags on top of it so that flags take precedence over values from the file
+func (f *Factory) MakeImageFromResource(resource *v1alpha1.Image, namespace, tag string) (*v1alpha1.Image, error) {
+	if resource.Name == "" {
+		return nil, errors.New("image resource must have a name")
+	}
+
+	// only the user provided fields are kept so that exported resources can be created
+	resource = resource.DeepCopy()
+	img := &v1alpha1.Image{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Image",
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        resource.Name,
+			Namespace:   resource.Namespace,
+			Labels:      resource.Labels,
+			Annotations: resource.Annotations,
+		},
+		Spec: resource.Spec,
+	}
+
+	if img.Namespace == "" {
+		img.Namespace = namespace
+	}
+
+	if tag != "" {
+		img.Spec.Tag = tag
+	}
+
+	if img.Spec.Tag == "" {
+		return nil, errors.Errorf("image %q must have a tag", img.Name)
+	}
+
+	if f.ServiceAccount != "" {
+		img.Spec.ServiceAccount = f.ServiceAccount
+	} else if img.Spec.ServiceAccount == "" {
+		img.Spec.ServiceAccount = defaultServiceAccount
+	}
+
+	if img.Spec.Builder.Name == "" {
+		img.Spec.Builder = f.makeBuilder(img.Namespace)
+	}
+
+	if img.Spec.Build == nil {
+		img.Spec.Build = &v1alpha1.ImageBuild{}
+	}
+
+	if err := f.validatePatch(img); err != nil {
+		return nil, err
+	}
+
+	if err := f.applyFlags(img); err != nil {
+		return nil, err
+	}
+
+	sourceSet := paramSet{}
+	if img.Spec.Source.Git != nil {
+		sourceSet.add("git", img.Spec.Source.Git.URL)
+	}
+	if img.Spec.Source.Blob != nil {
+		sourceSet.add("blob", img.Spec.Source.Blob.URL)
+	}
+	if img.Spec.Source.Registry != nil {
+		sourceSet.add("registry", img.Spec.Source.Registry.Image)
+	}
+
+	if len(sourceSet) != 1 {
+		return nil, errors.Errorf("image %q source must be one of git, blob, or local-path", img.Name)
+	}
+
+	return img, nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+type RevisionResolver interface {
+	Resolve(namespace, serviceAccount, url, revision string) (string, error)
+}
+
+// pinRevision replaces a git branch or tag with the commit it currently points to
+func (f *Factory) pinRevision(img *v1alpha1.Image) error {
+	if !f.PinRevision {
+		return nil
+	}
+
+	git := img.Spec.Source.Git
+	if git == nil {
+		return errors.New("pin-revision can only be used with a git source")
+	}
+
+	serviceAccount := img.Spec.ServiceAccount
+	if serviceAccount == "" {
+		serviceAccount = defaultServiceAccount
+	}
+
+	sha, err := f.RevisionResolver.Resolve(img.Namespace, serviceAccount, git.URL, git.Revision)
+	if err != nil {
+		return errors.Wrapf(err, "failed to pin git revision %q", git.Revision)
+	}
+
+	git.Revision = sha
+	return nil
+}
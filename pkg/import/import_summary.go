@@ -0,0 +1,97 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import
+
+import (
+	"github.com/google/go-containerregistry/pkg/authn"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	ImportSummaryKind = "ImportSummary"
+
+	ActionCreated   = "created"
+	ActionUpdated   = "updated"
+	ActionUnchanged = "unchanged"
+)
+
+// ImportSummary is a machine-readable record of the resources processed by an import.
+// It is a runtime.Object so that it can be printed with the --output formats.
+type ImportSummary struct {
+	metav1.TypeMeta `json:",inline"`
+	Resources       []ImportedResource `json:"resources"`
+}
+
+type ImportedResource struct {
+	Kind   string           `json:"kind"`
+	Name   string           `json:"name"`
+	Action string           `json:"action"`
+	Images []RelocatedImage `json:"images,omitempty"`
+}
+
+type RelocatedImage struct {
+	Source    string `json:"source"`
+	Relocated string `json:"relocated"`
+}
+
+func (s *ImportSummary) DeepCopyObject() runtime.Object {
+	c := &ImportSummary{TypeMeta: s.TypeMeta}
+	for _, r := range s.Resources {
+		r.Images = append([]RelocatedImage(nil), r.Images...)
+		c.Resources = append(c.Resources, r)
+	}
+	return c
+}
+
+// imageRecorder records the source reference of every image it relocates.
+// Relocated images are matched to their source by digest.
+type imageRecorder struct {
+	fetcher   ImageFetcher
+	relocator ImageRelocator
+	sources   map[string]string
+	relocated []RelocatedImage
+}
+
+func newImageRecorder(fetcher ImageFetcher, relocator ImageRelocator) *imageRecorder {
+	return &imageRecorder{
+		fetcher:   fetcher,
+		relocator: relocator,
+		sources:   map[string]string{},
+	}
+}
+
+func (r *imageRecorder) Fetch(keychain authn.Keychain, image string) (v1.Image, error) {
+	img, err := r.fetcher.Fetch(keychain, image)
+	if err != nil {
+		return nil, err
+	}
+
+	if digest, err := img.Digest(); err == nil {
+		r.sources[digest.String()] = image
+	}
+	return img, nil
+}
+
+func (r *imageRecorder) Relocate(keychain authn.Keychain, src v1.Image, destination string) (string, error) {
+	ref, err := r.relocator.Relocate(keychain, src, destination)
+	if err != nil {
+		return "", err
+	}
+
+	var source string
+	if digest, err := src.Digest(); err == nil {
+		source = r.sources[digest.String()]
+	}
+	r.relocated = append(r.relocated, RelocatedImage{Source: source, Relocated: ref})
+	return ref, nil
+}
+
+// take returns the images relocated since the last call
+func (r *imageRecorder) take() []RelocatedImage {
+	images := r.relocated
+	r.relocated = nil
+	return images
+}
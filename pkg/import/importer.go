@@ -11,6 +11,7 @@ import (
 	"github.com/pivotal/kpack/pkg/client/clientset/versioned"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -50,6 +51,8 @@ type Importer struct {
 	clusterStoreFactory *clusterstore.Factory
 	clusterStackFactory *clusterstack.Factory
 	timestampProvider   TimestampProvider
+	images              *imageRecorder
+	imported            []ImportedResource
 }
 
 type relocatedDescriptor struct {
@@ -60,16 +63,29 @@ type relocatedDescriptor struct {
 }
 
 func NewImporter(printer Printer, k8sClient kubernetes.Interface, client versioned.Interface, fetcher ImageFetcher, relocator ImageRelocator, waiter commands.ResourceWaiter, timestampProvider TimestampProvider) *Importer {
+	images := newImageRecorder(fetcher, relocator)
 	return &Importer{
-		imageRelocator:      relocator,
+		imageRelocator:      images,
 		client:              client,
 		k8sClient:           k8sClient,
 		printer:             printer,
 		waiter:              waiter,
-		imageFetcher:        fetcher,
+		imageFetcher:        images,
 		timestampProvider:   timestampProvider,
-		clusterStackFactory: clusterstack.NewFactory(printer, relocator, fetcher),
-		clusterStoreFactory: clusterstore.NewFactory(printer, relocator, fetcher),
+		images:              images,
+		clusterStackFactory: clusterstack.NewFactory(printer, images, images),
+		clusterStoreFactory: clusterstore.NewFactory(printer, images, images),
+	}
+}
+
+// Summary returns the resources processed by the last descriptor import and the images relocated for them
+func (i *Importer) Summary() *ImportSummary {
+	return &ImportSummary{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       ImportSummaryKind,
+			APIVersion: CurrentAPIVersion,
+		},
+		Resources: i.imported,
 	}
 }
 
@@ -169,6 +185,9 @@ func (i *Importer) relocateDescriptor(ctx context.Context, keychain authn.Keycha
 		objs             []runtime.Object
 	)
 
+	i.imported = nil
+	i.images.take()
+
 	if descriptor.HasLifecycleImage() {
 		updatedLifecycle, err = i.relocateLifecycle(ctx, keychain, kpConfig, ts, descriptor.GetLifecycleImage())
 		if err != nil {
@@ -191,7 +210,7 @@ func (i *Importer) relocateDescriptor(ctx context.Context, keychain authn.Keycha
 
 	clusterstacks := make([]*v1alpha1.ClusterStack, 0)
 	for _, clusterStack := range descriptor.GetClusterStacks() {
-		rStack, err := i.constructClusterStack(keychain, kpConfig, clusterStack)
+		rStack, err := i.constructClusterStack(ctx, keychain, kpConfig, clusterStack)
 		if err != nil {
 			return relocatedDescriptor{}, nil, err
 		}
@@ -203,7 +222,7 @@ func (i *Importer) relocateDescriptor(ctx context.Context, keychain authn.Keycha
 
 	clusterBuilders := make([]*v1alpha1.ClusterBuilder, 0)
 	for _, clusterBuilder := range descriptor.GetClusterBuilders() {
-		rBuilder, err := i.constructClusterBuilder(kpConfig, clusterBuilder)
+		rBuilder, err := i.constructClusterBuilder(ctx, kpConfig, clusterBuilder)
 		if err != nil {
 			return relocatedDescriptor{}, nil, err
 		}
@@ -245,6 +264,8 @@ func (i *Importer) relocateLifecycle(ctx context.Context, keychain authn.Keychai
 
 	newConfigMap.SetAnnotations(map[string]string{"kpack.io/import-timestamp": ts})
 	newConfigMap.Data["image"] = relocatedLifecycle
+
+	i.record("ConfigMap", existingLifecycleConfig.Name, true, existingLifecycleConfig.Data["image"] != relocatedLifecycle)
 	return newConfigMap, nil
 }
 
@@ -268,6 +289,7 @@ func (i *Importer) constructClusterStore(ctx context.Context, keychain authn.Key
 			return nil, err
 		}
 
+		i.record(v1alpha1.ClusterStoreKind, store.Name, true, !equality.Semantic.DeepEqual(existingStore.Spec, updatedStore.Spec))
 		return updatedStore, nil
 	}
 
@@ -275,10 +297,12 @@ func (i *Importer) constructClusterStore(ctx context.Context, keychain authn.Key
 	if err != nil {
 		return nil, err
 	}
+
+	i.record(v1alpha1.ClusterStoreKind, store.Name, false, true)
 	return newStore, nil
 }
 
-func (i *Importer) constructClusterStack(keychain authn.Keychain, kpConfig config.KpConfig, stack ClusterStack) (*v1alpha1.ClusterStack, error) {
+func (i *Importer) constructClusterStack(ctx context.Context, keychain authn.Keychain, kpConfig config.KpConfig, stack ClusterStack) (*v1alpha1.ClusterStack, error) {
 	if err := i.printer.PrintStatus("Importing ClusterStack '%s'...", stack.Name); err != nil {
 		return nil, err
 	}
@@ -288,10 +312,17 @@ func (i *Importer) constructClusterStack(keychain authn.Keychain, kpConfig confi
 		return nil, err
 	}
 
+	existingStack, err := i.client.KpackV1alpha1().ClusterStacks().Get(ctx, stack.Name, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	exists := err == nil
+	i.record(v1alpha1.ClusterStackKind, stack.Name, exists, !exists || !equality.Semantic.DeepEqual(existingStack.Spec, newStack.Spec))
 	return newStack, nil
 }
 
-func (i *Importer) constructClusterBuilder(kpConfig config.KpConfig, builder ClusterBuilder) (*v1alpha1.ClusterBuilder, error) {
+func (i *Importer) constructClusterBuilder(ctx context.Context, kpConfig config.KpConfig, builder ClusterBuilder) (*v1alpha1.ClusterBuilder, error) {
 	if err := i.printer.PrintStatus("Importing ClusterBuilder '%s'...", builder.Name); err != nil {
 		return nil, err
 	}
@@ -327,9 +358,33 @@ func (i *Importer) constructClusterBuilder(kpConfig config.KpConfig, builder Clu
 		return nil, err
 	}
 
+	existingBuilder, err := i.client.KpackV1alpha1().ClusterBuilders().Get(ctx, builder.Name, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	exists := err == nil
+	i.record(v1alpha1.ClusterBuilderKind, builder.Name, exists, !exists || !equality.Semantic.DeepEqual(existingBuilder.Spec, newCB.Spec))
 	return newCB, nil
 }
 
+// record adds a resource to the import summary along with the images relocated while constructing it
+func (i *Importer) record(kind, name string, exists, changed bool) {
+	action := ActionCreated
+	if exists && changed {
+		action = ActionUpdated
+	} else if exists {
+		action = ActionUnchanged
+	}
+
+	i.imported = append(i.imported, ImportedResource{
+		Kind:   kind,
+		Name:   name,
+		Action: action,
+		Images: i.images.take(),
+	})
+}
+
 func (i *Importer) updateLifecycleConfigMap(ctx context.Context, updatedLifecycle *corev1.ConfigMap) error {
 	_, err := i.k8sClient.CoreV1().ConfigMaps("kpack").Update(ctx, updatedLifecycle, metav1.UpdateOptions{})
 
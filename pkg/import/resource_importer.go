@@ -0,0 +1,282 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+const (
+	ResourceAPIVersion = "kpack.io/v1alpha1"
+	imageKind          = "Image"
+)
+
+// IsResourceManifest reports whether raw contains kpack resources rather than a dependency descriptor.
+func IsResourceManifest(raw string) bool {
+	docs, err := splitDocuments(raw)
+	if err != nil {
+		return false
+	}
+
+	for _, doc := range docs {
+		var typeMeta metav1.TypeMeta
+		if err := yaml.Unmarshal(doc, &typeMeta); err == nil && typeMeta.APIVersion == ResourceAPIVersion {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *Importer) ImportResources(ctx context.Context, namespace, rawResources string) ([]runtime.Object, error) {
+	objs, err := i.readResources(namespace, rawResources)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, obj := range objs {
+		if err := i.saveResource(ctx, obj); err != nil {
+			return nil, err
+		}
+	}
+
+	return objs, nil
+}
+
+func (i *Importer) ImportResourcesDryRun(namespace, rawResources string) ([]runtime.Object, error) {
+	return i.readResources(namespace, rawResources)
+}
+
+func (i *Importer) readResources(namespace, rawResources string) ([]runtime.Object, error) {
+	docs, err := splitDocuments(rawResources)
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []runtime.Object
+	for n, doc := range docs {
+		var typeMeta metav1.TypeMeta
+		if err := yaml.Unmarshal(doc, &typeMeta); err != nil {
+			return nil, errors.Wrapf(err, "invalid resource in document %d", n+1)
+		}
+
+		if typeMeta.APIVersion == "" && typeMeta.Kind == "" {
+			continue
+		}
+
+		obj, ok := newResource(typeMeta)
+		if !ok {
+			if err := i.printer.Printlnf("Skipping unsupported resource %q with apiVersion %q in document %d", typeMeta.Kind, typeMeta.APIVersion, n+1); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err := yaml.Unmarshal(doc, obj); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s in document %d", typeMeta.Kind, n+1)
+		}
+
+		if obj.GetName() == "" {
+			return nil, errors.Errorf("%s in document %d must have a name", typeMeta.Kind, n+1)
+		}
+
+		if obj.GetNamespace() == "" && (typeMeta.Kind == v1alpha1.BuilderKind || typeMeta.Kind == imageKind) {
+			obj.SetNamespace(namespace)
+		}
+
+		if err := i.printer.PrintStatus("Importing %s '%s'...", typeMeta.Kind, obj.GetName()); err != nil {
+			return nil, err
+		}
+
+		if err := k8s.SetLastAppliedCfg(obj); err != nil {
+			return nil, err
+		}
+
+		objs = append(objs, obj)
+	}
+
+	return objs, nil
+}
+
+type resource interface {
+	k8s.Annotatable
+	GetName() string
+	GetNamespace() string
+	SetNamespace(namespace string)
+}
+
+func newResource(typeMeta metav1.TypeMeta) (resource, bool) {
+	if typeMeta.APIVersion != ResourceAPIVersion {
+		return nil, false
+	}
+
+	switch typeMeta.Kind {
+	case v1alpha1.ClusterStoreKind:
+		return &v1alpha1.ClusterStore{}, true
+	case v1alpha1.ClusterStackKind:
+		return &v1alpha1.ClusterStack{}, true
+	case v1alpha1.ClusterBuilderKind:
+		return &v1alpha1.ClusterBuilder{}, true
+	case v1alpha1.BuilderKind:
+		return &v1alpha1.Builder{}, true
+	case imageKind:
+		return &v1alpha1.Image{}, true
+	default:
+		return nil, false
+	}
+}
+
+func (i *Importer) saveResource(ctx context.Context, obj runtime.Object) error {
+	switch r := obj.(type) {
+	case *v1alpha1.ClusterStore:
+		return i.applyClusterStore(ctx, r)
+	case *v1alpha1.ClusterStack:
+		return i.applyClusterStack(ctx, r)
+	case *v1alpha1.ClusterBuilder:
+		return i.applyClusterBuilder(ctx, r)
+	case *v1alpha1.Builder:
+		return i.applyBuilder(ctx, r)
+	case *v1alpha1.Image:
+		return i.applyImage(ctx, r)
+	default:
+		return errors.Errorf("unsupported resource type %T", obj)
+	}
+}
+
+func (i *Importer) applyClusterStore(ctx context.Context, store *v1alpha1.ClusterStore) error {
+	existing, err := i.client.KpackV1alpha1().ClusterStores().Get(ctx, store.Name, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	if k8serrors.IsNotFound(err) {
+		store, err = i.client.KpackV1alpha1().ClusterStores().Create(ctx, store, metav1.CreateOptions{})
+	} else {
+		updated := existing.DeepCopy()
+		updated.Spec = store.Spec
+		updated.Annotations = k8s.MergeAnnotations(updated.Annotations, store.Annotations)
+		store, err = i.client.KpackV1alpha1().ClusterStores().Update(ctx, updated, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	return i.waiter.Wait(ctx, store)
+}
+
+func (i *Importer) applyClusterStack(ctx context.Context, stack *v1alpha1.ClusterStack) error {
+	existing, err := i.client.KpackV1alpha1().ClusterStacks().Get(ctx, stack.Name, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	if k8serrors.IsNotFound(err) {
+		stack, err = i.client.KpackV1alpha1().ClusterStacks().Create(ctx, stack, metav1.CreateOptions{})
+	} else {
+		updated := existing.DeepCopy()
+		updated.Spec = stack.Spec
+		updated.Annotations = k8s.MergeAnnotations(updated.Annotations, stack.Annotations)
+		stack, err = i.client.KpackV1alpha1().ClusterStacks().Update(ctx, updated, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	return i.waiter.Wait(ctx, stack)
+}
+
+func (i *Importer) applyClusterBuilder(ctx context.Context, cb *v1alpha1.ClusterBuilder) error {
+	existing, err := i.client.KpackV1alpha1().ClusterBuilders().Get(ctx, cb.Name, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	if k8serrors.IsNotFound(err) {
+		cb, err = i.client.KpackV1alpha1().ClusterBuilders().Create(ctx, cb, metav1.CreateOptions{})
+	} else {
+		updated := existing.DeepCopy()
+		updated.Spec = cb.Spec
+		updated.Annotations = k8s.MergeAnnotations(updated.Annotations, cb.Annotations)
+		cb, err = i.client.KpackV1alpha1().ClusterBuilders().Update(ctx, updated, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	return i.waiter.Wait(ctx, cb)
+}
+
+func (i *Importer) applyBuilder(ctx context.Context, b *v1alpha1.Builder) error {
+	existing, err := i.client.KpackV1alpha1().Builders(b.Namespace).Get(ctx, b.Name, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	if k8serrors.IsNotFound(err) {
+		b, err = i.client.KpackV1alpha1().Builders(b.Namespace).Create(ctx, b, metav1.CreateOptions{})
+	} else {
+		updated := existing.DeepCopy()
+		updated.Spec = b.Spec
+		updated.Annotations = k8s.MergeAnnotations(updated.Annotations, b.Annotations)
+		b, err = i.client.KpackV1alpha1().Builders(b.Namespace).Update(ctx, updated, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	return i.waiter.Wait(ctx, b)
+}
+
+func (i *Importer) applyImage(ctx context.Context, img *v1alpha1.Image) error {
+	existing, err := i.client.KpackV1alpha1().Images(img.Namespace).Get(ctx, img.Name, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	if k8serrors.IsNotFound(err) {
+		img, err = i.client.KpackV1alpha1().Images(img.Namespace).Create(ctx, img, metav1.CreateOptions{})
+	} else {
+		updated := existing.DeepCopy()
+		updated.Spec = img.Spec
+		updated.Annotations = k8s.MergeAnnotations(updated.Annotations, img.Annotations)
+		img, err = i.client.KpackV1alpha1().Images(img.Namespace).Update(ctx, updated, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	return i.waiter.Wait(ctx, img)
+}
+
+func splitDocuments(raw string) ([][]byte, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(strings.NewReader(raw)))
+
+	var docs [][]byte
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(doc)) > 0 {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
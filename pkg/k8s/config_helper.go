@@ -8,6 +8,7 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/config"
@@ -17,6 +18,8 @@ type ConfigHelper interface {
 	GetCanonicalRepository(ctx context.Context) (string, error)
 	GetCanonicalServiceAccount(ctx context.Context) (string, error)
 	GetKpConfig(ctx context.Context) (config.KpConfig, error)
+	GetDefaultClusterBuilder(ctx context.Context) (string, error)
+	SetDefaultClusterBuilder(ctx context.Context, name string) error
 }
 
 const (
@@ -24,6 +27,7 @@ const (
 	kpConfigMapName            = "kp-config"
 	canonicalRepositoryKey     = "canonical.repository"
 	canonicalServiceAccountKey = "canonical.repository.serviceaccount"
+	defaultClusterBuilderKey   = "default.clusterbuilder"
 )
 
 type defaultConfigHelper struct {
@@ -77,6 +81,43 @@ func (d defaultConfigHelper) GetCanonicalServiceAccount(ctx context.Context) (st
 	return val, err
 }
 
+// GetDefaultClusterBuilder returns an empty string when no default cluster builder is configured
+func (d defaultConfigHelper) GetDefaultClusterBuilder(ctx context.Context) (string, error) {
+	kpConfig, err := d.cs.K8sClient.CoreV1().ConfigMaps(kpNamespace).Get(ctx, kpConfigMapName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Wrapf(err, "failed to get default cluster builder")
+	}
+	return kpConfig.Data[defaultClusterBuilderKey], nil
+}
+
+func (d defaultConfigHelper) SetDefaultClusterBuilder(ctx context.Context, name string) error {
+	configMaps := d.cs.K8sClient.CoreV1().ConfigMaps(kpNamespace)
+
+	kpConfig, err := configMaps.Get(ctx, kpConfigMapName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kpConfigMapName,
+				Namespace: kpNamespace,
+			},
+			Data: map[string]string{defaultClusterBuilderKey: name},
+		}, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	if kpConfig.Data == nil {
+		kpConfig.Data = map[string]string{}
+	}
+	kpConfig.Data[defaultClusterBuilderKey] = name
+
+	_, err = configMaps.Update(ctx, kpConfig, metav1.UpdateOptions{})
+	return err
+}
+
 func (d defaultConfigHelper) getValue(ctx context.Context, key string) (string, error) {
 	var value string
 
@@ -0,0 +1,142 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package k8s
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Metadata holds the labels and annotations to set on or remove from a resource,
+// labels and annotations are provided in the form key=value
+type Metadata struct {
+	Labels            []string
+	Annotations       []string
+	DeleteLabels      []string
+	DeleteAnnotations []string
+
+	// AllowDelete also accepts labels and annotations in the form key- to remove
+	// the key from the resource, the same way as kubectl label and kubectl annotate
+	AllowDelete bool
+}
+
+type parsedMetadata struct {
+	labels            map[string]string
+	annotations       map[string]string
+	deleteLabels      []string
+	deleteAnnotations []string
+}
+
+func (m Metadata) IsEmpty() bool {
+	return len(m.Labels) == 0 && len(m.Annotations) == 0 && len(m.DeleteLabels) == 0 && len(m.DeleteAnnotations) == 0
+}
+
+func (m Metadata) Validate() error {
+	_, err := m.parse()
+	return err
+}
+
+// Apply merges the labels and annotations into the existing ones of the object and removes the deleted keys
+func (m Metadata) Apply(obj metav1.Object) error {
+	parsed, err := m.parse()
+	if err != nil {
+		return err
+	}
+
+	obj.SetLabels(mergeMetadata(obj.GetLabels(), parsed.labels, parsed.deleteLabels))
+	obj.SetAnnotations(mergeMetadata(obj.GetAnnotations(), parsed.annotations, parsed.deleteAnnotations))
+	return nil
+}
+
+func (m Metadata) parse() (parsedMetadata, error) {
+	parsed := parsedMetadata{
+		labels:            map[string]string{},
+		annotations:       map[string]string{},
+		deleteLabels:      append([]string{}, m.DeleteLabels...),
+		deleteAnnotations: append([]string{}, m.DeleteAnnotations...),
+	}
+
+	for _, l := range m.Labels {
+		if key, ok := m.deletedKey(l); ok {
+			parsed.deleteLabels = append(parsed.deleteLabels, key)
+			continue
+		}
+
+		key, value, err := parseKeyValue("label", l)
+		if err != nil {
+			return parsed, err
+		}
+
+		if errs := append(validation.IsQualifiedName(key), validation.IsValidLabelValue(value)...); len(errs) > 0 {
+			return parsed, errors.Errorf("invalid label %q: %s", l, strings.Join(errs, ", "))
+		}
+		parsed.labels[key] = value
+	}
+
+	for _, a := range m.Annotations {
+		if key, ok := m.deletedKey(a); ok {
+			parsed.deleteAnnotations = append(parsed.deleteAnnotations, key)
+			continue
+		}
+
+		key, value, err := parseKeyValue("annotation", a)
+		if err != nil {
+			return parsed, err
+		}
+
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return parsed, errors.Errorf("invalid annotation %q: %s", a, strings.Join(errs, ", "))
+		}
+		parsed.annotations[key] = value
+	}
+
+	for _, key := range parsed.deleteLabels {
+		if _, ok := parsed.labels[key]; ok {
+			return parsed, errors.Errorf("label %q cannot be both set and deleted", key)
+		}
+	}
+
+	for _, key := range parsed.deleteAnnotations {
+		if _, ok := parsed.annotations[key]; ok {
+			return parsed, errors.Errorf("annotation %q cannot be both set and deleted", key)
+		}
+	}
+
+	return parsed, nil
+}
+
+func (m Metadata) deletedKey(kv string) (string, bool) {
+	if !m.AllowDelete || strings.Contains(kv, "=") || !strings.HasSuffix(kv, "-") {
+		return "", false
+	}
+	return strings.TrimSuffix(kv, "-"), true
+}
+
+func parseKeyValue(kind, kv string) (string, string, error) {
+	parts := strings.SplitN(kv, "=", 2)
+	if len(parts) != 2 {
+		return "", "", errors.Errorf("invalid %s %q, expected key=value", kind, kv)
+	}
+	return parts[0], parts[1], nil
+}
+
+// mergeMetadata keeps a nil map when there is nothing to set so that unchanged resources do not produce a patch
+func mergeMetadata(existing, set map[string]string, deleted []string) map[string]string {
+	if len(set) == 0 && len(deleted) == 0 {
+		return existing
+	}
+
+	merged := MergeAnnotations(existing, set)
+	for _, key := range deleted {
+		delete(merged, key)
+	}
+
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
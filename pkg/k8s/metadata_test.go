@@ -0,0 +1,115 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package k8s_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+func TestMetadata(t *testing.T) {
+	spec.Run(t, "TestMetadata", testMetadata)
+}
+
+func testMetadata(t *testing.T, when spec.G, it spec.S) {
+	var obj *corev1.Secret
+
+	it.Before(func() {
+		obj = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "some-name",
+				Labels:      map[string]string{"team": "some-team", "old": "value"},
+				Annotations: map[string]string{"some-annotation": "some-value"},
+			},
+		}
+	})
+
+	it("merges labels and annotations and removes deleted keys", func() {
+		metadata := k8s.Metadata{
+			Labels:            []string{"team=other-team", "example.com/cost-center=123"},
+			Annotations:       []string{"description=some description"},
+			DeleteLabels:      []string{"old", "does-not-exist"},
+			DeleteAnnotations: []string{"some-annotation"},
+		}
+
+		require.NoError(t, metadata.Apply(obj))
+		require.Equal(t, map[string]string{"team": "other-team", "example.com/cost-center": "123"}, obj.Labels)
+		require.Equal(t, map[string]string{"description": "some description"}, obj.Annotations)
+	})
+
+	it("leaves the object unchanged when nothing is provided", func() {
+		require.NoError(t, k8s.Metadata{}.Apply(obj))
+		require.Equal(t, map[string]string{"team": "some-team", "old": "value"}, obj.Labels)
+		require.Equal(t, map[string]string{"some-annotation": "some-value"}, obj.Annotations)
+	})
+
+	it("removes the maps when every key is deleted", func() {
+		metadata := k8s.Metadata{
+			DeleteLabels:      []string{"team", "old"},
+			DeleteAnnotations: []string{"some-annotation"},
+		}
+
+		require.NoError(t, metadata.Apply(obj))
+		require.Nil(t, obj.Labels)
+		require.Nil(t, obj.Annotations)
+	})
+
+	it("removes the keys provided in the form key- when deleting is allowed", func() {
+		metadata := k8s.Metadata{
+			Labels:      []string{"old-", "team=other-team"},
+			Annotations: []string{"some-annotation-"},
+			AllowDelete: true,
+		}
+
+		require.NoError(t, metadata.Apply(obj))
+		require.Equal(t, map[string]string{"team": "other-team"}, obj.Labels)
+		require.Nil(t, obj.Annotations)
+	})
+
+	it("does not remove the keys provided in the form key- when deleting is not allowed", func() {
+		err := k8s.Metadata{Labels: []string{"old-"}}.Validate()
+		require.EqualError(t, err, `invalid label "old-", expected key=value`)
+	})
+
+	it("validates the format", func() {
+		err := k8s.Metadata{Labels: []string{"team"}}.Validate()
+		require.EqualError(t, err, `invalid label "team", expected key=value`)
+
+		err = k8s.Metadata{Annotations: []string{"description"}}.Validate()
+		require.EqualError(t, err, `invalid annotation "description", expected key=value`)
+	})
+
+	it("validates label keys and values", func() {
+		err := k8s.Metadata{Labels: []string{"team=not valid"}}.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `invalid label "team=not valid": a valid label must be an empty string or consist of alphanumeric characters`)
+
+		err = k8s.Metadata{Labels: []string{"not valid=team"}}.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `invalid label "not valid=team": name part must consist of alphanumeric characters`)
+	})
+
+	it("validates annotation keys", func() {
+		err := k8s.Metadata{Annotations: []string{"not valid=some value"}}.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `invalid annotation "not valid=some value": name part must consist of alphanumeric characters`)
+	})
+
+	it("does not allow setting and deleting the same key", func() {
+		err := k8s.Metadata{Labels: []string{"team=some-team"}, DeleteLabels: []string{"team"}}.Validate()
+		require.EqualError(t, err, `label "team" cannot be both set and deleted`)
+
+		err = k8s.Metadata{Annotations: []string{"description=value"}, DeleteAnnotations: []string{"description"}}.Validate()
+		require.EqualError(t, err, `annotation "description" cannot be both set and deleted`)
+
+		err = k8s.Metadata{Labels: []string{"team=some-team", "team-"}, AllowDelete: true}.Validate()
+		require.EqualError(t, err, `label "team" cannot be both set and deleted`)
+	})
+}
@@ -4,11 +4,16 @@
 package k8s
 
 import (
+	"bytes"
 	"encoding/json"
 
 	jsonpatch "github.com/evanphx/json-patch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// CreatePatch returns a json merge patch, lists in the patch replace the lists of the resource.
+// The kpack resources are custom resources and the api server does not support strategic merge
+// patches for them, so a list such as the order of a ClusterBuilder is always replaced as a whole.
 func CreatePatch(original, updated interface{}) ([]byte, error) {
 	originalBytes, err := json.Marshal(original)
 	if err != nil {
@@ -31,3 +36,29 @@ func CreatePatch(original, updated interface{}) ([]byte, error) {
 
 	return patch, nil
 }
+
+// AddResourceVersion returns the patch with the resource version of original, the api server rejects
+// the patch with a conflict when the resource was modified after original was read.
+// An empty patch and an original without a resource version are returned as is.
+func AddResourceVersion(patch []byte, original metav1.Object) ([]byte, error) {
+	if len(patch) == 0 || original.GetResourceVersion() == "" {
+		return patch, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(patch))
+	decoder.UseNumber()
+
+	var patchMap map[string]interface{}
+	if err := decoder.Decode(&patchMap); err != nil {
+		return nil, err
+	}
+
+	metadata, ok := patchMap["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+		patchMap["metadata"] = metadata
+	}
+	metadata["resourceVersion"] = original.GetResourceVersion()
+
+	return json.Marshal(patchMap)
+}
@@ -0,0 +1,60 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package k8s_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+func TestPatch(t *testing.T) {
+	spec.Run(t, "TestPatch", testPatch)
+}
+
+func testPatch(t *testing.T, when spec.G, it spec.S) {
+	when("AddResourceVersion", func() {
+		obj := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "some-name",
+				ResourceVersion: "42",
+			},
+		}
+
+		it("adds the resource version to the patch", func() {
+			patch, err := k8s.AddResourceVersion([]byte(`{"data":{"some-key":"c29tZS12YWx1ZQ=="}}`), obj)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"data":{"some-key":"c29tZS12YWx1ZQ=="},"metadata":{"resourceVersion":"42"}}`, string(patch))
+		})
+
+		it("keeps the metadata of the patch", func() {
+			patch, err := k8s.AddResourceVersion([]byte(`{"metadata":{"labels":{"team":"some-team"}}}`), obj)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"metadata":{"labels":{"team":"some-team"},"resourceVersion":"42"}}`, string(patch))
+		})
+
+		it("keeps large numbers as is", func() {
+			patch, err := k8s.AddResourceVersion([]byte(`{"spec":{"size":9007199254740993}}`), obj)
+			require.NoError(t, err)
+			require.Equal(t, `{"metadata":{"resourceVersion":"42"},"spec":{"size":9007199254740993}}`, string(patch))
+		})
+
+		it("returns an empty patch as is", func() {
+			patch, err := k8s.AddResourceVersion(nil, obj)
+			require.NoError(t, err)
+			require.Nil(t, patch)
+		})
+
+		it("returns the patch as is when the object has no resource version", func() {
+			patch, err := k8s.AddResourceVersion([]byte(`{"data":{}}`), &corev1.Secret{})
+			require.NoError(t, err)
+			require.Equal(t, `{"data":{}}`, string(patch))
+		})
+	})
+}
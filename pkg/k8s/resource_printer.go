@@ -8,14 +8,28 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"strings"
+	"text/tabwriter"
+	"text/template"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
 	"sigs.k8s.io/yaml"
 )
 
 const (
-	FormatYAML string = "yaml"
-	FormatJSON string = "json"
+	FormatYAML              string = "yaml"
+	FormatJSON              string = "json"
+	FormatName              string = "name"
+	FormatJSONPath          string = "jsonpath"
+	FormatJSONPathFile      string = "jsonpath-file"
+	FormatGoTemplate        string = "go-template"
+	FormatGoTemplateFile    string = "go-template-file"
+	FormatCustomColumns     string = "custom-columns"
+	FormatCustomColumnsFile string = "custom-columns-file"
 )
 
 type ObjectPrinter interface {
@@ -23,13 +37,39 @@ type ObjectPrinter interface {
 }
 
 func NewObjectPrinter(format string) (ObjectPrinter, error) {
-	switch format {
-	case FormatYAML:
+	switch {
+	case format == FormatYAML:
 		return &YAMLObjectPrinter{}, nil
-	case FormatJSON:
+	case format == FormatJSON:
 		return JSONObjectPrinter{}, nil
+	case format == FormatName:
+		return NameObjectPrinter{}, nil
+	case strings.HasPrefix(format, FormatJSONPath+"="):
+		return NewJSONPathObjectPrinter(strings.TrimPrefix(format, FormatJSONPath+"="))
+	case strings.HasPrefix(format, FormatJSONPathFile+"="):
+		data, err := ioutil.ReadFile(strings.TrimPrefix(format, FormatJSONPathFile+"="))
+		if err != nil {
+			return nil, fmt.Errorf("error reading jsonpath file: %v", err)
+		}
+		return NewJSONPathObjectPrinter(string(data))
+	case strings.HasPrefix(format, FormatGoTemplate+"="):
+		return NewGoTemplateObjectPrinter(strings.TrimPrefix(format, FormatGoTemplate+"="))
+	case strings.HasPrefix(format, FormatGoTemplateFile+"="):
+		data, err := ioutil.ReadFile(strings.TrimPrefix(format, FormatGoTemplateFile+"="))
+		if err != nil {
+			return nil, fmt.Errorf("error reading go-template file: %v", err)
+		}
+		return NewGoTemplateObjectPrinter(string(data))
+	case strings.HasPrefix(format, FormatCustomColumns+"="):
+		return NewCustomColumnsObjectPrinter(strings.TrimPrefix(format, FormatCustomColumns+"="))
+	case strings.HasPrefix(format, FormatCustomColumnsFile+"="):
+		data, err := ioutil.ReadFile(strings.TrimPrefix(format, FormatCustomColumnsFile+"="))
+		if err != nil {
+			return nil, fmt.Errorf("error reading custom columns file: %v", err)
+		}
+		return NewCustomColumnsObjectPrinterFromTemplate(string(data))
 	default:
-		return nil, fmt.Errorf("unsupported output format: %q, supported formats are yaml, json", format)
+		return nil, fmt.Errorf("unsupported output format: %q, supported formats are yaml, json, name, jsonpath=<template>, jsonpath-file=<path>, go-template=<template>, go-template-file=<path>, custom-columns=<spec>, custom-columns-file=<path>", format)
 	}
 }
 
@@ -77,3 +117,258 @@ func (j JSONObjectPrinter) PrintObject(obj runtime.Object, w io.Writer) error {
 	_, err = w.Write(buf.Bytes())
 	return err
 }
+
+// NameObjectPrinter prints "<kind>.<group>/<name>" for the object or each item of a list, following kubectl
+type NameObjectPrinter struct{}
+
+func (n NameObjectPrinter) PrintObject(obj runtime.Object, w io.Writer) error {
+	if !meta.IsListType(obj) {
+		return printName(obj, obj.GetObjectKind().GroupVersionKind(), w)
+	}
+
+	items, err := meta.ExtractList(obj)
+	if err != nil {
+		return err
+	}
+
+	// the items of a typed list such as an ImageList do not have their kind set
+	listGVK := obj.GetObjectKind().GroupVersionKind()
+	itemGVK := listGVK.GroupVersion().WithKind(strings.TrimSuffix(listGVK.Kind, "List"))
+
+	for _, item := range items {
+		gvk := item.GetObjectKind().GroupVersionKind()
+		if gvk.Kind == "" {
+			gvk = itemGVK
+		}
+
+		if err := printName(item, gvk, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printName(obj runtime.Object, gvk schema.GroupVersionKind, w io.Writer) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	if gvk.Kind == "" {
+		return fmt.Errorf("missing kind for %q", accessor.GetName())
+	}
+
+	kind := strings.ToLower(gvk.Kind)
+	if gvk.Group != "" {
+		kind += "." + gvk.Group
+	}
+
+	_, err = fmt.Fprintf(w, "%s/%s\n", kind, accessor.GetName())
+	return err
+}
+
+type JSONPathObjectPrinter struct {
+	jsonPath *jsonpath.JSONPath
+}
+
+func NewJSONPathObjectPrinter(tmpl string) (*JSONPathObjectPrinter, error) {
+	tmpl = strings.TrimSpace(tmpl)
+	if tmpl == "" {
+		return nil, fmt.Errorf("jsonpath template must not be empty")
+	}
+
+	j, err := parseJSONPath(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONPathObjectPrinter{jsonPath: j}, nil
+}
+
+func (j *JSONPathObjectPrinter) PrintObject(obj runtime.Object, w io.Writer) error {
+	queryObj, err := toQueryObject(obj)
+	if err != nil {
+		return err
+	}
+
+	if err := j.jsonPath.Execute(w, queryObj); err != nil {
+		return err
+	}
+
+	_, err = w.Write([]byte("\n"))
+	return err
+}
+
+type GoTemplateObjectPrinter struct {
+	tmpl *template.Template
+}
+
+func NewGoTemplateObjectPrinter(tmpl string) (*GoTemplateObjectPrinter, error) {
+	if strings.TrimSpace(tmpl) == "" {
+		return nil, fmt.Errorf("go-template must not be empty")
+	}
+
+	t, err := template.New("output").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing go-template: %v", err)
+	}
+
+	return &GoTemplateObjectPrinter{tmpl: t}, nil
+}
+
+func (g *GoTemplateObjectPrinter) PrintObject(obj runtime.Object, w io.Writer) error {
+	queryObj, err := toQueryObject(obj)
+	if err != nil {
+		return err
+	}
+
+	// render to a buffer so that nothing is written when the template fails
+	var buf bytes.Buffer
+	if err := g.tmpl.Execute(&buf, queryObj); err != nil {
+		return fmt.Errorf("error executing go-template: %v", err)
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+type column struct {
+	header   string
+	jsonPath *jsonpath.JSONPath
+}
+
+type CustomColumnsObjectPrinter struct {
+	columns       []column
+	headerPrinted bool
+}
+
+func NewCustomColumnsObjectPrinter(spec string) (*CustomColumnsObjectPrinter, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("custom-columns format specified but no custom columns given")
+	}
+
+	var columns []column
+	for _, part := range strings.Split(spec, ",") {
+		idx := strings.Index(part, ":")
+		if idx <= 0 {
+			return nil, fmt.Errorf("unexpected custom-columns spec: %s, expected <header>:<json-path-expr>", part)
+		}
+
+		j, err := parseJSONPath(part[idx+1:])
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column{header: part[:idx], jsonPath: j})
+	}
+
+	return &CustomColumnsObjectPrinter{columns: columns}, nil
+}
+
+func NewCustomColumnsObjectPrinterFromTemplate(tmpl string) (*CustomColumnsObjectPrinter, error) {
+	var lines []string
+	for _, line := range strings.Split(tmpl, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if len(lines) != 2 {
+		return nil, fmt.Errorf("invalid custom-columns template, expected 2 lines, found %d", len(lines))
+	}
+
+	headers := strings.Fields(lines[0])
+	paths := strings.Fields(lines[1])
+	if len(headers) != len(paths) {
+		return nil, fmt.Errorf("invalid custom-columns template, expected %d paths, found %d", len(headers), len(paths))
+	}
+
+	var specs []string
+	for i := range headers {
+		specs = append(specs, headers[i]+":"+paths[i])
+	}
+
+	return NewCustomColumnsObjectPrinter(strings.Join(specs, ","))
+}
+
+func (c *CustomColumnsObjectPrinter) PrintObject(obj runtime.Object, w io.Writer) error {
+	objs := []runtime.Object{obj}
+	if meta.IsListType(obj) {
+		var err error
+		objs, err = meta.ExtractList(obj)
+		if err != nil {
+			return err
+		}
+	}
+
+	writer := tabwriter.NewWriter(w, 0, 4, 4, ' ', 0)
+
+	if !c.headerPrinted {
+		var headers []string
+		for _, col := range c.columns {
+			headers = append(headers, col.header)
+		}
+		if _, err := fmt.Fprintln(writer, strings.Join(headers, "\t")); err != nil {
+			return err
+		}
+		c.headerPrinted = true
+	}
+
+	for _, o := range objs {
+		queryObj, err := toQueryObject(o)
+		if err != nil {
+			return err
+		}
+
+		var row []string
+		for _, col := range c.columns {
+			values, err := col.jsonPath.FindResults(queryObj)
+			if err != nil {
+				return err
+			}
+
+			var vals []string
+			for _, result := range values {
+				for _, v := range result {
+					vals = append(vals, fmt.Sprintf("%v", v.Interface()))
+				}
+			}
+
+			if len(vals) == 0 {
+				row = append(row, "<none>")
+			} else {
+				row = append(row, strings.Join(vals, ","))
+			}
+		}
+
+		if _, err := fmt.Fprintln(writer, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
+
+func parseJSONPath(expr string) (*jsonpath.JSONPath, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.Contains(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+
+	j := jsonpath.New("out").AllowMissingKeys(true)
+	if err := j.Parse(expr); err != nil {
+		return nil, fmt.Errorf("error parsing jsonpath %s: %v", expr, err)
+	}
+	return j, nil
+}
+
+func toQueryObject(obj runtime.Object) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var queryObj interface{}
+	err = json.Unmarshal(data, &queryObj)
+	return queryObj, err
+}
@@ -0,0 +1,287 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package k8s_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+func TestObjectPrinter(t *testing.T) {
+	spec.Run(t, "TestObjectPrinter", testObjectPrinter)
+}
+
+func testObjectPrinter(t *testing.T, when spec.G, it spec.S) {
+	img := &v1alpha1.Image{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "some-image",
+			Namespace: "some-namespace",
+		},
+		Spec: v1alpha1.ImageSpec{
+			Tag: "some-registry.io/some-repo",
+		},
+		Status: v1alpha1.ImageStatus{
+			LatestImage: "some-registry.io/some-repo@sha256:123",
+		},
+	}
+
+	when("jsonpath", func() {
+		it("prints the result of the expression", func() {
+			printer, err := k8s.NewObjectPrinter("jsonpath={.status.latestImage}")
+			require.NoError(t, err)
+
+			out := &bytes.Buffer{}
+			require.NoError(t, printer.PrintObject(img, out))
+			require.Equal(t, "some-registry.io/some-repo@sha256:123\n", out.String())
+		})
+
+		it("accepts expressions without braces", func() {
+			printer, err := k8s.NewObjectPrinter("jsonpath=.spec.tag")
+			require.NoError(t, err)
+
+			out := &bytes.Buffer{}
+			require.NoError(t, printer.PrintObject(img, out))
+			require.Equal(t, "some-registry.io/some-repo\n", out.String())
+		})
+
+		it("prints an empty string for missing fields", func() {
+			printer, err := k8s.NewObjectPrinter("jsonpath={.status.missing}")
+			require.NoError(t, err)
+
+			out := &bytes.Buffer{}
+			require.NoError(t, printer.PrintObject(img, out))
+			require.Equal(t, "\n", out.String())
+		})
+
+		it("errors with an empty expression", func() {
+			_, err := k8s.NewObjectPrinter("jsonpath=")
+			require.EqualError(t, err, "jsonpath template must not be empty")
+		})
+	})
+
+	when("jsonpath-file", func() {
+		it("reads the expression from a file", func() {
+			f, err := ioutil.TempFile("", "jsonpath")
+			require.NoError(t, err)
+			defer os.Remove(f.Name())
+
+			require.NoError(t, ioutil.WriteFile(f.Name(), []byte("{.metadata.name}\n"), 0644))
+
+			printer, err := k8s.NewObjectPrinter("jsonpath-file=" + f.Name())
+			require.NoError(t, err)
+
+			out := &bytes.Buffer{}
+			require.NoError(t, printer.PrintObject(img, out))
+			require.Equal(t, "some-image\n", out.String())
+		})
+	})
+
+	when("go-template", func() {
+		it("renders the template with the object", func() {
+			printer, err := k8s.NewObjectPrinter(`go-template=image {{.metadata.name}} built {{.status.latestImage}}{{"\n"}}`)
+			require.NoError(t, err)
+
+			out := &bytes.Buffer{}
+			require.NoError(t, printer.PrintObject(img, out))
+			require.Equal(t, "image some-image built some-registry.io/some-repo@sha256:123\n", out.String())
+		})
+
+		it("errors with an empty template", func() {
+			_, err := k8s.NewObjectPrinter("go-template=")
+			require.EqualError(t, err, "go-template must not be empty")
+		})
+
+		it("errors when the template cannot be parsed", func() {
+			_, err := k8s.NewObjectPrinter("go-template={{.metadata.name")
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "error parsing go-template")
+		})
+
+		it("errors and prints nothing when the template fails to execute", func() {
+			printer, err := k8s.NewObjectPrinter(`go-template={{.metadata.name}}{{index .metadata.name 100}}`)
+			require.NoError(t, err)
+
+			out := &bytes.Buffer{}
+			err = printer.PrintObject(img, out)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "error executing go-template")
+			require.Empty(t, out.String())
+		})
+	})
+
+	when("go-template-file", func() {
+		it("reads the template from a file", func() {
+			f, err := ioutil.TempFile("", "go-template")
+			require.NoError(t, err)
+			defer os.Remove(f.Name())
+
+			require.NoError(t, ioutil.WriteFile(f.Name(), []byte("name: {{.metadata.name}}\n"), 0644))
+
+			printer, err := k8s.NewObjectPrinter("go-template-file=" + f.Name())
+			require.NoError(t, err)
+
+			out := &bytes.Buffer{}
+			require.NoError(t, printer.PrintObject(img, out))
+			require.Equal(t, "name: some-image\n", out.String())
+		})
+
+		it("errors when the file cannot be read", func() {
+			_, err := k8s.NewObjectPrinter("go-template-file=does-not-exist")
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "error reading go-template file")
+		})
+	})
+
+	when("custom-columns", func() {
+		it("prints a header once followed by a row for each object", func() {
+			printer, err := k8s.NewObjectPrinter("custom-columns=NAME:.metadata.name,IMAGE:.status.latestImage")
+			require.NoError(t, err)
+
+			out := &bytes.Buffer{}
+			require.NoError(t, printer.PrintObject(img, out))
+			require.NoError(t, printer.PrintObject(&v1alpha1.Image{ObjectMeta: metav1.ObjectMeta{Name: "other-image"}}, out))
+			require.Equal(t, `NAME          IMAGE
+some-image    some-registry.io/some-repo@sha256:123
+other-image    <none>
+`, out.String())
+		})
+
+		it("prints a row for each item in a list", func() {
+			printer, err := k8s.NewObjectPrinter("custom-columns=NAME:.metadata.name,TAG:.spec.tag")
+			require.NoError(t, err)
+
+			list := &v1alpha1.ImageList{
+				Items: []v1alpha1.Image{
+					*img,
+					{ObjectMeta: metav1.ObjectMeta{Name: "other-image"}, Spec: v1alpha1.ImageSpec{Tag: "other-tag"}},
+				},
+			}
+
+			out := &bytes.Buffer{}
+			require.NoError(t, printer.PrintObject(list, out))
+			require.Equal(t, `NAME           TAG
+some-image     some-registry.io/some-repo
+other-image    other-tag
+`, out.String())
+		})
+
+		it("errors with an invalid spec", func() {
+			_, err := k8s.NewObjectPrinter("custom-columns=NAME")
+			require.EqualError(t, err, "unexpected custom-columns spec: NAME, expected <header>:<json-path-expr>")
+		})
+	})
+
+	when("custom-columns-file", func() {
+		it("reads the headers and paths from a file", func() {
+			f, err := ioutil.TempFile("", "custom-columns")
+			require.NoError(t, err)
+			defer os.Remove(f.Name())
+
+			require.NoError(t, ioutil.WriteFile(f.Name(), []byte("NAME   TAG\n.metadata.name   .spec.tag\n"), 0644))
+
+			printer, err := k8s.NewObjectPrinter("custom-columns-file=" + f.Name())
+			require.NoError(t, err)
+
+			out := &bytes.Buffer{}
+			require.NoError(t, printer.PrintObject(img, out))
+			require.Equal(t, `NAME          TAG
+some-image    some-registry.io/some-repo
+`, out.String())
+		})
+	})
+
+	when("name", func() {
+		typeMeta := func(kind string) metav1.TypeMeta {
+			return metav1.TypeMeta{APIVersion: "kpack.io/v1alpha1", Kind: kind}
+		}
+
+		it("prints the kind and name of the kpack resources", func() {
+			printer, err := k8s.NewObjectPrinter("name")
+			require.NoError(t, err)
+
+			out := &bytes.Buffer{}
+			for _, obj := range []runtime.Object{
+				&v1alpha1.Image{TypeMeta: typeMeta("Image"), ObjectMeta: metav1.ObjectMeta{Name: "some-image"}},
+				&v1alpha1.Builder{TypeMeta: typeMeta(v1alpha1.BuilderKind), ObjectMeta: metav1.ObjectMeta{Name: "some-builder"}},
+				&v1alpha1.ClusterBuilder{TypeMeta: typeMeta(v1alpha1.ClusterBuilderKind), ObjectMeta: metav1.ObjectMeta{Name: "some-cluster-builder"}},
+				&v1alpha1.ClusterStack{TypeMeta: typeMeta(v1alpha1.ClusterStackKind), ObjectMeta: metav1.ObjectMeta{Name: "some-stack"}},
+				&v1alpha1.ClusterStore{TypeMeta: typeMeta(v1alpha1.ClusterStoreKind), ObjectMeta: metav1.ObjectMeta{Name: "some-store"}},
+			} {
+				require.NoError(t, printer.PrintObject(obj, out))
+			}
+
+			require.Equal(t, `image.kpack.io/some-image
+builder.kpack.io/some-builder
+clusterbuilder.kpack.io/some-cluster-builder
+clusterstack.kpack.io/some-stack
+clusterstore.kpack.io/some-store
+`, out.String())
+		})
+
+		it("omits the group of core resources", func() {
+			printer, err := k8s.NewObjectPrinter("name")
+			require.NoError(t, err)
+
+			out := &bytes.Buffer{}
+			secret := &corev1.Secret{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"}, ObjectMeta: metav1.ObjectMeta{Name: "some-secret"}}
+			require.NoError(t, printer.PrintObject(secret, out))
+			require.Equal(t, "secret/some-secret\n", out.String())
+		})
+
+		it("prints a line for each item of a typed list", func() {
+			printer, err := k8s.NewObjectPrinter("name")
+			require.NoError(t, err)
+
+			list := &v1alpha1.ImageList{
+				TypeMeta: typeMeta("ImageList"),
+				Items: []v1alpha1.Image{
+					*img,
+					{ObjectMeta: metav1.ObjectMeta{Name: "other-image"}},
+				},
+			}
+
+			out := &bytes.Buffer{}
+			require.NoError(t, printer.PrintObject(list, out))
+			require.Equal(t, "image.kpack.io/some-image\nimage.kpack.io/other-image\n", out.String())
+		})
+
+		it("uses the kind of each item of a v1 list", func() {
+			printer, err := k8s.NewObjectPrinter("name")
+			require.NoError(t, err)
+
+			builder := &v1alpha1.Builder{TypeMeta: typeMeta(v1alpha1.BuilderKind), ObjectMeta: metav1.ObjectMeta{Name: "some-builder"}}
+			list := &metav1.List{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "List"},
+				Items:    []runtime.RawExtension{{Object: builder}},
+			}
+
+			out := &bytes.Buffer{}
+			require.NoError(t, printer.PrintObject(list, out))
+			require.Equal(t, "builder.kpack.io/some-builder\n", out.String())
+		})
+
+		it("errors when the kind is not set", func() {
+			printer, err := k8s.NewObjectPrinter("name")
+			require.NoError(t, err)
+
+			require.EqualError(t, printer.PrintObject(img, &bytes.Buffer{}), `missing kind for "some-image"`)
+		})
+	})
+
+	it("errors with an unsupported format", func() {
+		_, err := k8s.NewObjectPrinter("xml")
+		require.EqualError(t, err, `unsupported output format: "xml", supported formats are yaml, json, name, jsonpath=<template>, jsonpath-file=<path>, go-template=<template>, go-template-file=<path>, custom-columns=<spec>, custom-columns-file=<path>`)
+	})
+}
@@ -5,9 +5,11 @@ package fakes
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"github.com/pkg/errors"
 )
 
@@ -65,7 +67,13 @@ func (f *Fetcher) Fetch(_ authn.Keychain, src string) (v1.Image, error) {
 	f.callCount++
 	image, ok := f.images[src]
 	if !ok {
-		return nil, errors.Errorf("image not found: %q", src)
+		return nil, errors.WithStack(&transport.Error{
+			StatusCode: http.StatusNotFound,
+			Errors: []transport.Diagnostic{{
+				Code:    transport.ManifestUnknownErrorCode,
+				Message: fmt.Sprintf("image not found: %q", src),
+			}},
+		})
 	}
 	return image, nil
 }
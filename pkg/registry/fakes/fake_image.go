@@ -9,8 +9,9 @@ import (
 )
 
 type FakeImage struct {
-	labels map[string]string
-	digest v1.Hash
+	labels           map[string]string
+	digest           v1.Hash
+	layerAnnotations []map[string]string
 }
 
 func NewFakeImage(digest string) FakeImage {
@@ -22,6 +23,17 @@ func NewFakeImage(digest string) FakeImage {
 	}
 }
 
+// NewFakeAnnotatedImage has a manifest layer for each of the annotations, such as the layers of a cosign signature
+func NewFakeAnnotatedImage(digest string, layerAnnotations ...map[string]string) FakeImage {
+	return FakeImage{
+		digest: v1.Hash{
+			Algorithm: "sha256",
+			Hex:       digest,
+		},
+		layerAnnotations: layerAnnotations,
+	}
+}
+
 func NewFakeLabeledImage(label, labelValue, digest string) FakeImage {
 	return FakeImage{
 		labels: map[string]string{
@@ -69,7 +81,11 @@ func (f FakeImage) Digest() (v1.Hash, error) {
 }
 
 func (f FakeImage) Manifest() (*v1.Manifest, error) {
-	return &v1.Manifest{}, nil
+	manifest := &v1.Manifest{}
+	for _, annotations := range f.layerAnnotations {
+		manifest.Layers = append(manifest.Layers, v1.Descriptor{Annotations: annotations})
+	}
+	return manifest, nil
 }
 
 func (f FakeImage) RawManifest() ([]byte, error) {
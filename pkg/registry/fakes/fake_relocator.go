@@ -11,6 +11,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/stream"
 )
 
 type Relocator struct {
@@ -30,6 +31,10 @@ func (r *Relocator) Relocate(keychain authn.Keychain, image v1.Image, dest strin
 		Dest     string
 	}{keychain, image, dest})
 
+	if err := consumeStreamedLayers(image); err != nil {
+		return "", err
+	}
+
 	digest, err := image.Digest()
 	if err != nil {
 		return "", err
@@ -56,6 +61,31 @@ func (r *Relocator) Relocate(keychain authn.Keychain, image v1.Image, dest strin
 	return refDigestStr, err
 }
 
+// consumeStreamedLayers reads the layers that have not been read yet as a real relocator would
+func consumeStreamedLayers(image v1.Image) error {
+	layers, err := image.Layers()
+	if err != nil {
+		return err
+	}
+
+	for _, layer := range layers {
+		if _, err := layer.Digest(); err != stream.ErrNotComputed {
+			continue
+		}
+
+		rc, err := layer.Compressed()
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(ioutil.Discard, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *Relocator) CallCount() int {
 	return len(r.calls)
 }
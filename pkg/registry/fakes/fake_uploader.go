@@ -6,13 +6,18 @@ package fakes
 import (
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/google/go-containerregistry/pkg/authn"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/archive"
 )
 
 type SourceUploader struct {
 	changeState bool
 	writer      io.Writer
+
+	Filter archive.FileFilter
 }
 
 func NewFakeSourceUploader(writer io.Writer, changeState bool) *SourceUploader {
@@ -22,7 +27,17 @@ func NewFakeSourceUploader(writer io.Writer, changeState bool) *SourceUploader {
 	}
 }
 
-func (f *SourceUploader) Upload(keychain authn.Keychain, dstImgRefStr, srcPath string) (string, error) {
+func (f *SourceUploader) Upload(keychain authn.Keychain, dstImgRefStr, srcPath string, filter archive.FileFilter) (string, error) {
+	f.Filter = filter
+	if filter != nil {
+		// walk the local source so that the filter behaves as it would for a real upload
+		tarPath, err := archive.CreateTar(srcPath, filter)
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(tarPath)
+	}
+
 	uploadPath := fmt.Sprintf("%s:source-id", dstImgRefStr)
 	var message string
 	if !f.changeState {
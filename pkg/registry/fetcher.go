@@ -39,6 +39,7 @@ func (d DefaultFetcher) Fetch(keychain authn.Keychain, src string) (v1.Image, er
 		// https://github.com/golang/go/issues/16736
 		if runtime.GOOS == "windows" {
 			d.tlsCfg.CaCertPath = ""
+			d.tlsCfg.CaCertPaths = nil
 		}
 
 		t, err := d.tlsCfg.Transport()
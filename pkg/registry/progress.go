@@ -0,0 +1,101 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	progressBarWidth  = 30
+	progressStep      = 25
+	progressLogPeriod = 5 * time.Second
+	progressClearLine = "\r\033[2K"
+)
+
+// progressPrinter renders byte count progress as a bar on a terminal
+// and as periodic percentage lines otherwise
+type progressPrinter struct {
+	writer    io.Writer
+	label     string
+	tty       bool
+	nextPct   int64
+	lastPrint time.Time
+}
+
+func newProgressPrinter(writer io.Writer, label string) *progressPrinter {
+	return &progressPrinter{
+		writer:    writer,
+		label:     label,
+		tty:       isTerminal(),
+		nextPct:   progressStep,
+		lastPrint: time.Now(),
+	}
+}
+
+func (p *progressPrinter) Update(complete, total int64) {
+	now := time.Now()
+
+	if p.tty {
+		if now.Sub(p.lastPrint) < framerate {
+			return
+		}
+		p.lastPrint = now
+		fmt.Fprintf(p.writer, "%s\t%s %s", progressClearLine, p.label, progressText(complete, total))
+		return
+	}
+
+	if total <= 0 {
+		if now.Sub(p.lastPrint) < progressLogPeriod {
+			return
+		}
+		p.lastPrint = now
+		fmt.Fprintf(p.writer, "\t%s %s\n", p.label, progressText(complete, total))
+		return
+	}
+
+	pct := complete * 100 / total
+	if pct < p.nextPct || pct >= 100 {
+		return
+	}
+	for p.nextPct <= pct {
+		p.nextPct += progressStep
+	}
+	fmt.Fprintf(p.writer, "\t%s %s\n", p.label, progressText(complete, total))
+}
+
+// Done clears the progress bar so that the caller can print a summary line
+func (p *progressPrinter) Done() {
+	if p.tty {
+		fmt.Fprint(p.writer, progressClearLine)
+	}
+}
+
+func progressText(complete, total int64) string {
+	if total <= 0 {
+		return readableSize(complete)
+	}
+
+	if complete > total {
+		complete = total
+	}
+	filled := int(complete * progressBarWidth / total)
+	return fmt.Sprintf("[%s%s] %s / %s (%d%%)",
+		strings.Repeat("=", filled),
+		strings.Repeat(" ", progressBarWidth-filled),
+		readableSize(complete),
+		readableSize(total),
+		complete*100/total,
+	)
+}
+
+func isTerminal() bool {
+	return terminal.IsTerminal(int(os.Stdout.Fd())) || terminal.IsTerminal(int(os.Stderr.Fd()))
+}
@@ -6,12 +6,14 @@ package registry
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"time"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/stream"
 )
 
 type Relocator interface {
@@ -27,6 +29,10 @@ func NewDiscardRelocator(writer io.Writer) DiscardRelocator {
 }
 
 func (d DiscardRelocator) Relocate(keychain authn.Keychain, src v1.Image, destination string) (string, error) {
+	if err := consumeStreamedLayers(src); err != nil {
+		return "", err
+	}
+
 	cfg, err := getDstImageInfo(src, destination)
 	if err != nil {
 		return "", err
@@ -37,20 +43,38 @@ func (d DiscardRelocator) Relocate(keychain authn.Keychain, src v1.Image, destin
 }
 
 type DefaultRelocator struct {
-	tlsCfg TLSConfig
-	writer io.Writer
+	tlsCfg   TLSConfig
+	writer   io.Writer
+	progress bool
 }
 
 func NewDefaultRelocator(writer io.Writer, tlsCfg TLSConfig) DefaultRelocator {
 	return DefaultRelocator{writer: writer, tlsCfg: tlsCfg}
 }
 
+// NewProgressRelocator returns a relocator that reports the bytes pushed to the registry
+// instead of displaying a spinner
+func NewProgressRelocator(writer io.Writer, tlsCfg TLSConfig) DefaultRelocator {
+	return DefaultRelocator{writer: writer, tlsCfg: tlsCfg, progress: true}
+}
+
 func (d DefaultRelocator) Relocate(keychain authn.Keychain, src v1.Image, destination string) (string, error) {
+	streamed, err := hasStreamedLayers(src)
+	if err != nil {
+		return "", err
+	} else if streamed {
+		return d.relocateStreamed(keychain, src, destination)
+	}
+
 	cfg, err := getDstImageInfo(src, destination)
 	if err != nil {
 		return "", err
 	}
 
+	if d.progress {
+		return d.relocateWithProgress(keychain, src, cfg)
+	}
+
 	if _, err := d.writer.Write([]byte(fmt.Sprintf("\tUploading '%s'", cfg.refDigestStr))); err != nil {
 		return cfg.refDigestStr, err
 	}
@@ -59,21 +83,87 @@ func (d DefaultRelocator) Relocate(keychain authn.Keychain, src v1.Image, destin
 	defer spinner.Stop()
 	go spinner.Write()
 
-	transport, err := d.tlsCfg.Transport()
+	return cfg.refDigestStr, d.write(keychain, src, cfg.refRepo, cfg.tag)
+}
+
+// relocateStreamed writes an image with layers that are read as they are pushed,
+// the digest of the image is only known once the layers have been written
+func (d DefaultRelocator) relocateStreamed(keychain authn.Keychain, src v1.Image, destination string) (string, error) {
+	refRepo, err := parseDstRepo(destination)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := fmt.Fprintf(d.writer, "\tUploading '%s'\n", refRepo); err != nil {
+		return "", err
+	}
+
+	if err := d.write(keychain, src, refRepo, refRepo.Context().Tag(timestampTag())); err != nil {
+		return "", err
+	}
+
+	cfg, err := getDstImageInfo(src, destination)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = fmt.Fprintf(d.writer, "\tUploaded '%s' (%s)\n", cfg.refDigestStr, readableSize(cfg.size))
+	return cfg.refDigestStr, err
+}
+
+func (d DefaultRelocator) relocateWithProgress(keychain authn.Keychain, src v1.Image, cfg relocateImageInfo) (string, error) {
+	if _, err := fmt.Fprintf(d.writer, "\tUploading '%s'\n", cfg.refDigestStr); err != nil {
+		return cfg.refDigestStr, err
+	}
+
+	updates := make(chan v1.Update, 100)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	printer := newProgressPrinter(d.writer, "Pushing")
+
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case update, ok := <-updates:
+				if !ok {
+					return
+				}
+				printer.Update(update.Complete, update.Total)
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	err := d.write(keychain, src, cfg.refRepo, cfg.tag, remote.WithProgress(updates))
+	close(stop)
+	<-done
+	printer.Done()
 	if err != nil {
 		return cfg.refDigestStr, err
 	}
+
+	_, err = fmt.Fprintf(d.writer, "\tUploaded '%s' (%s)\n", cfg.refDigestStr, readableSize(cfg.size))
+	return cfg.refDigestStr, err
+}
+
+func (d DefaultRelocator) write(keychain authn.Keychain, src v1.Image, refRepo name.Reference, tag name.Tag, writeOptions ...remote.Option) error {
+	transport, err := d.tlsCfg.Transport()
+	if err != nil {
+		return err
+	}
 	imgWriteOptions := []remote.Option{
 		remote.WithAuthFromKeychain(keychain),
 		remote.WithTransport(transport),
 	}
 
-	err = remote.Write(cfg.refRepo, src, imgWriteOptions...)
+	err = remote.Write(refRepo, src, append(imgWriteOptions, writeOptions...)...)
 	if err != nil {
-		return cfg.refDigestStr, newImageAccessError(cfg.refRepo.Context().RegistryStr(), err)
+		return newImageAccessError(refRepo.Context().RegistryStr(), err)
 	}
 
-	return cfg.refDigestStr, remote.Tag(cfg.tag, src, imgWriteOptions...)
+	return remote.Tag(tag, src, imgWriteOptions...)
 }
 
 type relocateImageInfo struct {
@@ -86,15 +176,7 @@ type relocateImageInfo struct {
 func getDstImageInfo(srcImage v1.Image, dstRepoStr string) (relocateImageInfo, error) {
 	imgInfo := relocateImageInfo{}
 
-	refDstRepo, err := name.ParseReference(dstRepoStr, name.WeakValidation)
-	if err != nil {
-		return imgInfo, err
-	}
-
-	refContext := refDstRepo.Context()
-	refName := fmt.Sprintf("%s/%s", refContext.RegistryStr(), refContext.RepositoryStr())
-
-	refDstRepo, err = name.ParseReference(refName, name.WeakValidation)
+	refDstRepo, err := parseDstRepo(dstRepoStr)
 	if err != nil {
 		return imgInfo, err
 	}
@@ -118,6 +200,62 @@ func getDstImageInfo(srcImage v1.Image, dstRepoStr string) (relocateImageInfo, e
 	return imgInfo, err
 }
 
+func parseDstRepo(dstRepoStr string) (name.Reference, error) {
+	refDstRepo, err := name.ParseReference(dstRepoStr, name.WeakValidation)
+	if err != nil {
+		return nil, err
+	}
+
+	refContext := refDstRepo.Context()
+	return name.ParseReference(fmt.Sprintf("%s/%s", refContext.RegistryStr(), refContext.RepositoryStr()), name.WeakValidation)
+}
+
+func hasStreamedLayers(img v1.Image) (bool, error) {
+	layers, err := streamedLayers(img)
+	return len(layers) > 0, err
+}
+
+// consumeStreamedLayers reads the streamed layers of the image without writing them
+// anywhere so that the digest of the image can be computed
+func consumeStreamedLayers(img v1.Image) error {
+	layers, err := streamedLayers(img)
+	if err != nil {
+		return err
+	}
+
+	for _, layer := range layers {
+		rc, err := layer.Compressed()
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(ioutil.Discard, rc); err != nil {
+			rc.Close()
+			return err
+		}
+
+		if err := rc.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func streamedLayers(img v1.Image) ([]v1.Layer, error) {
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	var streamed []v1.Layer
+	for _, layer := range layers {
+		if _, err := layer.Digest(); err == stream.ErrNotComputed {
+			streamed = append(streamed, layer)
+		}
+	}
+	return streamed, nil
+}
+
 func timestampTag() string {
 	now := time.Now()
 	return fmt.Sprintf("%s%02d%02d%02d", now.Format("20060102"), now.Hour(), now.Minute(), now.Second())
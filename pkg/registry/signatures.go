@@ -0,0 +1,140 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/pkg/errors"
+)
+
+const (
+	signatureSuffix   = "sig"
+	attestationSuffix = "att"
+	sbomSuffix        = "sbom"
+
+	predicateTypeAnnotation = "predicateType"
+)
+
+var sbomPredicateTypes = []string{"https://spdx.dev/Document", "https://cyclonedx.org/bom"}
+
+// SignatureStatus describes the cosign signatures, attestations and SBOM stored next to an image
+type SignatureStatus struct {
+	Signatures     int
+	PredicateTypes []string
+	SBOM           bool
+}
+
+// FetchSignatureStatus looks up the artifacts that cosign stores in the repository of ref with
+// the sha256-<digest>.sig, .att and .sbom tags, an artifact that does not exist is not an error
+func FetchSignatureStatus(fetcher Fetcher, keychain authn.Keychain, ref string) (SignatureStatus, error) {
+	var status SignatureStatus
+
+	digest, err := resolveDigest(fetcher, keychain, ref)
+	if err != nil {
+		return status, err
+	}
+
+	signatures, err := fetchLayerAnnotations(fetcher, keychain, cosignTag(digest, signatureSuffix))
+	if err != nil {
+		return status, err
+	}
+	status.Signatures = len(signatures)
+
+	attestations, err := fetchLayerAnnotations(fetcher, keychain, cosignTag(digest, attestationSuffix))
+	if err != nil {
+		return status, err
+	}
+	for _, annotations := range attestations {
+		predicateType, ok := annotations[predicateTypeAnnotation]
+		if !ok {
+			continue
+		}
+		status.PredicateTypes = append(status.PredicateTypes, predicateType)
+		if isSBOMPredicateType(predicateType) {
+			status.SBOM = true
+		}
+	}
+
+	sboms, err := fetchLayerAnnotations(fetcher, keychain, cosignTag(digest, sbomSuffix))
+	if err != nil {
+		return status, err
+	}
+	if sboms != nil {
+		status.SBOM = true
+	}
+
+	return status, nil
+}
+
+func resolveDigest(fetcher Fetcher, keychain authn.Keychain, ref string) (name.Digest, error) {
+	if digest, err := name.NewDigest(ref, name.WeakValidation); err == nil {
+		return digest, nil
+	}
+
+	tag, err := name.NewTag(ref, name.WeakValidation)
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	img, err := fetcher.Fetch(keychain, ref)
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	hash, err := img.Digest()
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	return tag.Context().Digest(hash.String()), nil
+}
+
+func cosignTag(digest name.Digest, suffix string) string {
+	return fmt.Sprintf("%s:%s.%s", digest.Context().Name(), strings.Replace(digest.DigestStr(), ":", "-", 1), suffix)
+}
+
+// fetchLayerAnnotations returns nil when the artifact does not exist and a non-nil
+// slice with the annotations of every layer of the artifact otherwise
+func fetchLayerAnnotations(fetcher Fetcher, keychain authn.Keychain, ref string) ([]map[string]string, error) {
+	img, err := fetcher.Fetch(keychain, ref)
+	if isNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := make([]map[string]string, 0, len(manifest.Layers))
+	for _, layer := range manifest.Layers {
+		annotations = append(annotations, layer.Annotations)
+	}
+	return annotations, nil
+}
+
+func isNotFound(err error) bool {
+	var transportErr *transport.Error
+	if !errors.As(err, &transportErr) {
+		return false
+	}
+	return transportErr.StatusCode == http.StatusNotFound
+}
+
+func isSBOMPredicateType(predicateType string) bool {
+	for _, t := range sbomPredicateTypes {
+		if strings.HasPrefix(predicateType, t) {
+			return true
+		}
+	}
+	return false
+}
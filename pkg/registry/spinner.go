@@ -6,11 +6,8 @@ package registry
 import (
 	"fmt"
 	"io"
-	"os"
 	"strconv"
 	"time"
-
-	"golang.org/x/crypto/ssh/terminal"
 )
 
 const framerate = time.Millisecond * 150
@@ -26,13 +23,12 @@ type uploadSpinner struct {
 }
 
 func newUploadSpinner(writer io.Writer, size int64) *uploadSpinner {
-	isTerminal := terminal.IsTerminal(int(os.Stdout.Fd())) || terminal.IsTerminal(int(os.Stderr.Fd()))
 	sp := &uploadSpinner{
 		size:     readableSize(size),
 		stopChan: make(chan struct{}),
 		doneChan: make(chan struct{}),
 		Output:   writer,
-		NotTty:   !isTerminal,
+		NotTty:   !isTerminal(),
 	}
 	return sp
 }
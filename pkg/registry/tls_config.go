@@ -10,26 +10,36 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"os"
 	"runtime"
 	"time"
+
+	"github.com/google/go-containerregistry/pkg/logs"
 )
 
 type TLSConfig struct {
 	CaCertPath  string
+	CaCertPaths []string
 	VerifyCerts bool
+	Verbose     bool
 }
 
 func (t *TLSConfig) Transport() (*http.Transport, error) {
+	if t.Verbose {
+		enableVerboseLogging()
+	}
+
 	pool, err := x509.SystemCertPool()
 	if err != nil {
 		pool = x509.NewCertPool()
 	}
 
-	if t.CaCertPath != "" {
-		if cert, err := ioutil.ReadFile(t.CaCertPath); err != nil {
-			return nil, fmt.Errorf("reading CA certificate from '%s': %s", t.CaCertPath, err)
+	caCertPaths := t.caCertPaths()
+	for _, path := range caCertPaths {
+		if cert, err := ioutil.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("reading CA certificate from '%s': %s", path, err)
 		} else if ok := pool.AppendCertsFromPEM(cert); !ok {
-			return nil, fmt.Errorf("adding CA certificate from '%s': failed", t.CaCertPath)
+			return nil, fmt.Errorf("adding CA certificate from '%s': failed", path)
 		}
 	}
 
@@ -53,9 +63,29 @@ func (t *TLSConfig) Transport() (*http.Transport, error) {
 
 	// Do not set RootCAs when custom CA is not set on windows
 	// https://github.com/golang/go/issues/16736
-	if runtime.GOOS == "windows" && t.CaCertPath == "" {
+	if runtime.GOOS == "windows" && len(caCertPaths) == 0 {
 		transport.TLSClientConfig.RootCAs = nil
 	}
 
 	return transport, nil
 }
+
+func (t *TLSConfig) caCertPaths() []string {
+	var paths []string
+	if t.CaCertPath != "" {
+		paths = append(paths, t.CaCertPath)
+	}
+	for _, p := range t.CaCertPaths {
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// enableVerboseLogging sends the go-containerregistry debug and warning logs to stderr, the debug logs
+// contain the registry requests and responses, including auth challenges, and the warnings contain the retries
+func enableVerboseLogging() {
+	logs.Debug.SetOutput(os.Stderr)
+	logs.Warn.SetOutput(os.Stderr)
+}
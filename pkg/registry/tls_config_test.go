@@ -7,9 +7,11 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/google/go-containerregistry/pkg/logs"
 	"github.com/sclevine/spec"
 	"github.com/stretchr/testify/require"
 
@@ -52,6 +54,70 @@ func testTLSConfig(t *testing.T, when spec.G, it spec.S) {
 		require.True(t, transport.TLSClientConfig.InsecureSkipVerify)
 	})
 
+	it("adds each of the ca cert paths to the cert pool", func() {
+		certPath := filepath.Join("testdata", "ca.crt")
+		certData, err := ioutil.ReadFile(certPath)
+		require.NoError(t, err)
+
+		block, _ := pem.Decode(certData)
+		require.NotNil(t, block)
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+
+		systemPool, err := x509.SystemCertPool()
+		if err != nil {
+			systemPool = x509.NewCertPool()
+		}
+
+		tlsConfig := registry.TLSConfig{
+			CaCertPaths: []string{certPath},
+			VerifyCerts: true,
+		}
+
+		transport, err := tlsConfig.Transport()
+		require.NoError(t, err)
+
+		subjects := transport.TLSClientConfig.RootCAs.Subjects()
+		require.Contains(t, subjects, cert.RawSubject)
+		require.Len(t, subjects, len(systemPool.Subjects())+1)
+	})
+
+	it("adds every certificate of a PEM bundle to the cert pool", func() {
+		certData, err := ioutil.ReadFile(filepath.Join("testdata", "ca-bundle.crt"))
+		require.NoError(t, err)
+
+		var expectedSubjects [][]byte
+		for block, rest := pem.Decode(certData); block != nil; block, rest = pem.Decode(rest) {
+			cert, err := x509.ParseCertificate(block.Bytes)
+			require.NoError(t, err)
+			expectedSubjects = append(expectedSubjects, cert.RawSubject)
+		}
+		require.Len(t, expectedSubjects, 2)
+
+		tlsConfig := registry.TLSConfig{
+			CaCertPath:  filepath.Join("testdata", "ca-bundle.crt"),
+			VerifyCerts: true,
+		}
+
+		transport, err := tlsConfig.Transport()
+		require.NoError(t, err)
+
+		subjects := transport.TLSClientConfig.RootCAs.Subjects()
+		for _, expected := range expectedSubjects {
+			require.Contains(t, subjects, expected)
+		}
+	})
+
+	it("returns an error when a ca cert path is not a PEM certificate", func() {
+		tlsConfig := registry.TLSConfig{
+			CaCertPaths: []string{filepath.Join("testdata", "sample.zip")},
+		}
+
+		_, err := tlsConfig.Transport()
+		require.EqualError(t, err, "adding CA certificate from 'testdata/sample.zip': failed")
+	})
+
 	it("sets skip verify to false when verify certs is true", func() {
 		fetcher := registry.TLSConfig{
 			CaCertPath:  "",
@@ -62,4 +128,16 @@ func testTLSConfig(t *testing.T, when spec.G, it spec.S) {
 		require.NoError(t, err)
 		require.False(t, transport.TLSClientConfig.InsecureSkipVerify)
 	})
+
+	it("sends the registry debug logs to stderr when verbose", func() {
+		defer logs.Debug.SetOutput(ioutil.Discard)
+		defer logs.Warn.SetOutput(ioutil.Discard)
+
+		tlsConfig := registry.TLSConfig{Verbose: true}
+
+		_, err := tlsConfig.Transport()
+		require.NoError(t, err)
+		require.Equal(t, os.Stderr, logs.Debug.Writer())
+		require.Equal(t, os.Stderr, logs.Warn.Writer())
+	})
 }
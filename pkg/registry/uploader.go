@@ -4,11 +4,14 @@
 package registry
 
 import (
+	"io"
 	"os"
 
 	"github.com/google/go-containerregistry/pkg/authn"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/stream"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/pkg/errors"
 
@@ -16,31 +19,28 @@ import (
 )
 
 type SourceUploader interface {
-	Upload(keychain authn.Keychain, dstImgRefStr, srcPath string) (string, error)
+	Upload(keychain authn.Keychain, dstImgRefStr, srcPath string, filter archive.FileFilter) (string, error)
 }
 
 type DefaultSourceUploader struct {
 	Relocator Relocator
+	// Writer receives the number of source bytes uploaded when set
+	Writer io.Writer
 }
 
-func (d DefaultSourceUploader) Upload(keychain authn.Keychain, dstImgRefStr, srcPath string) (string, error) {
-	srcTarPath, err := readPathToTar(srcPath)
+func (d DefaultSourceUploader) Upload(keychain authn.Keychain, dstImgRefStr, srcPath string, filter archive.FileFilter) (string, error) {
+	layer, cleanup, err := d.sourceLayer(srcPath, filter)
 	if err != nil {
 		return "", err
 	}
 
-	defer os.Remove(srcTarPath)
+	defer cleanup()
 
 	image, err := random.Image(0, 0)
 	if err != nil {
 		return "", err
 	}
 
-	layer, err := tarball.LayerFromFile(srcTarPath)
-	if err != nil {
-		return "", err
-	}
-
 	image, err = mutate.AppendLayers(image, layer)
 	if err != nil {
 		return "", err
@@ -49,17 +49,60 @@ func (d DefaultSourceUploader) Upload(keychain authn.Keychain, dstImgRefStr, src
 	return d.Relocator.Relocate(keychain, image, dstImgRefStr)
 }
 
-func readPathToTar(path string) (string, error) {
+// sourceLayer returns a layer of the local source. Directories are streamed into the layer
+// as it is read so that the source is never held in full on disk or in memory.
+func (d DefaultSourceUploader) sourceLayer(path string, filter archive.FileFilter) (v1.Layer, func(), error) {
 	fi, err := os.Stat(path)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 
 	if !fi.IsDir() && archive.IsZip(path) {
-		return archive.ZipToTar(path)
+		tarPath, err := archive.ZipToTar(path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cleanup := func() { os.Remove(tarPath) }
+		layer, err := tarball.LayerFromFile(tarPath)
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		return layer, cleanup, nil
 	} else if !fi.IsDir() {
-		return "", errors.New("local path must be a directory or zip")
+		return nil, nil, errors.New("local path must be a directory or zip")
 	}
 
-	return archive.CreateTar(path)
+	pr, pw := io.Pipe()
+	go func() {
+		var w io.Writer = pw
+		var printer *progressPrinter
+		if d.Writer != nil {
+			printer = newProgressPrinter(d.Writer, "Uploading")
+			w = &progressWriter{writer: pw, printer: printer}
+		}
+
+		err := archive.WriteTar(w, path, filter)
+		if printer != nil {
+			printer.Done()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	// closing the reader stops the archiving if the layer is never consumed
+	return stream.NewLayer(pr), func() { pr.Close() }, nil
+}
+
+type progressWriter struct {
+	writer  io.Writer
+	written int64
+	printer *progressPrinter
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.writer.Write(b)
+	p.written += int64(n)
+	p.printer.Update(p.written, 0)
+	return n, err
 }
@@ -4,6 +4,7 @@
 package registry_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/pivotal/kpack/pkg/registry/registryfakes"
@@ -33,7 +34,7 @@ func testUploader(t *testing.T, when spec.G, it spec.S) {
 		)
 
 		it("relocates local contents to registry", func() {
-			_, err := uploader.Upload(&registryfakes.FakeKeychain{}, "myregistry.com/blah", "testdata/sample")
+			_, err := uploader.Upload(&registryfakes.FakeKeychain{}, "myregistry.com/blah", "testdata/sample", nil)
 			require.NoError(t, err)
 
 			require.Equal(t, 1, fakeRelocator.CallCount())
@@ -45,7 +46,7 @@ func testUploader(t *testing.T, when spec.G, it spec.S) {
 		})
 
 		it("relocates local zip to registry", func() {
-			_, err := uploader.Upload(&registryfakes.FakeKeychain{}, "myregistry.com/blah", "testdata/sample.zip")
+			_, err := uploader.Upload(&registryfakes.FakeKeychain{}, "myregistry.com/blah", "testdata/sample.zip", nil)
 			require.NoError(t, err)
 
 			require.Equal(t, 1, fakeRelocator.CallCount())
@@ -57,8 +58,20 @@ func testUploader(t *testing.T, when spec.G, it spec.S) {
 		})
 
 
+		it("computes the same digest without pushing on a dry run", func() {
+			var out bytes.Buffer
+			dryRunUploader := registry.DefaultSourceUploader{
+				Relocator: registry.NewDiscardRelocator(&out),
+			}
+
+			ref, err := dryRunUploader.Upload(&registryfakes.FakeKeychain{}, "myregistry.com/blah", "testdata/sample", nil)
+			require.NoError(t, err)
+			require.Equal(t, "myregistry.com/blah@"+testdataDigest, ref)
+			require.Equal(t, "\tSkipping 'myregistry.com/blah@"+testdataDigest+"'\n", out.String())
+		})
+
 		it("returns err on path to invalid zip", func() {
-			_, err := uploader.Upload(&registryfakes.FakeKeychain{}, "myregistry.com/blah", "testdata/sample/app")
+			_, err := uploader.Upload(&registryfakes.FakeKeychain{}, "myregistry.com/blah", "testdata/sample/app", nil)
 			require.EqualError(t, err, "local path must be a directory or zip")
 
 			require.Equal(t, 0, fakeRelocator.CallCount())
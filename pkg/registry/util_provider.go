@@ -22,7 +22,10 @@ func (d DefaultUtilProvider) Relocator(writer io.Writer, tlsCfg TLSConfig, chang
 }
 
 func (d DefaultUtilProvider) SourceUploader(writer io.Writer, tlsCfg TLSConfig, changeState bool) SourceUploader {
-	return &DefaultSourceUploader{Relocator: d.Relocator(writer, tlsCfg, changeState)}
+	if !changeState {
+		return &DefaultSourceUploader{Relocator: NewDiscardRelocator(writer)}
+	}
+	return &DefaultSourceUploader{Relocator: NewProgressRelocator(writer, tlsCfg), Writer: writer}
 }
 
 func (d DefaultUtilProvider) Fetcher(config TLSConfig) Fetcher {
@@ -6,6 +6,8 @@ package secret
 import (
 	"encoding/json"
 	"io/ioutil"
+	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -14,8 +16,11 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
 const (
 	DockerhubUrl  = "https://index.docker.io/v1/"
 	GcrUrl        = "gcr.io"
@@ -36,6 +41,27 @@ type Factory struct {
 	GitUrl                string
 	GitSshKeyFile         string
 	GitUser               string
+	FromLiteral           []string
+	FromFile              []string
+	DockerConfigFile      string
+	Combine               bool
+}
+
+// MakeSecrets behaves like MakeSecret but a docker config file results in one secret per registry unless Combine is set
+func (f *Factory) MakeSecrets(name, namespace string) ([]*corev1.Secret, []string, error) {
+	if err := f.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	if f.DockerConfigFile != "" {
+		return f.makeDockerConfigSecrets(name, namespace)
+	}
+
+	secret, target, err := f.MakeSecret(name, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []*corev1.Secret{secret}, []string{target}, nil
 }
 
 func (f *Factory) MakeSecret(name, namespace string) (*corev1.Secret, string, error) {
@@ -59,6 +85,10 @@ func (f *Factory) MakeSecret(name, namespace string) (*corev1.Secret, string, er
 		return f.makeGitSshSecret(name, namespace)
 	case gitBasicAuthKind:
 		return f.makeGitBasicAuthSecret(name, namespace)
+	case genericKind:
+		return f.makeGenericSecret(name, namespace)
+	case dockerConfigKind:
+		return nil, "", errors.Errorf("dockerconfig secrets must be created with MakeSecrets")
 	}
 
 	return nil, "", errors.Errorf("incorrect flags provided")
@@ -70,9 +100,15 @@ func (f *Factory) validate() error {
 	set.add("registry", f.Registry)
 	set.add("gcr", f.GcrServiceAccountFile)
 	set.add("git", f.GitUrl)
+	set.add("generic", strings.Join(f.FromLiteral, "")+strings.Join(f.FromFile, ""))
+	set.add("dockerconfig", f.DockerConfigFile)
 
 	if len(set) != 1 {
-		return errors.Errorf("secret must be one of dockerhub, gcr, registry, or git")
+		return errors.Errorf("secret must be one of dockerhub, gcr, registry, git, dockerconfig, or generic")
+	}
+
+	if f.Combine && !set.contains("dockerconfig") {
+		return errors.Errorf("parameter combine can only be used with dockerconfig")
 	}
 
 	set.add("registry-user", f.RegistryUser)
@@ -87,6 +123,14 @@ func (f *Factory) validate() error {
 		return set.getExtraParamsError("gcr")
 	}
 
+	if set.contains("generic") && len(set) != 1 {
+		return set.getExtraParamsError("generic")
+	}
+
+	if set.contains("dockerconfig") && len(set) != 1 {
+		return set.getExtraParamsError("dockerconfig")
+	}
+
 	if set.contains("registry") {
 		if !set.contains("registry-user") {
 			return errors.Errorf("missing parameter registry-user")
@@ -127,6 +171,10 @@ func (f *Factory) getSecretKind() (secretKind, error) {
 		return gitSshKind, nil
 	} else if f.GitUrl != "" && f.GitUser != "" {
 		return gitBasicAuthKind, nil
+	} else if len(f.FromLiteral) > 0 || len(f.FromFile) > 0 {
+		return genericKind, nil
+	} else if f.DockerConfigFile != "" {
+		return dockerConfigKind, nil
 	}
 	return "", errors.Errorf("received secret with unknown type")
 }
@@ -271,6 +319,155 @@ func (f *Factory) makeGitBasicAuthSecret(name string, namespace string) (*corev1
 	}, f.GitUrl, nil
 }
 
+func (f *Factory) makeGenericSecret(name string, namespace string) (*corev1.Secret, string, error) {
+	data := map[string][]byte{}
+
+	addKey := func(key string, value []byte) error {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return errors.Errorf("invalid key %q: %s", key, strings.Join(errs, ", "))
+		}
+		if _, ok := data[key]; ok {
+			return errors.Errorf("duplicate key %q", key)
+		}
+		data[key] = value
+		return nil
+	}
+
+	for _, literal := range f.FromLiteral {
+		parts := strings.SplitN(literal, "=", 2)
+		if len(parts) != 2 {
+			return nil, "", errors.Errorf("invalid from-literal %q, expected KEY=VALUE", literal)
+		}
+
+		if err := addKey(parts[0], []byte(parts[1])); err != nil {
+			return nil, "", err
+		}
+	}
+
+	for _, file := range f.FromFile {
+		key, path := filepath.Base(file), file
+		if parts := strings.SplitN(file, "=", 2); len(parts) == 2 {
+			key, path = parts[0], parts[1]
+		}
+
+		buf, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if err := addKey(key, buf); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Data: data,
+		Type: corev1.SecretTypeOpaque,
+	}, "", nil
+}
+
+func (f *Factory) makeDockerConfigSecrets(name, namespace string) ([]*corev1.Secret, []string, error) {
+	config, registries, err := readDockerConfig(f.DockerConfigFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if f.Combine {
+		secret, err := makeDockerConfigJsonSecret(name, namespace, config.Auths, registries)
+		if err != nil {
+			return nil, nil, err
+		}
+		return []*corev1.Secret{secret}, []string{strings.Join(registries, ",")}, nil
+	}
+
+	var secrets []*corev1.Secret
+	for _, registry := range registries {
+		secret, err := makeDockerConfigJsonSecret(registrySecretName(name, registry), namespace, config.Auths, []string{registry})
+		if err != nil {
+			return nil, nil, err
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, registries, nil
+}
+
+// readDockerConfig returns the docker config of the file and the sorted registries that have credentials
+func readDockerConfig(path string) (DockerConfigJson, []string, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return DockerConfigJson{}, nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &fields); err != nil {
+		return DockerConfigJson{}, nil, errors.Wrapf(err, "invalid docker config %q", path)
+	}
+
+	if _, ok := fields["auths"]; !ok {
+		return DockerConfigJson{}, nil, errors.Errorf("docker config %q does not contain an \"auths\" key", path)
+	}
+
+	var config DockerConfigJson
+	if err := json.Unmarshal(buf, &config); err != nil {
+		return DockerConfigJson{}, nil, errors.Wrapf(err, "invalid docker config %q", path)
+	}
+
+	var registries []string
+	for registry, auth := range config.Auths {
+		if auth != (authn.AuthConfig{}) {
+			registries = append(registries, registry)
+		}
+	}
+	sort.Strings(registries)
+
+	if len(registries) == 0 {
+		return DockerConfigJson{}, nil, errors.Errorf("docker config %q does not contain any registry credentials", path)
+	}
+
+	return config, registries, nil
+}
+
+func makeDockerConfigJsonSecret(name, namespace string, auths DockerCredentials, registries []string) (*corev1.Secret, error) {
+	configJson := DockerConfigJson{Auths: DockerCredentials{}}
+	for _, registry := range registries {
+		configJson.Auths[registry] = auths[registry]
+	}
+
+	dockerCfgJson, err := json.Marshal(configJson)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: dockerCfgJson,
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+	}, nil
+}
+
+// registrySecretName appends the registry host to the secret name, eg. my-creds and https://index.docker.io/v1/ become my-creds-index-docker-io
+func registrySecretName(name, registry string) string {
+	host := registry
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+
+	suffix := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(host), "-"), "-")
+	return name + "-" + suffix
+}
+
 type secretKind string
 
 const (
@@ -279,6 +476,8 @@ const (
 	registryKind                = "registry"
 	gitSshKind                  = "git ssh"
 	gitBasicAuthKind            = "git basic auth"
+	genericKind                 = "generic"
+	dockerConfigKind            = "dockerconfig"
 )
 
 type paramSet map[string]interface{}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/sclevine/spec"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/secret"
 )
@@ -36,7 +37,7 @@ func testSecretFactory(t *testing.T, when spec.G, it spec.S) {
 	when("no params are set", func() {
 		it("returns an error message", func() {
 			_, _, err := factory.MakeSecret("test-name", "test-namespace")
-			require.EqualError(t, err, "secret must be one of dockerhub, gcr, registry, or git")
+			require.EqualError(t, err, "secret must be one of dockerhub, gcr, registry, git, dockerconfig, or generic")
 		})
 	})
 
@@ -45,7 +46,7 @@ func testSecretFactory(t *testing.T, when spec.G, it spec.S) {
 			factory.DockerhubId = "some-dockerhub-id"
 			factory.GcrServiceAccountFile = "some-gcr-service-account"
 			_, _, err := factory.MakeSecret("test-name", "test-namespace")
-			require.EqualError(t, err, "secret must be one of dockerhub, gcr, registry, or git")
+			require.EqualError(t, err, "secret must be one of dockerhub, gcr, registry, git, dockerconfig, or generic")
 		})
 	})
 
@@ -138,6 +139,37 @@ func testSecretFactory(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("using generic key/value pairs", func() {
+		it("can make an opaque secret", func() {
+			factory.FromLiteral = []string{"some-key=some-value"}
+			s, target, err := factory.MakeSecret("test-name", "test-namespace")
+			require.NoError(t, err)
+			require.Equal(t, corev1.SecretTypeOpaque, s.Type)
+			require.Equal(t, map[string][]byte{"some-key": []byte("some-value")}, s.Data)
+			require.Empty(t, target)
+		})
+
+		it("returns an error message when mixed with other params", func() {
+			factory.FromLiteral = []string{"some-key=some-value"}
+			factory.GitUser = "some-git-user"
+			_, _, err := factory.MakeSecret("test-name", "test-namespace")
+			require.EqualError(t, err, "extraneous parameters: git-user")
+		})
+
+		it("validates the literal format", func() {
+			factory.FromLiteral = []string{"some-key"}
+			_, _, err := factory.MakeSecret("test-name", "test-namespace")
+			require.EqualError(t, err, `invalid from-literal "some-key", expected KEY=VALUE`)
+		})
+
+		it("validates the keys", func() {
+			factory.FromLiteral = []string{"some key=some-value"}
+			_, _, err := factory.MakeSecret("test-name", "test-namespace")
+			require.Error(t, err)
+			require.Contains(t, err.Error(), `invalid key "some key"`)
+		})
+	})
+
 	when("using git ssh keys", func() {
 		it("validates that the git url begins with git@", func() {
 			factory.GitUrl = "some-git"
@@ -61,7 +61,12 @@ func (u *Uploader) ValidateStackIDs(keychain authn.Keychain, buildImageTag, runI
 		return "", err
 	}
 
-	buildStackId, err := getStackId(buildImage)
+	buildConfig, err := buildImage.ConfigFile()
+	if err != nil {
+		return "", err
+	}
+
+	buildStackId, err := getStackId(buildConfig)
 	if err != nil {
 		return "", err
 	}
@@ -71,7 +76,12 @@ func (u *Uploader) ValidateStackIDs(keychain authn.Keychain, buildImageTag, runI
 		return "", err
 	}
 
-	runStackId, err := getStackId(runImage)
+	runConfig, err := runImage.ConfigFile()
+	if err != nil {
+		return "", err
+	}
+
+	runStackId, err := getStackId(runConfig)
 	if err != nil {
 		return "", err
 	}
@@ -80,6 +90,14 @@ func (u *Uploader) ValidateStackIDs(keychain authn.Keychain, buildImageTag, runI
 		return "", errors.Errorf("build stack '%s' does not match run stack '%s'", buildStackId, runStackId)
 	}
 
+	if err := validatePlatform("os", buildConfig.OS, runConfig.OS); err != nil {
+		return "", err
+	}
+
+	if err := validatePlatform("architecture", buildConfig.Architecture, runConfig.Architecture); err != nil {
+		return "", err
+	}
+
 	return buildStackId, nil
 }
 
@@ -109,12 +127,7 @@ func (u *Uploader) UploadedRunImageRef(keychain authn.Keychain, imageTag, dest s
 	return fmt.Sprintf("%s@%s", path.Join(dest, RunImageName), digest.String()), nil
 }
 
-func getStackId(img v1.Image) (string, error) {
-	config, err := img.ConfigFile()
-	if err != nil {
-		return "", err
-	}
-
+func getStackId(config *v1.ConfigFile) (string, error) {
 	labels := config.Config.Labels
 
 	id, ok := labels[IdLabel]
@@ -124,3 +137,11 @@ func getStackId(img v1.Image) (string, error) {
 
 	return id, nil
 }
+
+// validatePlatform skips images that do not set the field in their config
+func validatePlatform(field, buildValue, runValue string) error {
+	if buildValue == "" || runValue == "" || buildValue == runValue {
+		return nil
+	}
+	return errors.Errorf("build image %s '%s' does not match run image %s '%s'", field, buildValue, field, runValue)
+}
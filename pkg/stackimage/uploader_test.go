@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"testing"
 
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/random"
 	"github.com/pivotal/kpack/pkg/registry/imagehelpers"
 	kpackregistryfakes "github.com/pivotal/kpack/pkg/registry/registryfakes"
@@ -93,6 +95,29 @@ func testBuildpackageUploader(t *testing.T, when spec.G, it spec.S) {
 			_, err = uploader.ValidateStackIDs(fakeKeychain, "some/remote-build", "some/remote-run")
 			require.EqualError(t, err, "build stack 'some-id' does not match run stack 'some-other-id'")
 		})
+
+		it("returns error when the image os differs", func() {
+			testBuildImage, err := random.Image(10, 10)
+			require.NoError(t, err)
+
+			testRunImage, err := random.Image(10, 10)
+			require.NoError(t, err)
+
+			testBuildImage, err = imagehelpers.SetStringLabel(testBuildImage, "io.buildpacks.stack.id", "some-id")
+			require.NoError(t, err)
+
+			testRunImage, err = imagehelpers.SetStringLabel(testRunImage, "io.buildpacks.stack.id", "some-id")
+			require.NoError(t, err)
+
+			testBuildImage = setPlatform(t, testBuildImage, "linux", "amd64")
+			testRunImage = setPlatform(t, testRunImage, "windows", "amd64")
+
+			fetcher.AddImage("some/remote-build", testBuildImage)
+			fetcher.AddImage("some/remote-run", testRunImage)
+
+			_, err = uploader.ValidateStackIDs(fakeKeychain, "some/remote-build", "some/remote-run")
+			require.EqualError(t, err, "build image os 'linux' does not match run image os 'windows'")
+		})
 	})
 
 	when("UploadedBuildImageRef", func() {
@@ -131,3 +156,15 @@ func testBuildpackageUploader(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 }
+
+func setPlatform(t *testing.T, image v1.Image, os, architecture string) v1.Image {
+	config, err := image.ConfigFile()
+	require.NoError(t, err)
+
+	config.OS = os
+	config.Architecture = architecture
+
+	image, err = mutate.ConfigFile(image, config)
+	require.NoError(t, err)
+	return image
+}
@@ -21,9 +21,11 @@ func (f FakeClientSetProvider) GetClientSet(namespace string) (clientSet k8s.Cli
 	return f.clientSet, nil
 }
 
+// GetFakeKpackProvider uses an empty k8s clientset so that optional lookups such as the kp-config ConfigMap find nothing
 func GetFakeKpackProvider(kpackClient *kpackfakes.Clientset, namespace string) FakeClientSetProvider {
 	return FakeClientSetProvider{
 		clientSet: k8s.ClientSet{
+			K8sClient:   k8sfakes.NewSimpleClientset(),
 			KpackClient: kpackClient,
 			Namespace:   namespace,
 		},